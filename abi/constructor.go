@@ -28,7 +28,7 @@ func (b *Builder) processConstructor(unit *ir.Constructor) (*Method, error) {
 		}
 		toReturn.Inputs = append(
 			toReturn.Inputs,
-			b.buildMethodIO(methodIo, parameter.GetTypeDescription()),
+			b.buildMethodIO(methodIo, parameterTypeDescription(parameter)),
 		)
 	}
 
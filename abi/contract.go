@@ -48,8 +48,11 @@ func (c *Contract) ToProto() *abi_pb.Contract {
 func (b *Builder) processContract(contract *ir.Contract) (*Contract, error) {
 	toReturn := Contract{}
 
-	// Process state variables.
+	// Process state variables. Only public ones have getters.
 	for _, stateVar := range contract.GetStateVariables() {
+		if stateVar.GetVisibility() != ast_pb.Visibility_PUBLIC {
+			continue
+		}
 		method := b.processStateVariable(stateVar)
 		toReturn = append(toReturn, method)
 	}
@@ -64,6 +67,13 @@ func (b *Builder) processContract(contract *ir.Contract) (*Contract, error) {
 		toReturn = append(toReturn, method)
 	}
 
+	// Process events declared by base contracts.
+	inheritedEvents, err := b.processInheritedEvents(contract, toReturn)
+	if err != nil {
+		return nil, err
+	}
+	toReturn = append(toReturn, inheritedEvents...)
+
 	// Process errors.
 	for _, errorNode := range contract.GetErrors() {
 		method, err := b.processError(errorNode)
@@ -74,6 +84,13 @@ func (b *Builder) processContract(contract *ir.Contract) (*Contract, error) {
 		toReturn = append(toReturn, method)
 	}
 
+	// Process errors declared by base contracts or reverted with in their code.
+	inheritedErrors, err := b.processInheritedErrors(contract, toReturn)
+	if err != nil {
+		return nil, err
+	}
+	toReturn = append(toReturn, inheritedErrors...)
+
 	// Process constructor.
 	if contract.GetConstructor() != nil {
 		method, err := b.processConstructor(contract.GetConstructor())
@@ -84,16 +101,29 @@ func (b *Builder) processContract(contract *ir.Contract) (*Contract, error) {
 		toReturn = append(toReturn, method)
 	}
 
-	// Process functions.
+	// Process functions. Functions of interfaces are implicitly external, while libraries expose their
+	// public and external functions the same way contracts do.
 	for _, function := range contract.GetFunctions() {
-		if function.GetVisibility() == ast_pb.Visibility_PUBLIC && function.GetVisibility() == ast_pb.Visibility_EXTERNAL {
-			method, err := b.processFunction(function)
-			if err != nil {
-				return nil, err
-			}
+		if contract.GetKind() != ast_pb.NodeType_KIND_INTERFACE &&
+			function.GetVisibility() != ast_pb.Visibility_PUBLIC && function.GetVisibility() != ast_pb.Visibility_EXTERNAL {
+			continue
+		}
+
+		method, err := b.processFunction(function)
+		if err != nil {
+			return nil, err
+		}
 
-			toReturn = append(toReturn, method)
+		if contract.GetKind() == ast_pb.NodeType_KIND_LIBRARY {
+			for i, parameter := range function.GetParameters() {
+				if parameter.GetAST() != nil && parameter.GetAST().GetStorageLocation() == ast_pb.StorageLocation_STORAGE {
+					method.Inputs[i].Type = solcLibraryStorageType(parameterTypeDescription(parameter))
+					method.Inputs[i].Components = nil
+				}
+			}
 		}
+
+		toReturn = append(toReturn, method)
 	}
 
 	// Process fallback.
@@ -117,6 +147,15 @@ func (b *Builder) processContract(contract *ir.Contract) (*Contract, error) {
 	return &toReturn, nil
 }
 
+// parameterTypeDescription returns the type description of the parameter, along with the array
+// dimensions of its type name that descriptions of arrays of user defined types leave out.
+func parameterTypeDescription(parameter *ir.Parameter) *ast.TypeDescription {
+	if parameter.GetAST() == nil {
+		return parameter.GetTypeDescription()
+	}
+	return solcTypeDescription(parameter.GetTypeDescription(), parameter.GetAST().GetTypeName())
+}
+
 // buildMethodIO constructs a MethodIO object based on the provided method and type description.
 // It resolves the type of the method and sets the appropriate fields.
 func (b *Builder) buildMethodIO(method MethodIO, typeDescr *ast.TypeDescription) MethodIO {
@@ -134,7 +173,12 @@ func (b *Builder) buildMethodIO(method MethodIO, typeDescr *ast.TypeDescription)
 		method.Type = "uint8"
 		method.InternalType = typeDescr.GetString()
 	case "struct":
-		return b.resolver.ResolveStructType(typeDescr)
+		resolved := b.resolver.ResolveStructType(typeDescr)
+		resolved.Name, resolved.Indexed = method.Name, method.Indexed
+		return resolved
+	case "userDefinedValueType":
+		method.Type, _ = b.resolver.ResolveUserDefinedValueType(typeDescr)
+		method.InternalType = typeDescr.GetString()
 	default:
 		method.Type = typeName
 		method.InternalType = typeDescr.GetString()
@@ -0,0 +1,155 @@
+package abi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo"
+)
+
+func TestInterfaceAndLibraryABI(t *testing.T) {
+	newBuilder := func() *Builder {
+		builder, err := NewBuilderFromSources(context.TODO(), &solgo.Sources{
+			SourceUnits: []*solgo.SourceUnit{
+				{
+					Name: "Registry",
+					Path: "Registry.sol",
+					Content: `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.19;
+
+struct Entry {
+    address owner;
+    uint64 expiry;
+}
+
+interface IRegistry {
+    event Registered(bytes32 indexed name, address owner);
+
+    function register(bytes32 name, Entry calldata entry) external returns (bool);
+
+    function entryOf(bytes32 name) external view returns (Entry memory);
+}
+
+library Entries {
+    struct Data {
+        mapping(bytes32 => Entry) entries;
+        uint256 count;
+    }
+
+    error Expired(bytes32 name);
+
+    function add(Data storage self, bytes32 name, Entry memory entry) public returns (uint256) {
+        self.entries[name] = entry;
+        return ++self.count;
+    }
+
+    function owners(mapping(bytes32 => address) storage self, bytes32 name) external view returns (address) {
+        return self[name];
+    }
+
+    function expiry(Entry memory entry) public pure returns (uint64) {
+        return entry.expiry;
+    }
+
+    function check(Data storage self, bytes32 name) internal view {
+        if (self.entries[name].expiry < block.timestamp) {
+            revert Expired(name);
+        }
+    }
+}
+
+contract Registry {
+    using Entries for Entries.Data;
+
+    Entries.Data private data;
+
+    function register(bytes32 name, Entry calldata entry) external returns (uint256) {
+        data.check(name);
+        return data.add(name, entry);
+    }
+}
+`,
+				},
+			},
+			EntrySourceUnitName: "Registry",
+			LocalSourcesPath:    "../sources/",
+		})
+		require.NoError(t, err)
+		assert.Empty(t, builder.Parse())
+		require.NoError(t, builder.Build())
+		return builder
+	}
+	builder := newBuilder()
+
+	entry := func(name string, internalType string) MethodIO {
+		return MethodIO{Name: name, Type: "tuple", InternalType: internalType, Components: []MethodIO{
+			{Name: "owner", Type: "address", InternalType: "address"},
+			{Name: "expiry", Type: "uint64", InternalType: "uint64"},
+		}}
+	}
+
+	registry := builder.GetRoot().GetContractByName("IRegistry")
+	require.NotNil(t, registry)
+	assert.Equal(t, []string{
+		"event Registered(bytes32 indexed name, address owner)",
+		"function register(bytes32 name, tuple(address owner, uint64 expiry) entry) returns (bool)",
+		"function entryOf(bytes32 name) view returns (tuple(address owner, uint64 expiry))",
+	}, registry.ToHumanReadable())
+	register := registry.GetMethodByName("register")
+	require.NotNil(t, register)
+	assert.Equal(t, entry("entry", "struct Entry"), register.Inputs[1])
+
+	library := builder.GetRoot().GetContractByName("Entries")
+	require.NotNil(t, library)
+	assert.Nil(t, library.GetMethodByName("check"))
+
+	add := library.GetMethodByName("add")
+	require.NotNil(t, add)
+	assert.Equal(t, []MethodIO{
+		{Name: "self", Type: "Entries.Data storage", InternalType: "struct Entries.Data"},
+		{Name: "name", Type: "bytes32", InternalType: "bytes32"},
+		entry("entry", "struct Entry"),
+	}, add.Inputs)
+	assert.Equal(t, "add(Entries.Data storage,bytes32,(address,uint64))", add.CanonicalSignature())
+
+	owners := library.GetMethodByName("owners")
+	require.NotNil(t, owners)
+	assert.Equal(t, "mapping(bytes32 => address) storage", owners.Inputs[0].Type)
+	assert.Equal(t, "view", owners.StateMutability)
+
+	expiry := library.GetMethodByName("expiry")
+	require.NotNil(t, expiry)
+	assert.Equal(t, "pure", expiry.StateMutability)
+	assert.Equal(t, []MethodIO{entry("entry", "struct Entry")}, expiry.Inputs)
+
+	assert.NotNil(t, library.GetMethodByName("Expired"))
+	assert.NotNil(t, builder.GetRoot().GetContractByName("Registry").GetMethodByName("register"))
+
+	type solcEntryForTest struct {
+		Name   string `json:"name"`
+		Type   string `json:"type"`
+		Inputs []struct {
+			Type string `json:"type"`
+		} `json:"inputs"`
+	}
+	solcJSON, err := newBuilder().ToSolcJSONByName("Entries")
+	require.NoError(t, err)
+	solcEntries := make([]solcEntryForTest, 0)
+	require.NoError(t, json.Unmarshal(solcJSON, &solcEntries))
+
+	types := make(map[string][]string)
+	for _, solcEntry := range solcEntries {
+		for _, input := range solcEntry.Inputs {
+			types[solcEntry.Name] = append(types[solcEntry.Name], input.Type)
+		}
+	}
+	assert.Equal(t, map[string][]string{
+		"Expired": {"bytes32"},
+		"add":     {"Entries.Data storage", "bytes32", "tuple"},
+		"expiry":  {"tuple"},
+		"owners":  {"mapping(bytes32 => address) storage", "bytes32"},
+	}, types)
+}
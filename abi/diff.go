@@ -0,0 +1,275 @@
+package abi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// DiffSeverity is how a change between two ABIs affects existing callers.
+type DiffSeverity string
+
+const (
+	DiffBreaking   DiffSeverity = "breaking"   // Existing callers, decoders or indexers stop working.
+	DiffAdditive   DiffSeverity = "additive"   // Something new is available, existing uses keep working.
+	DiffCompatible DiffSeverity = "compatible" // Nothing observable changes for callers, such as renamed parameters.
+)
+
+// DiffChangeKind is what changed between two ABIs.
+type DiffChangeKind string
+
+const (
+	DiffAdded               DiffChangeKind = "added"
+	DiffRemoved             DiffChangeKind = "removed"
+	DiffInputsChanged       DiffChangeKind = "inputs_changed"
+	DiffOutputsChanged      DiffChangeKind = "outputs_changed"
+	DiffMutabilityTightened DiffChangeKind = "mutability_tightened"
+	DiffMutabilityLoosened  DiffChangeKind = "mutability_loosened"
+	DiffIndexedChanged      DiffChangeKind = "indexed_changed"
+	DiffParametersRenamed   DiffChangeKind = "parameters_renamed"
+	DiffInternalTypeChanged DiffChangeKind = "internal_type_changed"
+)
+
+// DiffChange is a single difference between two ABIs.
+type DiffChange struct {
+	Severity    DiffSeverity   `json:"severity"`
+	Kind        DiffChangeKind `json:"kind"`
+	Type        string         `json:"type"`               // Type of the method, such as function or event.
+	Name        string         `json:"name"`               // Name of the method.
+	Signature   string         `json:"signature"`          // Canonical signature of the method in the old ABI, or in the new one if added.
+	Selector    string         `json:"selector,omitempty"` // Selector or topic of the signature.
+	Description string         `json:"description"`
+	Old         *Method        `json:"old,omitempty"`
+	New         *Method        `json:"new,omitempty"`
+}
+
+// DiffReport lists the differences between two ABIs of a contract.
+type DiffReport struct {
+	Breaking bool          `json:"breaking"`
+	Changes  []*DiffChange `json:"changes"`
+}
+
+// IsBreaking reports whether any of the changes breaks existing callers.
+func (r *DiffReport) IsBreaking() bool {
+	return r.Breaking
+}
+
+// GetChanges returns all the changes, in the order of the old ABI followed by the additions.
+func (r *DiffReport) GetChanges() []*DiffChange {
+	return r.Changes
+}
+
+// GetChangesBySeverity returns the changes of the given severity.
+func (r *DiffReport) GetChangesBySeverity(severity DiffSeverity) []*DiffChange {
+	toReturn := make([]*DiffChange, 0)
+	for _, change := range r.Changes {
+		if change.Severity == severity {
+			toReturn = append(toReturn, change)
+		}
+	}
+	return toReturn
+}
+
+// ToJSON returns the JSON representation of the report.
+func (r *DiffReport) ToJSON() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// mutabilityRanks orders state mutabilities from the most to the least restrictive.
+var mutabilityRanks = map[string]int{
+	"pure":       0,
+	"view":       1,
+	"nonpayable": 2,
+	"payable":    3,
+}
+
+// Diff compares two ABIs of a contract, such as the implementations before and after an upgrade, and
+// classifies every difference by how it affects existing callers.
+//
+// Methods are matched by their canonical signature. Removed functions, events, errors, fallback and
+// receive functions are breaking, and so are changed parameter types, which show as a method removed
+// and another one of the same name added. Changed outputs and changed indexed event parameters are
+// breaking, as existing decoders no longer match. Tightened mutability, such as payable to nonpayable,
+// is breaking, and so is loosening view or pure to a state changing mutability, as static calls then
+// revert. Additions and nonpayable becoming payable are additive, while renamed parameters and changed
+// internal types are compatible. Constructors are not callable once deployed, so their changes are
+// compatible.
+func Diff(oldABI *Contract, newABI *Contract) *DiffReport {
+	toReturn := &DiffReport{
+		Changes: make([]*DiffChange, 0),
+	}
+
+	oldMethods, newMethods := diffMethods(oldABI), diffMethods(newABI)
+	newByKey := make(map[string]*Method)
+	for _, method := range newMethods {
+		newByKey[diffKey(method)] = method
+	}
+	oldByKey := make(map[string]*Method)
+	for _, method := range oldMethods {
+		oldByKey[diffKey(method)] = method
+	}
+
+	add := func(severity DiffSeverity, kind DiffChangeKind, oldMethod *Method, newMethod *Method, description string) {
+		method := oldMethod
+		if method == nil {
+			method = newMethod
+		}
+		toReturn.Changes = append(toReturn.Changes, &DiffChange{
+			Severity:    severity,
+			Kind:        kind,
+			Type:        method.Type,
+			Name:        method.Name,
+			Signature:   method.CanonicalSignature(),
+			Selector:    method.Selector(),
+			Description: description,
+			Old:         oldMethod,
+			New:         newMethod,
+		})
+		if severity == DiffBreaking {
+			toReturn.Breaking = true
+		}
+	}
+
+	// Methods only in one of the ABIs with the same type and name are the same method with other inputs.
+	paired := make(map[*Method]bool)
+	for _, oldMethod := range oldMethods {
+		if newMethod, ok := newByKey[diffKey(oldMethod)]; ok {
+			paired[newMethod] = true
+			diffMatched(oldMethod, newMethod, add)
+			continue
+		}
+
+		var replacement *Method
+		for _, newMethod := range newMethods {
+			if _, matched := oldByKey[diffKey(newMethod)]; !matched && !paired[newMethod] &&
+				newMethod.Type == oldMethod.Type && newMethod.Name == oldMethod.Name {
+				replacement = newMethod
+				break
+			}
+		}
+
+		switch {
+		case replacement != nil && oldMethod.Type == "constructor":
+			paired[replacement] = true
+			add(DiffCompatible, DiffInputsChanged, oldMethod, replacement, fmt.Sprintf(
+				"constructor inputs changed from %s to %s", oldMethod.CanonicalSignature(), replacement.CanonicalSignature(),
+			))
+		case replacement != nil:
+			paired[replacement] = true
+			add(DiffBreaking, DiffInputsChanged, oldMethod, replacement, fmt.Sprintf(
+				"%s inputs changed from %s to %s", oldMethod.Type, oldMethod.CanonicalSignature(), replacement.CanonicalSignature(),
+			))
+		case oldMethod.Type == "constructor":
+			add(DiffCompatible, DiffRemoved, oldMethod, nil, "constructor removed")
+		default:
+			add(DiffBreaking, DiffRemoved, oldMethod, nil, fmt.Sprintf("%s %s removed", oldMethod.Type, oldMethod.CanonicalSignature()))
+		}
+	}
+
+	for _, newMethod := range newMethods {
+		if paired[newMethod] {
+			continue
+		}
+		if newMethod.Type == "constructor" {
+			add(DiffCompatible, DiffAdded, nil, newMethod, "constructor added")
+			continue
+		}
+		add(DiffAdditive, DiffAdded, nil, newMethod, fmt.Sprintf("%s %s added", newMethod.Type, newMethod.CanonicalSignature()))
+	}
+
+	return toReturn
+}
+
+// diffMatched records the differences between two methods with the same signature.
+func diffMatched(oldMethod *Method, newMethod *Method, add func(DiffSeverity, DiffChangeKind, *Method, *Method, string)) {
+	signature := oldMethod.CanonicalSignature()
+
+	oldTypes, newTypes := diffCanonicalTypes(oldMethod.Outputs), diffCanonicalTypes(newMethod.Outputs)
+	if oldTypes != newTypes {
+		add(DiffBreaking, DiffOutputsChanged, oldMethod, newMethod, fmt.Sprintf(
+			"%s outputs changed from (%s) to (%s)", signature, oldTypes, newTypes,
+		))
+	}
+
+	if oldMethod.Type == "event" && diffIndexed(oldMethod.Inputs) != diffIndexed(newMethod.Inputs) {
+		add(DiffBreaking, DiffIndexedChanged, oldMethod, newMethod, fmt.Sprintf(
+			"%s indexed parameters changed from (%s) to (%s)", signature, diffIndexed(oldMethod.Inputs), diffIndexed(newMethod.Inputs),
+		))
+	}
+
+	// Events and errors carry no mutability.
+	if oldMethod.Type != "event" && oldMethod.Type != "error" && oldMethod.StateMutability != newMethod.StateMutability {
+		oldRank, oldKnown := mutabilityRanks[oldMethod.StateMutability]
+		newRank, newKnown := mutabilityRanks[newMethod.StateMutability]
+		description := fmt.Sprintf("%s mutability changed from %s to %s", signature, oldMethod.StateMutability, newMethod.StateMutability)
+		switch {
+		case !oldKnown || !newKnown:
+		case newRank < oldRank:
+			add(DiffBreaking, DiffMutabilityTightened, oldMethod, newMethod, description)
+		case oldRank <= mutabilityRanks["view"] && newRank > mutabilityRanks["view"]:
+			add(DiffBreaking, DiffMutabilityLoosened, oldMethod, newMethod, description)
+		default:
+			add(DiffAdditive, DiffMutabilityLoosened, oldMethod, newMethod, description)
+		}
+	}
+
+	if diffNames(oldMethod.Inputs) != diffNames(newMethod.Inputs) || diffNames(oldMethod.Outputs) != diffNames(newMethod.Outputs) {
+		add(DiffCompatible, DiffParametersRenamed, oldMethod, newMethod, fmt.Sprintf("%s parameters renamed", signature))
+	}
+
+	// Internal types of outputs whose types changed are part of that change.
+	if diffInternalTypes(oldMethod.Inputs) != diffInternalTypes(newMethod.Inputs) ||
+		(oldTypes == newTypes && diffInternalTypes(oldMethod.Outputs) != diffInternalTypes(newMethod.Outputs)) {
+		add(DiffCompatible, DiffInternalTypeChanged, oldMethod, newMethod, fmt.Sprintf("%s internal types changed", signature))
+	}
+}
+
+// diffMethods returns the methods of the ABI, or none for a nil ABI.
+func diffMethods(contract *Contract) []*Method {
+	if contract == nil {
+		return nil
+	}
+	return *contract
+}
+
+// diffKey identifies a method across ABIs.
+func diffKey(method *Method) string {
+	return method.Type + ":" + method.CanonicalSignature()
+}
+
+// diffCanonicalTypes returns the canonical types of the parameters, comma separated.
+func diffCanonicalTypes(parameters []MethodIO) string {
+	types := make([]string, 0, len(parameters))
+	for _, parameter := range parameters {
+		types = append(types, canonicalType(parameter))
+	}
+	return strings.Join(types, ",")
+}
+
+// diffIndexed returns which parameters are indexed, comma separated.
+func diffIndexed(parameters []MethodIO) string {
+	indexed := make([]string, 0, len(parameters))
+	for _, parameter := range parameters {
+		indexed = append(indexed, fmt.Sprintf("%t", parameter.Indexed))
+	}
+	return strings.Join(indexed, ",")
+}
+
+// diffNames returns the names of the parameters and their components, comma separated.
+func diffNames(parameters []MethodIO) string {
+	names := make([]string, 0, len(parameters))
+	for _, parameter := range parameters {
+		names = append(names, parameter.Name+"("+diffNames(parameter.Components)+")")
+	}
+	return strings.Join(names, ",")
+}
+
+// diffInternalTypes returns the internal types of the parameters and their components, comma separated.
+func diffInternalTypes(parameters []MethodIO) string {
+	types := make([]string, 0, len(parameters))
+	for _, parameter := range parameters {
+		types = append(types, parameter.InternalType+"("+diffInternalTypes(parameter.Components)+")")
+	}
+	return strings.Join(types, ",")
+}
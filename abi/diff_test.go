@@ -0,0 +1,93 @@
+package abi
+
+import (
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	oldABI, err := ParseHumanReadableABI([]string{
+		"constructor(address owner)",
+		"function deposit() payable",
+		"function balanceOf(address account) view returns (uint256)",
+		"function withdraw(uint256 amount)",
+		"function total() view returns (uint256)",
+		"function name() view returns (string)",
+		"function sweep(address to)",
+		"event Deposited(address indexed account, uint256 amount)",
+		"error Unauthorized(address caller)",
+		"receive() payable",
+	})
+	require.NoError(t, err)
+
+	newABI, err := ParseHumanReadableABI([]string{
+		"constructor(address owner, uint256 fee)",
+		"function deposit()",
+		"function balanceOf(address owner) view returns (uint256)",
+		"function withdraw(uint256 amount, address to)",
+		"function total() view returns (uint128)",
+		"function name() pure returns (string)",
+		"function pause()",
+		"event Deposited(address indexed account, uint256 indexed amount)",
+		"error Unauthorized(address caller)",
+		"receive() payable",
+	})
+	require.NoError(t, err)
+
+	report := Diff(oldABI, newABI)
+	assert.True(t, report.IsBreaking())
+
+	type change struct {
+		Severity  DiffSeverity
+		Kind      DiffChangeKind
+		Signature string
+	}
+	changes := make([]change, 0)
+	for _, c := range report.GetChanges() {
+		changes = append(changes, change{c.Severity, c.Kind, c.Signature})
+	}
+	assert.Equal(t, []change{
+		{DiffCompatible, DiffInputsChanged, "constructor(address)"},
+		{DiffBreaking, DiffMutabilityTightened, "deposit()"},
+		{DiffCompatible, DiffParametersRenamed, "balanceOf(address)"},
+		{DiffBreaking, DiffInputsChanged, "withdraw(uint256)"},
+		{DiffBreaking, DiffOutputsChanged, "total()"},
+		{DiffBreaking, DiffMutabilityTightened, "name()"},
+		{DiffBreaking, DiffRemoved, "sweep(address)"},
+		{DiffBreaking, DiffIndexedChanged, "Deposited(address,uint256)"},
+		{DiffAdditive, DiffAdded, "pause()"},
+	}, changes)
+
+	assert.Len(t, report.GetChangesBySeverity(DiffAdditive), 1)
+	assert.Equal(t, "0x8456cb59", report.GetChangesBySeverity(DiffAdditive)[0].Selector)
+
+	// Static calls revert once a view function changes state.
+	loosened := Diff(
+		&Contract{{Type: "function", Name: "price", StateMutability: "view", Inputs: []MethodIO{}}},
+		&Contract{{Type: "function", Name: "price", StateMutability: "nonpayable", Inputs: []MethodIO{}}},
+	)
+	require.Len(t, loosened.GetChanges(), 1)
+	assert.Equal(t, DiffBreaking, loosened.GetChanges()[0].Severity)
+	assert.Equal(t, DiffMutabilityLoosened, loosened.GetChanges()[0].Kind)
+
+	payable := Diff(
+		&Contract{{Type: "function", Name: "buy", StateMutability: "nonpayable", Inputs: []MethodIO{}}},
+		&Contract{{Type: "function", Name: "buy", StateMutability: "payable", Inputs: []MethodIO{}}},
+	)
+	assert.False(t, payable.IsBreaking())
+	assert.Len(t, payable.GetChangesBySeverity(DiffAdditive), 1)
+
+	unchanged := Diff(oldABI, oldABI)
+	assert.False(t, unchanged.IsBreaking())
+	assert.Empty(t, unchanged.GetChanges())
+
+	encoded, err := report.ToJSON()
+	require.NoError(t, err)
+	var decoded DiffReport
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.True(t, decoded.Breaking)
+	assert.Len(t, decoded.Changes, len(report.Changes))
+}
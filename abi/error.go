@@ -3,6 +3,7 @@ package abi
 import (
 	"fmt"
 
+	"github.com/unpackdev/solgo/ast"
 	"github.com/unpackdev/solgo/ir"
 )
 
@@ -28,9 +29,126 @@ func (b *Builder) processError(unit *ir.Error) (*Method, error) {
 		}
 		toReturn.Inputs = append(
 			toReturn.Inputs,
-			b.buildMethodIO(methodIo, parameter.GetTypeDescription()),
+			b.buildMethodIO(methodIo, parameterTypeDescription(parameter)),
 		)
 	}
 
 	return toReturn, nil
 }
+
+// processErrorDefinition processes an error definition that is not part of the IR contract, such as one
+// declared by a base contract, and returns a Method representation of it.
+func (b *Builder) processErrorDefinition(definition *ast.ErrorDefinition) (*Method, error) {
+	toReturn := &Method{
+		Name:            definition.GetName(),
+		Inputs:          make([]MethodIO, 0),
+		Outputs:         make([]MethodIO, 0),
+		Type:            "error",
+		StateMutability: "view",
+	}
+
+	if definition.GetParameters() == nil {
+		return toReturn, nil
+	}
+
+	for _, parameter := range definition.GetParameters().GetParameters() {
+		if parameter.GetTypeDescription() == nil {
+			return nil, fmt.Errorf("nil type description for error parameter %s", parameter.GetName())
+		}
+
+		methodIo := MethodIO{
+			Name: parameter.GetName(),
+		}
+		toReturn.Inputs = append(
+			toReturn.Inputs,
+			b.buildMethodIO(methodIo, solcTypeDescription(parameter.GetTypeDescription(), parameter.GetTypeName())),
+		)
+	}
+
+	return toReturn, nil
+}
+
+// processInheritedErrors returns the errors declared by the base contracts of the contract and the errors
+// reverted with anywhere in its inheritance, including file-level errors imported from other files, as
+// solc includes them in the contract ABI. Errors with a signature already in methods are skipped.
+func (b *Builder) processInheritedErrors(contract *ir.Contract, methods Contract) ([]*Method, error) {
+	toReturn := make([]*Method, 0)
+
+	seen := make(map[string]bool)
+	for _, method := range methods {
+		if method.Type == "error" {
+			seen[method.CanonicalSignature()] = true
+		}
+	}
+
+	add := func(definition *ast.ErrorDefinition) error {
+		method, err := b.processErrorDefinition(definition)
+		if err != nil {
+			return err
+		}
+		if signature := method.CanonicalSignature(); !seen[signature] {
+			seen[signature] = true
+			toReturn = append(toReturn, method)
+		}
+		return nil
+	}
+
+	// Contracts whose inheritance cannot be resolved still get the errors reverted with in their own code.
+	tree := b.parser.GetAstBuilder().GetTree()
+	linearized := b.linearizedContracts(contract)
+
+	for _, node := range linearized {
+		if node == nil {
+			continue
+		}
+		for _, child := range node.GetNodes() {
+			if definition, ok := child.(*ast.ErrorDefinition); ok {
+				if err := add(definition); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	var walkErr error
+	for _, node := range linearized {
+		if node == nil {
+			continue
+		}
+		_ = tree.WalkNode(node, &ast.NodeVisitor{
+			Visit: func(visited ast.Node[ast.NodeType]) bool {
+				revert, ok := visited.(*ast.RevertStatement)
+				if !ok || walkErr != nil {
+					return walkErr == nil
+				}
+				if definition := b.solcRevertedError(revert); definition != nil {
+					walkErr = add(definition)
+				}
+				return true
+			},
+		})
+	}
+	if walkErr != nil {
+		return nil, walkErr
+	}
+
+	return toReturn, nil
+}
+
+// linearizedContracts returns the AST nodes of the contract and its base contracts, in the order of its C3
+// linearization, or only the contract itself when its inheritance cannot be resolved.
+func (b *Builder) linearizedContracts(contract *ir.Contract) []ast.Node[ast.NodeType] {
+	inheritance, err := b.parser.GetInheritance(contract.GetName())
+	if err != nil {
+		return []ast.Node[ast.NodeType]{contract.GetAST().GetContract()}
+	}
+
+	tree := b.parser.GetAstBuilder().GetTree()
+	toReturn := make([]ast.Node[ast.NodeType], 0, len(inheritance.Linearized))
+	for _, id := range inheritance.Linearized {
+		if node := tree.GetById(id); node != nil {
+			toReturn = append(toReturn, node)
+		}
+	}
+	return toReturn
+}
@@ -1,9 +1,13 @@
 package abi
 
 import (
+	"context"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo"
 	"github.com/unpackdev/solgo/ast"
 	"github.com/unpackdev/solgo/ir"
 )
@@ -96,3 +100,142 @@ func TestProcessError(t *testing.T) {
 		})
 	}
 }
+
+func TestCustomErrorsInAbi(t *testing.T) {
+	builder, err := NewBuilderFromSources(context.TODO(), &solgo.Sources{
+		SourceUnits: []*solgo.SourceUnit{
+			{
+				Name: "Vault",
+				Path: "Vault.sol",
+				Content: `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.4;
+
+error Unauthorized(address caller);
+error Unused();
+
+contract Vault {
+    error InsufficientBalance(uint256 available, uint256 required);
+
+    address owner;
+    mapping(address => uint256) balances;
+
+    function withdraw(uint256 amount) public {
+        if (msg.sender != owner) revert Unauthorized(msg.sender);
+        if (balances[msg.sender] < amount) {
+            revert InsufficientBalance({available: balances[msg.sender], required: amount});
+        }
+        balances[msg.sender] -= amount;
+    }
+}
+`,
+			},
+		},
+		EntrySourceUnitName: "Vault",
+		LocalSourcesPath:    "../sources/",
+	})
+	require.NoError(t, err)
+	require.Empty(t, builder.Parse())
+	require.NoError(t, builder.Build())
+
+	errors := make(map[string]*Method)
+	for _, method := range *builder.GetEntryContract() {
+		if method.Type == "error" {
+			errors[method.Name] = method
+		}
+	}
+	require.Len(t, errors, 2, "unused file-level errors must not be part of the contract ABI")
+
+	insufficient := errors["InsufficientBalance"]
+	require.NotNil(t, insufficient)
+	assert.Equal(t, "InsufficientBalance(uint256,uint256)", insufficient.CanonicalSignature())
+	assert.Equal(t, "0xcf479181", insufficient.Selector())
+
+	unauthorized := errors["Unauthorized"]
+	require.NotNil(t, unauthorized)
+	assert.Equal(t, []MethodIO{{Name: "caller", Type: "address", InternalType: "address"}}, unauthorized.Inputs)
+	assert.Equal(t, "0x8e4a23d6", unauthorized.Selector())
+
+	goAbi, err := builder.ToABI(builder.GetEntryContract())
+	require.NoError(t, err)
+	assert.Len(t, goAbi.Errors, 2)
+	assert.Equal(t, "8e4a23d6", common.Bytes2Hex(goAbi.Errors["Unauthorized"].ID.Bytes()[:4]))
+}
+
+func TestProcessInheritedErrors(t *testing.T) {
+	builder, err := NewBuilderFromSources(context.TODO(), &solgo.Sources{
+		SourceUnits: []*solgo.SourceUnit{
+			{
+				Name: "Errors",
+				Path: "Errors.sol",
+				Content: `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.19;
+
+error Unauthorized(address caller);
+error Unused();
+
+library Math {
+    error Overflow(uint256 value);
+}
+`,
+			},
+			{
+				Name: "Vault",
+				Path: "Vault.sol",
+				Content: `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.19;
+
+import "./Errors.sol";
+
+error Paused();
+
+contract Base {
+    error BaseFailed();
+
+    function check() internal view {
+        if (msg.sender == address(0)) revert Paused();
+    }
+}
+
+contract Vault is Base {
+    error Closed(uint256 id);
+
+    function close(uint256 id) external {
+        if (id == 0) revert Unauthorized(msg.sender);
+        if (id == 1) revert Math.Overflow(id);
+        check();
+    }
+}
+`,
+			},
+		},
+		EntrySourceUnitName: "Vault",
+		LocalSourcesPath:    "../sources/",
+	})
+	require.NoError(t, err)
+	require.Empty(t, builder.Parse())
+	require.NoError(t, builder.Build())
+
+	errors := make([]string, 0)
+	for _, method := range *builder.GetEntryContract() {
+		if method.Type == "error" {
+			errors = append(errors, method.CanonicalSignature())
+		}
+	}
+	// Errors declared by bases and reverted with by inherited code are included, unused ones are not.
+	assert.ElementsMatch(t, []string{
+		"Closed(uint256)",
+		"Unauthorized(address)",
+		"Overflow(uint256)",
+		"BaseFailed()",
+		"Paused()",
+	}, errors)
+
+	// Revert data decodes from the generated ABI alone.
+	goAbi, err := builder.ToGethABI()
+	require.NoError(t, err)
+	unauthorized := goAbi.Errors["Unauthorized"]
+	revertData := append(unauthorized.ID.Bytes()[:4], common.LeftPadBytes(common.HexToAddress("0x01").Bytes(), 32)...)
+	decoded, err := unauthorized.Unpack(revertData)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{common.HexToAddress("0x01")}, decoded)
+}
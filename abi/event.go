@@ -3,6 +3,7 @@ package abi
 import (
 	"fmt"
 
+	"github.com/unpackdev/solgo/ast"
 	"github.com/unpackdev/solgo/ir"
 )
 
@@ -15,6 +16,7 @@ func (b *Builder) processEvent(unit *ir.Event) (*Method, error) {
 		Outputs:         make([]MethodIO, 0),
 		Type:            "event",
 		StateMutability: "view", // Events in Ethereum are view-only and don't modify state.
+		Anonymous:       unit.IsAnonymous(),
 	}
 
 	// Process parameters of the event.
@@ -30,13 +32,82 @@ func (b *Builder) processEvent(unit *ir.Event) (*Method, error) {
 		}
 		toReturn.Inputs = append(
 			toReturn.Inputs,
-			b.buildMethodIO(methodIo, parameter.GetTypeDescription()),
+			b.buildMethodIO(methodIo, parameterTypeDescription(parameter)),
 		)
 	}
 
 	return toReturn, nil
 }
 
+// processEventDefinition processes an event definition that is not part of the IR contract, such as one
+// declared by a base contract, and returns a Method representation of it.
+func (b *Builder) processEventDefinition(definition *ast.EventDefinition) (*Method, error) {
+	toReturn := &Method{
+		Name:            definition.GetName(),
+		Inputs:          make([]MethodIO, 0),
+		Outputs:         make([]MethodIO, 0),
+		Type:            "event",
+		StateMutability: "view",
+		Anonymous:       definition.IsAnonymous(),
+	}
+
+	if definition.GetParameters() == nil {
+		return toReturn, nil
+	}
+
+	for _, parameter := range definition.GetParameters().GetParameters() {
+		if parameter.GetTypeDescription() == nil {
+			return nil, fmt.Errorf("nil type description for event parameter %s", parameter.GetName())
+		}
+
+		methodIo := MethodIO{
+			Name:    parameter.GetName(),
+			Indexed: parameter.IsIndexed(),
+		}
+		toReturn.Inputs = append(
+			toReturn.Inputs,
+			b.buildMethodIO(methodIo, solcTypeDescription(parameter.GetTypeDescription(), parameter.GetTypeName())),
+		)
+	}
+
+	return toReturn, nil
+}
+
+// processInheritedEvents returns the events declared by the base contracts of the contract, as solc
+// includes them in the contract ABI. Events with a signature already in methods are skipped.
+func (b *Builder) processInheritedEvents(contract *ir.Contract, methods Contract) ([]*Method, error) {
+	toReturn := make([]*Method, 0)
+
+	seen := make(map[string]bool)
+	for _, method := range methods {
+		if method.Type == "event" {
+			seen[method.CanonicalSignature()] = true
+		}
+	}
+
+	for _, node := range b.linearizedContracts(contract) {
+		if node == nil {
+			continue
+		}
+		for _, child := range node.GetNodes() {
+			definition, ok := child.(*ast.EventDefinition)
+			if !ok {
+				continue
+			}
+			method, err := b.processEventDefinition(definition)
+			if err != nil {
+				return nil, err
+			}
+			if signature := method.CanonicalSignature(); !seen[signature] {
+				seen[signature] = true
+				toReturn = append(toReturn, method)
+			}
+		}
+	}
+
+	return toReturn, nil
+}
+
 func (b *Builder) GetEventAsAbi(unit *ir.Event) ([]*Method, error) {
 	method, err := b.processEvent(unit)
 	if err != nil {
@@ -29,7 +29,7 @@ func (b *Builder) processFunction(unit *ir.Function) (*Method, error) {
 		}
 		toReturn.Inputs = append(
 			toReturn.Inputs,
-			b.buildMethodIO(methodIo, parameter.GetTypeDescription()),
+			b.buildMethodIO(methodIo, parameterTypeDescription(parameter)),
 		)
 	}
 
@@ -43,7 +43,7 @@ func (b *Builder) processFunction(unit *ir.Function) (*Method, error) {
 		}
 		toReturn.Outputs = append(
 			toReturn.Outputs,
-			b.buildMethodIO(methodIo, parameter.GetTypeDescription()),
+			b.buildMethodIO(methodIo, parameterTypeDescription(parameter)),
 		)
 
 	}
@@ -0,0 +1,118 @@
+package abi
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// ToGethABI converts the ABI of the entry contract into an ethereum/go-ethereum ABI object, ready to pack
+// and unpack calls, events and errors.
+func (b *Builder) ToGethABI() (*abi.ABI, error) {
+	if b.root == nil {
+		return nil, fmt.Errorf("abi root is not built, call Build() first")
+	}
+	return b.ToGethABIByName(b.root.GetEntryName())
+}
+
+// ToGethABIByName converts the ABI of the contract with the given name into an ethereum/go-ethereum ABI
+// object. See ToGethABI.
+func (b *Builder) ToGethABIByName(contractName string) (*abi.ABI, error) {
+	if b.root == nil {
+		return nil, fmt.Errorf("abi root is not built, call Build() first")
+	}
+	contract := b.root.GetContractByName(contractName)
+	if contract == nil {
+		return nil, fmt.Errorf("contract %s not found", contractName)
+	}
+	return contract.ToGethABI()
+}
+
+// ToGethABI converts the contract into an ethereum/go-ethereum ABI object directly, without going through
+// its JSON encoding. Overloaded functions and events are named the way go-ethereum names them when
+// decoding JSON, such as transfer and transfer0.
+func (c *Contract) ToGethABI() (*abi.ABI, error) {
+	toReturn := &abi.ABI{
+		Methods: make(map[string]abi.Method),
+		Events:  make(map[string]abi.Event),
+		Errors:  make(map[string]abi.Error),
+	}
+
+	for _, method := range *c {
+		inputs, err := gethArguments(method.Inputs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert inputs of %s %s: %w", method.Type, method.Name, err)
+		}
+
+		switch method.Type {
+		case "constructor":
+			toReturn.Constructor = abi.NewMethod("", "", abi.Constructor, method.StateMutability, false, false, inputs, nil)
+		case "function":
+			outputs, err := gethArguments(method.Outputs)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert outputs of function %s: %w", method.Name, err)
+			}
+			name := abi.ResolveNameConflict(method.Name, func(s string) bool { _, ok := toReturn.Methods[s]; return ok })
+			toReturn.Methods[name] = abi.NewMethod(name, method.Name, abi.Function, method.StateMutability, false, false, inputs, outputs)
+		case "fallback":
+			if toReturn.HasFallback() {
+				return nil, errors.New("only single fallback is allowed")
+			}
+			toReturn.Fallback = abi.NewMethod("", "", abi.Fallback, method.StateMutability, false, false, nil, nil)
+		case "receive":
+			if toReturn.HasReceive() {
+				return nil, errors.New("only single receive is allowed")
+			}
+			if method.StateMutability != "payable" {
+				return nil, errors.New("the state mutability of receive can only be payable")
+			}
+			toReturn.Receive = abi.NewMethod("", "", abi.Receive, method.StateMutability, false, false, nil, nil)
+		case "event":
+			name := abi.ResolveNameConflict(method.Name, func(s string) bool { _, ok := toReturn.Events[s]; return ok })
+			toReturn.Events[name] = abi.NewEvent(name, method.Name, method.Anonymous, inputs)
+		case "error":
+			toReturn.Errors[method.Name] = abi.NewError(method.Name, inputs)
+		default:
+			return nil, fmt.Errorf("unsupported method type %s of %s", method.Type, method.Name)
+		}
+	}
+
+	return toReturn, nil
+}
+
+// gethArguments converts method parameters into ethereum/go-ethereum arguments.
+func gethArguments(parameters []MethodIO) (abi.Arguments, error) {
+	toReturn := make(abi.Arguments, 0, len(parameters))
+	for _, parameter := range parameters {
+		argumentType, err := abi.NewType(parameter.Type, parameter.InternalType, gethArgumentMarshalings(parameter.Components))
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", parameter.Name, err)
+		}
+		toReturn = append(toReturn, abi.Argument{
+			Name:    parameter.Name,
+			Type:    argumentType,
+			Indexed: parameter.Indexed,
+		})
+	}
+	return toReturn, nil
+}
+
+// gethArgumentMarshalings converts tuple components into the form ethereum/go-ethereum builds tuple
+// types from.
+func gethArgumentMarshalings(components []MethodIO) []abi.ArgumentMarshaling {
+	if len(components) == 0 {
+		return nil
+	}
+	toReturn := make([]abi.ArgumentMarshaling, 0, len(components))
+	for _, component := range components {
+		toReturn = append(toReturn, abi.ArgumentMarshaling{
+			Name:         component.Name,
+			Type:         component.Type,
+			InternalType: component.InternalType,
+			Components:   gethArgumentMarshalings(component.Components),
+			Indexed:      component.Indexed,
+		})
+	}
+	return toReturn
+}
@@ -0,0 +1,99 @@
+package abi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo"
+)
+
+func TestToGethABI(t *testing.T) {
+	builder, err := NewBuilderFromSources(context.TODO(), &solgo.Sources{
+		SourceUnits: []*solgo.SourceUnit{
+			{
+				Name: "Vault",
+				Path: "Vault.sol",
+				Content: `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.19;
+
+contract Vault {
+    struct Position {
+        address owner;
+        uint256 size;
+    }
+
+    Position[] public positions;
+
+    event Opened(uint256 indexed id, Position position);
+
+    error Closed(uint256 id);
+
+    constructor(address owner) {}
+
+    event Moved(address to);
+
+    event Moved(address to, uint256 amount);
+
+    receive() external payable {}
+}
+`,
+			},
+		},
+		EntrySourceUnitName: "Vault",
+		LocalSourcesPath:    "../sources/",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, builder.Parse())
+	require.NoError(t, builder.Build())
+
+	converted, err := builder.ToGethABI()
+	require.NoError(t, err)
+
+	// The direct conversion matches decoding the JSON encoding of the contract.
+	decoded, err := builder.ToABI(builder.GetEntryContract())
+	require.NoError(t, err)
+	require.Len(t, converted.Methods, len(decoded.Methods))
+	for name, method := range decoded.Methods {
+		require.Contains(t, converted.Methods, name)
+		assert.Equal(t, method.Sig, converted.Methods[name].Sig)
+		assert.Equal(t, method.ID, converted.Methods[name].ID)
+		assert.Equal(t, method.StateMutability, converted.Methods[name].StateMutability)
+	}
+	for name, event := range decoded.Events {
+		require.Contains(t, converted.Events, name)
+		assert.Equal(t, event.ID, converted.Events[name].ID)
+	}
+	for name, e := range decoded.Errors {
+		require.Contains(t, converted.Errors, name)
+		assert.Equal(t, e.ID, converted.Errors[name].ID)
+	}
+	assert.Equal(t, decoded.Constructor.Sig, converted.Constructor.Sig)
+	assert.True(t, converted.HasReceive())
+
+	// Overloads are named the way go-ethereum names them.
+	assert.Equal(t, "Moved(address)", converted.Events["Moved"].Sig)
+	assert.Equal(t, "Moved(address,uint256)", converted.Events["Moved0"].Sig)
+
+	// Tuples pack and unpack without going through JSON.
+	position := struct {
+		Owner common.Address
+		Size  *big.Int
+	}{common.HexToAddress("0x01"), big.NewInt(42)}
+	data, err := converted.Events["Opened"].Inputs.NonIndexed().Pack(position)
+	require.NoError(t, err)
+
+	unpacked, err := converted.Events["Opened"].Inputs.NonIndexed().Unpack(data)
+	require.NoError(t, err)
+	require.Len(t, unpacked, 1)
+
+	calldata, err := converted.Pack("positions", big.NewInt(1))
+	require.NoError(t, err)
+	assert.Equal(t, converted.Methods["positions"].ID, calldata[:4])
+
+	_, err = builder.ToGethABIByName("Missing")
+	assert.Error(t, err)
+}
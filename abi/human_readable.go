@@ -0,0 +1,246 @@
+package abi
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// humanReadableElementaryType matches the elementary types of human-readable fragments, with optional array
+// dimensions.
+var humanReadableElementaryType = regexp.MustCompile(`^(u?int\d*|bytes\d*|u?fixed[\dx]*|address|bool|string|function)((\[\d*\])*)$`)
+
+// humanReadableIdentifier matches method and parameter names.
+var humanReadableIdentifier = regexp.MustCompile(`^[a-zA-Z_$][a-zA-Z0-9_$]*$`)
+
+// ToHumanReadable returns the methods of the contract as ethers.js human-readable ABI fragments,
+// in the order the contract lists them.
+func (c *Contract) ToHumanReadable() []string {
+	toReturn := make([]string, 0, len(*c))
+	for _, method := range *c {
+		toReturn = append(toReturn, method.HumanReadableSignature())
+	}
+	return toReturn
+}
+
+// ParseHumanReadableABI parses ethers.js human-readable ABI fragments into a contract, the reverse
+// of Contract.ToHumanReadable.
+func ParseHumanReadableABI(fragments []string) (*Contract, error) {
+	toReturn := Contract{}
+	for _, fragment := range fragments {
+		method, err := ParseHumanReadable(fragment)
+		if err != nil {
+			return nil, err
+		}
+		toReturn = append(toReturn, method)
+	}
+	return &toReturn, nil
+}
+
+// ParseHumanReadable parses a single ethers.js human-readable ABI fragment, such as
+// `function transfer(address to, uint256 amount) returns (bool)` or
+// `event Transfer(address indexed from, address indexed to, uint256 value)`. Fragments without a
+// keyword are parsed as functions. Internal types are set to the ABI types, as fragments do not
+// carry Solidity names of structs, enums or contracts.
+func ParseHumanReadable(fragment string) (*Method, error) {
+	source := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(fragment), ";"))
+
+	toReturn := &Method{
+		Inputs:  make([]MethodIO, 0),
+		Outputs: make([]MethodIO, 0),
+		Type:    "function",
+	}
+
+	rest := source
+	for _, keyword := range []string{"function", "event", "error", "constructor", "fallback", "receive"} {
+		if strings.HasPrefix(rest, keyword) && (len(rest) == len(keyword) || !isIdentifierByte(rest[len(keyword)])) {
+			toReturn.Type = keyword
+			rest = strings.TrimSpace(rest[len(keyword):])
+			break
+		}
+	}
+
+	open := strings.Index(rest, "(")
+	if open == -1 {
+		return nil, fmt.Errorf("invalid human-readable fragment %q: missing parameter list", fragment)
+	}
+	name := strings.TrimSpace(rest[:open])
+	switch toReturn.Type {
+	case "function", "event", "error":
+		if !humanReadableIdentifier.MatchString(name) {
+			return nil, fmt.Errorf("invalid human-readable fragment %q: invalid name %q", fragment, name)
+		}
+		toReturn.Name = name
+	default:
+		if name != "" {
+			return nil, fmt.Errorf("invalid human-readable fragment %q: %s cannot be named", fragment, toReturn.Type)
+		}
+	}
+
+	inputs, rest, err := splitParameterList(rest[open:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid human-readable fragment %q: %w", fragment, err)
+	}
+	if toReturn.Inputs, err = parseHumanReadableParameters(inputs, toReturn.Type == "event"); err != nil {
+		return nil, fmt.Errorf("invalid human-readable fragment %q: %w", fragment, err)
+	}
+
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimSpace(rest) {
+		word := rest
+		if idx := strings.IndexAny(rest, " ("); idx != -1 {
+			word = rest[:idx]
+		}
+		rest = rest[len(word):]
+
+		switch word {
+		case "view", "pure", "payable", "nonpayable":
+			toReturn.StateMutability = word
+		case "anonymous":
+			if toReturn.Type != "event" {
+				return nil, fmt.Errorf("invalid human-readable fragment %q: only events can be anonymous", fragment)
+			}
+			toReturn.Anonymous = true
+		case "external", "public", "virtual", "override":
+		case "returns":
+			var outputs string
+			if outputs, rest, err = splitParameterList(strings.TrimSpace(rest)); err != nil {
+				return nil, fmt.Errorf("invalid human-readable fragment %q: %w", fragment, err)
+			}
+			if toReturn.Outputs, err = parseHumanReadableParameters(outputs, false); err != nil {
+				return nil, fmt.Errorf("invalid human-readable fragment %q: %w", fragment, err)
+			}
+		default:
+			return nil, fmt.Errorf("invalid human-readable fragment %q: unexpected %q", fragment, word)
+		}
+	}
+
+	if toReturn.StateMutability == "" {
+		switch toReturn.Type {
+		case "receive":
+			toReturn.StateMutability = "payable"
+		case "function", "constructor", "fallback":
+			toReturn.StateMutability = "nonpayable"
+		}
+	}
+
+	return toReturn, nil
+}
+
+// splitParameterList splits the parenthesized parameter list at the start of the source from the
+// rest of it, returning the list without its parentheses.
+func splitParameterList(source string) (string, string, error) {
+	if !strings.HasPrefix(source, "(") {
+		return "", "", fmt.Errorf("expected parameter list at %q", source)
+	}
+	depth := 0
+	for i, char := range source {
+		switch char {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return source[1:i], source[i+1:], nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("unbalanced parentheses in %q", source)
+}
+
+// parseHumanReadableParameters parses a comma separated parameter list, such as
+// `address indexed from, tuple(address maker, uint256 amount)[] orders`.
+func parseHumanReadableParameters(source string, event bool) ([]MethodIO, error) {
+	toReturn := make([]MethodIO, 0)
+	if strings.TrimSpace(source) == "" {
+		return toReturn, nil
+	}
+
+	depth, start := 0, 0
+	parts := make([]string, 0)
+	for i, char := range source {
+		switch char {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, source[start:i])
+				start = i + 1
+			}
+		}
+	}
+	parts = append(parts, source[start:])
+
+	for _, part := range parts {
+		parameter, err := parseHumanReadableParameter(strings.TrimSpace(part), event)
+		if err != nil {
+			return nil, err
+		}
+		toReturn = append(toReturn, parameter)
+	}
+
+	return toReturn, nil
+}
+
+// parseHumanReadableParameter parses a single parameter, its type followed by the optional indexed
+// keyword, data location and name.
+func parseHumanReadableParameter(source string, event bool) (MethodIO, error) {
+	toReturn := MethodIO{}
+
+	rest := source
+	if strings.HasPrefix(rest, "tuple(") || strings.HasPrefix(rest, "(") {
+		components, after, err := splitParameterList(strings.TrimPrefix(rest, "tuple"))
+		if err != nil {
+			return toReturn, err
+		}
+		if toReturn.Components, err = parseHumanReadableParameters(components, false); err != nil {
+			return toReturn, err
+		}
+		dimensions := after
+		if idx := strings.IndexByte(after, ' '); idx != -1 {
+			dimensions = after[:idx]
+		}
+		if dimensions != "" && !humanReadableElementaryType.MatchString("bool"+dimensions) {
+			return toReturn, fmt.Errorf("invalid tuple dimensions %q", dimensions)
+		}
+		toReturn.Type = "tuple" + dimensions
+		rest = after[len(dimensions):]
+	} else {
+		typeName := rest
+		if idx := strings.IndexByte(rest, ' '); idx != -1 {
+			typeName = rest[:idx]
+		}
+		rest = rest[len(typeName):]
+
+		matches := humanReadableElementaryType.FindStringSubmatch(typeName)
+		if matches == nil {
+			return toReturn, fmt.Errorf("unsupported parameter type %q", typeName)
+		}
+		switch matches[1] {
+		case "uint", "int":
+			typeName = matches[1] + "256" + matches[2]
+		}
+		toReturn.Type = typeName
+		toReturn.InternalType = typeName
+	}
+
+	for _, word := range strings.Fields(rest) {
+		switch {
+		case word == "indexed" && event:
+			toReturn.Indexed = true
+		case word == "calldata" || word == "memory" || word == "storage" || word == "payable":
+		case toReturn.Name == "" && humanReadableIdentifier.MatchString(word):
+			toReturn.Name = word
+		default:
+			return toReturn, fmt.Errorf("unexpected %q in parameter %q", word, source)
+		}
+	}
+
+	return toReturn, nil
+}
+
+// isIdentifierByte reports whether the byte can be part of an identifier.
+func isIdentifierByte(char byte) bool {
+	return char == '_' || char == '$' || (char >= '0' && char <= '9') || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
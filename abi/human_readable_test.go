@@ -0,0 +1,136 @@
+package abi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHumanReadable(t *testing.T) {
+	testCases := []struct {
+		name      string
+		fragment  string
+		canonical string
+		rendered  string
+		wantErr   bool
+	}{
+		{
+			name:      "Function",
+			fragment:  "function transfer(address to, uint256 amount) returns (bool)",
+			canonical: "transfer(address,uint256)",
+			rendered:  "function transfer(address to, uint256 amount) returns (bool)",
+		},
+		{
+			name:      "View Function With Named Outputs",
+			fragment:  "function balanceOf(address owner) external view returns (uint balance);",
+			canonical: "balanceOf(address)",
+			rendered:  "function balanceOf(address owner) view returns (uint256 balance)",
+		},
+		{
+			name:      "Event",
+			fragment:  "event Transfer(address indexed from, address indexed to, uint256 value)",
+			canonical: "Transfer(address,address,uint256)",
+			rendered:  "event Transfer(address indexed from, address indexed to, uint256 value)",
+		},
+		{
+			name:      "Anonymous Event",
+			fragment:  "event Log(address indexed sender, bytes data) anonymous",
+			canonical: "Log(address,bytes)",
+			rendered:  "event Log(address indexed sender, bytes data) anonymous",
+		},
+		{
+			name:      "Error",
+			fragment:  "error InsufficientBalance(uint256 available, uint256 required)",
+			canonical: "InsufficientBalance(uint256,uint256)",
+			rendered:  "error InsufficientBalance(uint256 available, uint256 required)",
+		},
+		{
+			name:      "Tuple Array",
+			fragment:  "function submit(tuple(address maker, uint256 amount)[] calldata orders, string memo) payable",
+			canonical: "submit((address,uint256)[],string)",
+			rendered:  "function submit(tuple(address maker, uint256 amount)[] orders, string memo) payable",
+		},
+		{
+			name:      "Nested Tuple Shorthand",
+			fragment:  "function settle((address, (uint8 side, bytes32[2] ids)) order, address payable to)",
+			canonical: "settle((address,(uint8,bytes32[2])),address)",
+			rendered:  "function settle(tuple(address, tuple(uint8 side, bytes32[2] ids)) order, address to)",
+		},
+		{
+			name:      "Constructor",
+			fragment:  "constructor(string name_) payable",
+			canonical: "constructor(string)",
+			rendered:  "constructor(string name_) payable",
+		},
+		{
+			name:      "Receive",
+			fragment:  "receive() external payable",
+			canonical: "receive()",
+			rendered:  "receive() payable",
+		},
+		{
+			name:      "Function Without Keyword",
+			fragment:  "approve(address spender, uint256 amount)",
+			canonical: "approve(address,uint256)",
+			rendered:  "function approve(address spender, uint256 amount)",
+		},
+		{name: "Unknown Type", fragment: "function open(Vault.Status status)", wantErr: true},
+		{name: "Unbalanced Parentheses", fragment: "function open(address to", wantErr: true},
+		{name: "Unexpected Modifier", fragment: "function open() onlyOwner", wantErr: true},
+		{name: "Named Constructor", fragment: "constructor init()", wantErr: true},
+		{name: "Anonymous Function", fragment: "function open() anonymous", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			method, err := ParseHumanReadable(tc.fragment)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.canonical, method.CanonicalSignature())
+			assert.Equal(t, tc.rendered, method.HumanReadableSignature())
+
+			if method.Anonymous {
+				assert.Empty(t, method.Selector())
+				gethABI, err := (&Contract{method}).ToGethABI()
+				require.NoError(t, err)
+				assert.True(t, gethABI.Events[method.Name].Anonymous)
+			}
+
+			reparsed, err := ParseHumanReadable(method.HumanReadableSignature())
+			require.NoError(t, err)
+			assert.Equal(t, method, reparsed)
+		})
+	}
+}
+
+func TestContractToHumanReadable(t *testing.T) {
+	fragments := []string{
+		"constructor(address owner)",
+		"event Approval(address indexed owner, address indexed spender, uint256 value)",
+		"function allowance(address owner, address spender) view returns (uint256)",
+		"function approve(address spender, uint256 amount) returns (bool)",
+		"error Unauthorized(address caller)",
+		"fallback() payable",
+	}
+
+	contract, err := ParseHumanReadableABI(fragments)
+	require.NoError(t, err)
+	assert.Equal(t, fragments, contract.ToHumanReadable())
+
+	// Events and errors built from sources are marked view, which fragments leave out.
+	event := &Method{Type: "event", Name: "Ping", StateMutability: "view", Inputs: []MethodIO{}}
+	assert.Equal(t, "event Ping()", (&Contract{event}).ToHumanReadable()[0])
+
+	parsed, err := (&Builder{}).ToABI(contract)
+	require.NoError(t, err)
+	assert.Contains(t, parsed.Methods, "approve")
+	assert.Contains(t, parsed.Events, "Approval")
+	assert.Contains(t, parsed.Errors, "Unauthorized")
+
+	_, err = ParseHumanReadableABI([]string{"function ok()", "function broken("})
+	assert.Error(t, err)
+}
@@ -55,6 +55,7 @@ type Method struct {
 	Name            string     `json:"name"`                 // Name of the function.
 	Type            string     `json:"type"`                 // Type of the method (always "function" for functions).
 	StateMutability string     `json:"stateMutability"`      // State mutability of the function (e.g., pure, view, nonpayable, payable).
+	Anonymous       bool       `json:"anonymous,omitempty"`  // Whether the event is anonymous, emitted without its topic0. Only used by events.
 }
 
 func (m *Method) ToJSON() (json.RawMessage, error) {
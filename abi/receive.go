@@ -27,7 +27,7 @@ func (b *Builder) processReceive(unit *ir.Receive) (*Method, error) {
 		}
 		toReturn.Inputs = append(
 			toReturn.Inputs,
-			b.buildMethodIO(methodIo, parameter.GetTypeDescription()),
+			b.buildMethodIO(methodIo, parameterTypeDescription(parameter)),
 		)
 	}
 
@@ -0,0 +1,88 @@
+package abi
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/unpackdev/solgo/utils"
+)
+
+// SelectorEntry maps the 4-byte selector of a function or error, or the 32-byte topic0 of an event, to
+// its ABI entry, so traces and logs can be cross-referenced without hashing signatures.
+type SelectorEntry struct {
+	Selector     string  `json:"selector"`                // 0x prefixed selector, or topic0 for events.
+	Signature    string  `json:"signature"`               // Canonical signature the selector is computed from.
+	Type         string  `json:"type"`                    // Type of the entry: function, error or event.
+	Name         string  `json:"name"`                    // Name of the entry.
+	ContractName string  `json:"contract_name,omitempty"` // Contract the entry belongs to, set by Root.GetSelectors.
+	Method       *Method `json:"-"`
+}
+
+// SelectorBytes returns the 4-byte selector of a function or error, or nil for other methods.
+func (m *Method) SelectorBytes() []byte {
+	if m.Type != "function" && m.Type != "error" {
+		return nil
+	}
+	return utils.Keccak256([]byte(m.CanonicalSignature()))[:4]
+}
+
+// Topic returns the topic0 hash of an event, the first topic of its logs, or the zero hash for anonymous
+// events, which are logged without it, and other methods.
+func (m *Method) Topic() common.Hash {
+	if m.Type != "event" || m.Anonymous {
+		return common.Hash{}
+	}
+	return common.BytesToHash(utils.Keccak256([]byte(m.CanonicalSignature())))
+}
+
+// GetSelectors returns the selectors of the functions and errors and the topics of the events of the
+// contract, in the order of the ABI. Constructors, fallback and receive functions have none.
+func (c *Contract) GetSelectors() []*SelectorEntry {
+	toReturn := make([]*SelectorEntry, 0)
+	for _, method := range *c {
+		selector := method.Selector()
+		if selector == "" {
+			continue
+		}
+		toReturn = append(toReturn, &SelectorEntry{
+			Selector:  selector,
+			Signature: method.CanonicalSignature(),
+			Type:      method.Type,
+			Name:      method.Name,
+			Method:    method,
+		})
+	}
+	return toReturn
+}
+
+// GetMethodBySelector returns the function or error with the 4-byte selector, or the event with the
+// topic0 hash, given in hex with or without the 0x prefix. It returns nil if none matches.
+func (c *Contract) GetMethodBySelector(selector string) *Method {
+	selector = "0x" + strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(selector, "0x"), "0X"))
+	for _, method := range *c {
+		if method.Selector() == selector {
+			return method
+		}
+	}
+	return nil
+}
+
+// GetSelectors returns the selectors and topics of all the contracts, ordered by contract name and
+// then as in their ABIs.
+func (r *Root) GetSelectors() []*SelectorEntry {
+	names := make([]string, 0, len(r.GetContracts()))
+	for name := range r.GetContracts() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	toReturn := make([]*SelectorEntry, 0)
+	for _, name := range names {
+		for _, entry := range r.GetContractByName(name).GetSelectors() {
+			entry.ContractName = name
+			toReturn = append(toReturn, entry)
+		}
+	}
+	return toReturn
+}
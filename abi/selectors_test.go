@@ -0,0 +1,53 @@
+package abi
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectors(t *testing.T) {
+	contract, err := ParseHumanReadableABI([]string{
+		"constructor(string name)",
+		"function transfer(address to, uint256 amount) returns (bool)",
+		"event Transfer(address indexed from, address indexed to, uint256 value)",
+		"error Error(string reason)",
+		"receive() payable",
+	})
+	require.NoError(t, err)
+
+	type entry struct {
+		Selector  string
+		Signature string
+		Type      string
+	}
+	entries := make([]entry, 0)
+	for _, e := range contract.GetSelectors() {
+		entries = append(entries, entry{e.Selector, e.Signature, e.Type})
+	}
+	assert.Equal(t, []entry{
+		{"0xa9059cbb", "transfer(address,uint256)", "function"},
+		{"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", "Transfer(address,address,uint256)", "event"},
+		{"0x08c379a0", "Error(string)", "error"},
+	}, entries)
+
+	transfer := contract.GetMethodBySelector("A9059CBB")
+	require.NotNil(t, transfer)
+	assert.Equal(t, "transfer", transfer.Name)
+	assert.Equal(t, common.FromHex("0xa9059cbb"), transfer.SelectorBytes())
+	assert.Equal(t, common.Hash{}, transfer.Topic())
+
+	event := contract.GetMethodBySelector("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	require.NotNil(t, event)
+	assert.Equal(t, common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"), event.Topic())
+	assert.Nil(t, event.SelectorBytes())
+
+	assert.Nil(t, contract.GetMethodBySelector("0xdeadbeef"))
+
+	root := &Root{Contracts: map[string]*Contract{"Token": contract, "Empty": {}}}
+	selectors := root.GetSelectors()
+	require.Len(t, selectors, 3)
+	assert.Equal(t, "Token", selectors[0].ContractName)
+}
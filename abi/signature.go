@@ -0,0 +1,360 @@
+package abi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/unpackdev/solgo/utils"
+)
+
+// SignatureFormat defines the dialect a method signature is rendered in.
+type SignatureFormat int
+
+const (
+	// SignatureCanonical renders the canonical ABI signature, e.g. `transfer(address,uint256)`.
+	SignatureCanonical SignatureFormat = iota
+
+	// SignatureSolidity renders a Solidity declaration, e.g.
+	// `function transfer(address to, uint256 amount) external returns (bool)`.
+	SignatureSolidity
+
+	// SignatureHumanReadable renders an ethers.js human-readable ABI fragment, e.g.
+	// `function transfer(address to, uint256 amount) returns (bool)`.
+	SignatureHumanReadable
+
+	// SignatureSelector renders the 4-byte selector for functions and errors, or the
+	// 32-byte topic hash for events, e.g. `0xa9059cbb`.
+	SignatureSelector
+)
+
+// String returns the string representation of the SignatureFormat.
+func (f SignatureFormat) String() string {
+	switch f {
+	case SignatureCanonical:
+		return "canonical"
+	case SignatureSolidity:
+		return "solidity"
+	case SignatureHumanReadable:
+		return "human_readable"
+	case SignatureSelector:
+		return "selector"
+	default:
+		return "unknown"
+	}
+}
+
+// Canonicalization controls how enums, contracts and user-defined value types are rendered in
+// canonical signatures. Solc erases them to their ABI types before hashing, while documentation
+// usually keeps their Solidity names.
+type Canonicalization struct {
+	EnumsAsUint8                bool `json:"enums_as_uint8"`                  // Render enums as uint8 instead of their name, e.g. Vault.Status.
+	ContractsAsAddress          bool `json:"contracts_as_address"`            // Render contract and interface types as address instead of their name.
+	UnwrapUserDefinedValueTypes bool `json:"unwrap_user_defined_value_types"` // Render user-defined value types as their underlying type.
+}
+
+var (
+	// SolcCanonicalization matches solc, so selectors and topics computed with it are the ones contracts
+	// dispatch on and emit.
+	SolcCanonicalization = Canonicalization{
+		EnumsAsUint8:                true,
+		ContractsAsAddress:          true,
+		UnwrapUserDefinedValueTypes: true,
+	}
+
+	// DocumentationCanonicalization keeps the Solidity names of enums, contracts and user-defined value
+	// types, e.g. `open(Vault.Status,IToken)`. Selectors computed with it do not match deployed code.
+	DocumentationCanonicalization = Canonicalization{}
+)
+
+// elementaryTypeName matches the elementary Solidity types user-defined value types can wrap.
+var elementaryTypeName = regexp.MustCompile(`^(u?int\d*|bytes\d*|u?fixed[\dx]*|address|bool|string|function)$`)
+
+// Signature renders the method in the requested format. It is the single entry point
+// reports, documentation generators and decoders should use so that signatures are
+// rendered consistently across the toolkit.
+func (m *Method) Signature(format SignatureFormat) string {
+	switch format {
+	case SignatureCanonical:
+		return m.CanonicalSignature()
+	case SignatureSolidity:
+		return m.SolidityDeclaration()
+	case SignatureHumanReadable:
+		return m.HumanReadableSignature()
+	case SignatureSelector:
+		return m.Selector()
+	default:
+		return ""
+	}
+}
+
+// CanonicalSignature returns the canonical ABI signature of the method as used for
+// selector and topic computation. Tuples are expanded into their component types.
+// Constructors, fallback and receive functions have no name and are rendered by type.
+func (m *Method) CanonicalSignature() string {
+	return m.CanonicalSignatureWith(SolcCanonicalization)
+}
+
+// CanonicalSignatureWith returns the canonical signature of the method with enums, contracts and
+// user-defined value types rendered as the canonicalization requests.
+func (m *Method) CanonicalSignatureWith(c Canonicalization) string {
+	types := make([]string, 0, len(m.Inputs))
+	for _, input := range m.Inputs {
+		types = append(types, c.canonicalType(input))
+	}
+
+	return fmt.Sprintf("%s(%s)", m.signatureName(), strings.Join(types, ","))
+}
+
+// Selector returns the 0x-prefixed 4-byte selector for functions and errors, or the
+// 32-byte topic hash for events. Methods without a selector, such as constructors,
+// fallback and receive functions, and anonymous events, whose logs carry no topic0,
+// return an empty string.
+func (m *Method) Selector() string {
+	return m.SelectorWith(SolcCanonicalization)
+}
+
+// SelectorWith returns the selector or topic hash of the signature rendered with the
+// canonicalization. Only SolcCanonicalization yields the selectors of deployed contracts.
+func (m *Method) SelectorWith(c Canonicalization) string {
+	switch m.Type {
+	case "function", "error":
+		return "0x" + hex.EncodeToString(utils.Keccak256([]byte(m.CanonicalSignatureWith(c)))[:4])
+	case "event":
+		if m.Anonymous {
+			return ""
+		}
+		return "0x" + hex.EncodeToString(utils.Keccak256([]byte(m.CanonicalSignatureWith(c))))
+	default:
+		return ""
+	}
+}
+
+// HumanReadableSignature returns the method as an ethers.js human-readable ABI fragment.
+// Non-payable state mutability is omitted, matching the output of ethers.
+func (m *Method) HumanReadableSignature() string {
+	var sb strings.Builder
+
+	switch m.Type {
+	case "constructor":
+		sb.WriteString("constructor")
+	case "fallback", "receive":
+		sb.WriteString(m.Type + "()")
+	default:
+		sb.WriteString(m.Type + " " + m.Name)
+	}
+
+	if m.Type != "fallback" && m.Type != "receive" {
+		params := make([]string, 0, len(m.Inputs))
+		for _, input := range m.Inputs {
+			params = append(params, humanReadableParameter(input, m.Type == "event"))
+		}
+		sb.WriteString("(" + strings.Join(params, ", ") + ")")
+	}
+
+	if m.Anonymous {
+		sb.WriteString(" anonymous")
+	}
+
+	if mutability := m.mutabilityKeyword(); mutability != "" {
+		sb.WriteString(" " + mutability)
+	}
+
+	if len(m.Outputs) > 0 {
+		params := make([]string, 0, len(m.Outputs))
+		for _, output := range m.Outputs {
+			params = append(params, humanReadableParameter(output, false))
+		}
+		sb.WriteString(" returns (" + strings.Join(params, ", ") + ")")
+	}
+
+	return sb.String()
+}
+
+// SolidityDeclaration returns the method as a Solidity declaration, as it would appear
+// in an interface. Internal types are preferred over ABI types so structs, enums and
+// contracts keep their names, and reference types are given a data location.
+func (m *Method) SolidityDeclaration() string {
+	var sb strings.Builder
+
+	switch m.Type {
+	case "constructor":
+		sb.WriteString("constructor")
+	case "fallback", "receive":
+		sb.WriteString(m.Type + "()")
+	default:
+		sb.WriteString(m.Type + " " + m.Name)
+	}
+
+	if m.Type != "fallback" && m.Type != "receive" {
+		location := "calldata"
+		if m.Type == "constructor" {
+			location = "memory"
+		}
+
+		params := make([]string, 0, len(m.Inputs))
+		for _, input := range m.Inputs {
+			switch m.Type {
+			case "event", "error":
+				params = append(params, solidityParameter(input, "", m.Type == "event"))
+			default:
+				params = append(params, solidityParameter(input, location, false))
+			}
+		}
+		sb.WriteString("(" + strings.Join(params, ", ") + ")")
+	}
+
+	if m.Anonymous {
+		sb.WriteString(" anonymous")
+	}
+
+	if m.Type == "function" || m.Type == "fallback" || m.Type == "receive" {
+		sb.WriteString(" external")
+	}
+
+	if mutability := m.mutabilityKeyword(); mutability != "" {
+		sb.WriteString(" " + mutability)
+	}
+
+	if len(m.Outputs) > 0 {
+		params := make([]string, 0, len(m.Outputs))
+		for _, output := range m.Outputs {
+			params = append(params, solidityParameter(output, "memory", false))
+		}
+		sb.WriteString(" returns (" + strings.Join(params, ", ") + ")")
+	}
+
+	return sb.String()
+}
+
+// mutabilityKeyword returns the state mutability written in signatures, which is left out for
+// non-payable methods and for events and errors, which the builder marks as view.
+func (m *Method) mutabilityKeyword() string {
+	if m.Type == "event" || m.Type == "error" || m.StateMutability == "nonpayable" {
+		return ""
+	}
+	return m.StateMutability
+}
+
+// signatureName returns the name used in the canonical signature of the method.
+func (m *Method) signatureName() string {
+	if m.Name == "" {
+		return m.Type
+	}
+	return m.Name
+}
+
+// canonicalType returns the canonical ABI type of the parameter, expanding tuples.
+func canonicalType(io MethodIO) string {
+	return SolcCanonicalization.canonicalType(io)
+}
+
+// canonicalType returns the type of the parameter as rendered by the canonicalization, expanding
+// tuples. Names are taken from the internal type, which keeps the array dimensions of the type.
+func (c Canonicalization) canonicalType(io MethodIO) string {
+	if strings.HasPrefix(io.Type, "tuple") {
+		components := make([]string, 0, len(io.Components))
+		for _, component := range io.Components {
+			components = append(components, c.canonicalType(component))
+		}
+
+		return "(" + strings.Join(components, ",") + ")" + strings.TrimPrefix(io.Type, "tuple")
+	}
+
+	switch {
+	case strings.HasPrefix(io.InternalType, "enum "):
+		if !c.EnumsAsUint8 {
+			return strings.TrimPrefix(io.InternalType, "enum ")
+		}
+	case strings.HasPrefix(io.InternalType, "contract "):
+		if !c.ContractsAsAddress {
+			return strings.TrimPrefix(io.InternalType, "contract ")
+		}
+	case isUserDefinedValueType(io):
+		if !c.UnwrapUserDefinedValueTypes {
+			return io.InternalType
+		}
+	}
+
+	return io.Type
+}
+
+// isUserDefinedValueType reports whether the internal type of the parameter names a user-defined
+// value type, such as Vault.Price, rather than an elementary type.
+func isUserDefinedValueType(io MethodIO) bool {
+	if io.InternalType == "" || io.InternalType == io.Type || strings.Contains(io.InternalType, " ") {
+		return false
+	}
+	name := io.InternalType
+	if idx := strings.Index(name, "["); idx != -1 {
+		name = name[:idx]
+	}
+	return !elementaryTypeName.MatchString(name)
+}
+
+// humanReadableType returns the ethers.js human-readable type of the parameter.
+func humanReadableType(io MethodIO) string {
+	if !strings.HasPrefix(io.Type, "tuple") {
+		return io.Type
+	}
+
+	components := make([]string, 0, len(io.Components))
+	for _, component := range io.Components {
+		components = append(components, humanReadableParameter(component, false))
+	}
+
+	return "tuple(" + strings.Join(components, ", ") + ")" + strings.TrimPrefix(io.Type, "tuple")
+}
+
+// humanReadableParameter returns the ethers.js human-readable form of a parameter.
+func humanReadableParameter(io MethodIO, event bool) string {
+	parts := []string{humanReadableType(io)}
+	if event && io.Indexed {
+		parts = append(parts, "indexed")
+	}
+	if io.Name != "" {
+		parts = append(parts, io.Name)
+	}
+	return strings.Join(parts, " ")
+}
+
+// solidityType returns the Solidity type of the parameter, preferring the internal type.
+func solidityType(io MethodIO) string {
+	if io.InternalType == "" {
+		if strings.HasPrefix(io.Type, "tuple") {
+			return canonicalType(io)
+		}
+		return io.Type
+	}
+
+	for _, prefix := range []string{"struct ", "enum ", "contract "} {
+		if strings.HasPrefix(io.InternalType, prefix) {
+			return strings.TrimPrefix(io.InternalType, prefix)
+		}
+	}
+
+	return io.InternalType
+}
+
+// solidityParameter returns the Solidity form of a parameter. The data location is
+// only applied to reference types.
+func solidityParameter(io MethodIO, location string, event bool) string {
+	parts := []string{solidityType(io)}
+	if location != "" && isReferenceType(io) {
+		parts = append(parts, location)
+	}
+	if event && io.Indexed {
+		parts = append(parts, "indexed")
+	}
+	if io.Name != "" {
+		parts = append(parts, io.Name)
+	}
+	return strings.Join(parts, " ")
+}
+
+// isReferenceType reports whether the parameter requires a data location in Solidity.
+func isReferenceType(io MethodIO) bool {
+	return io.Type == "string" || io.Type == "bytes" ||
+		strings.HasPrefix(io.Type, "tuple") || strings.HasSuffix(io.Type, "]")
+}
@@ -0,0 +1,346 @@
+package abi
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-json"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+	"github.com/unpackdev/solgo/ast"
+)
+
+// SignatureSiteKind is the kind of a site of the sources using a function signature.
+type SignatureSiteKind string
+
+const (
+	SignatureDeclaration SignatureSiteKind = "declaration" // Declaration of the function, in contracts, interfaces and libraries.
+	SignatureReference   SignatureSiteKind = "reference"   // Reference to the function, such as a call or a selector.
+	SignatureLiteral     SignatureSiteKind = "literal"     // String literal holding the signature, such as in abi.encodeWithSignature.
+	SignatureGetter      SignatureSiteKind = "getter"      // Public state variable whose getter has the signature.
+)
+
+// SignatureSite is a site of the sources using a renamed signature.
+type SignatureSite struct {
+	Kind        SignatureSiteKind `json:"kind"`
+	NodeId      int64             `json:"node_id"`
+	Src         ast.SrcNode       `json:"src"`
+	Text        string            `json:"text"`                  // Text of the site as written.
+	Replacement string            `json:"replacement,omitempty"` // Text written in place of the site, if rewritten.
+	Reason      string            `json:"reason,omitempty"`      // Why the site could not be rewritten safely.
+}
+
+// SignatureRename lists the sites rewritten by RenameSignature and the ones left for review.
+type SignatureRename struct {
+	OldSignature string           `json:"old_signature"`
+	NewSignature string           `json:"new_signature"`
+	Rewritten    []*SignatureSite `json:"rewritten"`
+	Unsafe       []*SignatureSite `json:"unsafe"`
+}
+
+// IsSafe reports whether every site using the signature was rewritten, leaving nothing to review.
+func (r *SignatureRename) IsSafe() bool {
+	return len(r.Unsafe) == 0
+}
+
+// GetRewritten returns the sites rewritten, in source order.
+func (r *SignatureRename) GetRewritten() []*SignatureSite {
+	return r.Rewritten
+}
+
+// GetUnsafe returns the sites which could not be rewritten safely, in source order.
+func (r *SignatureRename) GetUnsafe() []*SignatureSite {
+	return r.Unsafe
+}
+
+// ToJSON returns the JSON representation of the rename.
+func (r *SignatureRename) ToJSON() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// RenameSignature rewrites every use of a function signature across the sources into another one, such
+// as "transfer(address,uint256)" into "send(address,uint256)". Signatures are matched canonically, so
+// uint, structs, enums and contracts match their ABI types.
+//
+// Declarations, in contracts, interfaces and libraries alike, are renamed and get the new parameter types
+// when their count stays the same. Calls and other references resolved to them are renamed, and
+// abi.encodeWithSignature literals holding the signature are rewritten. Sites left as they were, or whose
+// rewrite needs a closer look, are reported as unsafe: references which could not be resolved, literals
+// used elsewhere, public state variable getters, parameter types which need a struct or another data
+// location, and the arguments passed at every call once the parameter types change.
+//
+// Rewrites are replacements of the AST builder, so ToSourceFile and ToPatch of the AST builder return the
+// rewritten sources. Source fidelity is enabled if it was not.
+func (b *Builder) RenameSignature(oldSignature string, newSignature string) (*SignatureRename, error) {
+	oldMethod, err := parseRenamedSignature(oldSignature)
+	if err != nil {
+		return nil, err
+	}
+	newMethod, err := parseRenamedSignature(newSignature)
+	if err != nil {
+		return nil, err
+	}
+
+	astBuilder := b.parser.GetAstBuilder()
+	if astBuilder == nil || astBuilder.GetTree() == nil || astBuilder.GetRoot() == nil {
+		return nil, errors.New("sources are not parsed, call Parse() first")
+	}
+	if !astBuilder.HasSourceFidelity() {
+		astBuilder.SetSourceFidelity(true)
+	}
+
+	toReturn := &SignatureRename{
+		OldSignature: oldMethod.CanonicalSignature(),
+		NewSignature: newMethod.CanonicalSignature(),
+		Rewritten:    make([]*SignatureSite, 0),
+		Unsafe:       make([]*SignatureSite, 0),
+	}
+	if toReturn.OldSignature == toReturn.NewSignature {
+		return toReturn, nil
+	}
+	retyped := diffCanonicalTypes(oldMethod.Inputs) != diffCanonicalTypes(newMethod.Inputs)
+
+	rewrite := func(site *SignatureSite, text string) {
+		if err := astBuilder.ReplaceSourceRange(site.Src, text); err != nil {
+			site.Reason = err.Error()
+			toReturn.Unsafe = append(toReturn.Unsafe, site)
+			return
+		}
+		site.Replacement = text
+		toReturn.Rewritten = append(toReturn.Rewritten, site)
+	}
+	unsafe := func(site *SignatureSite, reason string) {
+		site.Reason = reason
+		toReturn.Unsafe = append(toReturn.Unsafe, site)
+	}
+	text := func(node ast.Node[ast.NodeType]) string {
+		if fidelity := astBuilder.GetSourceFidelity(node); fidelity != nil {
+			return fidelity.Text
+		}
+		return ""
+	}
+
+	tree := astBuilder.GetTree()
+	structs := b.resolver.solcStructs()
+
+	// Declarations come first, as references are matched by the declarations they resolve to.
+	declarations := make(map[int64]bool)
+	_, _ = tree.ExecuteTypeVisit(ast_pb.NodeType_FUNCTION_DEFINITION, func(node ast.Node[ast.NodeType]) (bool, error) {
+		function, ok := node.(*ast.Function)
+		if !ok || function.GetName() != oldMethod.Name {
+			return true, nil
+		}
+		parameters := b.declaredInputs(function.GetParameters())
+		if (&Method{Name: function.GetName(), Inputs: parameters}).CanonicalSignature() != toReturn.OldSignature {
+			return true, nil
+		}
+		declarations[function.GetId()] = true
+
+		if oldMethod.Name != newMethod.Name {
+			rewrite(&SignatureSite{
+				Kind:   SignatureDeclaration,
+				NodeId: function.GetId(),
+				Src:    function.GetNameLocation(),
+				Text:   function.GetName(),
+			}, newMethod.Name)
+		}
+		if !retyped {
+			return true, nil
+		}
+
+		if len(parameters) != len(newMethod.Inputs) {
+			unsafe(&SignatureSite{
+				Kind:   SignatureDeclaration,
+				NodeId: function.GetId(),
+				Src:    function.GetSrc(),
+				Text:   text(function),
+			}, fmt.Sprintf("parameter count changes from %d to %d", len(parameters), len(newMethod.Inputs)))
+			return true, nil
+		}
+		for i, parameter := range function.GetParameters().GetParameters() {
+			input := newMethod.Inputs[i]
+			if canonicalType(parameters[i]) == canonicalType(input) || parameter.GetTypeName() == nil {
+				continue
+			}
+			site := &SignatureSite{
+				Kind:   SignatureDeclaration,
+				NodeId: parameter.GetId(),
+				Src:    parameter.GetTypeName().GetSrc(),
+				Text:   text(parameter.GetTypeName()),
+			}
+			oldIO := MethodIO{Type: parameters[i].Type}
+			switch {
+			case strings.HasPrefix(input.Type, "tuple"):
+				unsafe(site, fmt.Sprintf("parameter %d becomes %s, which needs a struct type", i, canonicalType(input)))
+			case isReferenceType(oldIO) != isReferenceType(input):
+				unsafe(site, fmt.Sprintf("parameter %d becomes %s, which needs another data location", i, input.Type))
+			default:
+				rewrite(site, input.Type)
+			}
+		}
+		return true, nil
+	})
+
+	// References are renamed by the identifier or member they are written with.
+	reference := func(node ast.Node[ast.NodeType], name string, referenced int64, src ast.SrcNode) {
+		if name != oldMethod.Name || (referenced != 0 && !declarations[referenced]) {
+			return
+		}
+		site := &SignatureSite{
+			Kind:   SignatureReference,
+			NodeId: node.GetId(),
+			Src:    src,
+			Text:   name,
+		}
+		if referenced == 0 {
+			site.Src, site.Text = node.GetSrc(), text(node)
+			unsafe(site, "reference could not be resolved to a declaration")
+			return
+		}
+		if oldMethod.Name != newMethod.Name {
+			rewrite(site, newMethod.Name)
+		}
+		if retyped {
+			b.renameArgumentsUnsafe(node, text, unsafe)
+		}
+	}
+	_, _ = tree.ExecuteTypeVisit(ast_pb.NodeType_IDENTIFIER, func(node ast.Node[ast.NodeType]) (bool, error) {
+		if identifier, ok := node.(*ast.PrimaryExpression); ok {
+			reference(identifier, identifier.GetName(), identifier.GetReferencedDeclaration(), identifier.GetSrc())
+		}
+		return true, nil
+	})
+	_, _ = tree.ExecuteTypeVisit(ast_pb.NodeType_MEMBER_ACCESS, func(node ast.Node[ast.NodeType]) (bool, error) {
+		if member, ok := node.(*ast.MemberAccessExpression); ok {
+			reference(member, member.GetMemberName(), member.GetReferencedDeclaration(), member.GetMemberLocation())
+		}
+		return true, nil
+	})
+
+	_, _ = tree.ExecuteTypeVisit(ast_pb.NodeType_LITERAL, func(node ast.Node[ast.NodeType]) (bool, error) {
+		literal, ok := node.(*ast.PrimaryExpression)
+		if !ok || literal.GetKind() != ast_pb.NodeType_STRING {
+			return true, nil
+		}
+		written := text(literal)
+		if len(written) < 2 {
+			return true, nil
+		}
+		method, err := ParseHumanReadable(written[1 : len(written)-1])
+		if err != nil || method.Type != "function" || method.CanonicalSignature() != toReturn.OldSignature {
+			return true, nil
+		}
+
+		site := &SignatureSite{
+			Kind:   SignatureLiteral,
+			NodeId: literal.GetId(),
+			Src:    literal.GetSrc(),
+			Text:   written,
+		}
+		call, ok := astBuilder.GetParent(literal).(*ast.FunctionCall)
+		if !ok || !isEncodeWithSignature(call) || len(call.GetArguments()) == 0 || call.GetArguments()[0].GetId() != literal.GetId() {
+			unsafe(site, "signature literal is not the signature of an abi.encodeWithSignature call")
+			return true, nil
+		}
+		rewrite(site, written[:1]+toReturn.NewSignature+written[len(written)-1:])
+		if retyped {
+			unsafe(&SignatureSite{
+				Kind:   SignatureLiteral,
+				NodeId: call.GetId(),
+				Src:    call.GetSrc(),
+				Text:   text(call),
+			}, "arguments are not rewritten to the new parameter types")
+		}
+		return true, nil
+	})
+
+	_, _ = tree.ExecuteTypeVisit(ast_pb.NodeType_VARIABLE_DECLARATION, func(node ast.Node[ast.NodeType]) (bool, error) {
+		declaration, ok := node.(*ast.StateVariableDeclaration)
+		if !ok || declaration.GetName() != oldMethod.Name || declaration.GetVisibility() != ast_pb.Visibility_PUBLIC {
+			return true, nil
+		}
+		getter := &Method{Name: declaration.GetName()}
+		inputs, _ := b.solcGetter(declaration, structs)
+		for _, input := range inputs {
+			getter.Inputs = append(getter.Inputs, methodIOFromSolc(input))
+		}
+		if getter.CanonicalSignature() == toReturn.OldSignature {
+			unsafe(&SignatureSite{
+				Kind:   SignatureGetter,
+				NodeId: declaration.GetId(),
+				Src:    declaration.GetSrc(),
+				Text:   text(declaration),
+			}, "getter of a public state variable, rename the variable instead")
+		}
+		return true, nil
+	})
+
+	for _, sites := range [][]*SignatureSite{toReturn.Rewritten, toReturn.Unsafe} {
+		sort.SliceStable(sites, func(i, j int) bool {
+			return sites[i].Src.Start < sites[j].Src.Start
+		})
+	}
+
+	return toReturn, nil
+}
+
+// renameArgumentsUnsafe reports the call made through the reference, if any, as its arguments are not
+// rewritten when the parameter types change.
+func (b *Builder) renameArgumentsUnsafe(node ast.Node[ast.NodeType], text func(ast.Node[ast.NodeType]) string, unsafe func(*SignatureSite, string)) {
+	call, ok := b.parser.GetAstBuilder().GetParent(node).(*ast.FunctionCall)
+	if !ok || call.GetExpression() == nil || call.GetExpression().GetId() != node.GetId() {
+		unsafe(&SignatureSite{
+			Kind:   SignatureReference,
+			NodeId: node.GetId(),
+			Src:    node.GetSrc(),
+			Text:   text(node),
+		}, "uses of the function are not rewritten to the new parameter types")
+		return
+	}
+	unsafe(&SignatureSite{
+		Kind:   SignatureReference,
+		NodeId: call.GetId(),
+		Src:    call.GetSrc(),
+		Text:   text(call),
+	}, "arguments are not rewritten to the new parameter types")
+}
+
+// parseRenamedSignature parses a function signature, such as "transfer(address,uint256)".
+func parseRenamedSignature(signature string) (*Method, error) {
+	method, err := ParseHumanReadable(signature)
+	if err != nil {
+		return nil, err
+	}
+	if method.Type != "function" {
+		return nil, fmt.Errorf("signature %q is not a function signature", signature)
+	}
+	return method, nil
+}
+
+// isEncodeWithSignature reports whether the call is abi.encodeWithSignature.
+func isEncodeWithSignature(call *ast.FunctionCall) bool {
+	member, ok := call.GetExpression().(*ast.MemberAccessExpression)
+	if !ok || member.GetMemberName() != "encodeWithSignature" {
+		return false
+	}
+	base, ok := member.GetExpression().(*ast.PrimaryExpression)
+	return ok && base.GetName() == "abi"
+}
+
+// declaredInputs returns the inputs of a function declaration, resolved the way the functions of the
+// ABI are.
+func (b *Builder) declaredInputs(list *ast.ParameterList) []MethodIO {
+	toReturn := make([]MethodIO, 0)
+	if list == nil {
+		return toReturn
+	}
+	for _, parameter := range list.GetParameters() {
+		methodIo := MethodIO{Name: parameter.GetName()}
+		if parameter.GetTypeDescription() != nil {
+			methodIo = b.buildMethodIO(methodIo, solcTypeDescription(parameter.GetTypeDescription(), parameter.GetTypeName()))
+		}
+		toReturn = append(toReturn, methodIo)
+	}
+	return toReturn
+}
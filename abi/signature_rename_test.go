@@ -0,0 +1,106 @@
+package abi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo"
+)
+
+func TestRenameSignature(t *testing.T) {
+	content := `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.19;
+
+interface IToken {
+    function transfer(address to, uint amount) external returns (bool);
+}
+
+contract Token is IToken {
+    mapping(address => uint256) public balances;
+
+    function transfer(address to, uint256 amount) external override returns (bool) {
+        balances[to] += amount;
+        return true;
+    }
+}
+
+contract Payer {
+    function pay(IToken token, address to) external returns (bytes4) {
+        token.transfer(to, 1);
+        (bool ok, ) = address(token).call(abi.encodeWithSignature("transfer(address,uint256)", to, 2));
+        require(ok);
+        return bytes4(keccak256('transfer(address,uint256)'));
+    }
+}
+`
+	builder, err := NewBuilderFromSources(context.TODO(), &solgo.Sources{
+		SourceUnits:         []*solgo.SourceUnit{{Name: "Token", Path: "Token.sol", Content: content}},
+		EntrySourceUnitName: "Token",
+	})
+	require.NoError(t, err)
+	require.Empty(t, builder.Parse())
+
+	_, err = builder.RenameSignature("event Transfer(address)", "send(address)")
+	assert.Error(t, err)
+
+	rename, err := builder.RenameSignature("transfer(address,uint)", "send(address,uint256)")
+	require.NoError(t, err)
+	assert.Equal(t, "transfer(address,uint256)", rename.OldSignature)
+
+	type site struct {
+		Kind        SignatureSiteKind
+		Text        string
+		Replacement string
+	}
+	sites := func(list []*SignatureSite) []site {
+		toReturn := make([]site, 0)
+		for _, s := range list {
+			toReturn = append(toReturn, site{s.Kind, s.Text, s.Replacement})
+		}
+		return toReturn
+	}
+	assert.Equal(t, []site{
+		{SignatureDeclaration, "transfer", "send"},
+		{SignatureDeclaration, "transfer", "send"},
+		{SignatureReference, "transfer", "send"},
+		{SignatureLiteral, `"transfer(address,uint256)"`, `"send(address,uint256)"`},
+	}, sites(rename.GetRewritten()))
+
+	// Literals hashed by hand are left for review.
+	assert.False(t, rename.IsSafe())
+	assert.Equal(t, []site{
+		{SignatureLiteral, "'transfer(address,uint256)'", ""},
+	}, sites(rename.GetUnsafe()))
+
+	source, err := builder.GetAstBuilder().ToSourceFile("Token")
+	require.NoError(t, err)
+	assert.Contains(t, source, "function send(address to, uint amount) external returns (bool);")
+	assert.Contains(t, source, "function send(address to, uint256 amount) external override returns (bool) {")
+	assert.Contains(t, source, "token.send(to, 1);")
+	assert.Contains(t, source, `abi.encodeWithSignature("send(address,uint256)", to, 2)`)
+
+	// Changed parameter types are written to declarations, while arguments are left for review.
+	builder.GetAstBuilder().SetSourceFidelity(false)
+	rename, err = builder.RenameSignature("transfer(address,uint256)", "transfer(address,uint128)")
+	require.NoError(t, err)
+	assert.Equal(t, []site{
+		{SignatureDeclaration, "uint", "uint128"},
+		{SignatureDeclaration, "uint256", "uint128"},
+		{SignatureLiteral, `"transfer(address,uint256)"`, `"transfer(address,uint128)"`},
+	}, sites(rename.GetRewritten()))
+	assert.Equal(t, []site{
+		{SignatureReference, "token.transfer(to, 1)", ""},
+		{SignatureLiteral, `abi.encodeWithSignature("transfer(address,uint256)", to, 2)`, ""},
+		{SignatureLiteral, "'transfer(address,uint256)'", ""},
+	}, sites(rename.GetUnsafe()))
+
+	// Getters of public state variables are not renamed.
+	builder.GetAstBuilder().SetSourceFidelity(false)
+	rename, err = builder.RenameSignature("balances(address)", "balanceOf(address)")
+	require.NoError(t, err)
+	assert.Empty(t, rename.GetRewritten())
+	require.Len(t, rename.GetUnsafe(), 1)
+	assert.Equal(t, SignatureGetter, rename.GetUnsafe()[0].Kind)
+}
@@ -0,0 +1,183 @@
+package abi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMethodSignature(t *testing.T) {
+	testCases := []struct {
+		name          string
+		method        *Method
+		canonical     string
+		solidity      string
+		humanReadable string
+		selector      string
+	}{
+		{
+			name: "ERC20 Transfer Function",
+			method: &Method{
+				Type: "function",
+				Name: "transfer",
+				Inputs: []MethodIO{
+					{Name: "to", Type: "address", InternalType: "address"},
+					{Name: "amount", Type: "uint256", InternalType: "uint256"},
+				},
+				Outputs:         []MethodIO{{Name: "", Type: "bool", InternalType: "bool"}},
+				StateMutability: "nonpayable",
+			},
+			canonical:     "transfer(address,uint256)",
+			solidity:      "function transfer(address to, uint256 amount) external returns (bool)",
+			humanReadable: "function transfer(address to, uint256 amount) returns (bool)",
+			selector:      "0xa9059cbb",
+		},
+		{
+			name: "ERC20 Transfer Event",
+			method: &Method{
+				Type: "event",
+				Name: "Transfer",
+				Inputs: []MethodIO{
+					{Name: "from", Type: "address", Indexed: true},
+					{Name: "to", Type: "address", Indexed: true},
+					{Name: "value", Type: "uint256"},
+				},
+			},
+			canonical:     "Transfer(address,address,uint256)",
+			solidity:      "event Transfer(address indexed from, address indexed to, uint256 value)",
+			humanReadable: "event Transfer(address indexed from, address indexed to, uint256 value)",
+			selector:      "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+		},
+		{
+			name: "Custom Error",
+			method: &Method{
+				Type: "error",
+				Name: "InsufficientBalance",
+				Inputs: []MethodIO{
+					{Name: "available", Type: "uint256"},
+					{Name: "required", Type: "uint256"},
+				},
+			},
+			canonical:     "InsufficientBalance(uint256,uint256)",
+			solidity:      "error InsufficientBalance(uint256 available, uint256 required)",
+			humanReadable: "error InsufficientBalance(uint256 available, uint256 required)",
+			selector:      "0xcf479181",
+		},
+		{
+			name: "Tuple Array Input",
+			method: &Method{
+				Type: "function",
+				Name: "submit",
+				Inputs: []MethodIO{
+					{
+						Name:         "orders",
+						Type:         "tuple[]",
+						InternalType: "struct Exchange.Order[]",
+						Components: []MethodIO{
+							{Name: "maker", Type: "address", InternalType: "address"},
+							{Name: "amount", Type: "uint256", InternalType: "uint256"},
+						},
+					},
+					{Name: "memo", Type: "string", InternalType: "string"},
+				},
+				StateMutability: "payable",
+			},
+			canonical:     "submit((address,uint256)[],string)",
+			solidity:      "function submit(Exchange.Order[] calldata orders, string calldata memo) external payable",
+			humanReadable: "function submit(tuple(address maker, uint256 amount)[] orders, string memo) payable",
+			selector:      "0x388cf5f7",
+		},
+		{
+			name: "Constructor",
+			method: &Method{
+				Type:            "constructor",
+				Inputs:          []MethodIO{{Name: "name_", Type: "string", InternalType: "string"}},
+				StateMutability: "nonpayable",
+			},
+			canonical:     "constructor(string)",
+			solidity:      "constructor(string memory name_)",
+			humanReadable: "constructor(string name_)",
+			selector:      "",
+		},
+		{
+			name: "Receive",
+			method: &Method{
+				Type:            "receive",
+				StateMutability: "payable",
+			},
+			canonical:     "receive()",
+			solidity:      "receive() external payable",
+			humanReadable: "receive() payable",
+			selector:      "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.canonical, tc.method.Signature(SignatureCanonical))
+			assert.Equal(t, tc.solidity, tc.method.Signature(SignatureSolidity))
+			assert.Equal(t, tc.humanReadable, tc.method.Signature(SignatureHumanReadable))
+			assert.Equal(t, tc.selector, tc.method.Signature(SignatureSelector))
+		})
+	}
+}
+
+func TestMethodCanonicalization(t *testing.T) {
+	method := &Method{
+		Type: "function",
+		Name: "open",
+		Inputs: []MethodIO{
+			{Name: "status", Type: "uint8", InternalType: "enum Vault.Status"},
+			{Name: "token", Type: "address", InternalType: "contract IToken"},
+			{Name: "prices", Type: "uint128[]", InternalType: "Vault.Price[]"},
+			{
+				Name:         "order",
+				Type:         "tuple",
+				InternalType: "struct Vault.Order",
+				Components: []MethodIO{
+					{Name: "side", Type: "uint8", InternalType: "enum Vault.Status"},
+					{Name: "asset", Type: "address", InternalType: "contract IToken"},
+				},
+			},
+			{Name: "treasury", Type: "address", InternalType: "address payable"},
+		},
+		StateMutability: "nonpayable",
+	}
+
+	testCases := []struct {
+		name             string
+		canonicalization Canonicalization
+		signature        string
+		selector         string
+	}{
+		{
+			name:             "Solc",
+			canonicalization: SolcCanonicalization,
+			signature:        "open(uint8,address,uint128[],(uint8,address),address)",
+			selector:         "0x4b37bdaa",
+		},
+		{
+			name:             "Documentation",
+			canonicalization: DocumentationCanonicalization,
+			signature:        "open(Vault.Status,IToken,Vault.Price[],(Vault.Status,IToken),address)",
+			selector:         "0x1a9d1a15",
+		},
+		{
+			name:             "Unwrapped Value Types Only",
+			canonicalization: Canonicalization{UnwrapUserDefinedValueTypes: true},
+			signature:        "open(Vault.Status,IToken,uint128[],(Vault.Status,IToken),address)",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.signature, method.CanonicalSignatureWith(tc.canonicalization))
+			if tc.selector != "" {
+				assert.Equal(t, tc.selector, method.SelectorWith(tc.canonicalization))
+			}
+		})
+	}
+
+	assert.Equal(t, method.CanonicalSignatureWith(SolcCanonicalization), method.CanonicalSignature())
+	assert.Equal(t, method.SelectorWith(SolcCanonicalization), method.Selector())
+}
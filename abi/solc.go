@@ -0,0 +1,366 @@
+package abi
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+	"github.com/unpackdev/solgo/ast"
+	"github.com/unpackdev/solgo/utils"
+)
+
+// solcEntry is an entry of a solc ABI. Fields are declared in the order solc writes them, which is
+// alphabetical, and are left out for the entry types solc leaves them out for.
+type solcEntry struct {
+	Anonymous       *bool            `json:"anonymous,omitempty"`
+	Inputs          *[]solcParameter `json:"inputs,omitempty"`
+	Name            *string          `json:"name,omitempty"`
+	Outputs         *[]solcParameter `json:"outputs,omitempty"`
+	StateMutability string           `json:"stateMutability,omitempty"`
+	Type            string           `json:"type"`
+
+	selector string // Breaks ties between overloads, which solc lists in selector order.
+}
+
+// solcParameter is a parameter of a solc ABI entry, with its fields in the order solc writes them.
+type solcParameter struct {
+	Components   *[]solcParameter `json:"components,omitempty"`
+	Indexed      *bool            `json:"indexed,omitempty"`
+	InternalType string           `json:"internalType"`
+	Name         string           `json:"name"`
+	Type         string           `json:"type"`
+}
+
+// solcArraySuffix matches the outermost array dimension of a type string, such as [] or [2].
+var solcArraySuffix = regexp.MustCompile(`\[\d*\]$`)
+
+// solcArrayDimensions matches all array dimensions of a type name as written, such as [][2].
+var solcArrayDimensions = regexp.MustCompile(`(\[\d*\])+$`)
+
+// ToSolcJSON returns the ABI the builder generated for the entry contract, encoded byte for byte as solc
+// does. See Contract.ToSolcJSON.
+func (b *Builder) ToSolcJSON() ([]byte, error) {
+	if b.root == nil {
+		return nil, fmt.Errorf("abi root is not built, call Build() first")
+	}
+	return b.ToSolcJSONByName(b.root.GetEntryName())
+}
+
+// ToSolcJSONByName returns the solc ABI of the contract with the given name. See ToSolcJSON.
+func (b *Builder) ToSolcJSONByName(contractName string) ([]byte, error) {
+	if b.root == nil {
+		return nil, fmt.Errorf("abi root is not built, call Build() first")
+	}
+	contract := b.root.GetContractByName(contractName)
+	if contract == nil {
+		return nil, fmt.Errorf("contract %s not found", contractName)
+	}
+	return contract.ToSolcJSON()
+}
+
+// ToSolcJSON encodes the contract byte for byte as solc does: entries sorted by type, name and
+// selector, with their fields in alphabetical order and left out for the entry types solc leaves them
+// out for.
+func (c *Contract) ToSolcJSON() ([]byte, error) {
+	entries := make([]*solcEntry, 0, len(*c))
+	for _, method := range *c {
+		entries = append(entries, newSolcEntry(method))
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Type != entries[j].Type {
+			return entries[i].Type < entries[j].Type
+		}
+		if entries[i].name() != entries[j].name() {
+			return entries[i].name() < entries[j].name()
+		}
+		return entries[i].selector < entries[j].selector
+	})
+
+	return json.Marshal(entries)
+}
+
+// newSolcEntry converts the method into the entry solc writes for it.
+func newSolcEntry(method *Method) *solcEntry {
+	toReturn := &solcEntry{Type: method.Type}
+
+	switch method.Type {
+	case "function":
+		toReturn.Inputs = newSolcParameters(method.Inputs, false)
+		toReturn.Name = solcName(method.Name)
+		toReturn.Outputs = newSolcParameters(method.Outputs, false)
+		toReturn.StateMutability = method.StateMutability
+	case "event":
+		anonymous := method.Anonymous
+		toReturn.Anonymous = &anonymous
+		toReturn.Inputs = newSolcParameters(method.Inputs, true)
+		toReturn.Name = solcName(method.Name)
+	case "error":
+		toReturn.Inputs = newSolcParameters(method.Inputs, false)
+		toReturn.Name = solcName(method.Name)
+	case "constructor":
+		toReturn.Inputs = newSolcParameters(method.Inputs, false)
+		toReturn.StateMutability = method.StateMutability
+	default:
+		toReturn.StateMutability = method.StateMutability
+	}
+
+	toReturn.selector = common.Bytes2Hex(utils.Keccak256([]byte(method.CanonicalSignature())))
+	return toReturn
+}
+
+// newSolcParameters converts the parameters into the ones solc writes. Event parameters carry whether
+// they are indexed, and tuples their components.
+func newSolcParameters(parameters []MethodIO, event bool) *[]solcParameter {
+	toReturn := make([]solcParameter, 0, len(parameters))
+	for _, parameter := range parameters {
+		converted := solcParameter{
+			InternalType: parameter.InternalType,
+			Name:         parameter.Name,
+			Type:         parameter.Type,
+		}
+		if strings.HasPrefix(parameter.Type, "tuple") {
+			converted.Components = newSolcParameters(parameter.Components, false)
+		}
+		if event {
+			indexed := parameter.Indexed
+			converted.Indexed = &indexed
+		}
+		toReturn = append(toReturn, converted)
+	}
+	return &toReturn
+}
+
+// name returns the name of the entry, or an empty string for entries solc writes without one.
+func (e *solcEntry) name() string {
+	if e.Name == nil {
+		return ""
+	}
+	return *e.Name
+}
+
+// solcName returns a pointer to the name, as entries leave out names by leaving them nil.
+func solcName(name string) *string {
+	return &name
+}
+
+// solcStructs returns the struct definitions of the sources by canonical name, such as Vault.Position,
+// and by name, which is how file level structs are referenced.
+func (t *TypeResolver) solcStructs() map[string]*ast.StructDefinition {
+	toReturn := make(map[string]*ast.StructDefinition)
+	add := func(node ast.Node[ast.NodeType]) {
+		if definition, ok := node.(*ast.StructDefinition); ok {
+			toReturn[definition.GetCanonicalName()] = definition
+			if _, exists := toReturn[definition.GetName()]; !exists {
+				toReturn[definition.GetName()] = definition
+			}
+		}
+	}
+
+	if root := t.parser.GetRoot(); root != nil && root.GetAST() != nil {
+		for _, node := range root.GetAST().GetGlobalNodes() {
+			add(node)
+		}
+	}
+	_, _ = t.parser.GetAstBuilder().GetTree().ExecuteTypeVisit(
+		ast_pb.NodeType_STRUCT_DEFINITION,
+		func(node ast.Node[ast.NodeType]) (bool, error) {
+			add(node)
+			return true, nil
+		},
+	)
+
+	return toReturn
+}
+
+// solcParameter resolves the type of a parameter the way solc does: structs become tuples with their
+// members as components, enums uint8, contracts address and user defined value types their underlying
+// type, while the internal type keeps the Solidity type along with its array dimensions.
+func (t *TypeResolver) solcParameter(name string, typeDescription *ast.TypeDescription, structs map[string]*ast.StructDefinition) solcParameter {
+	toReturn := solcParameter{Name: name}
+	if typeDescription == nil {
+		return toReturn
+	}
+
+	typeString := solcTypeString(typeDescription)
+	if strings.HasPrefix(typeString, "function") {
+		toReturn.InternalType = typeString
+		toReturn.Type = "function"
+		return toReturn
+	}
+
+	base, dimensions := typeString, ""
+	for solcArraySuffix.MatchString(base) {
+		suffix := solcArraySuffix.FindString(base)
+		base, dimensions = strings.TrimSuffix(base, suffix), suffix+dimensions
+	}
+	identifier := typeDescription.GetIdentifier()
+
+	// File level structs and enums are named Global.Name in the AST, while solc names them as declared.
+	if strings.HasPrefix(base, "struct Global.") || strings.HasPrefix(base, "enum Global.") {
+		base = strings.Replace(base, " Global.", " ", 1)
+	}
+
+	switch {
+	case strings.HasPrefix(base, "struct "):
+		toReturn.InternalType = base + dimensions
+		toReturn.Type = "tuple" + dimensions
+		components := make([]solcParameter, 0)
+		if definition, ok := structs[strings.TrimPrefix(base, "struct ")]; ok {
+			for _, member := range definition.GetMembers() {
+				components = append(components, t.solcParameter(member.GetName(), solcTypeDescription(member.GetTypeDescription(), member.GetTypeName()), structs))
+			}
+		}
+		toReturn.Components = &components
+	case strings.HasPrefix(base, "enum "):
+		toReturn.InternalType = base + dimensions
+		toReturn.Type = "uint8" + dimensions
+	case strings.HasPrefix(base, "contract "):
+		toReturn.InternalType = base + dimensions
+		toReturn.Type = "address" + dimensions
+	case base == "address payable" || (base == "address" && strings.HasPrefix(identifier, "t_address_payable")):
+		toReturn.InternalType = "address payable" + dimensions
+		toReturn.Type = "address" + dimensions
+	case strings.Contains(identifier, "t_userDefinedValueType"):
+		toReturn.InternalType = base + dimensions
+		toReturn.Type = normalizeTypeName(base) + dimensions
+		if underlying, ok := t.ResolveUserDefinedValueType(&ast.TypeDescription{TypeString: base, TypeIdentifier: identifier}); ok {
+			toReturn.Type = underlying + dimensions
+		}
+	default:
+		toReturn.Type = normalizeTypeName(base) + dimensions
+		toReturn.InternalType = toReturn.Type
+	}
+
+	return toReturn
+}
+
+// solcGetter returns the inputs and outputs of the getter solc generates for a public state variable:
+// a parameter for every mapping key and array index, and the value, or the members of a struct value
+// other than mappings and arrays, which getters cannot return.
+func (b *Builder) solcGetter(declaration *ast.StateVariableDeclaration, structs map[string]*ast.StructDefinition) ([]solcParameter, []solcParameter) {
+	inputs, outputs := make([]solcParameter, 0), make([]solcParameter, 0)
+
+	typeName := declaration.GetTypeName()
+	if typeName == nil {
+		return inputs, outputs
+	}
+	// Descriptions of constants are the ones of their values, such as int_const 10, so the type name is
+	// preferred.
+	typeDescription := typeName.GetTypeDescription()
+	if typeDescription == nil {
+		typeDescription = declaration.GetTypeDescription()
+	}
+	typeDescription = solcTypeDescription(typeDescription, typeName)
+	for typeName.GetKeyType() != nil && typeName.GetValueType() != nil {
+		inputs = append(inputs, b.resolver.solcParameter("", typeName.GetKeyType().GetTypeDescription(), structs))
+		typeName = typeName.GetValueType()
+		typeDescription = solcTypeDescription(solcResolvedTypeDescription(typeName, structs), typeName)
+	}
+	if typeDescription == nil {
+		return inputs, outputs
+	}
+
+	typeString := solcTypeString(typeDescription)
+	for solcArraySuffix.MatchString(typeString) {
+		typeString = solcArraySuffix.ReplaceAllString(typeString, "")
+		inputs = append(inputs, solcParameter{InternalType: "uint256", Name: "", Type: "uint256"})
+	}
+	value := b.resolver.solcParameter("", &ast.TypeDescription{
+		TypeIdentifier: typeDescription.GetIdentifier(),
+		TypeString:     typeString,
+	}, structs)
+
+	if value.Components == nil {
+		return inputs, append(outputs, value)
+	}
+	if definition, ok := structs[strings.TrimPrefix(value.InternalType, "struct ")]; ok {
+		for i, member := range definition.GetMembers() {
+			memberType := solcTypeString(solcTypeDescription(member.GetTypeDescription(), member.GetTypeName()))
+			if strings.HasPrefix(memberType, "mapping(") || solcArraySuffix.MatchString(memberType) {
+				continue
+			}
+			outputs = append(outputs, (*value.Components)[i])
+		}
+	}
+	return inputs, outputs
+}
+
+// solcRevertedError returns the error definition the revert statement reverts with, or nil for
+// reverts with a reason string.
+func (b *Builder) solcRevertedError(revert *ast.RevertStatement) *ast.ErrorDefinition {
+	if definition, ok := b.parser.GetAstBuilder().GetTree().GetById(revert.GetReferencedDeclaration()).(*ast.ErrorDefinition); ok {
+		return definition
+	}
+	if name := revert.GetErrorName(); name != "" {
+		if definition, ok := b.parser.GetAstBuilder().GetSymbolTable().ResolveIdentifier(revert.GetId(), name).(*ast.ErrorDefinition); ok {
+			return definition
+		}
+	}
+	return nil
+}
+
+// solcResolvedTypeDescription returns the description of the type name, resolving structs the parser
+// left unresolved, such as file level structs that are values of mappings, by their name.
+func solcResolvedTypeDescription(typeName *ast.TypeName, structs map[string]*ast.StructDefinition) *ast.TypeDescription {
+	typeDescription := typeName.GetTypeDescription()
+	if typeDescription != nil && !strings.HasPrefix(typeDescription.GetIdentifier(), "t_unknown") {
+		return typeDescription
+	}
+	name := solcArrayDimensions.ReplaceAllString(typeName.GetName(), "")
+	if definition, ok := structs[name]; ok {
+		return &ast.TypeDescription{
+			TypeIdentifier: "t_struct$_" + strings.ReplaceAll(definition.GetCanonicalName(), ".", "_"),
+			TypeString:     "struct " + definition.GetCanonicalName(),
+		}
+	}
+	return typeDescription
+}
+
+// solcTypeString returns the type string of the description without its data location.
+func solcTypeString(typeDescription *ast.TypeDescription) string {
+	if typeDescription == nil {
+		return ""
+	}
+	typeString := typeDescription.GetString()
+	for _, location := range []string{" storage ref", " storage pointer", " storage", " memory", " calldata"} {
+		typeString = strings.TrimSuffix(typeString, location)
+	}
+	return typeString
+}
+
+// solcLibraryStorageType returns the type of a storage pointer parameter of a library function, the
+// type it points to followed by storage, such as `Lib.Data storage` or `mapping(address => uint256)
+// storage`, as solc names parameters the contract ABI cannot encode in library ABIs and selectors.
+func solcLibraryStorageType(typeDescription *ast.TypeDescription) string {
+	typeString := solcTypeString(typeDescription)
+	for _, prefix := range []string{"struct Global.", "enum Global.", "struct ", "enum ", "contract "} {
+		typeString = strings.TrimPrefix(typeString, prefix)
+	}
+	if strings.HasPrefix(typeString, "mapping(") {
+		typeString = strings.Join(strings.Fields(strings.ReplaceAll(typeString, "=>", " => ")), " ")
+	}
+	return typeString + " storage"
+}
+
+// solcTypeDescription returns the type description with the array dimensions of the type name as
+// written, as descriptions of arrays of user defined types, such as Position[], leave them out.
+func solcTypeDescription(typeDescription *ast.TypeDescription, typeName *ast.TypeName) *ast.TypeDescription {
+	if typeDescription == nil || typeName == nil || typeName.GetKeyType() != nil {
+		return typeDescription
+	}
+	dimensions := solcArrayDimensions.FindString(typeName.GetName())
+	typeString := solcTypeString(typeDescription)
+	if dimensions == "" || strings.HasSuffix(typeString, dimensions) {
+		return typeDescription
+	}
+	// Descriptions of nested arrays, such as uint8[3][], may keep only some of their dimensions.
+	return &ast.TypeDescription{
+		TypeIdentifier: typeDescription.GetIdentifier(),
+		TypeString:     solcArrayDimensions.ReplaceAllString(typeString, "") + dimensions,
+	}
+}
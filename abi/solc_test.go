@@ -0,0 +1,145 @@
+package abi
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo"
+)
+
+func TestToSolcJSON(t *testing.T) {
+	builder, err := NewBuilderFromSources(context.TODO(), &solgo.Sources{
+		SourceUnits: []*solgo.SourceUnit{
+			{
+				Name: "Vault",
+				Path: "Vault.sol",
+				Content: `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.19;
+
+interface IToken {
+    function balanceOf(address account) external view returns (uint256);
+}
+
+error Unauthorized(address caller);
+
+contract Base {
+    event Deposited(address indexed account, uint256 amount);
+
+    function version() public pure virtual returns (uint256) {
+        return 1;
+    }
+}
+
+contract Vault is Base {
+    type Price is uint128;
+
+    enum Status { Open, Closed }
+
+    struct Position {
+        address owner;
+        uint256 size;
+        Status status;
+        bytes data;
+        uint256[] history;
+    }
+
+    IToken public token;
+    address payable public treasury;
+    Position[] public positions;
+    mapping(address => mapping(uint256 => Price)) public prices;
+    uint256[] public amounts;
+
+    event Opened(uint256 indexed id, Position position);
+
+    event Swept(address indexed to) anonymous;
+
+    error Closed(uint256 id);
+
+    constructor(IToken _token) payable {
+        token = _token;
+        treasury = payable(msg.sender);
+    }
+
+    function open(Position calldata position) external returns (uint256 id) {
+        positions.push(position);
+        id = positions.length - 1;
+        emit Opened(id, position);
+    }
+
+    function close(uint256 id) external {
+        if (positions[id].owner != msg.sender) revert Unauthorized(msg.sender);
+        if (positions[id].status == Status.Closed) revert Closed(id);
+        positions[id].status = Status.Closed;
+    }
+
+    function getPositions() external view returns (Position[] memory) {
+        return positions;
+    }
+
+    function quote(Price price, Status status) external pure returns (Price) {
+        if (status == Status.Closed) return Price.wrap(0);
+        return price;
+    }
+
+    function version() public pure override returns (uint256) {
+        return 2;
+    }
+
+    receive() external payable {}
+}
+`,
+			},
+		},
+		EntrySourceUnitName: "Vault",
+		LocalSourcesPath:    "../sources/",
+	})
+	require.NoError(t, err)
+	require.Empty(t, builder.Parse())
+	require.NoError(t, builder.Build())
+
+	encoded, err := builder.ToSolcJSON()
+	require.NoError(t, err)
+	// Expected output is the ABI solc 0.8.19 emits for the contract.
+	expected := `[` +
+		`{"inputs":[{"internalType":"contract IToken","name":"_token","type":"address"}],"stateMutability":"payable","type":"constructor"},` +
+		`{"inputs":[{"internalType":"uint256","name":"id","type":"uint256"}],"name":"Closed","type":"error"},` +
+		`{"inputs":[{"internalType":"address","name":"caller","type":"address"}],"name":"Unauthorized","type":"error"},` +
+		`{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"account","type":"address"},{"indexed":false,"internalType":"uint256","name":"amount","type":"uint256"}],"name":"Deposited","type":"event"},` +
+		`{"anonymous":false,"inputs":[{"indexed":true,"internalType":"uint256","name":"id","type":"uint256"},{"components":[{"internalType":"address","name":"owner","type":"address"},{"internalType":"uint256","name":"size","type":"uint256"},{"internalType":"enum Vault.Status","name":"status","type":"uint8"},{"internalType":"bytes","name":"data","type":"bytes"},{"internalType":"uint256[]","name":"history","type":"uint256[]"}],"indexed":false,"internalType":"struct Vault.Position","name":"position","type":"tuple"}],"name":"Opened","type":"event"},` +
+		`{"anonymous":true,"inputs":[{"indexed":true,"internalType":"address","name":"to","type":"address"}],"name":"Swept","type":"event"},` +
+		`{"inputs":[{"internalType":"uint256","name":"","type":"uint256"}],"name":"amounts","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},` +
+		`{"inputs":[{"internalType":"uint256","name":"id","type":"uint256"}],"name":"close","outputs":[],"stateMutability":"nonpayable","type":"function"},` +
+		`{"inputs":[],"name":"getPositions","outputs":[{"components":[{"internalType":"address","name":"owner","type":"address"},{"internalType":"uint256","name":"size","type":"uint256"},{"internalType":"enum Vault.Status","name":"status","type":"uint8"},{"internalType":"bytes","name":"data","type":"bytes"},{"internalType":"uint256[]","name":"history","type":"uint256[]"}],"internalType":"struct Vault.Position[]","name":"","type":"tuple[]"}],"stateMutability":"view","type":"function"},` +
+		`{"inputs":[{"components":[{"internalType":"address","name":"owner","type":"address"},{"internalType":"uint256","name":"size","type":"uint256"},{"internalType":"enum Vault.Status","name":"status","type":"uint8"},{"internalType":"bytes","name":"data","type":"bytes"},{"internalType":"uint256[]","name":"history","type":"uint256[]"}],"internalType":"struct Vault.Position","name":"position","type":"tuple"}],"name":"open","outputs":[{"internalType":"uint256","name":"id","type":"uint256"}],"stateMutability":"nonpayable","type":"function"},` +
+		`{"inputs":[{"internalType":"uint256","name":"","type":"uint256"}],"name":"positions","outputs":[{"internalType":"address","name":"owner","type":"address"},{"internalType":"uint256","name":"size","type":"uint256"},{"internalType":"enum Vault.Status","name":"status","type":"uint8"},{"internalType":"bytes","name":"data","type":"bytes"}],"stateMutability":"view","type":"function"},` +
+		`{"inputs":[{"internalType":"address","name":"","type":"address"},{"internalType":"uint256","name":"","type":"uint256"}],"name":"prices","outputs":[{"internalType":"Vault.Price","name":"","type":"uint128"}],"stateMutability":"view","type":"function"},` +
+		`{"inputs":[{"internalType":"Vault.Price","name":"price","type":"uint128"},{"internalType":"enum Vault.Status","name":"status","type":"uint8"}],"name":"quote","outputs":[{"internalType":"Vault.Price","name":"","type":"uint128"}],"stateMutability":"pure","type":"function"},` +
+		`{"inputs":[],"name":"token","outputs":[{"internalType":"contract IToken","name":"","type":"address"}],"stateMutability":"view","type":"function"},` +
+		`{"inputs":[],"name":"treasury","outputs":[{"internalType":"address payable","name":"","type":"address"}],"stateMutability":"view","type":"function"},` +
+		`{"inputs":[],"name":"version","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"pure","type":"function"},` +
+		`{"stateMutability":"payable","type":"receive"}` +
+		`]`
+	assert.Equal(t, expected, string(encoded))
+
+	parsed, err := abi.JSON(strings.NewReader(string(encoded)))
+	require.NoError(t, err)
+	assert.Len(t, parsed.Methods, 10)
+	assert.Len(t, parsed.Events, 3)
+	assert.True(t, parsed.Events["Swept"].Anonymous)
+	assert.Len(t, parsed.Errors, 2)
+	assert.True(t, parsed.HasReceive())
+
+	base, err := builder.ToSolcJSONByName("Base")
+	require.NoError(t, err)
+	assert.Equal(t, `[`+
+		`{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"account","type":"address"},{"indexed":false,"internalType":"uint256","name":"amount","type":"uint256"}],"name":"Deposited","type":"event"},`+
+		`{"inputs":[],"name":"version","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"pure","type":"function"}`+
+		`]`, string(base))
+
+	_, err = builder.ToSolcJSONByName("Missing")
+	assert.Error(t, err)
+}
@@ -4,9 +4,11 @@ import (
 	"github.com/unpackdev/solgo/ir"
 )
 
-// processStateVariable processes the provided StateVariable from the IR and constructs a Method representation.
-// The returned Method will have its Type set to "function" and its StateMutability determined by the state variable's mutability.
-// Depending on the type of the state variable (e.g., mapping, contract, enum), the method's Inputs and Outputs are populated accordingly.
+// processStateVariable processes the provided StateVariable from the IR and constructs a Method representation
+// of its getter. The returned Method will have its Type set to "function".
+// When the declaration is available, the getter is synthesized exactly like solc does: a uint256 input for every
+// array index and an input for every mapping key, returning the value or, for structs, the members other than
+// mappings and arrays. Otherwise the method's Inputs and Outputs are derived from the type description alone.
 func (b *Builder) processStateVariable(stateVar *ir.StateVariable) *Method {
 	toReturn := &Method{
 		Name:            stateVar.GetName(),
@@ -16,6 +18,18 @@ func (b *Builder) processStateVariable(stateVar *ir.StateVariable) *Method {
 		StateMutability: b.normalizeStateMutability(stateVar.GetStateMutability()),
 	}
 
+	if declaration := stateVar.GetAST(); declaration != nil && declaration.GetTypeName() != nil && b.resolver.parser != nil {
+		inputs, outputs := b.solcGetter(declaration, b.resolver.solcStructs())
+		for _, input := range inputs {
+			toReturn.Inputs = append(toReturn.Inputs, methodIOFromSolc(input))
+		}
+		for _, output := range outputs {
+			toReturn.Outputs = append(toReturn.Outputs, methodIOFromSolc(output))
+		}
+		toReturn.StateMutability = "view" // Getters never modify state.
+		return toReturn
+	}
+
 	typeName := b.resolver.ResolveType(stateVar.GetTypeDescription())
 
 	switch typeName {
@@ -40,6 +54,13 @@ func (b *Builder) processStateVariable(stateVar *ir.StateVariable) *Method {
 			Type:         "uint8",
 			InternalType: stateVar.GetTypeDescription().GetString(),
 		})
+	case "userDefinedValueType":
+		// User-defined value types are represented by their underlying elementary type in the ABI
+		underlyingType, _ := b.resolver.ResolveUserDefinedValueType(stateVar.GetTypeDescription())
+		toReturn.Outputs = append(toReturn.Outputs, MethodIO{
+			Type:         underlyingType,
+			InternalType: stateVar.GetTypeDescription().GetString(),
+		})
 	default:
 		// For all other types, simply append the type to the method's Outputs
 		toReturn.Outputs = append(toReturn.Outputs, MethodIO{
@@ -1,10 +1,15 @@
 package abi
 
 import (
+	"bytes"
+	"context"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+	"github.com/unpackdev/solgo"
 	"github.com/unpackdev/solgo/ast"
 	"github.com/unpackdev/solgo/ir"
 )
@@ -73,3 +78,113 @@ func TestProcessStateVariable(t *testing.T) {
 		})
 	}
 }
+
+func TestStateVariableGetters(t *testing.T) {
+	newBuilder := func() *Builder {
+		builder, err := NewBuilderFromSources(context.TODO(), &solgo.Sources{
+			SourceUnits: []*solgo.SourceUnit{
+				{
+					Name: "Getters",
+					Path: "Getters.sol",
+					Content: `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.19;
+
+struct Position {
+    address owner;
+    uint256[] history;
+    mapping(address => bool) operators;
+    string label;
+    Point origin;
+}
+
+struct Point {
+    int128 x;
+    int128 y;
+}
+
+contract Getters {
+    enum Status { Active, Closed }
+
+    uint256 public constant LIMIT = 10;
+    address public immutable factory;
+    uint256 internal hidden;
+    uint256 counted;
+    uint256[] public values;
+    uint8[3][] public grid;
+    mapping(address => mapping(uint256 => Status)) public statuses;
+    mapping(address => bytes32[]) public tags;
+    mapping(uint256 => Position) public positions;
+    Point[] public points;
+    Getters public self;
+
+    constructor() {
+        factory = msg.sender;
+    }
+}
+`,
+				},
+			},
+			EntrySourceUnitName: "Getters",
+			LocalSourcesPath:    "../sources/",
+		})
+		require.NoError(t, err)
+		assert.Empty(t, builder.Parse())
+		require.NoError(t, builder.Build())
+		return builder
+	}
+	builder := newBuilder()
+
+	contract := builder.GetRoot().GetContractByName("Getters")
+	require.NotNil(t, contract)
+	assert.Nil(t, contract.GetMethodByName("hidden"))
+	assert.Nil(t, contract.GetMethodByName("counted"))
+
+	point := MethodIO{Name: "origin", Type: "tuple", InternalType: "struct Point", Components: []MethodIO{
+		{Name: "x", Type: "int128", InternalType: "int128"},
+		{Name: "y", Type: "int128", InternalType: "int128"},
+	}}
+	index := MethodIO{Type: "uint256", InternalType: "uint256"}
+
+	testCases := []struct {
+		name    string
+		inputs  []MethodIO
+		outputs []MethodIO
+	}{
+		{"LIMIT", []MethodIO{}, []MethodIO{index}},
+		{"factory", []MethodIO{}, []MethodIO{{Type: "address", InternalType: "address"}}},
+		{"values", []MethodIO{index}, []MethodIO{index}},
+		{"grid", []MethodIO{index, index}, []MethodIO{{Type: "uint8", InternalType: "uint8"}}},
+		{
+			"statuses",
+			[]MethodIO{{Type: "address", InternalType: "address"}, index},
+			[]MethodIO{{Type: "uint8", InternalType: "enum Getters.Status"}},
+		},
+		{"tags", []MethodIO{{Type: "address", InternalType: "address"}, index}, []MethodIO{{Type: "bytes32", InternalType: "bytes32"}}},
+		{"positions", []MethodIO{index}, []MethodIO{
+			{Name: "owner", Type: "address", InternalType: "address"},
+			{Name: "label", Type: "string", InternalType: "string"},
+			point,
+		}},
+		{"points", []MethodIO{index}, point.Components},
+		{"self", []MethodIO{}, []MethodIO{{Type: "address", InternalType: "contract Getters"}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			method := contract.GetMethodByName(tc.name)
+			require.NotNil(t, method)
+			assert.Equal(t, "view", method.StateMutability)
+			assert.Equal(t, tc.inputs, method.Inputs)
+			assert.Equal(t, tc.outputs, method.Outputs)
+		})
+	}
+
+	// The synthesized getters match the ones of the solc ABI.
+	solcJSON, err := newBuilder().ToSolcJSONByName("Getters")
+	require.NoError(t, err)
+	solcABI, err := abi.JSON(bytes.NewReader(solcJSON))
+	require.NoError(t, err)
+	for _, tc := range testCases {
+		assert.Equal(t, solcABI.Methods[tc.name].Sig, contract.GetMethodByName(tc.name).CanonicalSignature(), tc.name)
+	}
+}
@@ -1,8 +1,11 @@
 package abi
 
 import (
+	"regexp"
+	"strconv"
 	"strings"
 
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
 	"github.com/unpackdev/solgo/ast"
 	"github.com/unpackdev/solgo/ir"
 	"github.com/unpackdev/solgo/utils"
@@ -46,9 +49,77 @@ func (t *TypeResolver) ResolveType(typeName *ast.TypeDescription) string {
 		return "error"
 	}
 
+	if strings.Contains(typeName.GetIdentifier(), "t_userDefinedValueType") {
+		return "userDefinedValueType"
+	}
+
 	return normalizeTypeName(typeName.GetString())
 }
 
+// ResolveUserDefinedValueType resolves a user-defined value type (including arrays of it) to its
+// underlying elementary type, the same way solc normalizes such types in the ABI.
+// It returns false if the user-defined value type definition cannot be found.
+func (t *TypeResolver) ResolveUserDefinedValueType(typeName *ast.TypeDescription) (string, bool) {
+	name := typeName.GetString()
+	suffix := ""
+	if idx := strings.Index(name, "["); idx != -1 {
+		name, suffix = name[:idx], name[idx:]
+	}
+
+	if udvt := t.findUserDefinedValueType(name, typeName.GetIdentifier()); udvt != nil && udvt.GetUnderlyingType() != nil {
+		return normalizeTypeName(udvt.GetUnderlyingType().GetString()) + suffix, true
+	}
+
+	return typeName.GetString(), false
+}
+
+// userDefinedValueTypeIdRegex matches the declaration id type identifiers of user-defined value types end with.
+var userDefinedValueTypeIdRegex = regexp.MustCompile(`t_userDefinedValueType\$_(\w+?)_\$(\d+)`)
+
+// findUserDefinedValueType returns the user-defined value type definition the type identifier references
+// by declaration id, as types of the same name can be declared in different contracts. Without an id,
+// such as for types only known by name, it searches file-level and contract-level definitions by name,
+// which can be canonical (Contract.Name) or simple.
+func (t *TypeResolver) findUserDefinedValueType(name string, identifier string) *ast.UserDefinedValueTypeDefinition {
+	if parts := strings.Split(name, "."); len(parts) > 1 {
+		name = parts[len(parts)-1]
+	}
+
+	root := t.parser.GetRoot()
+	if root == nil || root.GetAST() == nil {
+		return nil
+	}
+
+	tree := t.parser.GetAstBuilder().GetTree()
+	if matches := userDefinedValueTypeIdRegex.FindStringSubmatch(identifier); matches != nil {
+		if id, err := strconv.ParseInt(matches[2], 10, 64); err == nil {
+			if udvt, ok := tree.GetById(id).(*ast.UserDefinedValueTypeDefinition); ok && udvt.GetName() == matches[1] {
+				return udvt
+			}
+		}
+	}
+
+	for _, node := range root.GetAST().GetGlobalNodes() {
+		if udvt, ok := node.(*ast.UserDefinedValueTypeDefinition); ok && udvt.GetName() == name {
+			return udvt
+		}
+	}
+
+	var toReturn *ast.UserDefinedValueTypeDefinition
+	_, _ = tree.ExecuteTypeVisit(
+		ast_pb.NodeType_USER_DEFINED_VALUE_TYPE,
+		func(node ast.Node[ast.NodeType]) (bool, error) {
+			if udvt, ok := node.(*ast.UserDefinedValueTypeDefinition); ok && udvt.GetName() == name {
+				toReturn = udvt
+				return false, nil
+			}
+			return true, nil
+		},
+	)
+
+	return toReturn
+}
+
 // ResolveMappingType resolves the input and output types for a given mapping type.
 // It returns slices of MethodIO for inputs and outputs respectively.
 func (t *TypeResolver) ResolveMappingType(typeName *ast.TypeDescription) ([]MethodIO, []MethodIO) {
@@ -89,60 +160,33 @@ func (t *TypeResolver) ResolveMappingType(typeName *ast.TypeDescription) ([]Meth
 	return inputs, outputs
 }
 
-// ResolveStructType resolves the type of a given struct and returns its MethodIO representation.
+// ResolveStructType resolves the type of a given struct and returns its MethodIO representation: a
+// tuple, with the array dimensions of the type, whose internal type is the canonical name of the struct
+// and whose components are its named members, nested structs resolved recursively. Members solc leaves
+// out of the ABI, such as mappings, are skipped. The name of the parameter is left to the caller.
 func (t *TypeResolver) ResolveStructType(typeName *ast.TypeDescription) MethodIO {
-	nameCleaned := strings.Replace(typeName.GetString(), "struct ", "", -1)
-	nameCleaned = strings.TrimLeft(nameCleaned, "[]")
-	nameCleaned = strings.TrimRight(nameCleaned, "[]")
-	nameParts := strings.Split(nameCleaned, ".")
+	return methodIOFromSolc(t.solcParameter("", typeName, t.solcStructs()))
+}
 
+// methodIOFromSolc converts a resolved solc parameter, along with its components, to a MethodIO.
+func methodIOFromSolc(parameter solcParameter) MethodIO {
 	toReturn := MethodIO{
-		Name:         nameParts[1],
-		Components:   make([]MethodIO, 0),
-		Type:         "tuple",
-		InternalType: typeName.GetString(),
+		Name:         parameter.Name,
+		Type:         parameter.Type,
+		InternalType: parameter.InternalType,
 	}
-
-	for _, contract := range t.parser.GetRoot().GetContracts() {
-		for _, structVar := range contract.GetStructs() {
-			if structVar.GetName() == toReturn.Name {
-				for _, member := range structVar.GetMembers() {
-					// Mapping types are not supported in structs
-					if isMappingType(member.GetTypeDescription().GetString()) {
-						continue
-					}
-
-					if isContractType(member.GetTypeDescription().GetString()) {
-						toReturn.Outputs = append(toReturn.Outputs, MethodIO{
-							Name:         member.GetName(),
-							Type:         "address",
-							InternalType: member.GetTypeDescription().GetString(),
-						})
-
-						continue
-					}
-
-					dType := t.discoverType(member.GetTypeDescription().GetString())
-					if len(dType.Outputs) > 0 {
-						for _, out := range dType.Outputs {
-							toReturn.Components = append(toReturn.Components, MethodIO{
-								Name:         out.Name,
-								Type:         out.Type,
-								InternalType: member.GetTypeDescription().GetString(),
-							})
-						}
-					} else {
-						toReturn.Components = append(toReturn.Components, MethodIO{
-							Name:         member.GetName(),
-							Type:         dType.Type,
-							InternalType: member.GetTypeDescription().GetString(),
-						})
-					}
-				}
+	if parameter.Indexed != nil {
+		toReturn.Indexed = *parameter.Indexed
+	}
+	if parameter.Components != nil {
+		toReturn.Components = make([]MethodIO, 0, len(*parameter.Components))
+		for _, component := range *parameter.Components {
+			if component.Type == "mapping" || strings.HasPrefix(component.InternalType, "mapping(") {
+				continue
 			}
+			toReturn.Components = append(toReturn.Components, methodIOFromSolc(component))
 		}
 	}
-
 	return toReturn
 }
 
@@ -273,6 +317,14 @@ func (t *TypeResolver) discoverType(typeName string) Type {
 			}
 		}
 
+		if udvt := t.findUserDefinedValueType(typeName, ""); udvt != nil && udvt.GetUnderlyingType() != nil {
+			toReturn.Outputs = append(toReturn.Outputs, Type{
+				Type:         normalizeTypeName(udvt.GetUnderlyingType().GetString()),
+				InternalType: udvt.GetTypeDescription().GetString(),
+			})
+			return toReturn
+		}
+
 		for _, node := range t.parser.GetRoot().GetAST().GetGlobalNodes() {
 			switch nodeCtx := node.(type) {
 			case *ast.EnumDefinition:
@@ -1,10 +1,12 @@
 package abi
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+	"github.com/unpackdev/solgo"
 	"github.com/unpackdev/solgo/ast"
 )
 
@@ -163,3 +165,223 @@ func TestDiscoverType(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveUserDefinedValueType(t *testing.T) {
+	builder, err := NewBuilderFromSources(context.TODO(), &solgo.Sources{
+		SourceUnits: []*solgo.SourceUnit{
+			{
+				Name: "Prices",
+				Path: "Prices.sol",
+				Content: `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.8;
+
+type Price is uint256;
+
+contract Prices {
+    type Amount is uint128;
+
+    Price public last;
+    mapping(address => Amount) public amounts;
+
+    function set(Price price, Amount amount) public returns (Price) {
+        last = price;
+        amounts[msg.sender] = amount;
+        return price;
+    }
+}
+`,
+			},
+		},
+		EntrySourceUnitName: "Prices",
+		LocalSourcesPath:    "../sources/",
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, builder.Parse())
+	assert.NoError(t, builder.Build())
+
+	underlying, found := builder.GetTypeResolver().ResolveUserDefinedValueType(&ast.TypeDescription{
+		TypeIdentifier: "t_array$_t_userDefinedValueType$_Price_$1_$dyn_memory_ptr",
+		TypeString:     "Price[]",
+	})
+	assert.True(t, found)
+	assert.Equal(t, "uint256[]", underlying)
+
+	_, found = builder.GetTypeResolver().ResolveUserDefinedValueType(&ast.TypeDescription{
+		TypeIdentifier: "t_userDefinedValueType$_Unknown_$1",
+		TypeString:     "Unknown",
+	})
+	assert.False(t, found)
+
+	contract := builder.GetEntryContract()
+	irFunctions := builder.GetParser().GetRoot().GetContracts()[0].GetFunctions()
+	assert.Len(t, irFunctions, 1)
+	methods, err := builder.GetFunctionAsABI(irFunctions[0])
+	assert.NoError(t, err)
+	set := methods[0]
+	assert.Equal(t, []MethodIO{
+		{Name: "price", Type: "uint256", InternalType: "Price"},
+		{Name: "amount", Type: "uint128", InternalType: "Prices.Amount"},
+	}, set.Inputs)
+	assert.Equal(t, "uint256", set.Outputs[0].Type)
+
+	last := contract.GetMethodByName("last")
+	assert.NotNil(t, last)
+	assert.Equal(t, "uint256", last.Outputs[0].Type)
+	assert.Equal(t, "Price", last.Outputs[0].InternalType)
+
+	amounts := contract.GetMethodByName("amounts")
+	assert.NotNil(t, amounts)
+	assert.Equal(t, "address", amounts.Inputs[0].Type)
+	assert.Equal(t, "uint128", amounts.Outputs[0].Type)
+	assert.Equal(t, "Prices.Amount", amounts.Outputs[0].InternalType)
+}
+
+func TestResolveUserDefinedValueTypeById(t *testing.T) {
+	builder, err := NewBuilderFromSources(context.TODO(), &solgo.Sources{
+		SourceUnits: []*solgo.SourceUnit{
+			{
+				Name: "Amounts",
+				Path: "Amounts.sol",
+				Content: `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.8;
+
+contract Balances {
+    type Amount is uint128;
+
+    function balance(Amount amount) public pure returns (Amount) {
+        return amount;
+    }
+}
+
+contract Amounts {
+    type Amount is int64;
+
+    function delta(Amount amount) public pure returns (Amount) {
+        return amount;
+    }
+}
+`,
+			},
+		},
+		EntrySourceUnitName: "Amounts",
+		LocalSourcesPath:    "../sources/",
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, builder.Parse())
+	assert.NoError(t, builder.Build())
+
+	contracts := builder.GetParser().GetRoot().GetContracts()
+	assert.Len(t, contracts, 2)
+
+	for i, expected := range []string{"uint128", "int64"} {
+		irFunctions := contracts[i].GetFunctions()
+		assert.Len(t, irFunctions, 1)
+
+		parameter := irFunctions[0].GetParameters()[0]
+		underlying, found := builder.GetTypeResolver().ResolveUserDefinedValueType(parameter.GetTypeDescription())
+		assert.True(t, found)
+		assert.Equal(t, expected, underlying)
+
+		methods, err := builder.GetFunctionAsABI(irFunctions[0])
+		assert.NoError(t, err)
+		assert.Equal(t, expected, methods[0].Inputs[0].Type)
+		assert.Equal(t, expected, methods[0].Outputs[0].Type)
+	}
+}
+
+func TestResolveStructType(t *testing.T) {
+	builder, err := NewBuilderFromSources(context.TODO(), &solgo.Sources{
+		SourceUnits: []*solgo.SourceUnit{
+			{
+				Name: "Book",
+				Path: "Book.sol",
+				Content: `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.19;
+
+interface IFeed {
+    function latest() external view returns (uint256);
+}
+
+struct Point {
+    uint256 x;
+    uint256 y;
+}
+
+contract Book {
+    enum Side { Buy, Sell }
+
+    struct Order {
+        address maker;
+        Side side;
+        IFeed feed;
+        Point[] path;
+        Point target;
+    }
+
+    event Placed(address indexed maker, Order order, Point[] indexed corners);
+
+    error Rejected(Order order);
+
+    Order public last;
+
+    function place(Order calldata order) external returns (Order[] memory) {
+        emit Placed(order.maker, order, order.path);
+        last = order;
+        return new Order[](0);
+    }
+}
+`,
+			},
+		},
+		EntrySourceUnitName: "Book",
+		LocalSourcesPath:    "../sources/",
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, builder.Parse())
+	assert.NoError(t, builder.Build())
+
+	point := func(name string, kind string, internalType string) MethodIO {
+		return MethodIO{Name: name, Type: kind, InternalType: internalType, Components: []MethodIO{
+			{Name: "x", Type: "uint256", InternalType: "uint256"},
+			{Name: "y", Type: "uint256", InternalType: "uint256"},
+		}}
+	}
+	order := func(name string, kind string, internalType string) MethodIO {
+		return MethodIO{Name: name, Type: kind, InternalType: internalType, Components: []MethodIO{
+			{Name: "maker", Type: "address", InternalType: "address"},
+			{Name: "side", Type: "uint8", InternalType: "enum Book.Side"},
+			{Name: "feed", Type: "address", InternalType: "contract IFeed"},
+			point("path", "tuple[]", "struct Point[]"),
+			point("target", "tuple", "struct Point"),
+		}}
+	}
+
+	contract := builder.GetEntryContract()
+
+	placed := contract.GetMethodByName("Placed")
+	assert.NotNil(t, placed)
+	corners := point("corners", "tuple[]", "struct Point[]")
+	corners.Indexed = true
+	assert.Equal(t, []MethodIO{
+		{Name: "maker", Type: "address", InternalType: "address", Indexed: true},
+		order("order", "tuple", "struct Book.Order"),
+		corners,
+	}, placed.Inputs)
+
+	rejected := contract.GetMethodByName("Rejected")
+	assert.NotNil(t, rejected)
+	assert.Equal(t, []MethodIO{order("order", "tuple", "struct Book.Order")}, rejected.Inputs)
+
+	last := contract.GetMethodByName("last")
+	assert.NotNil(t, last)
+	// Getters leave out array members of structs.
+	lastOutputs := order("", "tuple", "struct Book.Order").Components
+	assert.Equal(t, append(lastOutputs[:3:3], lastOutputs[4]), last.Outputs)
+
+	irFunctions := builder.GetParser().GetRoot().GetEntryContract().GetFunctions()
+	assert.Len(t, irFunctions, 1)
+	methods, err := builder.GetFunctionAsABI(irFunctions[0])
+	assert.NoError(t, err)
+	assert.Equal(t, []MethodIO{order("order", "tuple", "struct Book.Order")}, methods[0].Inputs)
+	assert.Equal(t, []MethodIO{order("", "tuple[]", "struct Book.Order[]")}, methods[0].Outputs)
+}
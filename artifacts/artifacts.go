@@ -0,0 +1,253 @@
+package artifacts
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// Version is the version of the artifact encoding.
+const Version = 1
+
+var (
+	// ErrDuplicateUnit is returned when adding a unit under a name already used in the session.
+	ErrDuplicateUnit = errors.New("unit already exists")
+
+	// ErrUnitNotFound is returned when restoring a unit the artifact does not have.
+	ErrUnitNotFound = errors.New("unit not found")
+
+	// ErrTypeMismatch is returned when restoring a unit into a message of another type.
+	ErrTypeMismatch = errors.New("unit holds another message type")
+)
+
+// Fields of the encoded artifact and of its units.
+const (
+	artifactVersionField protowire.Number = 1
+	artifactStringField  protowire.Number = 2
+	artifactTypeField    protowire.Number = 3
+	artifactUnitField    protowire.Number = 4
+
+	unitNameField protowire.Number = 1
+	unitTypeField protowire.Number = 2
+	unitDataField protowire.Number = 3
+)
+
+// Unit is a single export of an artifact, encoded against the tables of the artifact.
+type Unit struct {
+	Name        string `json:"name"`         // Name the unit was added under, such as the contract name.
+	MessageType string `json:"message_type"` // Full name of the exported message, such as unpack.v1.ir.Root.
+	Data        []byte `json:"data"`         // Encoded message.
+}
+
+// Session collects exports sharing string and type tables. It is safe for concurrent use.
+type Session struct {
+	mu     sync.Mutex
+	tables *tables
+	units  []*Unit
+	names  map[string]bool
+}
+
+// NewSession creates an empty session.
+func NewSession() *Session {
+	return &Session{
+		tables: newTables(),
+		units:  make([]*Unit, 0),
+		names:  make(map[string]bool),
+	}
+}
+
+// Add encodes the message against the tables of the session and adds it as a unit under the given name.
+func (s *Session) Add(name string, message proto.Message) error {
+	if message == nil {
+		return fmt.Errorf("nil message for unit %s", name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.names[name] {
+		return fmt.Errorf("%w: %s", ErrDuplicateUnit, name)
+	}
+
+	s.units = append(s.units, &Unit{
+		Name:        name,
+		MessageType: MessageType(message),
+		Data:        s.tables.encodeMessage(message.ProtoReflect()),
+	})
+	s.names[name] = true
+	return nil
+}
+
+// GetUnits returns the units added to the session so far.
+func (s *Session) GetUnits() []*Unit {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Unit{}, s.units...)
+}
+
+// Artifact returns an artifact holding the units added to the session so far.
+func (s *Session) Artifact() *Artifact {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &Artifact{
+		Version: Version,
+		Strings: append([]string{}, s.tables.strings...),
+		Types:   append([][]byte{}, s.tables.types...),
+		Units:   append([]*Unit{}, s.units...),
+	}
+}
+
+// Artifact is a set of exports together with the string and type tables they reference.
+type Artifact struct {
+	Version uint64   `json:"version"`
+	Strings []string `json:"strings"`
+	Types   [][]byte `json:"types"`
+	Units   []*Unit  `json:"units"`
+}
+
+// GetUnit returns the unit with the given name, or nil if there is none.
+func (a *Artifact) GetUnit(name string) *Unit {
+	for _, unit := range a.Units {
+		if unit.Name == name {
+			return unit
+		}
+	}
+	return nil
+}
+
+// Unmarshal restores the unit with the given name into the message, which must be of the exported type.
+func (a *Artifact) Unmarshal(name string, message proto.Message) error {
+	unit := a.GetUnit(name)
+	if unit == nil {
+		return fmt.Errorf("%w: %s", ErrUnitNotFound, name)
+	}
+
+	if MessageType(message) != unit.MessageType {
+		return fmt.Errorf("%w: %s holds %s, not %s", ErrTypeMismatch, name, unit.MessageType, MessageType(message))
+	}
+
+	proto.Reset(message)
+	d := &decoder{strings: a.Strings, types: a.Types}
+	return d.decodeMessage(unit.Data, message.ProtoReflect())
+}
+
+// Encode returns the binary form of the artifact.
+func (a *Artifact) Encode() []byte {
+	var toReturn []byte
+	toReturn = protowire.AppendTag(toReturn, artifactVersionField, protowire.VarintType)
+	toReturn = protowire.AppendVarint(toReturn, a.Version)
+
+	for _, value := range a.Strings {
+		toReturn = protowire.AppendTag(toReturn, artifactStringField, protowire.BytesType)
+		toReturn = protowire.AppendString(toReturn, value)
+	}
+
+	for _, value := range a.Types {
+		toReturn = protowire.AppendTag(toReturn, artifactTypeField, protowire.BytesType)
+		toReturn = protowire.AppendBytes(toReturn, value)
+	}
+
+	for _, unit := range a.Units {
+		var encoded []byte
+		encoded = protowire.AppendTag(encoded, unitNameField, protowire.BytesType)
+		encoded = protowire.AppendString(encoded, unit.Name)
+		encoded = protowire.AppendTag(encoded, unitTypeField, protowire.BytesType)
+		encoded = protowire.AppendString(encoded, unit.MessageType)
+		encoded = protowire.AppendTag(encoded, unitDataField, protowire.BytesType)
+		encoded = protowire.AppendBytes(encoded, unit.Data)
+
+		toReturn = protowire.AppendTag(toReturn, artifactUnitField, protowire.BytesType)
+		toReturn = protowire.AppendBytes(toReturn, encoded)
+	}
+
+	return toReturn
+}
+
+// Decode parses the binary form of an artifact.
+func Decode(data []byte) (*Artifact, error) {
+	toReturn := &Artifact{
+		Strings: make([]string, 0),
+		Types:   make([][]byte, 0),
+		Units:   make([]*Unit, 0),
+	}
+
+	err := consumeFields(data, func(number protowire.Number, value []byte, varint uint64) error {
+		switch number {
+		case artifactVersionField:
+			if varint != Version {
+				return fmt.Errorf("%w: unsupported version %d", ErrMalformed, varint)
+			}
+			toReturn.Version = varint
+		case artifactStringField:
+			toReturn.Strings = append(toReturn.Strings, string(value))
+		case artifactTypeField:
+			toReturn.Types = append(toReturn.Types, value)
+		case artifactUnitField:
+			unit := &Unit{}
+			err := consumeFields(value, func(number protowire.Number, value []byte, _ uint64) error {
+				switch number {
+				case unitNameField:
+					unit.Name = string(value)
+				case unitTypeField:
+					unit.MessageType = string(value)
+				case unitDataField:
+					unit.Data = value
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			toReturn.Units = append(toReturn.Units, unit)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if toReturn.Version == 0 {
+		return nil, fmt.Errorf("%w: missing version", ErrMalformed)
+	}
+
+	return toReturn, nil
+}
+
+// consumeFields calls fn with every varint or length delimited field of the data.
+func consumeFields(data []byte, fn func(number protowire.Number, value []byte, varint uint64) error) error {
+	for len(data) > 0 {
+		number, wireType, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return ErrMalformed
+		}
+		data = data[n:]
+
+		var value []byte
+		var varint uint64
+		switch wireType {
+		case protowire.VarintType:
+			varint, n = protowire.ConsumeVarint(data)
+		case protowire.BytesType:
+			value, n = protowire.ConsumeBytes(data)
+		default:
+			return ErrMalformed
+		}
+		if n < 0 {
+			return ErrMalformed
+		}
+		data = data[n:]
+
+		if err := fn(number, value, varint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MessageType returns the full name of the message type, as recorded in units.
+func MessageType(message proto.Message) string {
+	return string(message.ProtoReflect().Descriptor().FullName())
+}
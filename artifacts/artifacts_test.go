@@ -0,0 +1,105 @@
+package artifacts
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+	ir_pb "github.com/unpackdev/protos/dist/go/ir"
+	"github.com/unpackdev/solgo"
+	"github.com/unpackdev/solgo/ir"
+	"google.golang.org/protobuf/proto"
+)
+
+const tokenForTest = `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+contract %s {
+    mapping(address => uint256) public balanceOf;
+    mapping(address => mapping(address => uint256)) public allowance;
+    uint256 public totalSupply;
+
+    event Transfer(address indexed from, address indexed to, uint256 value);
+    event Approval(address indexed owner, address indexed spender, uint256 value);
+
+    function transfer(address to, uint256 amount) public returns (bool) {
+        require(balanceOf[msg.sender] >= amount, "balance");
+        balanceOf[msg.sender] -= amount;
+        balanceOf[to] += amount;
+        emit Transfer(msg.sender, to, amount);
+        return true;
+    }
+
+    function approve(address spender, uint256 amount) public returns (bool) {
+        allowance[msg.sender][spender] = amount;
+        emit Approval(msg.sender, spender, amount);
+        return true;
+    }
+}
+`
+
+func TestSession(t *testing.T) {
+	session := NewSession()
+	irRoots := make(map[string]*ir_pb.Root)
+	astRoots := make(map[string]*ast_pb.RootSourceUnit)
+	standalone := 0
+
+	for _, name := range []string{"TokenA", "TokenB", "TokenC"} {
+		builder, err := ir.NewBuilderFromSources(context.TODO(), &solgo.Sources{
+			SourceUnits: []*solgo.SourceUnit{
+				{
+					Name:    name,
+					Path:    name + ".sol",
+					Content: fmt.Sprintf(tokenForTest, name),
+				},
+			},
+			EntrySourceUnitName: name,
+			LocalSourcesPath:    "../sources/",
+		})
+		require.NoError(t, err)
+		require.Empty(t, builder.Parse())
+		require.NoError(t, builder.Build())
+
+		irRoots[name] = builder.ToProto()
+		astRoots[name] = builder.GetAstBuilder().ToProto()
+
+		require.NoError(t, session.Add(name+".ir", irRoots[name]))
+		require.NoError(t, session.Add(name+".ast", astRoots[name]))
+
+		for _, message := range []proto.Message{irRoots[name], astRoots[name]} {
+			encoded, err := proto.Marshal(message)
+			require.NoError(t, err)
+			standalone += len(encoded)
+		}
+	}
+
+	assert.ErrorIs(t, session.Add("TokenA.ir", irRoots["TokenA"]), ErrDuplicateUnit)
+	require.Len(t, session.GetUnits(), 6)
+
+	encoded := session.Artifact().Encode()
+	assert.Less(t, len(encoded)*2, standalone, "shared tables should at least halve the exports")
+
+	artifact, err := Decode(encoded)
+	require.NoError(t, err)
+	require.Len(t, artifact.Units, 6)
+	assert.Equal(t, MessageType(&ir_pb.Root{}), artifact.GetUnit("TokenB.ir").MessageType)
+
+	for name := range irRoots {
+		irRoot := &ir_pb.Root{}
+		require.NoError(t, artifact.Unmarshal(name+".ir", irRoot))
+		assert.True(t, proto.Equal(irRoots[name], irRoot), name)
+
+		astRoot := &ast_pb.RootSourceUnit{}
+		require.NoError(t, artifact.Unmarshal(name+".ast", astRoot))
+		assert.True(t, proto.Equal(astRoots[name], astRoot), name)
+	}
+
+	assert.ErrorIs(t, artifact.Unmarshal("TokenA.ast", &ir_pb.Root{}), ErrTypeMismatch)
+	assert.ErrorIs(t, artifact.Unmarshal("TokenD.ir", &ir_pb.Root{}), ErrUnitNotFound)
+
+	_, err = Decode(encoded[:len(encoded)-3])
+	assert.ErrorIs(t, err, ErrMalformed)
+}
@@ -0,0 +1,310 @@
+package artifacts
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ErrMalformed is returned when decoding data that is not a valid artifact or unit.
+var ErrMalformed = errors.New("malformed artifact")
+
+// typeMessages are the messages deduplicated through the type table.
+var typeMessages = map[protoreflect.FullName]bool{
+	(&ast_pb.TypeDescription{}).ProtoReflect().Descriptor().FullName(): true,
+}
+
+// Map entries are encoded as nested messages with the key in field 1 and the value in field 2.
+const (
+	mapKeyField   protowire.Number = 1
+	mapValueField protowire.Number = 2
+)
+
+// tables holds the strings and types shared by the units of a session.
+type tables struct {
+	strings     []string
+	stringIndex map[string]uint64
+	types       [][]byte
+	typeIndex   map[string]uint64
+}
+
+// newTables creates empty tables.
+func newTables() *tables {
+	return &tables{
+		strings:     make([]string, 0),
+		stringIndex: make(map[string]uint64),
+		types:       make([][]byte, 0),
+		typeIndex:   make(map[string]uint64),
+	}
+}
+
+// internString returns the index of the string, adding it to the string table if needed.
+func (t *tables) internString(value string) uint64 {
+	if index, ok := t.stringIndex[value]; ok {
+		return index
+	}
+	index := uint64(len(t.strings))
+	t.strings = append(t.strings, value)
+	t.stringIndex[value] = index
+	return index
+}
+
+// internType returns the index of the encoded type, adding it to the type table if needed.
+func (t *tables) internType(encoded []byte) uint64 {
+	if index, ok := t.typeIndex[string(encoded)]; ok {
+		return index
+	}
+	index := uint64(len(t.types))
+	t.types = append(t.types, encoded)
+	t.typeIndex[string(encoded)] = index
+	return index
+}
+
+// encodeMessage encodes the message like the protocol buffer wire format does, except that strings are
+// replaced by varint indices into the string table and type messages by varint indices into the type table.
+func (t *tables) encodeMessage(message protoreflect.Message) []byte {
+	var toReturn []byte
+	message.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		switch {
+		case field.IsMap():
+			// Entries are sorted by key so that the same message always encodes the same way.
+			entries := value.Map()
+			keys := make([]protoreflect.MapKey, 0, entries.Len())
+			entries.Range(func(key protoreflect.MapKey, _ protoreflect.Value) bool {
+				keys = append(keys, key)
+				return true
+			})
+			sort.Slice(keys, func(i, j int) bool {
+				return keys[i].String() < keys[j].String()
+			})
+			for _, key := range keys {
+				var encoded []byte
+				encoded = t.encodeValue(encoded, mapKeyField, field.MapKey(), key.Value())
+				encoded = t.encodeValue(encoded, mapValueField, field.MapValue(), entries.Get(key))
+				toReturn = protowire.AppendTag(toReturn, field.Number(), protowire.BytesType)
+				toReturn = protowire.AppendBytes(toReturn, encoded)
+			}
+		case field.IsList():
+			list := value.List()
+			for i := 0; i < list.Len(); i++ {
+				toReturn = t.encodeValue(toReturn, field.Number(), field, list.Get(i))
+			}
+		default:
+			toReturn = t.encodeValue(toReturn, field.Number(), field, value)
+		}
+		return true
+	})
+	return toReturn
+}
+
+// encodeValue appends a single value of the field under the given field number.
+func (t *tables) encodeValue(b []byte, number protowire.Number, field protoreflect.FieldDescriptor, value protoreflect.Value) []byte {
+	switch field.Kind() {
+	case protoreflect.StringKind:
+		b = protowire.AppendTag(b, number, protowire.VarintType)
+		return protowire.AppendVarint(b, t.internString(value.String()))
+	case protoreflect.BytesKind:
+		b = protowire.AppendTag(b, number, protowire.BytesType)
+		return protowire.AppendBytes(b, value.Bytes())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		encoded := t.encodeMessage(value.Message())
+		if typeMessages[field.Message().FullName()] {
+			b = protowire.AppendTag(b, number, protowire.VarintType)
+			return protowire.AppendVarint(b, t.internType(encoded))
+		}
+		b = protowire.AppendTag(b, number, protowire.BytesType)
+		return protowire.AppendBytes(b, encoded)
+	case protoreflect.BoolKind:
+		b = protowire.AppendTag(b, number, protowire.VarintType)
+		return protowire.AppendVarint(b, protowire.EncodeBool(value.Bool()))
+	case protoreflect.EnumKind:
+		b = protowire.AppendTag(b, number, protowire.VarintType)
+		return protowire.AppendVarint(b, uint64(value.Enum()))
+	case protoreflect.Int32Kind, protoreflect.Int64Kind, protoreflect.Sint32Kind, protoreflect.Sint64Kind,
+		protoreflect.Sfixed32Kind, protoreflect.Sfixed64Kind:
+		b = protowire.AppendTag(b, number, protowire.VarintType)
+		return protowire.AppendVarint(b, protowire.EncodeZigZag(value.Int()))
+	case protoreflect.Uint32Kind, protoreflect.Uint64Kind, protoreflect.Fixed32Kind, protoreflect.Fixed64Kind:
+		b = protowire.AppendTag(b, number, protowire.VarintType)
+		return protowire.AppendVarint(b, value.Uint())
+	case protoreflect.FloatKind:
+		b = protowire.AppendTag(b, number, protowire.Fixed32Type)
+		return protowire.AppendFixed32(b, math.Float32bits(float32(value.Float())))
+	case protoreflect.DoubleKind:
+		b = protowire.AppendTag(b, number, protowire.Fixed64Type)
+		return protowire.AppendFixed64(b, math.Float64bits(value.Float()))
+	}
+	return b
+}
+
+// decoder restores messages from their encoding against the tables of an artifact.
+type decoder struct {
+	strings []string
+	types   [][]byte
+}
+
+// decodeMessage decodes the encoding of a message into it.
+func (d *decoder) decodeMessage(b []byte, message protoreflect.Message) error {
+	for len(b) > 0 {
+		number, wireType, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return ErrMalformed
+		}
+		b = b[n:]
+
+		field := message.Descriptor().Fields().ByNumber(number)
+		if field == nil {
+			return fmt.Errorf("%w: unknown field %d of %s", ErrMalformed, number, message.Descriptor().FullName())
+		}
+
+		switch {
+		case field.IsMap():
+			entry, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return ErrMalformed
+			}
+			b = b[n:]
+			if err := d.decodeMapEntry(entry, field, message.Mutable(field).Map()); err != nil {
+				return err
+			}
+		case field.IsList():
+			list := message.Mutable(field).List()
+			var element protoreflect.Value
+			if field.Kind() == protoreflect.MessageKind || field.Kind() == protoreflect.GroupKind {
+				element = list.NewElement()
+			}
+			value, n, err := d.decodeValue(b, wireType, field, element)
+			if err != nil {
+				return err
+			}
+			b = b[n:]
+			list.Append(value)
+		default:
+			var current protoreflect.Value
+			if field.Kind() == protoreflect.MessageKind || field.Kind() == protoreflect.GroupKind {
+				current = message.Mutable(field)
+			}
+			value, n, err := d.decodeValue(b, wireType, field, current)
+			if err != nil {
+				return err
+			}
+			b = b[n:]
+			message.Set(field, value)
+		}
+	}
+	return nil
+}
+
+// decodeMapEntry decodes a map entry into the map.
+func (d *decoder) decodeMapEntry(b []byte, field protoreflect.FieldDescriptor, entries protoreflect.Map) error {
+	key, value := field.MapKey().Default(), field.MapValue().Default()
+	if field.MapValue().Kind() == protoreflect.MessageKind || field.MapValue().Kind() == protoreflect.GroupKind {
+		value = entries.NewValue()
+	}
+
+	for len(b) > 0 {
+		number, wireType, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return ErrMalformed
+		}
+		b = b[n:]
+
+		var err error
+		switch number {
+		case mapKeyField:
+			key, n, err = d.decodeValue(b, wireType, field.MapKey(), protoreflect.Value{})
+		case mapValueField:
+			value, n, err = d.decodeValue(b, wireType, field.MapValue(), value)
+		default:
+			return ErrMalformed
+		}
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+
+	entries.Set(key.MapKey(), value)
+	return nil
+}
+
+// decodeValue decodes a single value of the field. Message values are decoded into current, which must
+// be a valid mutable message value for message fields.
+func (d *decoder) decodeValue(b []byte, wireType protowire.Type, field protoreflect.FieldDescriptor, current protoreflect.Value) (protoreflect.Value, int, error) {
+	switch field.Kind() {
+	case protoreflect.StringKind:
+		index, n := protowire.ConsumeVarint(b)
+		if n < 0 || wireType != protowire.VarintType || index >= uint64(len(d.strings)) {
+			return protoreflect.Value{}, 0, ErrMalformed
+		}
+		return protoreflect.ValueOfString(d.strings[index]), n, nil
+	case protoreflect.BytesKind:
+		value, n := protowire.ConsumeBytes(b)
+		if n < 0 || wireType != protowire.BytesType {
+			return protoreflect.Value{}, 0, ErrMalformed
+		}
+		return protoreflect.ValueOfBytes(append([]byte{}, value...)), n, nil
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		var encoded []byte
+		var n int
+		switch wireType {
+		case protowire.VarintType:
+			var index uint64
+			index, n = protowire.ConsumeVarint(b)
+			if n < 0 || index >= uint64(len(d.types)) {
+				return protoreflect.Value{}, 0, ErrMalformed
+			}
+			encoded = d.types[index]
+		case protowire.BytesType:
+			encoded, n = protowire.ConsumeBytes(b)
+			if n < 0 {
+				return protoreflect.Value{}, 0, ErrMalformed
+			}
+		default:
+			return protoreflect.Value{}, 0, ErrMalformed
+		}
+		if err := d.decodeMessage(encoded, current.Message()); err != nil {
+			return protoreflect.Value{}, 0, err
+		}
+		return current, n, nil
+	case protoreflect.FloatKind:
+		value, n := protowire.ConsumeFixed32(b)
+		if n < 0 || wireType != protowire.Fixed32Type {
+			return protoreflect.Value{}, 0, ErrMalformed
+		}
+		return protoreflect.ValueOfFloat32(math.Float32frombits(value)), n, nil
+	case protoreflect.DoubleKind:
+		value, n := protowire.ConsumeFixed64(b)
+		if n < 0 || wireType != protowire.Fixed64Type {
+			return protoreflect.Value{}, 0, ErrMalformed
+		}
+		return protoreflect.ValueOfFloat64(math.Float64frombits(value)), n, nil
+	}
+
+	value, n := protowire.ConsumeVarint(b)
+	if n < 0 || wireType != protowire.VarintType {
+		return protoreflect.Value{}, 0, ErrMalformed
+	}
+
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		return protoreflect.ValueOfBool(protowire.DecodeBool(value)), n, nil
+	case protoreflect.EnumKind:
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(int32(value))), n, nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return protoreflect.ValueOfInt32(int32(protowire.DecodeZigZag(value))), n, nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return protoreflect.ValueOfInt64(protowire.DecodeZigZag(value)), n, nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return protoreflect.ValueOfUint32(uint32(value)), n, nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return protoreflect.ValueOfUint64(value), n, nil
+	}
+
+	return protoreflect.Value{}, 0, fmt.Errorf("%w: unsupported kind %s", ErrMalformed, field.Kind())
+}
@@ -0,0 +1,10 @@
+// Package artifacts bundles the protocol buffer exports of many contracts into a single artifact with
+// session-level string and type tables. Every string of the exported messages, such as names, type
+// identifiers and source paths, is stored once in the string table and referenced by index, and every
+// type description is stored once in the type table. Corpora dominated by copies of the same libraries,
+// such as OpenZeppelin, shrink dramatically compared to marshaling every export on its own.
+//
+// A Session collects the exports, typically the results of ast.ASTBuilder.ToProto and ir.Builder.ToProto,
+// and produces an Artifact. Artifacts encode to a compact binary form and restore every export losslessly
+// into its original message type.
+package artifacts
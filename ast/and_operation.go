@@ -50,9 +50,10 @@ func (f *AndOperation) GetSrc() SrcNode {
 	return f.Src
 }
 
-// GetTypeDescription returns the type description associated with the AndOperation.
+// GetTypeDescription returns the type description associated with the AndOperation, which is
+// always bool.
 func (f *AndOperation) GetTypeDescription() *TypeDescription {
-	return f.TypeDescriptions[0]
+	return boolType()
 }
 
 // GetNodes returns the child nodes of the AndOperation.
@@ -1,6 +1,8 @@
 package ast
 
 import (
+	"github.com/goccy/go-json"
+
 	ast_pb "github.com/unpackdev/protos/dist/go/ast"
 )
 
@@ -16,6 +18,9 @@ type BaseContract struct {
 	Src SrcNode `json:"src"`
 	// BaseName is the name of the base contract.
 	BaseName *BaseContractName `json:"base_name"`
+	// Arguments are the arguments given to the base constructor in the inheritance specifier, as in
+	// contract Token is ERC20("Token", "TKN").
+	Arguments []Node[NodeType] `json:"arguments,omitempty"`
 }
 
 // GetId returns the unique identifier of the base contract.
@@ -39,6 +44,71 @@ func (b *BaseContract) GetBaseName() *BaseContractName {
 	return b.BaseName
 }
 
+// GetArguments returns the arguments given to the base constructor in the inheritance specifier. It is
+// empty when the arguments are given in the constructor instead, or when there are none.
+func (b *BaseContract) GetArguments() []Node[NodeType] {
+	return b.Arguments
+}
+
+// UnmarshalJSON unmarshals the base contract, including the argument nodes.
+func (b *BaseContract) UnmarshalJSON(data []byte) error {
+	var tempMap map[string]json.RawMessage
+	if err := json.Unmarshal(data, &tempMap); err != nil {
+		return err
+	}
+
+	if id, ok := tempMap["id"]; ok {
+		if err := json.Unmarshal(id, &b.Id); err != nil {
+			return err
+		}
+	}
+
+	if nodeType, ok := tempMap["node_type"]; ok {
+		if err := json.Unmarshal(nodeType, &b.NodeType); err != nil {
+			return err
+		}
+	}
+
+	if src, ok := tempMap["src"]; ok {
+		if err := json.Unmarshal(src, &b.Src); err != nil {
+			return err
+		}
+	}
+
+	if baseName, ok := tempMap["base_name"]; ok {
+		if err := json.Unmarshal(baseName, &b.BaseName); err != nil {
+			return err
+		}
+	}
+
+	if arguments, ok := tempMap["arguments"]; ok {
+		var nodes []json.RawMessage
+		if err := json.Unmarshal(arguments, &nodes); err != nil {
+			return err
+		}
+
+		for _, tempNode := range nodes {
+			var tempNodeMap map[string]json.RawMessage
+			if err := json.Unmarshal(tempNode, &tempNodeMap); err != nil {
+				return err
+			}
+
+			var tempNodeType ast_pb.NodeType
+			if err := json.Unmarshal(tempNodeMap["node_type"], &tempNodeType); err != nil {
+				return err
+			}
+
+			node, err := unmarshalNode(tempNode, tempNodeType)
+			if err != nil {
+				return err
+			}
+			b.Arguments = append(b.Arguments, node)
+		}
+	}
+
+	return nil
+}
+
 // ToProto returns the protobuf representation of the base contract.
 func (b *BaseContract) ToProto() *ast_pb.BaseContract {
 	return &ast_pb.BaseContract{
@@ -107,3 +177,19 @@ func (b *BaseContractName) ToProto() *ast_pb.BaseContractName {
 		ReferencedDeclaration: b.GetReferencedDeclaration(),
 	}
 }
+
+// baseContractArguments returns the arguments given to base constructors in the inheritance specifiers
+// of a contract. They are not among the child nodes of the contract, so walks resolving references and
+// types visit them explicitly, in the scope of the contract.
+func baseContractArguments(node Node[NodeType]) []Node[NodeType] {
+	inherits, ok := node.(interface{ GetBaseContracts() []*BaseContract })
+	if !ok {
+		return nil
+	}
+
+	var toReturn []Node[NodeType]
+	for _, base := range inherits.GetBaseContracts() {
+		toReturn = append(toReturn, base.GetArguments()...)
+	}
+	return toReturn
+}
@@ -50,9 +50,13 @@ func (f *BitAndOperation) GetSrc() SrcNode {
 	return f.Src
 }
 
-// GetTypeDescription returns the type description associated with the BitAndOperation.
+// GetTypeDescription returns the type description associated with the BitAndOperation, which is the
+// type both operands implicitly convert to.
 func (f *BitAndOperation) GetTypeDescription() *TypeDescription {
-	return f.TypeDescriptions[0]
+	if len(f.TypeDescriptions) < 2 {
+		return f.TypeDescriptions[0]
+	}
+	return commonType(f.TypeDescriptions[0], f.TypeDescriptions[1])
 }
 
 // GetNodes returns the child nodes of the BitAndOperation.
@@ -50,9 +50,13 @@ func (f *BitOrOperation) GetSrc() SrcNode {
 	return f.Src
 }
 
-// GetTypeDescription returns the type description associated with the BitOrOperation.
+// GetTypeDescription returns the type description associated with the BitOrOperation, which is the
+// type both operands implicitly convert to.
 func (f *BitOrOperation) GetTypeDescription() *TypeDescription {
-	return f.TypeDescriptions[0]
+	if len(f.TypeDescriptions) < 2 {
+		return f.TypeDescriptions[0]
+	}
+	return commonType(f.TypeDescriptions[0], f.TypeDescriptions[1])
 }
 
 // GetNodes returns the child nodes of the BitOrOperation.
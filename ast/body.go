@@ -23,6 +23,7 @@ type BodyNode struct {
 	Implemented bool             `json:"implemented"` // Implemented indicates whether the function is implemented.
 	Statements  []Node[NodeType] `json:"statements"`  // Statements is the list of AST nodes in the body.
 	TypedProto  bool             `json:"-"`           // TypedProto indicates whether the node should use TypedStruct as proto descriptor.
+	lazy        *lazyBody        // lazy loads the statements of a body left out of the parse tree, see ASTBuilder.SetLazyParser.
 }
 
 // NewBodyNode creates a new BodyNode with the provided ASTBuilder.
@@ -83,6 +84,9 @@ func (b *BodyNode) GetTypeDescription() *TypeDescription {
 
 // GetNodes returns the nodes associated with the body node.
 func (b *BodyNode) GetNodes() []Node[NodeType] {
+	if b == nil {
+		return nil
+	}
 	return b.Statements
 }
 
@@ -174,12 +178,7 @@ func (b *BodyNode) ToProto() NodeType {
 		return NewTypedStruct(&proto, "Block")
 	}
 
-	switch b.NodeType {
-	case ast_pb.NodeType_UNCHECKED_BLOCK:
-		return NewTypedStruct(&proto, "Block")
-	default:
-		return &proto
-	}
+	return &proto
 }
 
 // ParseDefinitions is a method of the BodyNode struct. It parses the definitions of a contract body element context.
@@ -281,50 +280,52 @@ func (b *BodyNode) ParseBlock(
 	// This is a basic approach and it's not 100% correct, but it's good enough for now.
 	b.Implemented = len(bodyCtx.GetChildren()) > 0
 
-	for _, statementCtx := range bodyCtx.AllStatement() {
-		for _, child := range statementCtx.GetChildren() {
-			b.parseStatements(unit, contractNode, parentNode, child)
-		}
-	}
+	b.parseBlockChildren(unit, contractNode, parentNode, bodyCtx)
 
 	return b
 }
 
-// ParseUncheckedBlock is a method of the BodyNode struct. It parses an unchecked block context.
-// It takes a source unit, a contract node, a function node, and an unchecked block context as arguments.
-// It sets the node type of the BodyNode to UNCHECKED_BLOCK and sets its source node.
-// It then iterates over all the statements in the block context and parses each one by calling the parseStatements helper function.
-// It finally returns the BodyNode itself.
-func (b *BodyNode) ParseUncheckedBlock(
+// parseBlockChildren parses the statements and unchecked blocks of a block context in source order.
+// Unchecked blocks are kept as dedicated UncheckedBlock nodes rather than being folded into the body.
+func (b *BodyNode) parseBlockChildren(
 	unit *SourceUnit[Node[ast_pb.SourceUnit]],
 	contractNode Node[NodeType],
 	fnNode Node[NodeType],
-	bodyCtx parser.IUncheckedBlockContext,
-) Node[NodeType] {
-	b.NodeType = ast_pb.NodeType_UNCHECKED_BLOCK
-	b.Src = SrcNode{
-		Line:        int64(bodyCtx.GetStart().GetLine()),
-		Column:      int64(bodyCtx.GetStart().GetColumn()),
-		Start:       int64(bodyCtx.GetStart().GetStart()),
-		End:         int64(bodyCtx.GetStop().GetStop()),
-		Length:      int64(bodyCtx.GetStop().GetStop() - bodyCtx.GetStart().GetStart() + 1),
-		ParentIndex: contractNode.GetId(),
-	}
-
-	for _, statementCtx := range bodyCtx.Block().AllStatement() {
-		for _, child := range statementCtx.GetChildren() {
-			b.parseStatements(unit, contractNode, fnNode, child)
+	bodyCtx parser.IBlockContext,
+) {
+	for _, childCtx := range bodyCtx.GetChildren() {
+		switch childCtx := childCtx.(type) {
+		case *parser.StatementContext:
+			for _, child := range childCtx.GetChildren() {
+				b.parseStatements(unit, contractNode, fnNode, child)
+			}
+		case *parser.UncheckedBlockContext:
+			unchecked := NewUncheckedBlock(b.ASTBuilder)
+			b.Statements = append(b.Statements, unchecked.Parse(
+				unit, contractNode, fnNode, b, childCtx,
+			))
 		}
 	}
+}
 
-	return b
+// ParseUncheckedBlock is a method of the BodyNode struct. It parses an unchecked block context into an
+// UncheckedBlock node enclosed by the body.
+//
+// Deprecated: Unchecked blocks are dedicated nodes, use NewUncheckedBlock and UncheckedBlock.Parse instead.
+func (b *BodyNode) ParseUncheckedBlock(
+	unit *SourceUnit[Node[ast_pb.SourceUnit]],
+	contractNode Node[NodeType],
+	fnNode Node[NodeType],
+	bodyCtx parser.IUncheckedBlockContext,
+) Node[NodeType] {
+	return NewUncheckedBlock(b.ASTBuilder).Parse(unit, contractNode, fnNode, b, bodyCtx)
 }
 
 func (b *BodyNode) ToString() string {
 	return ""
 }
 
-// parseStatements is a helper function for the ParseBlock and ParseUncheckedBlock methods.
+// parseStatements is a helper function for the ParseBlock method and UncheckedBlock nodes.
 // It takes a source unit, a contract node, a function node, and a child context as arguments.
 // It checks the type of the child context and based on its type, it creates a new node of the corresponding type and parses it.
 // If the type of the child context is unknown, it panics and prints an error message.
@@ -3,6 +3,8 @@ package ast
 import (
 	"bytes"
 	"context"
+	"sync"
+
 	"github.com/goccy/go-json"
 
 	ast_pb "github.com/unpackdev/protos/dist/go/ast"
@@ -33,6 +35,14 @@ type ASTBuilder struct {
 	currentVariables            []Node[NodeType]
 	globalDefinitions           []Node[NodeType]
 	currentImports              []Node[NodeType]
+	symbolTable                 *SymbolTable         // symbolTable is built lazily by GetSymbolTable.
+	nodeIndex                   *NodeIndex           // nodeIndex is built lazily by GetNodeIndex.
+	lazyParser                  *solgo.Parser        // lazyParser loads bodies left out of the parse tree, see SetLazyParser.
+	lazyErrors                  []error              // lazyErrors are the errors found in lazily loaded bodies.
+	lazyMutex                   sync.Mutex           // lazyMutex serializes the loading of lazy bodies, which updates the builder.
+	sourceFidelity              bool                 // sourceFidelity keeps the parsed input, see SetSourceFidelity.
+	snapshot                    *sourceSnapshot      // snapshot is the parsed input, taken lazily when source fidelity is enabled.
+	replacements                []*sourceReplacement // replacements are the texts set by ReplaceSource and ReplaceSourceRange.
 }
 
 // NewAstBuilder creates a new ASTBuilder with the provided Solidity parser and source code.
@@ -105,10 +115,22 @@ func (b *ASTBuilder) ResolveReferences() []error {
 		return err
 	}
 
-	// Cleanup the builder so garbage collector and memory usage is minimized...
-	b.GarbageCollect()
+	// References changed, so the symbol table has to be rebuilt before it is used to point
+	// identifiers and member accesses at their declarations.
+	b.symbolTable = nil
+	b.nodeIndex = nil
+	b.resolveDeclarationReferences()
+	b.resolveExpressionTypes()
+	errs := b.resolveLinearizations()
+	b.resolveImplementations()
+
+	// Cleanup the builder so garbage collector and memory usage is minimized, unless bodies
+	// loaded later on still have to be resolved against the declarations...
+	if !b.IsLazy() {
+		b.GarbageCollect()
+	}
 
-	return nil
+	return errs
 }
 
 // ImportFromJSON imports the AST from a JSON byte array.
@@ -125,6 +147,8 @@ func (b *ASTBuilder) ImportFromJSON(ctx context.Context, jsonBytes []byte) (*Roo
 	}
 
 	b.tree.SetRoot(toReturn)
+	b.symbolTable = nil
+	b.nodeIndex = nil
 
 	return toReturn, nil
 }
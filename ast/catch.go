@@ -38,6 +38,7 @@ type CatchStatement struct {
 func NewCatchClauseStatement(b *ASTBuilder) *CatchStatement {
 	return &CatchStatement{
 		ASTBuilder: b,
+		Id:         b.GetNextID(),
 		NodeType:   ast_pb.NodeType_TRY_CATCH_CLAUSE,
 		Kind:       ast_pb.NodeType_CATCH,
 	}
@@ -86,16 +87,61 @@ func (t *CatchStatement) GetTypeDescription() *TypeDescription {
 	}
 }
 
-// GetNodes returns the statements in the body of the 'catch' clause.
+// GetNodes returns the parameters and the statements in the body of the 'catch' clause.
 func (t *CatchStatement) GetNodes() []Node[NodeType] {
-	return t.Body.Statements
+	toReturn := make([]Node[NodeType], 0)
+	if t.Parameters != nil {
+		toReturn = append(toReturn, t.Parameters.GetNodes()...)
+	}
+	if t.Body != nil {
+		toReturn = append(toReturn, t.Body.GetNodes()...)
+	}
+	return toReturn
 }
 
-// GetName returns the name of the exception variable in the 'catch' clause, if any.
+// GetName returns the name of the error in the 'catch' clause, such as 'Error' or 'Panic', if any.
 func (t *CatchStatement) GetName() string {
 	return t.Name
 }
 
+// IsErrorClause returns true if the clause is a 'catch Error(string memory reason)' clause,
+// which is executed when the revert was caused by require, revert("reason") or a failed assertion in
+// pre-0.8.0 code.
+func (t *CatchStatement) IsErrorClause() bool {
+	return t.Name == "Error"
+}
+
+// IsPanicClause returns true if the clause is a 'catch Panic(uint errorCode)' clause, which is
+// executed on panics such as failed assertions, arithmetic overflows or division by zero.
+func (t *CatchStatement) IsPanicClause() bool {
+	return t.Name == "Panic"
+}
+
+// IsLowLevelClause returns true if the clause is a 'catch (bytes memory lowLevelData)' clause,
+// which receives the raw revert data of any error.
+func (t *CatchStatement) IsLowLevelClause() bool {
+	return t.Name == "" && t.Parameters != nil && len(t.Parameters.GetParameters()) > 0
+}
+
+// IsCatchAll returns true if the clause is a bare 'catch { ... }' clause that handles any error
+// without inspecting it.
+func (t *CatchStatement) IsCatchAll() bool {
+	return t.Name == "" && (t.Parameters == nil || len(t.Parameters.GetParameters()) == 0)
+}
+
+// GetSignature returns the canonical signature of the error the clause handles,
+// 'Error(string)' or 'Panic(uint256)'. It returns an empty string for low-level and catch-all clauses.
+func (t *CatchStatement) GetSignature() string {
+	switch {
+	case t.IsErrorClause():
+		return "Error(string)"
+	case t.IsPanicClause():
+		return "Panic(uint256)"
+	default:
+		return ""
+	}
+}
+
 // MarshalJSON marshals the CatchStatement node into a JSON byte slice.
 func (t *CatchStatement) UnmarshalJSON(data []byte) error {
 	var tempMap map[string]json.RawMessage
@@ -151,13 +197,19 @@ func (t *CatchStatement) UnmarshalJSON(data []byte) error {
 // ToProto returns the protobuf representation of the 'catch' clause.
 func (t *CatchStatement) ToProto() NodeType {
 	proto := ast_pb.Catch{
-		Id:         t.GetId(),
-		Name:       t.GetName(),
-		NodeType:   t.GetType(),
-		Kind:       t.GetKind(),
-		Src:        t.GetSrc().ToProto(),
-		Parameters: t.GetParameters().ToProto(),
-		Body:       t.GetBody().ToProto().(*ast_pb.Body),
+		Id:       t.GetId(),
+		Name:     t.GetName(),
+		NodeType: t.GetType(),
+		Kind:     t.GetKind(),
+		Src:      t.GetSrc().ToProto(),
+	}
+
+	if t.GetParameters() != nil {
+		proto.Parameters = t.GetParameters().ToProto()
+	}
+
+	if t.GetBody() != nil {
+		proto.Body = t.GetBody().ToProto().(*ast_pb.Body)
 	}
 
 	return NewTypedStruct(&proto, "Catch")
@@ -174,6 +226,7 @@ func (t *CatchStatement) Parse(
 ) Node[NodeType] {
 	t.Src = SrcNode{
 		Line:        int64(ctx.GetStart().GetLine()),
+		Column:      int64(ctx.GetStart().GetColumn()),
 		Start:       int64(ctx.GetStart().GetStart()),
 		End:         int64(ctx.GetStop().GetStop()),
 		Length:      int64(ctx.GetStop().GetStop() - ctx.GetStart().GetStart() + 1),
@@ -197,14 +250,6 @@ func (t *CatchStatement) Parse(
 		bodyNode := NewBodyNode(t.ASTBuilder, false)
 		bodyNode.ParseBlock(unit, contractNode, t, ctx.Block())
 		t.Body = bodyNode
-
-		if ctx.Block().AllUncheckedBlock() != nil {
-			for _, uncheckedCtx := range ctx.Block().AllUncheckedBlock() {
-				bodyNode := NewBodyNode(t.ASTBuilder, false)
-				bodyNode.ParseUncheckedBlock(unit, contractNode, t, uncheckedCtx)
-				t.Body.Statements = append(t.Body.Statements, bodyNode)
-			}
-		}
 	}
 
 	return t
@@ -51,9 +51,13 @@ func (f *Conditional) GetSrc() SrcNode {
 	return f.Src
 }
 
-// GetTypeDescription returns the type description associated with the Conditional.
-// TODO: Perhaps attaching at the end type descriptions to the conditional?
+// GetTypeDescription returns the type description associated with the Conditional, which is the
+// common type of both branches once expression types are resolved.
 func (f *Conditional) GetTypeDescription() *TypeDescription {
+	if f.TypeDescription != nil {
+		return f.TypeDescription
+	}
+
 	return &TypeDescription{
 		TypeString:     "conditional",
 		TypeIdentifier: "$_t_conditional",
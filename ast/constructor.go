@@ -118,6 +118,7 @@ func (c *Constructor) GetReturnParameters() *ParameterList {
 
 // GetBody returns the body of the constructor.
 func (c *Constructor) GetBody() *BodyNode {
+	c.Body.loadLazily()
 	return c.Body
 }
 
@@ -304,17 +305,12 @@ func (c *Constructor) Parse(
 	}
 
 	if ctx.Block() != nil && !ctx.Block().IsEmpty() {
-		bodyNode := NewBodyNode(c.ASTBuilder, false)
-		bodyNode.ParseBlock(unit, contractNode, c, ctx.Block())
-		c.Body = bodyNode
-
-		if ctx.Block().AllUncheckedBlock() != nil {
-			for _, uncheckedCtx := range ctx.Block().AllUncheckedBlock() {
-				bodyNode := NewBodyNode(c.ASTBuilder, false)
-				bodyNode.ParseUncheckedBlock(unit, contractNode, c, uncheckedCtx)
-				c.Body.Statements = append(c.Body.Statements, bodyNode)
-			}
+		bodyNode := c.parseLazyBlock(unit, contractNode, c, ctx.Block())
+		if bodyNode == nil {
+			bodyNode = NewBodyNode(c.ASTBuilder, false)
+			bodyNode.ParseBlock(unit, contractNode, c, ctx.Block())
 		}
+		c.Body = bodyNode
 	}
 
 	c.currentFunctions = append(c.currentFunctions, c)
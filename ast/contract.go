@@ -25,6 +25,7 @@ type Contract struct {
 	LinearizedBaseContracts []int64          `json:"linearized_base_contracts"`
 	BaseContracts           []*BaseContract  `json:"base_contracts"`
 	ContractDependencies    []int64          `json:"contract_dependencies"`
+	unimplemented           []Node[NodeType] // Members without an implementation, set once linearizations are resolved.
 }
 
 // NewContractDefinition creates a new instance of Contract.
@@ -86,6 +87,12 @@ func (c *Contract) IsFullyImplemented() bool {
 	return c.FullyImplemented
 }
 
+// GetUnimplementedMembers returns the functions and modifiers the contract ends up with, inherited ones
+// included, that have no implementation. It is empty until references are resolved.
+func (c *Contract) GetUnimplementedMembers() []Node[NodeType] {
+	return c.unimplemented
+}
+
 // GetNodes returns the child nodes of the Contract.
 func (c *Contract) GetNodes() []Node[NodeType] {
 	return c.Nodes
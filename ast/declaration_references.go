@@ -0,0 +1,591 @@
+package ast
+
+import (
+	"strings"
+
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+)
+
+// declarationResolver populates ReferencedDeclaration of identifiers and member access expressions
+// with the id of the declaring node, mirroring the referencedDeclaration field of solc's AST.
+type declarationResolver struct {
+	table *SymbolTable
+	calls map[*FunctionCallOption]*FunctionCall // Calls made with options, by their options node.
+}
+
+// newDeclarationResolver creates a declaration resolver looking declarations up in the symbol table.
+func newDeclarationResolver(table *SymbolTable) *declarationResolver {
+	return &declarationResolver{
+		table: table,
+		calls: make(map[*FunctionCallOption]*FunctionCall),
+	}
+}
+
+// resolveDeclarationReferences points identifiers and member accesses at the node declaring them,
+// using the scope aware symbol table. References without a declaration in the sources, such as
+// builtins (msg, block, abi) or members of elementary types, keep their current value.
+func (b *ASTBuilder) resolveDeclarationReferences() {
+	root := b.GetRoot()
+	if root == nil {
+		return
+	}
+
+	resolver := newDeclarationResolver(b.GetSymbolTable())
+
+	for _, node := range root.GetGlobalNodes() {
+		resolver.walk(node, nil)
+	}
+
+	for _, unit := range root.GetSourceUnits() {
+		resolver.walk(unit, nil)
+	}
+}
+
+// walk resolves the references of the node after those of its children, so that the base of a
+// member access is always resolved before the member itself.
+func (r *declarationResolver) walk(node Node[NodeType], parent Node[NodeType]) {
+	if isNilNode(node) {
+		return
+	}
+
+	if call, ok := node.(*FunctionCall); ok {
+		if option, ok := call.GetExpression().(*FunctionCallOption); ok {
+			r.calls[option] = call
+		}
+	}
+
+	for _, child := range node.GetNodes() {
+		r.walk(child, node)
+	}
+
+	for _, argument := range baseContractArguments(node) {
+		r.walk(argument, node)
+	}
+
+	switch n := node.(type) {
+	case *PrimaryExpression:
+		if n.GetName() == "" || n.GetValue() != "" {
+			return
+		}
+
+		candidates := r.table.resolveCandidates(n.GetId(), n.GetName())
+		if declaration := r.selectOverload(candidates, n, parent, nil); declaration != nil {
+			n.ReferencedDeclaration = declaration.GetId()
+			if fn, ok := declaration.(*Function); ok {
+				n.TypeDescription = fn.GetTypeDescription()
+				r.bindCall(fn, n, parent)
+			}
+		}
+	case *MemberAccessExpression:
+		candidates, bound := r.resolveMember(n), Node[NodeType](nil)
+		if len(candidates) == 0 {
+			// Library functions attached with `using L for T` take the base expression as their first argument.
+			candidates, bound = r.resolveAttached(n), n.GetExpression()
+		}
+		if declaration := r.selectOverload(candidates, n, parent, bound); declaration != nil {
+			n.ReferencedDeclaration = declaration.GetId()
+			if fn, ok := declaration.(*Function); ok {
+				n.TypeDescription = fn.GetTypeDescription()
+				r.bindCall(fn, n, parent)
+			}
+		}
+	}
+}
+
+// bindCall points the call of the function, if the node is the called expression, at the function. The
+// references set while parsing depend on which declaration of the name is found first.
+func (r *declarationResolver) bindCall(fn *Function, node Node[NodeType], parent Node[NodeType]) {
+	call := r.callOf(node, parent)
+	if call == nil {
+		return
+	}
+
+	if option, ok := call.GetExpression().(*FunctionCallOption); ok {
+		option.ReferencedDeclaration = fn.GetId()
+		option.TypeDescription = fn.GetTypeDescription()
+	}
+	call.ReferencedDeclaration = fn.GetId()
+	call.TypeDescription = fn.GetTypeDescription()
+}
+
+// callOf returns the call of the node, if the node is the called expression, either directly or
+// through call options such as {value: ...}.
+func (r *declarationResolver) callOf(node Node[NodeType], parent Node[NodeType]) *FunctionCall {
+	switch p := parent.(type) {
+	case *FunctionCall:
+		if p.GetExpression() == node {
+			return p
+		}
+	case *FunctionCallOption:
+		if p.GetExpression() == node {
+			return r.calls[p]
+		}
+	}
+	return nil
+}
+
+// resolveMember returns the declarations the member access may refer to.
+func (r *declarationResolver) resolveMember(member *MemberAccessExpression) []Node[NodeType] {
+	name := member.GetMemberName()
+
+	// super skips the members of the current contract and continues along its C3 linearization.
+	if base, ok := member.GetExpression().(*PrimaryExpression); ok && base.GetName() == "super" {
+		if scope := r.contractScopeOf(base.GetId()); scope != nil {
+			return r.table.lookupSuper(scope, name)
+		}
+		return nil
+	}
+
+	switch container := r.containerOf(member.GetExpression()).(type) {
+	case *Contract, *Interface, *Library:
+		if scope := r.table.GetScope(container.GetId()); scope != nil {
+			return scope.lookupMember(name)
+		}
+	case *StructDefinition:
+		for _, field := range container.GetMembers() {
+			if field.GetName() == name {
+				return []Node[NodeType]{field}
+			}
+		}
+	case *EnumDefinition:
+		for _, value := range container.GetMembers() {
+			if value.GetName() == name {
+				return []Node[NodeType]{value}
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveAttached returns the library functions named like the member that the `using L for T`
+// directives of the enclosing contract attach to the type of the base expression. Directives of the
+// bases are used when the contract has none attaching the member, as solc did before 0.7.
+func (r *declarationResolver) resolveAttached(member *MemberAccessExpression) []Node[NodeType] {
+	scope := r.contractScopeOf(member.GetId())
+	if scope == nil || isNilNode(member.GetExpression()) {
+		return nil
+	}
+
+	baseType := r.valueTypeOf(member.GetExpression())
+	visited := make(map[*Scope]bool)
+	for queue := []*Scope{scope}; len(queue) > 0; queue = queue[1:] {
+		current := queue[0]
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+
+		toReturn := make([]Node[NodeType], 0)
+		seen := make(map[int64]bool)
+		for _, node := range current.GetNode().GetNodes() {
+			using, ok := node.(*UsingDirective)
+			if !ok || using.GetLibraryName() == nil || !usingAppliesTo(using, baseType) {
+				continue
+			}
+
+			library, ok := r.resolvePath(member.GetId(), using.GetLibraryName().Name).(*Library)
+			if !ok {
+				continue
+			}
+			libraryScope := r.table.GetScope(library.GetId())
+			if libraryScope == nil {
+				continue
+			}
+
+			for _, declaration := range libraryScope.GetDeclarations(member.GetMemberName()) {
+				if _, ok := declaration.(*Function); ok && !seen[declaration.GetId()] {
+					seen[declaration.GetId()] = true
+					toReturn = append(toReturn, declaration)
+				}
+			}
+		}
+		if len(toReturn) > 0 {
+			return toReturn
+		}
+
+		queue = append(queue, current.GetBases()...)
+	}
+
+	return nil
+}
+
+// usingAppliesTo reports whether the using directive attaches its library to values of the type,
+// which it does for every type with `using L for *`.
+func usingAppliesTo(using *UsingDirective, typeDescription *TypeDescription) bool {
+	typeName := using.GetTypeName()
+	if typeName == nil || typeName.GetName() == "*" {
+		return true
+	}
+	return sameType(using.GetTypeDescription(), typeDescription)
+}
+
+// containerOf returns the contract, library, interface, struct or enum whose members are accessed
+// through the expression, or nil when it cannot be determined.
+func (r *declarationResolver) containerOf(expr Node[NodeType]) Node[NodeType] {
+	switch e := expr.(type) {
+	case *PrimaryExpression:
+		if e.GetName() == "this" {
+			if scope := r.contractScopeOf(e.GetId()); scope != nil {
+				return scope.GetNode()
+			}
+			return nil
+		}
+	case *FunctionCall:
+		// Type conversions such as IERC20(token) expose the members of the converted type.
+		if declaration := r.declarationOf(e.GetExpression()); isMemberContainer(declaration) {
+			if _, isStruct := declaration.(*StructDefinition); !isStruct {
+				return declaration
+			}
+		}
+		return nil
+	}
+
+	if declaration := r.declarationOf(expr); isMemberContainer(declaration) {
+		return declaration
+	}
+
+	typeName, from := r.typeNameOf(expr)
+	return r.resolveTypeName(from, typeName)
+}
+
+// declarationOf returns the node declaring the identifier or member access expression.
+func (r *declarationResolver) declarationOf(expr Node[NodeType]) Node[NodeType] {
+	var refId int64
+	switch e := expr.(type) {
+	case *PrimaryExpression:
+		refId = e.GetReferencedDeclaration()
+	case *MemberAccessExpression:
+		refId = e.GetReferencedDeclaration()
+	default:
+		return nil
+	}
+
+	if refId <= 0 || refId == expr.GetId() {
+		return nil
+	}
+
+	return r.table.nodes[refId]
+}
+
+// typeNameOf returns the declared type of the value the expression evaluates to, together with the
+// id of the node the type name has to be resolved from.
+func (r *declarationResolver) typeNameOf(expr Node[NodeType]) (*TypeName, int64) {
+	switch e := expr.(type) {
+	case *PrimaryExpression, *MemberAccessExpression:
+		declaration := r.declarationOf(e)
+		if typed, ok := declaration.(interface{ GetTypeName() *TypeName }); ok {
+			return typed.GetTypeName(), declaration.GetId()
+		}
+	case *IndexAccess:
+		// Mapping type names are not always tagged as such once resolved, so the value type is
+		// what identifies them.
+		typeName, from := r.typeNameOf(e.GetBaseExpression())
+		if typeName != nil && typeName.ValueType != nil {
+			return typeName.ValueType, from
+		}
+	}
+
+	return nil, 0
+}
+
+// resolveTypeName resolves a user defined type name, as seen from the node with the given id, to the
+// declaring contract, interface, library, struct or enum.
+func (r *declarationResolver) resolveTypeName(from int64, typeName *TypeName) Node[NodeType] {
+	if typeName == nil {
+		return nil
+	}
+
+	name := typeName.GetName()
+	if typeName.PathNode != nil && typeName.PathNode.Name != "" {
+		name = typeName.PathNode.Name
+	}
+
+	// Path nodes keep the first segment of qualified names only, Library for Library.Struct, whereas the
+	// type string has all of them.
+	if typeDescription := typeName.GetTypeDescription(); typeDescription != nil {
+		fields := strings.Fields(typeDescription.GetString())
+		if len(fields) > 1 && strings.HasPrefix(fields[1], name+".") {
+			switch fields[0] {
+			case "struct", "enum", "contract":
+				name = fields[1]
+			}
+		}
+	}
+
+	if declaration := r.resolvePath(from, name); isMemberContainer(declaration) {
+		return declaration
+	}
+	return nil
+}
+
+// resolvePath resolves a possibly qualified name, as seen from the node with the given id, to the
+// node declaring it. Qualified names such as Library.Struct are resolved one segment at a time.
+func (r *declarationResolver) resolvePath(from int64, name string) Node[NodeType] {
+	if name == "" || strings.ContainsAny(name, "[(") {
+		return nil
+	}
+
+	parts := strings.Split(name, ".")
+	declaration := r.table.ResolveIdentifier(from, parts[0])
+	for _, part := range parts[1:] {
+		scope := r.table.GetScope(nodeIdOf(declaration))
+		if scope == nil {
+			return nil
+		}
+		members := scope.lookupMember(part)
+		if len(members) == 0 {
+			return nil
+		}
+		declaration = members[0]
+	}
+
+	return declaration
+}
+
+// contractScopeOf returns the scope of the contract enclosing the node with the given id.
+func (r *declarationResolver) contractScopeOf(nodeId int64) *Scope {
+	for scope := r.table.GetScopeOf(nodeId); scope != nil; scope = scope.GetParent() {
+		if scope.GetKind() == ScopeContract {
+			return scope
+		}
+	}
+	return nil
+}
+
+// resolveCandidates returns every declaration of the name in the innermost scope, visible from the
+// node with the given id, that declares it. Overloaded functions yield more than one candidate.
+func (t *SymbolTable) resolveCandidates(nodeId int64, name string) []Node[NodeType] {
+	declaration := t.ResolveIdentifier(nodeId, name)
+	if declaration == nil {
+		return nil
+	}
+
+	if _, ok := declaration.(*Function); !ok {
+		return []Node[NodeType]{declaration}
+	}
+
+	for scope := t.nodeScopes[nodeId]; scope != nil; scope = scope.Parent {
+		if candidates := scope.lookupMember(name); len(candidates) > 0 {
+			return candidates
+		}
+	}
+
+	return []Node[NodeType]{declaration}
+}
+
+// lookupMember returns the declarations of the name in the scope or, failing that, in its bases.
+func (s *Scope) lookupMember(name string) []Node[NodeType] {
+	if declarations := s.GetDeclarations(name); len(declarations) > 0 {
+		return declarations
+	}
+	return s.lookupMemberInBases(name, make(map[*Scope]bool))
+}
+
+// lookupMemberInBases returns the declarations of the name in the closest base declaring it.
+func (s *Scope) lookupMemberInBases(name string, visited map[*Scope]bool) []Node[NodeType] {
+	for _, base := range s.bases {
+		if visited[base] {
+			continue
+		}
+		visited[base] = true
+
+		if declarations := base.GetDeclarations(name); len(declarations) > 0 {
+			return declarations
+		}
+		if declarations := base.lookupMemberInBases(name, visited); len(declarations) > 0 {
+			return declarations
+		}
+	}
+	return nil
+}
+
+// lookupSuper returns the declarations super.name refers to from the contract scope: those of the first
+// contract following it in its C3 linearization that implements the name, which in diamond shaped
+// hierarchies may be a sibling rather than a syntactic base. As for solc, functions without a body and
+// private functions are skipped.
+func (t *SymbolTable) lookupSuper(scope *Scope, name string) []Node[NodeType] {
+	if t.linearizations == nil {
+		t.linearizations = make(map[*Scope][]*Scope)
+	}
+
+	linearized, err := t.linearize(scope, make(map[*Scope]bool))
+	if err != nil {
+		return scope.lookupMemberInBases(name, make(map[*Scope]bool))
+	}
+
+	for _, base := range linearized[1:] {
+		declarations := make([]Node[NodeType], 0)
+		for _, declaration := range base.GetDeclarations(name) {
+			if fn, ok := declaration.(*Function); ok && (!fn.IsImplemented() || fn.GetVisibility() == ast_pb.Visibility_PRIVATE) {
+				continue
+			}
+			declarations = append(declarations, declaration)
+		}
+		if len(declarations) > 0 {
+			return declarations
+		}
+	}
+	return nil
+}
+
+// selectOverload picks the candidate referenced by the node. When the node is called, overloaded
+// functions are told apart by the number and types of the arguments, preferring the candidate whose
+// parameters match the argument types exactly over those the arguments implicitly convert to. The
+// bound expression, if any, is passed as the first argument, as for attached library functions.
+func (r *declarationResolver) selectOverload(candidates []Node[NodeType], node Node[NodeType], parent Node[NodeType], bound Node[NodeType]) Node[NodeType] {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	call := r.callOf(node, parent)
+	if call == nil || (len(candidates) == 1 && bound == nil) {
+		return candidates[0]
+	}
+
+	arguments := make([]*TypeDescription, 0, len(call.GetArguments())+1)
+	if bound != nil {
+		arguments = append(arguments, r.valueTypeOf(bound))
+	}
+	for _, argument := range call.GetArguments() {
+		arguments = append(arguments, r.valueTypeOf(argument))
+	}
+
+	var convertible, sameArity Node[NodeType]
+	for _, candidate := range candidates {
+		fn, ok := candidate.(*Function)
+		if !ok || fn.GetParameters() == nil || len(fn.GetParameters().GetParameters()) != len(arguments) {
+			continue
+		}
+		if sameArity == nil {
+			sameArity = candidate
+		}
+
+		exact, converts := true, true
+		for i, parameter := range fn.GetParameters().GetParameters() {
+			if !sameType(arguments[i], parameter.GetTypeDescription()) {
+				exact = false
+			}
+			if !convertibleType(arguments[i], parameter.GetTypeDescription()) {
+				converts = false
+			}
+		}
+
+		if exact {
+			return candidate
+		}
+		if converts && convertible == nil {
+			convertible = candidate
+		}
+	}
+
+	switch {
+	case convertible != nil:
+		return convertible
+	case sameArity != nil:
+		return sameArity
+	case bound != nil:
+		// Attached functions that cannot take the arguments are not what the member refers to.
+		return nil
+	}
+	return candidates[0]
+}
+
+// valueTypeOf returns the type of the value the expression evaluates to. Expression types are only
+// computed once references are resolved, so identifiers and member accesses take the type of their
+// declaration, and calls the type of the single value returned by the called function.
+func (r *declarationResolver) valueTypeOf(expr Node[NodeType]) *TypeDescription {
+	if isNilNode(expr) {
+		return nil
+	}
+
+	switch e := expr.(type) {
+	case *PrimaryExpression, *MemberAccessExpression:
+		declaration := r.declarationOf(e)
+		if _, ok := declaration.(*Function); !ok && !isNilNode(declaration) && declaration.GetTypeDescription() != nil {
+			return declaration.GetTypeDescription()
+		}
+	case *FunctionCall:
+		if fn, ok := r.declarationOf(e.GetExpression()).(*Function); ok && fn.GetReturnParameters() != nil {
+			if returns := fn.GetReturnParameters().GetParameters(); len(returns) == 1 {
+				return returns[0].GetTypeDescription()
+			}
+		}
+	}
+
+	return expr.GetTypeDescription()
+}
+
+// sameType reports whether the types are the same, ignoring data locations, payability and the
+// qualification of user defined type names. Unknown types are assumed to be the same.
+func sameType(left *TypeDescription, right *TypeDescription) bool {
+	leftType, rightType := normalizedTypeString(left), normalizedTypeString(right)
+	return leftType == "" || rightType == "" || leftType == rightType
+}
+
+// convertibleType reports whether a value of the first type implicitly converts to the second type:
+// constants convert to integer types, string literals to strings and bytes, and integers to integers
+// they fit in without losing information.
+func convertibleType(from *TypeDescription, to *TypeDescription) bool {
+	if sameType(from, to) {
+		return true
+	}
+
+	if _, ok := rationalValue(from); ok {
+		return isIntegerType(to)
+	}
+
+	if strings.HasPrefix(from.GetString(), "literal_string") {
+		target := normalizedTypeString(to)
+		return target == "string" || strings.HasPrefix(target, "bytes")
+	}
+
+	fromSigned, fromBits, fromOk := integerType(from)
+	toSigned, toBits, toOk := integerType(to)
+	return fromOk && toOk && implicitlyConvertible(fromSigned, fromBits, toSigned, toBits)
+}
+
+// normalizedTypeString returns the type string without data locations, payability and the struct,
+// contract and enum keywords, with user defined type names unqualified and integer aliases expanded.
+func normalizedTypeString(typeDescription *TypeDescription) string {
+	if typeDescription == nil {
+		return ""
+	}
+
+	parts := make([]string, 0)
+	for _, part := range strings.Fields(typeDescription.GetString()) {
+		switch part {
+		case "memory", "calldata", "storage", "pointer", "ref", "payable", "struct", "contract", "enum":
+			continue
+		case "uint":
+			part = "uint256"
+		case "int":
+			part = "int256"
+		case "byte":
+			part = "bytes1"
+		}
+		if index := strings.LastIndex(part, "."); index >= 0 && !strings.ContainsAny(part, "()") {
+			part = part[index+1:]
+		}
+		parts = append(parts, part)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// isMemberContainer reports whether members of the node can be accessed with a member access.
+func isMemberContainer(node Node[NodeType]) bool {
+	switch node.(type) {
+	case *Contract, *Interface, *Library, *StructDefinition, *EnumDefinition:
+		return true
+	}
+	return false
+}
+
+// nodeIdOf returns the id of the node, or zero for nil nodes.
+func nodeIdOf(node Node[NodeType]) int64 {
+	if isNilNode(node) {
+		return 0
+	}
+	return node.GetId()
+}
@@ -0,0 +1,237 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+)
+
+func TestReferencedDeclarations(t *testing.T) {
+	astBuilder := buildAstFromSourceForTest(t, "Registry", `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+library MathLib {
+    function add(uint256 a, uint256 b) internal pure returns (uint256) {
+        return a + b;
+    }
+}
+
+interface IToken {
+    function transfer(address to, uint256 amount) external returns (bool);
+}
+
+contract Base {
+    function hook() internal virtual {}
+}
+
+contract Registry is Base {
+    enum Status { Active, Paused }
+
+    struct Position {
+        address owner;
+        uint256 amount;
+    }
+
+    mapping(address => Position) public positions;
+    Position public last;
+    Status public status;
+
+    function open(uint256 amount) external {
+        Position storage position = positions[msg.sender];
+        position.amount = amount;
+        positions[msg.sender].owner = msg.sender;
+        last.amount = MathLib.add(amount, 1);
+        status = Status.Paused;
+        IToken(address(0)).transfer(msg.sender, amount);
+        this.close();
+        close(1);
+        super.hook();
+    }
+
+    function close() public {}
+
+    function close(uint256 id) public {}
+
+    function hook() internal override {}
+}
+`)
+
+	declarations := make(map[int64]Node[NodeType])
+	for _, nodeType := range []ast_pb.NodeType{
+		ast_pb.NodeType_FUNCTION_DEFINITION,
+		ast_pb.NodeType_VARIABLE_DECLARATION,
+		ast_pb.NodeType_STRUCT_DEFINITION,
+		ast_pb.NodeType_ENUM_DEFINITION,
+		ast_pb.NodeType_CONTRACT_DEFINITION,
+	} {
+		for _, node := range collectNodesForTest(astBuilder, nodeType) {
+			declarations[node.GetId()] = node
+		}
+	}
+	for _, node := range collectNodesForTest(astBuilder, ast_pb.NodeType_ENUM_DEFINITION) {
+		for _, member := range node.(*EnumDefinition).GetMembers() {
+			declarations[member.GetId()] = member
+		}
+	}
+	for _, node := range collectNodesForTest(astBuilder, ast_pb.NodeType_STRUCT_DEFINITION) {
+		for _, member := range node.(*StructDefinition).GetMembers() {
+			declarations[member.GetId()] = member
+		}
+	}
+
+	// describe returns the name of the referenced declaration and of the scope declaring it.
+	describe := func(refId int64) string {
+		declaration, ok := declarations[refId]
+		if !ok {
+			return ""
+		}
+		name := declaration.(interface{ GetName() string }).GetName()
+		if fn, ok := declaration.(*Function); ok {
+			parent := astBuilder.GetSymbolTable().GetScopeOf(fn.GetId()).GetNode()
+			return parent.(interface{ GetName() string }).GetName() + "." + name
+		}
+		return name
+	}
+
+	members := make(map[string][]*MemberAccessExpression)
+	for _, node := range collectNodesForTest(astBuilder, ast_pb.NodeType_MEMBER_ACCESS) {
+		member := node.(*MemberAccessExpression)
+		members[member.GetMemberName()] = append(members[member.GetMemberName()], member)
+	}
+
+	require.Len(t, members["amount"], 2)
+	for _, member := range members["amount"] {
+		assert.Equal(t, "amount", describe(member.GetReferencedDeclaration()))
+		field, ok := declarations[member.GetReferencedDeclaration()].(*Parameter)
+		require.True(t, ok)
+		assert.Equal(t, "uint256", field.GetTypeName().GetName())
+	}
+
+	require.Len(t, members["owner"], 1)
+	assert.Equal(t, "owner", describe(members["owner"][0].GetReferencedDeclaration()))
+
+	require.Len(t, members["add"], 1)
+	assert.Equal(t, "MathLib.add", describe(members["add"][0].GetReferencedDeclaration()))
+
+	require.Len(t, members["Paused"], 1)
+	assert.Equal(t, "Paused", describe(members["Paused"][0].GetReferencedDeclaration()))
+
+	require.Len(t, members["transfer"], 1)
+	assert.Equal(t, "IToken.transfer", describe(members["transfer"][0].GetReferencedDeclaration()))
+
+	require.Len(t, members["close"], 1)
+	closeFn, ok := declarations[members["close"][0].GetReferencedDeclaration()].(*Function)
+	require.True(t, ok)
+	assert.Empty(t, closeFn.GetParameters().GetParameters())
+
+	require.Len(t, members["hook"], 1)
+	assert.Equal(t, "Base.hook", describe(members["hook"][0].GetReferencedDeclaration()))
+
+	// Builtin members are not declared in the sources.
+	require.NotEmpty(t, members["sender"])
+	for _, member := range members["sender"] {
+		assert.NotContains(t, declarations, member.GetReferencedDeclaration())
+	}
+
+	identifiers := make(map[string][]*PrimaryExpression)
+	for _, node := range collectNodesForTest(astBuilder, ast_pb.NodeType_IDENTIFIER) {
+		if expr, ok := node.(*PrimaryExpression); ok {
+			identifiers[expr.GetName()] = append(identifiers[expr.GetName()], expr)
+		}
+	}
+
+	for _, name := range []string{"amount", "position", "positions", "last", "status"} {
+		require.NotEmpty(t, identifiers[name], name)
+		for _, identifier := range identifiers[name] {
+			assert.Equal(t, name, describe(identifier.GetReferencedDeclaration()), name)
+		}
+	}
+
+	for _, identifier := range identifiers["close"] {
+		fn, ok := declarations[identifier.GetReferencedDeclaration()].(*Function)
+		require.True(t, ok)
+		assert.Len(t, fn.GetParameters().GetParameters(), 1)
+	}
+	for _, name := range []string{"MathLib", "IToken", "Status"} {
+		for _, identifier := range identifiers[name] {
+			assert.Equal(t, name, describe(identifier.GetReferencedDeclaration()), name)
+		}
+	}
+}
+
+func TestReferencedDeclarationsOverloads(t *testing.T) {
+	astBuilder := buildAstFromSourceForTest(t, "Ledger", `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+library Balances {
+    struct Map {
+        mapping(uint256 => uint256) values;
+    }
+
+    function get(Map storage map, uint256 key) internal view returns (uint256) {
+        return map.values[key];
+    }
+
+    function get(Map storage map, address key) internal view returns (uint256) {
+        return map.values[uint256(uint160(key))];
+    }
+}
+
+library Amounts {
+    function scale(uint256 amount, uint256 factor) internal pure returns (uint256) {
+        return amount * factor;
+    }
+}
+
+contract Ledger {
+    using Balances for Balances.Map;
+    using Amounts for uint256;
+
+    Balances.Map private balances;
+
+    function record(uint256 id) public {}
+
+    function record(address account) public {}
+
+    function run(address account, uint256 id) external view returns (uint256) {
+        record(account);
+        record(id);
+        return balances.get(account) + balances.get(id).scale(2);
+    }
+}
+`)
+
+	parameterTypes := func(refId int64) []string {
+		for _, node := range collectNodesForTest(astBuilder, ast_pb.NodeType_FUNCTION_DEFINITION) {
+			if node.GetId() != refId {
+				continue
+			}
+			toReturn := make([]string, 0)
+			for _, parameter := range node.(*Function).GetParameters().GetParameters() {
+				toReturn = append(toReturn, parameter.GetTypeDescription().GetString())
+			}
+			return toReturn
+		}
+		return nil
+	}
+
+	calls := make(map[string][][]string)
+	for _, node := range collectNodesForTest(astBuilder, ast_pb.NodeType_MEMBER_ACCESS) {
+		member := node.(*MemberAccessExpression)
+		calls[member.GetMemberName()] = append(calls[member.GetMemberName()], parameterTypes(member.GetReferencedDeclaration()))
+	}
+	for _, node := range collectNodesForTest(astBuilder, ast_pb.NodeType_IDENTIFIER) {
+		if expr, ok := node.(*PrimaryExpression); ok && expr.GetName() == "record" {
+			calls["record"] = append(calls["record"], parameterTypes(expr.GetReferencedDeclaration()))
+		}
+	}
+
+	// Attached library functions are resolved with the base expression bound to the first parameter.
+	assert.ElementsMatch(t, [][]string{{"struct Balances.Map", "address"}, {"struct Balances.Map", "uint256"}}, calls["get"])
+	assert.Equal(t, [][]string{{"uint256", "uint256"}}, calls["scale"])
+
+	// Overloads with the same number of parameters are told apart by the argument types.
+	assert.ElementsMatch(t, [][]string{{"address"}, {"uint256"}}, calls["record"])
+}
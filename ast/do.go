@@ -164,14 +164,6 @@ func (d *DoWhileStatement) Parse(
 		bodyNode := NewBodyNode(d.ASTBuilder, false)
 		bodyNode.ParseBlock(unit, contractNode, d, ctx.Statement().Block())
 		d.Body = bodyNode
-
-		if ctx.Statement().Block() != nil && ctx.Statement().Block().AllUncheckedBlock() != nil {
-			for _, uncheckedCtx := range ctx.Statement().Block().AllUncheckedBlock() {
-				bodyNode := NewBodyNode(d.ASTBuilder, false)
-				bodyNode.ParseUncheckedBlock(unit, contractNode, d, uncheckedCtx)
-				d.Body.Statements = append(d.Body.Statements, bodyNode)
-			}
-		}
 	}
 
 	return d
@@ -50,8 +50,17 @@ func (f *ExprOperation) GetSrc() SrcNode {
 	return f.Src
 }
 
-// GetTypeDescription returns the type description of the expression operation.
+// GetTypeDescription returns the type description of the expression operation, which is the type
+// of the base, or the folded constant if both the base and the exponent are constants.
 func (f *ExprOperation) GetTypeDescription() *TypeDescription {
+	if len(f.TypeDescriptions) < 2 {
+		return f.TypeDescriptions[0]
+	}
+	if typeDescription := binaryOperationType(
+		ast_pb.Operator_EXPONENTIATION, f.TypeDescriptions[0], f.TypeDescriptions[1],
+	); typeDescription != nil {
+		return typeDescription
+	}
 	return f.TypeDescriptions[0]
 }
 
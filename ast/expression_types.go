@@ -0,0 +1,544 @@
+package ast
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+)
+
+// maxConstantBits bounds the size of folded constants, matching the 4096 bit limit of solc for
+// rational number literals.
+const maxConstantBits = 4096
+
+// integerTypePattern matches elementary integer type strings such as uint8, int256 or uint.
+var integerTypePattern = regexp.MustCompile(`^(u?)int(\d*)$`)
+
+// numberUnits are the subdenominations a number literal may be suffixed with. Units that end with
+// another unit come first.
+var numberUnits = []struct {
+	name       string
+	multiplier int64
+}{
+	{"gwei", 1e9},
+	{"wei", 1},
+	{"szabo", 1e12},
+	{"finney", 1e15},
+	{"ether", 1e18},
+	{"seconds", 1},
+	{"minutes", 60},
+	{"hours", 3600},
+	{"days", 86400},
+	{"weeks", 604800},
+	{"years", 31536000},
+}
+
+// resolveExpressionTypes computes the type description of every expression after parsing, once all
+// references are resolved. Types are computed bottom up following the implicit conversion rules of
+// Solidity: literals get their rational constant type, operations on constants are folded, and
+// operations mixing types get the type both operands implicitly convert to.
+// Expressions whose operand types are unknown keep the type assigned while parsing.
+func (b *ASTBuilder) resolveExpressionTypes() {
+	root := b.GetRoot()
+	if root == nil {
+		return
+	}
+
+	for _, node := range root.GetGlobalNodes() {
+		resolveExpressionType(node)
+	}
+
+	for _, unit := range root.GetSourceUnits() {
+		resolveExpressionType(unit)
+	}
+}
+
+// resolveExpressionType computes the type of the node after the types of its children.
+func resolveExpressionType(node Node[NodeType]) {
+	if isNilNode(node) {
+		return
+	}
+
+	for _, child := range node.GetNodes() {
+		resolveExpressionType(child)
+	}
+
+	for _, argument := range baseContractArguments(node) {
+		resolveExpressionType(argument)
+	}
+
+	switch n := node.(type) {
+	case *PrimaryExpression:
+		if n.GetType() == ast_pb.NodeType_LITERAL && n.GetKind() == ast_pb.NodeType_NUMBER {
+			if typeDescription := numberLiteralType(n.GetValue()); typeDescription != nil {
+				n.TypeDescription = typeDescription
+			}
+		}
+	case *BinaryOperation:
+		if typeDescription := binaryOperationType(
+			n.Operator, typeOf(n.GetLeftExpression()), typeOf(n.GetRightExpression()),
+		); typeDescription != nil {
+			n.TypeDescription = typeDescription
+		}
+	case *ShiftOperation:
+		n.TypeDescriptions = operandTypes(n.GetExpressions())
+		if typeDescription := shiftOperationType(n); typeDescription != nil {
+			n.TypeDescription = typeDescription
+		}
+	case *BitXorOperation:
+		n.TypeDescriptions = operandTypes(n.GetExpressions())
+		if typeDescription := bitXorOperationType(n); typeDescription != nil {
+			n.TypeDescription = typeDescription
+		}
+	case *AndOperation:
+		n.TypeDescriptions = operandTypes(n.GetExpressions())
+	case *BitAndOperation:
+		n.TypeDescriptions = operandTypes(n.GetExpressions())
+	case *BitOrOperation:
+		n.TypeDescriptions = operandTypes(n.GetExpressions())
+	case *ExprOperation:
+		n.TypeDescriptions = operandTypes([]Node[NodeType]{n.GetLeftExpression(), n.GetRightExpression()})
+	case *UnaryPrefix:
+		if typeDescription := unaryOperationType(n.Operator, typeOf(n.GetExpression())); typeDescription != nil {
+			n.TypeDescription = typeDescription
+		}
+	case *UnarySuffix:
+		if typeDescription := typeOf(n.GetExpression()); typeDescription != nil {
+			n.TypeDescription = typeDescription
+		}
+	case *Conditional:
+		n.TypeDescriptions = operandTypes(n.GetExpressions())
+		n.TypeDescription = conditionalType(n)
+	case *TupleExpression:
+		if len(n.GetComponents()) == 1 {
+			if typeDescription := typeOf(n.GetComponents()[0]); typeDescription != nil {
+				n.TypeDescription = typeDescription
+			}
+		} else if len(n.GetComponents()) > 1 {
+			n.TypeDescription = n.buildTypeDescription()
+		}
+	case *Assignment:
+		if typeDescription := typeOf(n.GetLeftExpression()); typeDescription != nil {
+			n.TypeDescription = typeDescription
+		}
+	}
+}
+
+// shiftOperationType returns the type of the shift operation computed from the types of its operands,
+// or nil if it cannot be determined.
+func shiftOperationType(n *ShiftOperation) *TypeDescription {
+	if len(n.TypeDescriptions) != 2 {
+		return nil
+	}
+
+	operator := ast_pb.Operator_O_DEFAULT
+	if n.Operator == ast_pb.NodeType_SHIFT_LEFT_OPERATION {
+		operator = ast_pb.Operator_SHIFT_LEFT_EQUAL
+	} else if n.Operator == ast_pb.NodeType_SHIFT_RIGHT_OPERATION {
+		operator = ast_pb.Operator_SHIFT_RIGHT_EQUAL
+	}
+
+	return binaryOperationType(operator, n.TypeDescriptions[0], n.TypeDescriptions[1])
+}
+
+// bitXorOperationType returns the type of the bitwise xor operation computed from the types of its
+// operands, or nil if it cannot be determined.
+func bitXorOperationType(n *BitXorOperation) *TypeDescription {
+	if len(n.TypeDescriptions) != 2 {
+		return nil
+	}
+
+	return binaryOperationType(ast_pb.Operator_BIT_XOR_EQUAL, n.TypeDescriptions[0], n.TypeDescriptions[1])
+}
+
+// conditionalType returns the common type of both branches of the conditional, or nil if it cannot
+// be determined. Constants in the branches are converted to the smallest type holding them first.
+func conditionalType(n *Conditional) *TypeDescription {
+	if len(n.TypeDescriptions) != 3 {
+		return nil
+	}
+
+	return commonType(mobileType(n.TypeDescriptions[1]), mobileType(n.TypeDescriptions[2]))
+}
+
+// typeOf returns the type description of the node, or nil if the node or its type is unknown.
+func typeOf(node Node[NodeType]) (toReturn *TypeDescription) {
+	if isNilNode(node) {
+		return nil
+	}
+
+	// Some operations index into type descriptions that may be empty for malformed sources.
+	defer func() {
+		if r := recover(); r != nil {
+			toReturn = nil
+		}
+	}()
+
+	return node.GetTypeDescription()
+}
+
+// operandTypes returns the type descriptions of the operands, in operand order.
+func operandTypes(operands []Node[NodeType]) []*TypeDescription {
+	toReturn := make([]*TypeDescription, 0, len(operands))
+	for _, operand := range operands {
+		toReturn = append(toReturn, typeOf(operand))
+	}
+	return toReturn
+}
+
+// binaryOperationType returns the type of a binary operation on operands of the given types, or nil
+// if it cannot be determined. Operators that have an assignment form are identified by it, so that
+// shifts and bitwise operations, which have no operator of their own, can be expressed as well.
+func binaryOperationType(operator ast_pb.Operator, left, right *TypeDescription) *TypeDescription {
+	switch operator {
+	case ast_pb.Operator_EQUAL, ast_pb.Operator_NOT_EQUAL,
+		ast_pb.Operator_GREATER_THAN, ast_pb.Operator_GREATER_THAN_OR_EQUAL,
+		ast_pb.Operator_LESS_THAN, ast_pb.Operator_LESS_THAN_OR_EQUAL,
+		ast_pb.Operator_OR:
+		return boolType()
+	}
+
+	if left == nil || right == nil {
+		return nil
+	}
+
+	leftValue, leftConstant := rationalValue(left)
+	rightValue, rightConstant := rationalValue(right)
+	if leftConstant && rightConstant {
+		if value := foldConstants(operator, leftValue, rightValue); value != nil {
+			return rationalType(value)
+		}
+	}
+
+	switch operator {
+	case ast_pb.Operator_EXPONENTIATION, ast_pb.Operator_SHIFT_LEFT_EQUAL, ast_pb.Operator_SHIFT_RIGHT_EQUAL:
+		// The result has the type of the base, converted from a constant if the exponent or the
+		// shift amount is not one.
+		return mobileType(left)
+	}
+
+	return commonType(left, right)
+}
+
+// unaryOperationType returns the type of a unary prefix operation on an operand of the given type, or
+// nil if it cannot be determined.
+func unaryOperationType(operator ast_pb.Operator, operand *TypeDescription) *TypeDescription {
+	if operator == ast_pb.Operator_NOT {
+		return boolType()
+	}
+
+	if operand == nil {
+		return nil
+	}
+
+	if value, ok := rationalValue(operand); ok {
+		switch operator {
+		case ast_pb.Operator_SUBTRACT:
+			return rationalType(new(big.Rat).Neg(value))
+		case ast_pb.Operator_BIT_NOT:
+			if value.IsInt() {
+				return rationalType(new(big.Rat).SetInt(new(big.Int).Not(value.Num())))
+			}
+		}
+	}
+
+	return operand
+}
+
+// foldConstants computes the result of the operation on two constants, or returns nil if the result
+// is not a valid constant.
+func foldConstants(operator ast_pb.Operator, left, right *big.Rat) *big.Rat {
+	switch operator {
+	case ast_pb.Operator_ADDITION:
+		return new(big.Rat).Add(left, right)
+	case ast_pb.Operator_SUBTRACTION:
+		return new(big.Rat).Sub(left, right)
+	case ast_pb.Operator_MULTIPLICATION:
+		return new(big.Rat).Mul(left, right)
+	case ast_pb.Operator_DIVISION:
+		if right.Sign() == 0 {
+			return nil
+		}
+		return new(big.Rat).Quo(left, right)
+	case ast_pb.Operator_MODULO:
+		if !left.IsInt() || !right.IsInt() || right.Sign() == 0 {
+			return nil
+		}
+		return new(big.Rat).SetInt(new(big.Int).Rem(left.Num(), right.Num()))
+	case ast_pb.Operator_EXPONENTIATION:
+		if !right.IsInt() || !right.Num().IsInt64() {
+			return nil
+		}
+		exponent := right.Num().Int64()
+		if exponent < 0 {
+			exponent = -exponent
+		}
+		bits := int64(left.Num().BitLen() + left.Denom().BitLen())
+		if bits*exponent > maxConstantBits {
+			return nil
+		}
+		num := new(big.Int).Exp(left.Num(), big.NewInt(exponent), nil)
+		denom := new(big.Int).Exp(left.Denom(), big.NewInt(exponent), nil)
+		if right.Sign() < 0 {
+			if num.Sign() == 0 {
+				return nil
+			}
+			num, denom = denom, num
+		}
+		return new(big.Rat).SetFrac(num, denom)
+	case ast_pb.Operator_SHIFT_LEFT_EQUAL, ast_pb.Operator_SHIFT_RIGHT_EQUAL:
+		if !left.IsInt() || !right.IsInt() || !right.Num().IsInt64() || right.Sign() < 0 {
+			return nil
+		}
+		shift := right.Num().Int64()
+		if operator == ast_pb.Operator_SHIFT_RIGHT_EQUAL {
+			// Shifting right rounds towards negative infinity, as for signed integer types.
+			quotient := new(big.Int).Div(left.Num(), new(big.Int).Lsh(big.NewInt(1), uint(shift)))
+			return new(big.Rat).SetInt(quotient)
+		}
+		if int64(left.Num().BitLen())+shift > maxConstantBits {
+			return nil
+		}
+		return new(big.Rat).SetInt(new(big.Int).Lsh(left.Num(), uint(shift)))
+	case ast_pb.Operator_BIT_XOR_EQUAL:
+		if !left.IsInt() || !right.IsInt() {
+			return nil
+		}
+		return new(big.Rat).SetInt(new(big.Int).Xor(left.Num(), right.Num()))
+	}
+
+	return nil
+}
+
+// commonType returns the type both types implicitly convert to, or the left type if there is none.
+// Integer types convert to integer types of the same signedness that are at least as large, and
+// unsigned integers convert to larger signed integers as well. Constants convert to any integer type.
+func commonType(left, right *TypeDescription) *TypeDescription {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+
+	if _, ok := rationalValue(left); ok {
+		if _, ok := rationalValue(right); ok {
+			return mobileType(left)
+		}
+		if isIntegerType(right) {
+			return right
+		}
+		return left
+	}
+	if _, ok := rationalValue(right); ok {
+		return left
+	}
+
+	leftSigned, leftBits, leftOk := integerType(left)
+	rightSigned, rightBits, rightOk := integerType(right)
+	if leftOk && rightOk {
+		if implicitlyConvertible(rightSigned, rightBits, leftSigned, leftBits) {
+			return left
+		}
+		if implicitlyConvertible(leftSigned, leftBits, rightSigned, rightBits) {
+			return right
+		}
+	}
+
+	return left
+}
+
+// implicitlyConvertible reports whether an integer of the first type converts to the second type
+// without losing information.
+func implicitlyConvertible(fromSigned bool, fromBits int, toSigned bool, toBits int) bool {
+	if fromSigned == toSigned {
+		return fromBits <= toBits
+	}
+	return !fromSigned && fromBits < toBits
+}
+
+// mobileType returns the smallest integer type that can hold the constant, or the type itself if it
+// is not an integer constant.
+func mobileType(typeDescription *TypeDescription) *TypeDescription {
+	value, ok := rationalValue(typeDescription)
+	if !ok || !value.IsInt() {
+		return typeDescription
+	}
+
+	num := value.Num()
+	bits := num.BitLen()
+	prefix := "uint"
+	if num.Sign() < 0 {
+		prefix = "int"
+		// The two's complement of negative numbers needs one more bit, except for powers of two.
+		bits = new(big.Int).Sub(new(big.Int).Neg(num), big.NewInt(1)).BitLen() + 1
+	}
+
+	bits = (bits + 7) / 8 * 8
+	if bits == 0 {
+		bits = 8
+	}
+	if bits > 256 {
+		return typeDescription
+	}
+
+	typeString := fmt.Sprintf("%s%d", prefix, bits)
+	return &TypeDescription{
+		TypeIdentifier: "t_" + typeString,
+		TypeString:     typeString,
+	}
+}
+
+// rationalValue returns the value of a rational constant type, as described by its identifier.
+func rationalValue(typeDescription *TypeDescription) (*big.Rat, bool) {
+	if typeDescription == nil || !strings.HasPrefix(typeDescription.TypeIdentifier, "t_rational_") {
+		return nil, false
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(typeDescription.TypeIdentifier, "t_rational_"), "_by_", 2)
+	if len(parts) != 2 {
+		return nil, false
+	}
+
+	negative := strings.HasPrefix(parts[0], "minus_")
+	num, ok := new(big.Int).SetString(strings.TrimPrefix(parts[0], "minus_"), 10)
+	if !ok {
+		return nil, false
+	}
+	denom, ok := new(big.Int).SetString(parts[1], 10)
+	if !ok || denom.Sign() == 0 {
+		return nil, false
+	}
+
+	if negative {
+		num.Neg(num)
+	}
+
+	return new(big.Rat).SetFrac(num, denom), true
+}
+
+// rationalType returns the type of the rational constant, using the type strings and identifiers of
+// solc.
+func rationalType(value *big.Rat) *TypeDescription {
+	num := new(big.Int).Abs(value.Num())
+	sign := ""
+	if value.Sign() < 0 {
+		sign = "minus_"
+	}
+
+	typeIdentifier := fmt.Sprintf("t_rational_%s%s_by_%s", sign, num.String(), value.Denom().String())
+	if value.IsInt() {
+		return &TypeDescription{
+			TypeIdentifier: typeIdentifier,
+			TypeString:     "int_const " + value.Num().String(),
+		}
+	}
+
+	return &TypeDescription{
+		TypeIdentifier: typeIdentifier,
+		TypeString:     fmt.Sprintf("rational_const %s / %s", value.Num().String(), value.Denom().String()),
+	}
+}
+
+// numberLiteralType returns the type of the number literal, which is either a rational constant or
+// an address for hex literals of address length. It returns nil for literals that cannot be parsed.
+func numberLiteralType(literal string) *TypeDescription {
+	literal = strings.ReplaceAll(strings.TrimSpace(literal), "_", "")
+
+	unit := big.NewRat(1, 1)
+	for _, numberUnit := range numberUnits {
+		if strings.HasSuffix(literal, numberUnit.name) {
+			literal = strings.TrimSpace(strings.TrimSuffix(literal, numberUnit.name))
+			unit = big.NewRat(numberUnit.multiplier, 1)
+			break
+		}
+	}
+
+	var value *big.Rat
+	if strings.HasPrefix(literal, "0x") || strings.HasPrefix(literal, "0X") {
+		digits := literal[2:]
+		if len(digits) == 40 {
+			return &TypeDescription{
+				TypeIdentifier: "t_address",
+				TypeString:     "address",
+			}
+		}
+
+		number, ok := new(big.Int).SetString(digits, 16)
+		if !ok {
+			return nil
+		}
+		value = new(big.Rat).SetInt(number)
+	} else {
+		mantissa, exponent := literal, int64(0)
+		if index := strings.IndexAny(literal, "eE"); index >= 0 {
+			parsed, err := strconv.ParseInt(literal[index+1:], 10, 64)
+			if err != nil || parsed > maxConstantBits || parsed < -maxConstantBits {
+				return nil
+			}
+			mantissa, exponent = literal[:index], parsed
+		}
+
+		var ok bool
+		if value, ok = new(big.Rat).SetString(mantissa); !ok {
+			return nil
+		}
+
+		scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(abs(exponent)), nil))
+		if exponent < 0 {
+			value.Quo(value, scale)
+		} else {
+			value.Mul(value, scale)
+		}
+	}
+
+	return rationalType(value.Mul(value, unit))
+}
+
+// integerType returns the signedness and size of an elementary integer type.
+func integerType(typeDescription *TypeDescription) (bool, int, bool) {
+	if typeDescription == nil {
+		return false, 0, false
+	}
+
+	matches := integerTypePattern.FindStringSubmatch(typeDescription.TypeString)
+	if matches == nil {
+		return false, 0, false
+	}
+
+	bits := 256
+	if matches[2] != "" {
+		parsed, err := strconv.Atoi(matches[2])
+		if err != nil {
+			return false, 0, false
+		}
+		bits = parsed
+	}
+
+	return matches[1] == "", bits, true
+}
+
+// isIntegerType reports whether the type is an elementary integer type.
+func isIntegerType(typeDescription *TypeDescription) bool {
+	_, _, ok := integerType(typeDescription)
+	return ok
+}
+
+// boolType returns the type description of bool.
+func boolType() *TypeDescription {
+	return &TypeDescription{
+		TypeIdentifier: "t_bool",
+		TypeString:     "bool",
+	}
+}
+
+// abs returns the absolute value of the number.
+func abs(number int64) int64 {
+	if number < 0 {
+		return -number
+	}
+	return number
+}
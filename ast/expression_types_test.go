@@ -0,0 +1,104 @@
+package ast
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+)
+
+func TestResolveExpressionTypes(t *testing.T) {
+	astBuilder := buildAstFromSourceForTest(t, "Types", `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.4;
+
+library Math {}
+
+contract Types {
+    function run(uint8 small, uint256 large, int16 signed, bool flag) public pure {
+        uint256 mixed = small + large;
+        int16 widened = small * signed;
+        uint256 folded = 1 + 2 * 3;
+        uint256 hexadecimal = 0x10;
+        uint256 denominated = 2 ether;
+        uint256 scientific = 1.5e3;
+        uint256 power = 2 ** 8;
+        int256 negative = -1;
+        uint256 ratio = (1 / 2) * 4;
+        bool compared = small < large;
+        bool either = flag || small == 0;
+        uint8 shifted = small << 2;
+        uint256 constantShift = 1 << 10;
+        uint16 chosen = flag ? 1 : 300;
+        uint8 grouped = (small);
+        address account = 0x5B38Da6a701c568545dCfcB03FcB875f56beddC4;
+    }
+}
+`)
+
+	types := make(map[string]*TypeDescription)
+	for _, node := range collectNodesForTest(astBuilder, ast_pb.NodeType_VARIABLE_DECLARATION) {
+		declaration, ok := node.(*VariableDeclaration)
+		if !ok || len(declaration.GetDeclarations()) != 1 || declaration.GetInitialValue() == nil {
+			continue
+		}
+		types[declaration.GetDeclarations()[0].GetName()] = declaration.GetInitialValue().GetTypeDescription()
+	}
+
+	testCases := []struct {
+		name           string
+		typeString     string
+		typeIdentifier string
+	}{
+		{"mixed", "uint256", "t_uint256"},
+		{"widened", "int16", "t_int16"},
+		{"folded", "int_const 7", "t_rational_7_by_1"},
+		{"hexadecimal", "int_const 16", "t_rational_16_by_1"},
+		{"denominated", "int_const 2000000000000000000", "t_rational_2000000000000000000_by_1"},
+		{"scientific", "int_const 1500", "t_rational_1500_by_1"},
+		{"power", "int_const 256", "t_rational_256_by_1"},
+		{"negative", "int_const -1", "t_rational_minus_1_by_1"},
+		{"ratio", "int_const 2", "t_rational_2_by_1"},
+		{"compared", "bool", "t_bool"},
+		{"either", "bool", "t_bool"},
+		{"shifted", "uint8", "t_uint8"},
+		{"constantShift", "int_const 1024", "t_rational_1024_by_1"},
+		{"chosen", "uint16", "t_uint16"},
+		{"grouped", "uint8", "t_uint8"},
+		{"account", "address", "t_address"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			typeDescription := types[testCase.name]
+			require.NotNil(t, typeDescription)
+			assert.Equal(t, testCase.typeString, typeDescription.GetString())
+			assert.Equal(t, testCase.typeIdentifier, typeDescription.GetIdentifier())
+		})
+	}
+
+	libraries := 0
+	for _, node := range collectNodesForTest(astBuilder, ast_pb.NodeType_CONTRACT_DEFINITION) {
+		if library, ok := node.(*Library); ok {
+			libraries++
+			assert.Equal(t, "library Math", library.GetTypeDescription().GetString())
+		}
+	}
+	assert.Equal(t, 1, libraries)
+}
+
+func TestCommonType(t *testing.T) {
+	uint8Type := &TypeDescription{TypeString: "uint8", TypeIdentifier: "t_uint8"}
+	uint256Type := &TypeDescription{TypeString: "uint256", TypeIdentifier: "t_uint256"}
+	int8Type := &TypeDescription{TypeString: "int8", TypeIdentifier: "t_int8"}
+	constant := numberLiteralType("300")
+
+	assert.Equal(t, uint256Type, commonType(uint8Type, uint256Type))
+	assert.Equal(t, uint256Type, commonType(uint256Type, uint8Type))
+	assert.Equal(t, uint8Type, commonType(uint8Type, int8Type))
+	assert.Equal(t, uint8Type, commonType(uint8Type, constant))
+	assert.Equal(t, "uint16", mobileType(constant).GetString())
+	assert.Equal(t, "int8", mobileType(rationalType(big.NewRat(-128, 1))).GetString())
+	assert.Nil(t, numberLiteralType("0xzz"))
+}
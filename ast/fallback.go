@@ -99,6 +99,7 @@ func (f *Fallback) GetReturnParameters() *ParameterList {
 
 // GetBody returns the body of the Fallback node.
 func (f *Fallback) GetBody() *BodyNode {
+	f.Body.loadLazily()
 	return f.Body
 }
 
@@ -212,17 +213,12 @@ func (f *Fallback) Parse(
 	}
 
 	if ctx.Block() != nil && !ctx.Block().IsEmpty() {
-		bodyNode := NewBodyNode(f.ASTBuilder, false)
-		bodyNode.ParseBlock(unit, contractNode, f, ctx.Block())
-		f.Body = bodyNode
-
-		if ctx.Block().AllUncheckedBlock() != nil {
-			for _, uncheckedCtx := range ctx.Block().AllUncheckedBlock() {
-				bodyNode := NewBodyNode(f.ASTBuilder, false)
-				bodyNode.ParseUncheckedBlock(unit, contractNode, f, uncheckedCtx)
-				f.Body.Statements = append(f.Body.Statements, bodyNode)
-			}
+		bodyNode := f.parseLazyBlock(unit, contractNode, f, ctx.Block())
+		if bodyNode == nil {
+			bodyNode = NewBodyNode(f.ASTBuilder, false)
+			bodyNode.ParseBlock(unit, contractNode, f, ctx.Block())
 		}
+		f.Body = bodyNode
 	}
 
 	return f
@@ -92,6 +92,7 @@ func (f *Function) GetReturnParameters() *ParameterList {
 
 // GetBody returns the body of the Function node.
 func (f *Function) GetBody() *BodyNode {
+	f.Body.loadLazily()
 	return f.Body
 }
 
@@ -184,7 +185,7 @@ func (f *Function) GetSignature() string {
 // GetNodes returns a list of child nodes within the Function node.
 func (f *Function) GetNodes() []Node[NodeType] {
 	toReturn := []Node[NodeType]{}
-	toReturn = append(toReturn, f.GetBody().GetNodes()...)
+	toReturn = append(toReturn, f.Body.GetNodes()...)
 	toReturn = append(toReturn, f.GetParameters().GetNodes()...)
 	toReturn = append(toReturn, f.GetReturnParameters().GetNodes()...)
 
@@ -460,8 +461,11 @@ func (f *Function) Parse(
 	f.ReturnParameters = returnParams
 
 	if ctx.Block() != nil && !ctx.Block().IsEmpty() {
-		bodyNode := NewBodyNode(f.ASTBuilder, false)
-		bodyNode.ParseBlock(unit, contractNode, f, ctx.Block())
+		bodyNode := f.parseLazyBlock(unit, contractNode, f, ctx.Block())
+		if bodyNode == nil {
+			bodyNode = NewBodyNode(f.ASTBuilder, false)
+			bodyNode.ParseBlock(unit, contractNode, f, ctx.Block())
+		}
 		f.Body = bodyNode
 
 		// In case at any point we discover that the function is not implemented, we set the
@@ -469,20 +473,6 @@ func (f *Function) Parse(
 		if !bodyNode.Implemented {
 			f.Implemented = false
 		}
-
-		if ctx.Block().AllUncheckedBlock() != nil {
-			for _, uncheckedCtx := range ctx.Block().AllUncheckedBlock() {
-				bodyNode := NewBodyNode(f.ASTBuilder, false)
-				bodyNode.ParseUncheckedBlock(unit, contractNode, f, uncheckedCtx)
-				f.Body.Statements = append(f.Body.Statements, bodyNode)
-
-				// In case at any point we discover that the function is not implemented, we set the
-				// implemented flag to false.
-				if !bodyNode.Implemented {
-					f.Implemented = false
-				}
-			}
-		}
 	} else {
 		bodyNode := NewBodyNode(f.ASTBuilder, false)
 		bodyNode.Src = f.Src
@@ -356,6 +356,8 @@ type FunctionCallOption struct {
 	Kind                  ast_pb.NodeType  `json:"kind"`                             // Kind of the node.
 	Src                   SrcNode          `json:"src"`                              // Source location of the node.
 	Expression            Node[NodeType]   `json:"expression"`                       // Expression of the function call.
+	Names                 []string         `json:"names,omitempty"`                  // Names of the call options, such as value and gas.
+	Options               []Node[NodeType] `json:"options,omitempty"`                // Values of the call options, in the order of their names.
 	ReferencedDeclaration int64            `json:"referenced_declaration,omitempty"` // Referenced declaration of the function call.
 	TypeDescription       *TypeDescription `json:"type_description"`                 // Type description of the function call.
 }
@@ -408,9 +410,32 @@ func (f *FunctionCallOption) GetTypeDescription() *TypeDescription {
 	return f.TypeDescription
 }
 
-// GetNodes returns a slice of nodes that includes the expression of the FunctionCallOption node.
+// GetNames returns the names of the call options, such as value, gas and salt.
+func (f *FunctionCallOption) GetNames() []string {
+	return f.Names
+}
+
+// GetOptions returns the values of the call options, in the order of their names.
+func (f *FunctionCallOption) GetOptions() []Node[NodeType] {
+	return f.Options
+}
+
+// GetOption returns the value given to the named call option, or nil if the option is not set.
+func (f *FunctionCallOption) GetOption(name string) Node[NodeType] {
+	for i, optionName := range f.Names {
+		if optionName == name && i < len(f.Options) {
+			return f.Options[i]
+		}
+	}
+	return nil
+}
+
+// GetNodes returns a slice of nodes that includes the expression and the option values of the
+// FunctionCallOption node.
 func (f *FunctionCallOption) GetNodes() []Node[NodeType] {
-	return []Node[NodeType]{f.Expression}
+	toReturn := []Node[NodeType]{f.Expression}
+	toReturn = append(toReturn, f.Options...)
+	return toReturn
 }
 
 // GetReferenceDeclaration returns the referenced declaration of the FunctionCallOption node.
@@ -481,6 +506,37 @@ func (f *FunctionCallOption) UnmarshalJSON(data []byte) error {
 		}
 	}
 
+	if names, ok := tempMap["names"]; ok {
+		if err := json.Unmarshal(names, &f.Names); err != nil {
+			return err
+		}
+	}
+
+	if options, ok := tempMap["options"]; ok {
+		var nodes []json.RawMessage
+		if err := json.Unmarshal(options, &nodes); err != nil {
+			return err
+		}
+
+		for _, tempNode := range nodes {
+			var tempNodeMap map[string]json.RawMessage
+			if err := json.Unmarshal(tempNode, &tempNodeMap); err != nil {
+				return err
+			}
+
+			var tempNodeType ast_pb.NodeType
+			if err := json.Unmarshal(tempNodeMap["node_type"], &tempNodeType); err != nil {
+				return err
+			}
+
+			node, err := unmarshalNode(tempNode, tempNodeType)
+			if err != nil {
+				return err
+			}
+			f.Options = append(f.Options, node)
+		}
+	}
+
 	return nil
 }
 
@@ -552,5 +608,16 @@ func (f *FunctionCallOption) Parse(
 		f.TypeDescription = f.Expression.GetTypeDescription()
 	}
 
+	// Call options, such as {value: amount, gas: 5000}.
+	for _, namedArgumentCtx := range ctx.AllNamedArgument() {
+		f.Names = append(f.Names, namedArgumentCtx.Identifier().GetText())
+		f.Options = append(
+			f.Options,
+			expression.Parse(
+				unit, contractNode, fnNode, bodyNode, nil, f, f.GetId(), namedArgumentCtx.Expression(),
+			),
+		)
+	}
+
 	return f
 }
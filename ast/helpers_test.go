@@ -0,0 +1,49 @@
+package ast
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+	"github.com/unpackdev/solgo"
+)
+
+// buildAstFromSourceForTest parses a single source unit and returns the resolved AST builder.
+func buildAstFromSourceForTest(t *testing.T, name string, content string) *ASTBuilder {
+	sources := &solgo.Sources{
+		SourceUnits: []*solgo.SourceUnit{
+			{
+				Name:    name,
+				Path:    name + ".sol",
+				Content: content,
+			},
+		},
+		EntrySourceUnitName: name,
+		LocalSourcesPath:    buildFullPath("../sources/"),
+	}
+
+	parser, err := solgo.NewParserFromSources(context.TODO(), sources)
+	require.NoError(t, err)
+
+	astBuilder := NewAstBuilder(parser.GetParser(), parser.GetSources())
+	require.NoError(t, parser.RegisterListener(solgo.ListenerAst, astBuilder))
+	require.Empty(t, parser.Parse())
+	require.Empty(t, astBuilder.ResolveReferences())
+
+	return astBuilder
+}
+
+// collectNodesForTest returns every unique node of the given type reachable from the AST root.
+func collectNodesForTest(astBuilder *ASTBuilder, nodeType ast_pb.NodeType) []Node[NodeType] {
+	nodes := make([]Node[NodeType], 0)
+	seen := make(map[int64]bool)
+	_, _ = astBuilder.GetTree().ExecuteTypeVisit(nodeType, func(node Node[NodeType]) (bool, error) {
+		if !seen[node.GetId()] {
+			seen[node.GetId()] = true
+			nodes = append(nodes, node)
+		}
+		return true, nil
+	})
+	return nodes
+}
@@ -187,7 +187,12 @@ func (i *IfStatement) Parse(
 				break
 			}
 
-			i.Body = body
+			// Single statement branches, such as `if (x) revert Unauthorized();`, are parsed as
+			// if they were wrapped into a block.
+			for _, child := range statementCtx.GetChildren() {
+				body.parseStatements(unit, contractNode, fnNode, child)
+			}
+			break
 		}
 
 		i.Body = body
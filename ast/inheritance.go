@@ -41,6 +41,16 @@ func parseInheritanceFromCtx(b *ASTBuilder, unit *SourceUnit[Node[ast_pb.SourceU
 			},
 		}
 
+		if argumentsCtx := specifierCtx.CallArgumentList(); argumentsCtx != nil {
+			expression := NewExpression(b)
+			for _, expressionCtx := range argumentsCtx.AllExpression() {
+				baseContract.Arguments = append(
+					baseContract.Arguments,
+					expression.Parse(unit, contractNode, nil, nil, nil, contractNode, baseContract.GetId(), expressionCtx),
+				)
+			}
+		}
+
 		for _, unitNode := range b.sourceUnits {
 			if unitNode.GetName() == specifierCtx.IdentifierPath().GetText() {
 				baseContract.BaseName.ReferencedDeclaration = unitNode.GetId()
@@ -27,6 +27,7 @@ type Interface struct {
 	LinearizedBaseContracts []int64          `json:"linearized_base_contracts"` // List of linearized base contract identifiers.
 	BaseContracts           []*BaseContract  `json:"base_contracts"`            // List of base contracts.
 	ContractDependencies    []int64          `json:"contract_dependencies"`     // List of contract dependency identifiers.
+	unimplemented           []Node[NodeType] // Members without an implementation, set once linearizations are resolved.
 }
 
 // NewInterfaceDefinition creates a new Interface node with default values and returns it.
@@ -96,6 +97,12 @@ func (l *Interface) IsFullyImplemented() bool {
 	return l.FullyImplemented
 }
 
+// GetUnimplementedMembers returns the functions and modifiers the interface ends up with, inherited ones
+// included, that have no implementation. It is empty until references are resolved.
+func (l *Interface) GetUnimplementedMembers() []Node[NodeType] {
+	return l.unimplemented
+}
+
 // GetNodes returns a slice of child nodes within the interface.
 func (l *Interface) GetNodes() []Node[NodeType] {
 	return l.Nodes
@@ -0,0 +1,134 @@
+package ast
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+	"github.com/unpackdev/solgo"
+	"github.com/unpackdev/solgo/parser"
+)
+
+// SetLazyParser lets the builder load the bodies left out of the parse tree by a lazy parser, see
+// solgo.NewLazyParserFromSources. Function-like definitions then start with a body holding no statements,
+// which is parsed, resolved and typed the first time it is requested through GetBody. Walking the tree
+// through GetNodes does not load bodies.
+//
+// Declarations the resolver needs are kept after ResolveReferences, so bodies can be loaded at any time.
+// Bodies may be requested from several goroutines, each body is loaded once and loads run one at a time.
+func (b *ASTBuilder) SetLazyParser(parser *solgo.Parser) {
+	if parser != nil && parser.IsLazy() {
+		b.lazyParser = parser
+	}
+}
+
+// IsLazy reports whether function-like bodies are loaded on first access.
+func (b *ASTBuilder) IsLazy() bool {
+	return b.lazyParser != nil
+}
+
+// GetLazyErrors returns the syntax errors and unresolved references found in the bodies loaded so far.
+func (b *ASTBuilder) GetLazyErrors() []error {
+	b.lazyMutex.Lock()
+	defer b.lazyMutex.Unlock()
+	return append([]error(nil), b.lazyErrors...)
+}
+
+// lazyBody loads the statements of a body left out of the parse tree on first access.
+type lazyBody struct {
+	once sync.Once
+	load func()
+}
+
+// parseLazyBlock returns a body of the parent node to be loaded on first access when the block was
+// left out of the parse tree, or nil when the block was parsed eagerly.
+func (b *ASTBuilder) parseLazyBlock(
+	unit *SourceUnit[Node[ast_pb.SourceUnit]],
+	contractNode Node[NodeType],
+	parentNode Node[NodeType],
+	bodyCtx parser.IBlockContext,
+) *BodyNode {
+	if b.lazyParser == nil {
+		return nil
+	}
+
+	bodyRange := b.lazyParser.GetLazyBody(bodyCtx.GetStart().GetStart())
+	if bodyRange == nil {
+		return nil
+	}
+
+	toReturn := NewBodyNode(b, false)
+	toReturn.Src = SrcNode{
+		Line:        int64(bodyCtx.GetStart().GetLine()),
+		Column:      int64(bodyCtx.GetStart().GetColumn()),
+		Start:       int64(bodyCtx.GetStart().GetStart()),
+		End:         int64(bodyCtx.GetStop().GetStop()),
+		Length:      int64(bodyCtx.GetStop().GetStop() - bodyCtx.GetStart().GetStart() + 1),
+		ParentIndex: parentNode.GetId(),
+	}
+	toReturn.Implemented = len(bodyCtx.GetChildren()) > 0
+	toReturn.lazy = &lazyBody{
+		load: func() {
+			b.loadLazyBody(toReturn, unit, contractNode, parentNode, bodyRange)
+		},
+	}
+	return toReturn
+}
+
+// loadLazyBody parses the statements of the body and resolves their references. Parsing assigns node
+// ids and resolving updates the resolver, the symbol table and the node index of the builder, so loads
+// are serialized.
+func (b *ASTBuilder) loadLazyBody(
+	body *BodyNode,
+	unit *SourceUnit[Node[ast_pb.SourceUnit]],
+	contractNode Node[NodeType],
+	parentNode Node[NodeType],
+	bodyRange *solgo.BodyRange,
+) {
+	b.lazyMutex.Lock()
+	defer b.lazyMutex.Unlock()
+
+	bodyCtx, syntaxErrs := b.lazyParser.ParseLazyBody(bodyRange)
+	for _, syntaxErr := range syntaxErrs {
+		b.lazyErrors = append(b.lazyErrors, syntaxErr.Error())
+	}
+	if bodyCtx == nil {
+		return
+	}
+
+	firstId := atomic.LoadInt64(&b.nextID)
+	body.parseBlockChildren(unit, contractNode, parentNode, bodyCtx)
+
+	// Definitions are all known by now, so only references to undeclared names remain unprocessed.
+	for nodeId, node := range b.resolver.UnprocessedNodes {
+		if nodeId < firstId {
+			continue
+		}
+		if rNodeId, rNodeType := b.resolver.resolveByNode(node.Name, node.Node); rNodeType != nil {
+			if b.tree.UpdateNodeReferenceById(nodeId, rNodeId, rNodeType) {
+				delete(b.resolver.UnprocessedNodes, nodeId)
+				continue
+			}
+		}
+		b.lazyErrors = append(b.lazyErrors, fmt.Errorf(
+			"unable to resolve node by id %d - name: %s - type: %T",
+			nodeId, node.Name, node.Node,
+		))
+	}
+
+	// The symbol table has to see the new declarations before references are pointed at them.
+	b.symbolTable = nil
+	b.nodeIndex = nil
+	resolver := newDeclarationResolver(b.GetSymbolTable())
+	resolver.walk(body, parentNode)
+	resolveExpressionType(body)
+}
+
+// loadLazily loads the body on first access when it was left out of the parse tree.
+func (b *BodyNode) loadLazily() {
+	if b == nil || b.lazy == nil {
+		return
+	}
+	b.lazy.once.Do(b.lazy.load)
+}
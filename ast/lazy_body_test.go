@@ -0,0 +1,198 @@
+package ast
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+	"github.com/unpackdev/solgo"
+)
+
+const lazySourceForTest = `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+library Math {
+    function max(uint256 a, uint256 b) internal pure returns (uint256) {
+        return a > b ? a : b;
+    }
+}
+
+contract Vault {
+    uint256 public total;
+    address owner;
+
+    modifier onlyOwner() {
+        require(msg.sender == owner, "owner");
+        _;
+    }
+
+    constructor() {
+        owner = msg.sender;
+    }
+
+    function deposit(uint256 amount) external onlyOwner returns (uint256) {
+        total = Math.max(total, amount);
+        return _double(total);
+    }
+
+    function _double(uint256 value) internal pure returns (uint256) {
+        uint256 doubled = value * 2;
+        return doubled;
+    }
+
+    receive() external payable {
+        total += msg.value;
+    }
+}
+`
+
+func TestLazyBodies(t *testing.T) {
+	sources := &solgo.Sources{
+		SourceUnits: []*solgo.SourceUnit{
+			{
+				Name:    "Vault",
+				Path:    "Vault.sol",
+				Content: lazySourceForTest,
+			},
+		},
+		EntrySourceUnitName: "Vault",
+		LocalSourcesPath:    buildFullPath("../sources/"),
+	}
+
+	parser, err := solgo.NewLazyParserFromSources(context.TODO(), sources)
+	require.NoError(t, err)
+
+	astBuilder := NewAstBuilder(parser.GetParser(), parser.GetSources())
+	astBuilder.SetLazyParser(parser)
+	assert.True(t, astBuilder.IsLazy())
+	require.NoError(t, parser.RegisterListener(solgo.ListenerAst, astBuilder))
+	require.Empty(t, parser.Parse())
+	require.Empty(t, astBuilder.ResolveReferences())
+
+	eager := buildAstFromSourceForTest(t, "Vault", lazySourceForTest)
+
+	// Bodies stay out of the tree until they are requested.
+	assert.Empty(t, collectNodesForTest(astBuilder, ast_pb.NodeType_RETURN_STATEMENT))
+	assert.NotEmpty(t, collectNodesForTest(eager, ast_pb.NodeType_RETURN_STATEMENT))
+
+	functions := collectNodesForTest(astBuilder, ast_pb.NodeType_FUNCTION_DEFINITION)
+	eagerFunctions := collectNodesForTest(eager, ast_pb.NodeType_FUNCTION_DEFINITION)
+	require.Equal(t, len(eagerFunctions), len(functions))
+
+	for i, node := range functions {
+		function, ok := node.(*Function)
+		if !ok {
+			continue
+		}
+		eagerFunction := eagerFunctions[i].(*Function)
+
+		assert.Equal(t, eagerFunction.GetSignature(), function.GetSignature())
+		assert.Equal(t, eagerFunction.IsImplemented(), function.IsImplemented(), function.GetName())
+		assert.Empty(t, function.Body.GetStatements(), function.GetName())
+
+		body := function.GetBody()
+		eagerBody := eagerFunction.GetBody()
+		assert.Equal(t, eagerBody.GetSrc().GetStart(), body.GetSrc().GetStart(), function.GetName())
+		require.Len(t, body.GetStatements(), len(eagerBody.GetStatements()), function.GetName())
+		for j, statement := range body.GetStatements() {
+			expected, actual := eagerBody.GetStatements()[j].GetSrc(), statement.GetSrc()
+			assert.Equal(t, expected.GetLine(), actual.GetLine(), function.GetName())
+			assert.Equal(t, expected.GetColumn(), actual.GetColumn(), function.GetName())
+			assert.Equal(t, expected.GetStart(), actual.GetStart(), function.GetName())
+			assert.Equal(t, expected.GetEnd(), actual.GetEnd(), function.GetName())
+		}
+	}
+
+	for _, node := range collectNodesForTest(astBuilder, ast_pb.NodeType_MODIFIER_DEFINITION) {
+		assert.NotEmpty(t, node.(*ModifierDefinition).GetBody().GetStatements())
+	}
+
+	// References within loaded bodies point at their declarations.
+	declarations := make(map[int64]string)
+	for _, nodeType := range []ast_pb.NodeType{
+		ast_pb.NodeType_VARIABLE_DECLARATION, ast_pb.NodeType_FUNCTION_DEFINITION, ast_pb.NodeType_CONTRACT_DEFINITION,
+	} {
+		for _, node := range collectNodesForTest(astBuilder, nodeType) {
+			if named, ok := node.(interface{ GetName() string }); ok {
+				declarations[node.GetId()] = named.GetName()
+			}
+		}
+	}
+
+	referenced := make(map[string]bool)
+	for _, node := range collectNodesForTest(astBuilder, ast_pb.NodeType_IDENTIFIER) {
+		if identifier, ok := node.(*PrimaryExpression); ok {
+			if name, found := declarations[identifier.GetReferencedDeclaration()]; found {
+				referenced[name] = true
+			}
+		}
+	}
+	for _, name := range []string{"total", "owner", "_double", "value", "doubled", "Math"} {
+		assert.True(t, referenced[name], name)
+	}
+
+	assert.Empty(t, astBuilder.GetLazyErrors())
+}
+
+func TestLazyBodiesConcurrently(t *testing.T) {
+	sources := &solgo.Sources{
+		SourceUnits: []*solgo.SourceUnit{
+			{
+				Name:    "Vault",
+				Path:    "Vault.sol",
+				Content: lazySourceForTest,
+			},
+		},
+		EntrySourceUnitName: "Vault",
+		LocalSourcesPath:    buildFullPath("../sources/"),
+	}
+
+	parser, err := solgo.NewLazyParserFromSources(context.TODO(), sources)
+	require.NoError(t, err)
+
+	astBuilder := NewAstBuilder(parser.GetParser(), parser.GetSources())
+	astBuilder.SetLazyParser(parser)
+	require.NoError(t, parser.RegisterListener(solgo.ListenerAst, astBuilder))
+	require.Empty(t, parser.Parse())
+	require.Empty(t, astBuilder.ResolveReferences())
+
+	eager := buildAstFromSourceForTest(t, "Vault", lazySourceForTest)
+	eagerFunctions := collectNodesForTest(eager, ast_pb.NodeType_FUNCTION_DEFINITION)
+	functions := collectNodesForTest(astBuilder, ast_pb.NodeType_FUNCTION_DEFINITION)
+	require.Equal(t, len(eagerFunctions), len(functions))
+
+	// Every goroutine requests every body, so bodies are requested concurrently and more than once.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, node := range functions {
+				if function, ok := node.(*Function); ok {
+					function.GetBody()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	ids := make(map[int64]bool)
+	for i, node := range functions {
+		function, ok := node.(*Function)
+		if !ok {
+			continue
+		}
+
+		statements := function.GetBody().GetStatements()
+		assert.Len(t, statements, len(eagerFunctions[i].(*Function).GetBody().GetStatements()), function.GetName())
+		for _, statement := range statements {
+			assert.False(t, ids[statement.GetId()], "statement ids are unique")
+			ids[statement.GetId()] = true
+		}
+	}
+
+	assert.Empty(t, astBuilder.GetLazyErrors())
+}
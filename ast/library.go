@@ -28,6 +28,7 @@ type Library struct {
 	LinearizedBaseContracts []int64          `json:"linearized_base_contracts"` // LinearizedBaseContracts are the linearized base contracts of the library.
 	BaseContracts           []*BaseContract  `json:"base_contracts"`            // BaseContracts are the base contracts of the library.
 	ContractDependencies    []int64          `json:"contract_dependencies"`     // ContractDependencies are the contract dependencies of the library.
+	unimplemented           []Node[NodeType] // Members without an implementation, set once linearizations are resolved.
 }
 
 // NewLibraryDefinition creates a new Library with the provided ASTBuilder.
@@ -70,10 +71,9 @@ func (l *Library) GetNameLocation() SrcNode {
 }
 
 // GetTypeDescription returns the type description of the library node.
-// Currently, it returns nil and needs to be implemented.
 func (l *Library) GetTypeDescription() *TypeDescription {
 	return &TypeDescription{
-		TypeString:     fmt.Sprintf("contract %s", l.Name),
+		TypeString:     fmt.Sprintf("library %s", l.Name),
 		TypeIdentifier: fmt.Sprintf("$_t_contract_%s_%d", l.GetName(), l.GetId()),
 	}
 }
@@ -98,6 +98,12 @@ func (l *Library) IsFullyImplemented() bool {
 	return l.FullyImplemented
 }
 
+// GetUnimplementedMembers returns the functions and modifiers the library ends up with, inherited ones
+// included, that have no implementation. It is empty until references are resolved.
+func (l *Library) GetUnimplementedMembers() []Node[NodeType] {
+	return l.unimplemented
+}
+
 // GetNodes returns the nodes associated with the library.
 func (l *Library) GetNodes() []Node[NodeType] {
 	return l.Nodes
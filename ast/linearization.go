@@ -0,0 +1,235 @@
+package ast
+
+import (
+	"fmt"
+
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+)
+
+// Linearize returns the C3 linearization of the contract, interface or library with the given id,
+// the contract itself first and its most basic base last, which is the order solc reports as
+// linearizedBaseContracts and searches for virtual members and super calls. Base contracts that
+// cannot be found in the sources are left out.
+func (t *SymbolTable) Linearize(contractId int64) ([]Node[NodeType], error) {
+	scope, ok := t.scopes[contractId]
+	if !ok || scope.Kind != ScopeContract {
+		return nil, fmt.Errorf("node %d is not a contract", contractId)
+	}
+
+	if t.linearizations == nil {
+		t.linearizations = make(map[*Scope][]*Scope)
+	}
+
+	scopes, err := t.linearize(scope, make(map[*Scope]bool))
+	if err != nil {
+		return nil, err
+	}
+
+	toReturn := make([]Node[NodeType], 0, len(scopes))
+	for _, linearized := range scopes {
+		toReturn = append(toReturn, linearized.Node)
+	}
+	return toReturn, nil
+}
+
+// linearize computes the C3 linearization of the contract scope, memoizing the results.
+func (t *SymbolTable) linearize(scope *Scope, visiting map[*Scope]bool) ([]*Scope, error) {
+	if toReturn, ok := t.linearizations[scope]; ok {
+		return toReturn, nil
+	}
+
+	if visiting[scope] {
+		return nil, fmt.Errorf("cyclic inheritance of %s", scope.name())
+	}
+	visiting[scope] = true
+	defer delete(visiting, scope)
+
+	// Bases are linked most derived first, which is the order C3 merges them in.
+	sequences := make([][]*Scope, 0, len(scope.bases)+1)
+	for _, base := range scope.bases {
+		linearized, err := t.linearize(base, visiting)
+		if err != nil {
+			return nil, err
+		}
+		sequences = append(sequences, append([]*Scope{}, linearized...))
+	}
+	sequences = append(sequences, append([]*Scope{}, scope.bases...))
+
+	toReturn := []*Scope{scope}
+	for {
+		remaining := sequences[:0]
+		for _, sequence := range sequences {
+			if len(sequence) > 0 {
+				remaining = append(remaining, sequence)
+			}
+		}
+		sequences = remaining
+		if len(sequences) == 0 {
+			break
+		}
+
+		// The next contract is the first head not appearing in the tail of any sequence.
+		var head *Scope
+		for _, sequence := range sequences {
+			if !inTails(sequence[0], sequences) {
+				head = sequence[0]
+				break
+			}
+		}
+		if head == nil {
+			return nil, fmt.Errorf("linearization of inheritance graph of %s is impossible", scope.name())
+		}
+
+		toReturn = append(toReturn, head)
+		for i, sequence := range sequences {
+			if sequence[0] == head {
+				sequences[i] = sequence[1:]
+			}
+		}
+	}
+
+	t.linearizations[scope] = toReturn
+	return toReturn, nil
+}
+
+// inTails reports whether the scope appears in the tail of any of the sequences.
+func inTails(scope *Scope, sequences [][]*Scope) bool {
+	for _, sequence := range sequences {
+		for _, candidate := range sequence[1:] {
+			if candidate == scope {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// name returns the name of the node opening the scope.
+func (s *Scope) name() string {
+	if named, ok := s.Node.(interface{ GetName() string }); ok {
+		return named.GetName()
+	}
+	return fmt.Sprintf("#%d", s.Id)
+}
+
+// resolveLinearizations replaces the linearized base contracts recorded while parsing, which only
+// list the contract and the units it imports, with the C3 linearization of every contract.
+func (b *ASTBuilder) resolveLinearizations() []error {
+	var errs []error
+	if b.GetRoot() == nil {
+		return errs
+	}
+
+	table := b.GetSymbolTable()
+	for _, unit := range b.GetRoot().GetSourceUnits() {
+		for _, node := range unit.GetNodes() {
+			var target *[]int64
+			switch contract := node.(type) {
+			case *Contract:
+				target = &contract.LinearizedBaseContracts
+			case *Interface:
+				target = &contract.LinearizedBaseContracts
+			case *Library:
+				target = &contract.LinearizedBaseContracts
+			default:
+				continue
+			}
+
+			linearized, err := table.Linearize(node.GetId())
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			ids := make([]int64, 0, len(linearized))
+			for _, base := range linearized {
+				ids = append(ids, base.GetId())
+			}
+			*target = ids
+		}
+	}
+
+	return errs
+}
+
+// resolveImplementations recomputes whether each contract is fully implemented from its C3 linearization,
+// replacing the guess made while parsing, which only looks at the contract's own functions. Bases are
+// searched most derived first, so a function signature or modifier name is implemented when its most
+// derived declaration has a body. Public state variables implement the external functions matching their
+// getters. Contracts whose linearization cannot be computed keep the guess.
+func (b *ASTBuilder) resolveImplementations() {
+	if b.GetRoot() == nil {
+		return
+	}
+
+	table := b.GetSymbolTable()
+	for _, unit := range b.GetRoot().GetSourceUnits() {
+		for _, node := range unit.GetNodes() {
+			linearized, err := table.Linearize(node.GetId())
+			if err != nil {
+				continue
+			}
+
+			unimplemented := unimplementedMembers(node.GetId(), linearized)
+			switch contract := node.(type) {
+			case *Contract:
+				contract.unimplemented = unimplemented
+				contract.FullyImplemented = len(unimplemented) == 0
+			case *Interface:
+				contract.unimplemented = unimplemented
+				contract.FullyImplemented = len(unimplemented) == 0
+			case *Library:
+				contract.unimplemented = unimplemented
+				contract.FullyImplemented = len(unimplemented) == 0
+			}
+		}
+	}
+}
+
+// unimplementedMembers returns the functions and modifiers of the effective member set of the contract
+// with the given id that have no implementation, given its linearization.
+func unimplementedMembers(contractId int64, linearized []Node[NodeType]) []Node[NodeType] {
+	toReturn := make([]Node[NodeType], 0)
+	functions := make(map[string]bool)
+	modifiers := make(map[string]bool)
+
+	// declare records the most derived declaration of a member, reporting it when it has no body.
+	declare := func(seen map[string]bool, key string, member Node[NodeType], implemented bool) {
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		if !implemented {
+			toReturn = append(toReturn, member)
+		}
+	}
+
+	for _, base := range linearized {
+		if base == nil {
+			continue
+		}
+		inherited := base.GetId() != contractId
+
+		for _, member := range base.GetNodes() {
+			switch declaration := member.(type) {
+			case *Function:
+				if inherited && declaration.GetVisibility() == ast_pb.Visibility_PRIVATE {
+					continue
+				}
+				declare(functions, declaration.GetSignatureRaw(), declaration, declaration.IsImplemented())
+			case *Fallback:
+				declare(functions, "fallback()", declaration, declaration.IsImplemented())
+			case *Receive:
+				declare(functions, "receive()", declaration, declaration.IsImplemented())
+			case *ModifierDefinition:
+				declare(modifiers, declaration.GetName(), declaration, declaration.Body != nil)
+			case *StateVariableDeclaration:
+				if declaration.GetVisibility() == ast_pb.Visibility_PUBLIC {
+					declare(functions, declaration.GetGetterSignature(), declaration, true)
+				}
+			}
+		}
+	}
+
+	return toReturn
+}
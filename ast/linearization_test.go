@@ -0,0 +1,95 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFullyImplemented(t *testing.T) {
+	astBuilder := buildAstFromSourceForTest(t, "Token", `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.19;
+
+interface IToken {
+    function totalSupply() external view returns (uint256);
+    function decimals() external view returns (uint8);
+}
+
+interface IEmpty {}
+
+library Math {
+    function add(uint256 a, uint256 b) internal pure returns (uint256) {
+        return a + b;
+    }
+}
+
+abstract contract Base is IToken {
+    modifier guarded() virtual;
+
+    function decimals() external pure returns (uint8) {
+        return 18;
+    }
+
+    function _hook() internal virtual;
+}
+
+abstract contract Partial is Base {
+    modifier guarded() override {
+        _;
+    }
+}
+
+contract Token is Partial {
+    uint256 public override totalSupply;
+
+    function _hook() internal override {}
+}
+`)
+
+	contracts := make(map[string]Node[NodeType])
+	for _, unit := range astBuilder.GetRoot().GetSourceUnits() {
+		for _, node := range unit.GetNodes() {
+			if named, ok := node.(interface{ GetName() string }); ok {
+				contracts[named.GetName()] = node
+			}
+		}
+	}
+
+	names := func(members []Node[NodeType]) []string {
+		toReturn := make([]string, 0)
+		for _, member := range members {
+			toReturn = append(toReturn, member.(interface{ GetName() string }).GetName())
+		}
+		return toReturn
+	}
+
+	iToken, ok := contracts["IToken"].(*Interface)
+	require.True(t, ok)
+	assert.False(t, iToken.IsFullyImplemented())
+	assert.ElementsMatch(t, []string{"totalSupply", "decimals"}, names(iToken.GetUnimplementedMembers()))
+
+	iEmpty, ok := contracts["IEmpty"].(*Interface)
+	require.True(t, ok)
+	assert.True(t, iEmpty.IsFullyImplemented())
+
+	math, ok := contracts["Math"].(*Library)
+	require.True(t, ok)
+	assert.True(t, math.IsFullyImplemented())
+	assert.Empty(t, math.GetUnimplementedMembers())
+
+	base, ok := contracts["Base"].(*Contract)
+	require.True(t, ok)
+	assert.False(t, base.IsFullyImplemented())
+	assert.ElementsMatch(t, []string{"guarded", "_hook", "totalSupply"}, names(base.GetUnimplementedMembers()))
+
+	partial, ok := contracts["Partial"].(*Contract)
+	require.True(t, ok)
+	assert.False(t, partial.IsFullyImplemented())
+	assert.ElementsMatch(t, []string{"_hook", "totalSupply"}, names(partial.GetUnimplementedMembers()))
+
+	token, ok := contracts["Token"].(*Contract)
+	require.True(t, ok)
+	assert.True(t, token.IsFullyImplemented())
+	assert.Empty(t, token.GetUnimplementedMembers())
+}
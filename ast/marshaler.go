@@ -35,3 +35,25 @@ func NewTypedStruct(m protoreflect.ProtoMessage, protoType string) *v3.TypedStru
 		Value: s,
 	}
 }
+
+// SetTypedStructField sets a field of the TypedStruct value to the given ProtoMessage, for fields the
+// protos message does not define. The field is left out when either the TypedStruct or the message is nil.
+func SetTypedStructField(t *v3.TypedStruct, name string, m protoreflect.ProtoMessage) {
+	if t == nil || t.GetValue() == nil || m == nil {
+		return
+	}
+
+	jsonBytes, err := protojson.Marshal(m)
+	if err != nil {
+		zap.L().Error("failed to marshal proto to json", zap.Error(err))
+		return
+	}
+
+	v := &structpb.Value{}
+	if err := protojson.Unmarshal(jsonBytes, v); err != nil {
+		zap.L().Error("failed to unmarshal json to structpb", zap.Error(err))
+		return
+	}
+
+	t.Value.Fields[name] = v
+}
@@ -92,6 +92,7 @@ func (m *ModifierDefinition) GetParameters() *ParameterList {
 
 // GetBody returns the body node of the modifier.
 func (m *ModifierDefinition) GetBody() *BodyNode {
+	m.Body.loadLazily()
 	return m.Body
 }
 
@@ -217,17 +218,12 @@ func (m *ModifierDefinition) ParseDefinition(
 	m.Parameters = parameters
 
 	if ctx.Block() != nil && !ctx.Block().IsEmpty() {
-		bodyNode := NewBodyNode(m.ASTBuilder, false)
-		bodyNode.ParseBlock(unit, contractNode, m, ctx.Block())
-		m.Body = bodyNode
-
-		if ctx.Block().AllUncheckedBlock() != nil {
-			for _, uncheckedCtx := range ctx.Block().AllUncheckedBlock() {
-				bodyNode := NewBodyNode(m.ASTBuilder, false)
-				bodyNode.ParseUncheckedBlock(unit, contractNode, m, uncheckedCtx)
-				m.Body.Statements = append(m.Body.Statements, bodyNode)
-			}
+		bodyNode := m.parseLazyBlock(unit, contractNode, m, ctx.Block())
+		if bodyNode == nil {
+			bodyNode = NewBodyNode(m.ASTBuilder, false)
+			bodyNode.ParseBlock(unit, contractNode, m, ctx.Block())
 		}
+		m.Body = bodyNode
 	}
 
 	m.currentModifiers = append(m.currentModifiers, m)
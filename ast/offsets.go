@@ -0,0 +1,218 @@
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/unpackdev/solgo"
+)
+
+// byteOrderMark is the UTF-8 byte order mark some editors start files with.
+const byteOrderMark = "\ufeff"
+
+// SourceOffsets converts between the offsets tools use for the same source. SrcNode offsets count
+// characters, while solc and its source maps count bytes, which differ once the source has multi-byte
+// characters, a byte order mark being one. Diffs and line references are often taken on the source
+// normalized by NormalizeSource, whose offsets differ from the raw ones once the source has CRLF line
+// endings or a byte order mark. Line numbers are the same for raw and normalized sources.
+type SourceOffsets struct {
+	content    string
+	charStarts []int64 // Byte offset of every character, followed by the length of the content.
+	normalized []int64 // Normalized offset of every byte, followed by the normalized length.
+	raw        []int64 // Byte offset of every normalized byte, followed by the length of the content.
+	lineStarts []int64 // Byte offset of every line.
+}
+
+// NewSourceOffsets returns the offsets of the raw content of a source.
+func NewSourceOffsets(content string) *SourceOffsets {
+	toReturn := &SourceOffsets{
+		content:    content,
+		charStarts: make([]int64, 0, len(content)+1),
+		normalized: make([]int64, 0, len(content)+1),
+		raw:        make([]int64, 0, len(content)+1),
+		lineStarts: []int64{0},
+	}
+
+	for offset := range content {
+		toReturn.charStarts = append(toReturn.charStarts, int64(offset))
+	}
+	toReturn.charStarts = append(toReturn.charStarts, int64(len(content)))
+
+	skip := 0
+	if strings.HasPrefix(content, byteOrderMark) {
+		skip = len(byteOrderMark)
+	}
+	for offset := 0; offset < len(content); offset++ {
+		toReturn.normalized = append(toReturn.normalized, int64(len(toReturn.raw)))
+
+		switch {
+		case offset < skip:
+			continue
+		case content[offset] == '\r' && offset+1 < len(content) && content[offset+1] == '\n':
+			// The carriage return of CRLF is dropped and maps to the line feed following it.
+			continue
+		case content[offset] == '\r' || content[offset] == '\n':
+			toReturn.lineStarts = append(toReturn.lineStarts, int64(offset+1))
+		}
+		toReturn.raw = append(toReturn.raw, int64(offset))
+	}
+	toReturn.normalized = append(toReturn.normalized, int64(len(toReturn.raw)))
+	toReturn.raw = append(toReturn.raw, int64(len(content)))
+
+	return toReturn
+}
+
+// GetContent returns the raw content the offsets are of.
+func (s *SourceOffsets) GetContent() string {
+	return s.content
+}
+
+// HasByteOrderMark reports whether the content starts with a byte order mark.
+func (s *SourceOffsets) HasByteOrderMark() bool {
+	return strings.HasPrefix(s.content, byteOrderMark)
+}
+
+// HasCRLF reports whether the content has CRLF line endings.
+func (s *SourceOffsets) HasCRLF() bool {
+	return strings.Contains(s.content, "\r\n")
+}
+
+// ByteOffset returns the byte offset of the character offset, or -1 if it is out of range. The
+// offset following the last character is that following the content.
+func (s *SourceOffsets) ByteOffset(charOffset int64) int64 {
+	if charOffset < 0 || charOffset >= int64(len(s.charStarts)) {
+		return -1
+	}
+	return s.charStarts[charOffset]
+}
+
+// CharOffset returns the offset of the character the byte offset is in, or -1 if it is out of range.
+func (s *SourceOffsets) CharOffset(byteOffset int64) int64 {
+	if byteOffset < 0 || byteOffset > int64(len(s.content)) {
+		return -1
+	}
+
+	low, high := 0, len(s.charStarts)-1
+	for low < high {
+		middle := (low + high + 1) / 2
+		if s.charStarts[middle] <= byteOffset {
+			low = middle
+		} else {
+			high = middle - 1
+		}
+	}
+	return int64(low)
+}
+
+// NormalizedOffset returns the byte offset in the normalized content of the raw byte offset, or -1
+// if it is out of range. Bytes dropped by normalization map to the byte following them.
+func (s *SourceOffsets) NormalizedOffset(byteOffset int64) int64 {
+	if byteOffset < 0 || byteOffset >= int64(len(s.normalized)) {
+		return -1
+	}
+	return s.normalized[byteOffset]
+}
+
+// RawOffset returns the raw byte offset of the byte offset in the normalized content, or -1 if it is
+// out of range.
+func (s *SourceOffsets) RawOffset(normalizedOffset int64) int64 {
+	if normalizedOffset < 0 || normalizedOffset >= int64(len(s.raw)) {
+		return -1
+	}
+	return s.raw[normalizedOffset]
+}
+
+// Position returns the line, starting at one, and the byte column, starting at zero, of the raw byte
+// offset, or zeros if it is out of range. LF, CRLF and CR all end lines.
+func (s *SourceOffsets) Position(byteOffset int64) (int64, int64) {
+	if byteOffset < 0 || byteOffset > int64(len(s.content)) {
+		return 0, 0
+	}
+
+	low, high := 0, len(s.lineStarts)-1
+	for low < high {
+		middle := (low + high + 1) / 2
+		if s.lineStarts[middle] <= byteOffset {
+			low = middle
+		} else {
+			high = middle - 1
+		}
+	}
+	return int64(low + 1), byteOffset - s.lineStarts[low]
+}
+
+// ByteRange returns the byte offset and length of the source node, whose offsets are character
+// offsets into the content, as solc reports them.
+func (s *SourceOffsets) ByteRange(src SrcNode) (int64, int64, error) {
+	start := s.ByteOffset(src.Start)
+	end := s.ByteOffset(src.End + 1)
+	if start < 0 || end < start {
+		return 0, 0, fmt.Errorf("source range %d:%d is out of the content", src.Start, src.End)
+	}
+	return start, end - start, nil
+}
+
+// NormalizeSource returns the content without a byte order mark and with LF line endings only.
+func NormalizeSource(content string) string {
+	content = strings.TrimPrefix(content, byteOrderMark)
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	return strings.ReplaceAll(content, "\r", "\n")
+}
+
+// LocateSource returns the source unit the source node is in and its byte offset and length in the
+// content of the source unit, as solc reports them. The offsets of the source node are those of the
+// parsed input, in which source units are separated by two new lines.
+func LocateSource(sources *solgo.Sources, src SrcNode) (*solgo.SourceUnit, int64, int64, error) {
+	if sources == nil {
+		return nil, 0, 0, errors.New("no sources to locate the source node in")
+	}
+
+	offset := int64(0)
+	for i, unit := range sources.SourceUnits {
+		if i > 0 {
+			offset += 2
+		}
+		length := int64(utf8.RuneCountInString(unit.Content))
+		if src.Start >= offset && src.Start < offset+length {
+			local := src
+			local.Start -= offset
+			local.End -= offset
+			start, size, err := NewSourceOffsets(unit.Content).ByteRange(local)
+			if err != nil {
+				return nil, 0, 0, fmt.Errorf("failed to locate source node in %s: %w", unit.Name, err)
+			}
+			return unit, start, size, nil
+		}
+		offset += length
+	}
+
+	return nil, 0, 0, fmt.Errorf("source offset %d is in no source unit", src.Start)
+}
+
+// ParsedOffset returns the offset in the parsed input, as SrcNode offsets are, of the byte offset in
+// the content of the source unit solc reports it in, given by its path or, for source units without
+// one, by its name with the .sol extension.
+func ParsedOffset(sources *solgo.Sources, path string, byteOffset int64) (int64, error) {
+	if sources == nil {
+		return 0, errors.New("no sources to locate the offset in")
+	}
+
+	offset := int64(0)
+	for i, unit := range sources.SourceUnits {
+		if i > 0 {
+			offset += 2
+		}
+		if unit.Path == path || (unit.Path == "" && unit.Name+".sol" == path) {
+			charOffset := NewSourceOffsets(unit.Content).CharOffset(byteOffset)
+			if charOffset < 0 {
+				return 0, fmt.Errorf("offset %d is out of source unit %s", byteOffset, unit.Name)
+			}
+			return offset + charOffset, nil
+		}
+		offset += int64(utf8.RuneCountInString(unit.Content))
+	}
+
+	return 0, fmt.Errorf("source unit %s not found", path)
+}
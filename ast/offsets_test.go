@@ -0,0 +1,68 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceOffsets(t *testing.T) {
+	content := "\ufeff// SPDX-License-Identifier: MIT\r\npragma solidity ^0.8.4;\r\n\r\n// Grüße\r\ncontract Ledger {\r\n    uint256 public total;\r\n}\r\n"
+	astBuilder := buildAstFromSourceForTest(t, "Ledger", content)
+
+	var contract *Contract
+	for _, node := range astBuilder.GetRoot().GetSourceUnits()[0].GetNodes() {
+		if found, ok := node.(*Contract); ok {
+			contract = found
+		}
+	}
+	require.NotNil(t, contract)
+	assert.Equal(t, int64(5), contract.GetSrc().GetLine())
+
+	// solc reports byte offsets, which the byte order mark and the umlauts are longer in.
+	start := int64(strings.Index(content, "contract Ledger"))
+	end := int64(strings.LastIndex(content, "}") + 1)
+	unit, byteStart, byteLength, err := LocateSource(astBuilder.sources, contract.GetSrc())
+	require.NoError(t, err)
+	assert.Equal(t, "Ledger", unit.Name)
+	assert.Equal(t, start, byteStart)
+	assert.Equal(t, end-start, byteLength)
+	assert.Equal(t, start-4, contract.GetSrc().GetStart())
+
+	offsets := NewSourceOffsets(content)
+	assert.True(t, offsets.HasByteOrderMark())
+	assert.True(t, offsets.HasCRLF())
+	assert.Equal(t, contract.GetSrc().GetStart(), offsets.CharOffset(start))
+	assert.Equal(t, start, offsets.ByteOffset(contract.GetSrc().GetStart()))
+	assert.Equal(t, int64(1), offsets.CharOffset(3), "offsets past the byte order mark")
+	assert.Equal(t, int64(0), offsets.CharOffset(2), "offsets within a character")
+
+	// Offsets and lines align with those of the normalized content.
+	normalized := NormalizeSource(content)
+	assert.Equal(t, "// SPDX-License-Identifier: MIT\npragma solidity ^0.8.4;\n\n// Grüße\ncontract Ledger {\n    uint256 public total;\n}\n", normalized)
+	normalizedStart := int64(strings.Index(normalized, "contract Ledger"))
+	assert.Equal(t, normalizedStart, offsets.NormalizedOffset(start))
+	assert.Equal(t, start, offsets.RawOffset(normalizedStart))
+	assert.Equal(t, int64(0), offsets.NormalizedOffset(0))
+	assert.Equal(t, int64(len(normalized)), offsets.NormalizedOffset(int64(len(content))))
+	assert.Equal(t, int64(len(content)), offsets.RawOffset(int64(len(normalized))))
+
+	crlf := int64(strings.Index(content, "\r\n"))
+	assert.Equal(t, offsets.NormalizedOffset(crlf+1), offsets.NormalizedOffset(crlf))
+
+	line, column := offsets.Position(start + int64(len("contract ")))
+	assert.Equal(t, int64(5), line)
+	assert.Equal(t, int64(9), column)
+	assert.Equal(t, "contract Ledger {", strings.Split(normalized, "\n")[line-1])
+	line, _ = NewSourceOffsets("a\rb\nc").Position(4)
+	assert.Equal(t, int64(3), line)
+
+	assert.Equal(t, int64(-1), offsets.ByteOffset(-1))
+	assert.Equal(t, int64(-1), offsets.RawOffset(int64(len(normalized)+1)))
+	_, _, err = offsets.ByteRange(SrcNode{Start: int64(len(content)), End: int64(len(content)) + 10})
+	assert.Error(t, err)
+	_, _, _, err = LocateSource(astBuilder.sources, SrcNode{Start: int64(len(content))})
+	assert.Error(t, err)
+}
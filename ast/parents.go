@@ -0,0 +1,105 @@
+package ast
+
+// NodeIndex indexes the nodes of the AST by id together with their parents, so that upward traversals
+// do not require a scan of the whole tree. The parent of a node is the node listing it among its
+// children, while source units and global nodes have none.
+type NodeIndex struct {
+	nodes   map[int64]Node[NodeType]
+	parents map[int64]Node[NodeType]
+}
+
+// NewNodeIndex builds the node index for the given root node.
+func NewNodeIndex(root *RootNode) *NodeIndex {
+	index := &NodeIndex{
+		nodes:   make(map[int64]Node[NodeType]),
+		parents: make(map[int64]Node[NodeType]),
+	}
+
+	if root == nil {
+		return index
+	}
+
+	for _, node := range root.GetGlobalNodes() {
+		index.walk(node, nil)
+	}
+
+	for _, unit := range root.GetSourceUnits() {
+		index.walk(unit, nil)
+	}
+
+	return index
+}
+
+// GetById returns the node with the given id, or nil if the tree has none.
+func (i *NodeIndex) GetById(id int64) Node[NodeType] {
+	return i.nodes[id]
+}
+
+// GetParent returns the parent of the node, or nil if it has none. Nodes that are not reachable from
+// the root, such as ones created after the index was built, are looked up by their ParentIndex.
+func (i *NodeIndex) GetParent(node Node[NodeType]) Node[NodeType] {
+	if isNilNode(node) {
+		return nil
+	}
+
+	if _, ok := i.nodes[node.GetId()]; ok {
+		return i.parents[node.GetId()]
+	}
+
+	if parentIndex := node.GetSrc().GetParentIndex(); parentIndex != 0 && parentIndex != node.GetId() {
+		return i.nodes[parentIndex]
+	}
+
+	return nil
+}
+
+// GetAncestors returns the ancestors of the node, starting with its parent and ending with the
+// source unit or global node it belongs to.
+func (i *NodeIndex) GetAncestors(node Node[NodeType]) []Node[NodeType] {
+	toReturn := make([]Node[NodeType], 0)
+	visited := make(map[int64]bool)
+	for parent := i.GetParent(node); parent != nil && !visited[parent.GetId()]; parent = i.GetParent(parent) {
+		visited[parent.GetId()] = true
+		toReturn = append(toReturn, parent)
+	}
+	return toReturn
+}
+
+// walk records the node and its descendants along with their parents. Nodes reachable through
+// several parents keep the first one.
+func (i *NodeIndex) walk(node Node[NodeType], parent Node[NodeType]) {
+	if isNilNode(node) {
+		return
+	}
+
+	if _, ok := i.nodes[node.GetId()]; ok {
+		return
+	}
+
+	i.nodes[node.GetId()] = node
+	if parent != nil {
+		i.parents[node.GetId()] = parent
+	}
+
+	for _, child := range node.GetNodes() {
+		i.walk(child, node)
+	}
+}
+
+// GetNodeIndex returns the index of nodes and their parents, building it on first use.
+func (b *ASTBuilder) GetNodeIndex() *NodeIndex {
+	if b.nodeIndex == nil {
+		b.nodeIndex = NewNodeIndex(b.GetRoot())
+	}
+	return b.nodeIndex
+}
+
+// GetParent returns the parent of the node. See NodeIndex.GetParent.
+func (b *ASTBuilder) GetParent(node Node[NodeType]) Node[NodeType] {
+	return b.GetNodeIndex().GetParent(node)
+}
+
+// GetAncestors returns the ancestors of the node, nearest first. See NodeIndex.GetAncestors.
+func (b *ASTBuilder) GetAncestors(node Node[NodeType]) []Node[NodeType] {
+	return b.GetNodeIndex().GetAncestors(node)
+}
@@ -0,0 +1,60 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+)
+
+func TestGetParent(t *testing.T) {
+	astBuilder := buildAstFromSourceForTest(t, "Ledger", `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.4;
+
+contract Ledger {
+    mapping(address => uint256) public balances;
+
+    function credit(address account, uint256 amount) public returns (uint256) {
+        if (amount > 0) {
+            balances[account] += amount;
+        }
+        return balances[account];
+    }
+}
+`)
+
+	contracts := collectNodesForTest(astBuilder, ast_pb.NodeType_CONTRACT_DEFINITION)
+	require.Len(t, contracts, 1)
+	functions := collectNodesForTest(astBuilder, ast_pb.NodeType_FUNCTION_DEFINITION)
+	require.Len(t, functions, 1)
+	ifs := collectNodesForTest(astBuilder, ast_pb.NodeType_IF_STATEMENT)
+	require.Len(t, ifs, 1)
+	assignments := collectNodesForTest(astBuilder, ast_pb.NodeType_ASSIGNMENT)
+	require.NotEmpty(t, assignments)
+	assignment := assignments[len(assignments)-1]
+
+	unit := astBuilder.GetRoot().GetSourceUnits()[0]
+
+	assert.Nil(t, astBuilder.GetParent(unit))
+	assert.Equal(t, unit, astBuilder.GetParent(contracts[0]))
+	assert.Equal(t, contracts[0], astBuilder.GetParent(functions[0]))
+	assert.Equal(t, functions[0], astBuilder.GetParent(ifs[0]))
+	assert.Equal(t, ifs[0].GetId(), astBuilder.GetNodeIndex().GetById(ifs[0].GetId()).GetId())
+
+	ancestors := astBuilder.GetAncestors(assignment)
+	require.GreaterOrEqual(t, len(ancestors), 4)
+	assert.Equal(t, ast_pb.NodeType_SOURCE_UNIT, ancestors[len(ancestors)-1].GetType())
+	assert.Equal(t, contracts[0], ancestors[len(ancestors)-2])
+	assert.Equal(t, functions[0], ancestors[len(ancestors)-3])
+	assert.Contains(t, ancestors, ifs[0])
+	assert.Empty(t, astBuilder.GetAncestors(unit))
+
+	// Nodes outside of the tree are looked up by their parent index.
+	detached := &BreakStatement{
+		Id:  astBuilder.GetNextID(),
+		Src: SrcNode{ParentIndex: functions[0].GetId()},
+	}
+	assert.Equal(t, functions[0], astBuilder.GetParent(detached))
+	assert.Nil(t, astBuilder.GetParent(nil))
+}
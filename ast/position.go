@@ -0,0 +1,137 @@
+package ast
+
+import (
+	"path/filepath"
+)
+
+// NodeAt returns the innermost node whose source range covers the offset, or nil if there is none.
+// Offsets are those of SrcNode, that is positions within the parsed input, which for sources made of
+// several files is their combined content.
+func NodeAt(root *RootNode, offset int64) Node[NodeType] {
+	if root == nil || offset < 0 {
+		return nil
+	}
+
+	return innermostNodeCovering(positionCandidates(root, ""), offset, offset)
+}
+
+// NodeAtRange returns the innermost node whose source range covers from start through end, offsets of
+// SrcNode, or nil if there is none.
+func NodeAtRange(root *RootNode, start int64, end int64) Node[NodeType] {
+	if root == nil || start < 0 || end < start {
+		return nil
+	}
+
+	return innermostNodeCovering(positionCandidates(root, ""), start, end)
+}
+
+// NodeAtPosition returns the innermost node covering the line and column in the given file, or nil if
+// there is none. Lines start at one and columns at zero, as in SrcNode, and an empty file matches every
+// source unit.
+//
+// Nodes only record where they start, so the offset of the line is derived from the nodes and comments
+// starting on it. When nothing starts on the line, such as on a closing brace, the position is taken to be
+// right after the content of the closest line above it that has nodes. Roots imported from solc JSON
+// carry no line information, so nothing is found in them.
+func NodeAtPosition(root *RootNode, file string, line int64, column int64) Node[NodeType] {
+	if root == nil || line <= 0 || column < 0 {
+		return nil
+	}
+
+	candidates := positionCandidates(root, file)
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	lineStarts := make(map[int64]int64)
+	addLineStart := func(src SrcNode) {
+		if src.Line > 0 && src.Start >= src.Column {
+			lineStarts[src.Line] = src.Start - src.Column
+		}
+	}
+	walkPositionNodes(candidates, func(node Node[NodeType]) {
+		addLineStart(node.GetSrc())
+	})
+	for _, comment := range root.GetComments() {
+		addLineStart(comment.GetSrc())
+	}
+
+	if start, ok := lineStarts[line]; ok {
+		return innermostNodeCovering(candidates, start+column, start+column)
+	}
+
+	// Take the position to be just after what the closest line above it ends with.
+	lower, upper := int64(0), int64(-1)
+	for known, start := range lineStarts {
+		if known < line && known > lower {
+			lower = known
+		}
+		if known > line && (upper < 0 || start < upper) {
+			upper = start
+		}
+	}
+	if lower == 0 {
+		return nil
+	}
+
+	end := int64(-1)
+	walkPositionNodes(candidates, func(node Node[NodeType]) {
+		src := node.GetSrc()
+		if src.Line == lower && src.End > end && (upper < 0 || src.End < upper) {
+			end = src.End
+		}
+	})
+	if end < 0 || (upper >= 0 && end+1 >= upper) {
+		return nil
+	}
+
+	return innermostNodeCovering(candidates, end+1, end+1)
+}
+
+// positionCandidates returns the source units of the given file, or of every file when file is empty,
+// together with the global nodes of the root.
+func positionCandidates(root *RootNode, file string) []Node[NodeType] {
+	toReturn := make([]Node[NodeType], 0)
+	for _, sourceUnit := range root.GetSourceUnits() {
+		if file == "" || filepath.Base(sourceUnit.GetAbsolutePath()) == filepath.Base(filepath.Clean(file)) {
+			toReturn = append(toReturn, sourceUnit)
+		}
+	}
+
+	if len(toReturn) == 0 {
+		return toReturn
+	}
+
+	return append(toReturn, root.GetGlobalNodes()...)
+}
+
+// innermostNodeCovering returns the smallest node whose source range covers from through to. Of nodes
+// with equal ranges the deepest one is returned.
+func innermostNodeCovering(nodes []Node[NodeType], from int64, to int64) Node[NodeType] {
+	var toReturn Node[NodeType]
+	size := int64(-1)
+
+	walkPositionNodes(nodes, func(node Node[NodeType]) {
+		src := node.GetSrc()
+		if src.End < src.Start || src.Start > from || src.End < to {
+			return
+		}
+
+		if size < 0 || src.End-src.Start <= size {
+			toReturn, size = node, src.End-src.Start
+		}
+	})
+
+	return toReturn
+}
+
+// walkPositionNodes calls visit for the nodes and all of their descendants, parents before children.
+func walkPositionNodes(nodes []Node[NodeType], visit func(node Node[NodeType])) {
+	for _, node := range nodes {
+		if isNilNode(node) {
+			continue
+		}
+		visit(node)
+		walkPositionNodes(node.GetNodes(), visit)
+	}
+}
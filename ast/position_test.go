@@ -0,0 +1,90 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeAt(t *testing.T) {
+	content := `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.4;
+
+contract Ledger {
+    mapping(address => uint256) public balances;
+
+    function credit(address to, uint256 amount) external {
+        balances[to] += amount;
+    }
+}
+`
+	astBuilder := buildAstFromSourceForTest(t, "Ledger", content)
+	root := astBuilder.GetRoot()
+
+	offset := int64(strings.Index(content, "amount;"))
+	node := NodeAt(root, offset)
+	require.NotNil(t, node)
+	identifier, ok := node.(*PrimaryExpression)
+	require.True(t, ok, "got %T", node)
+	assert.Equal(t, "amount", identifier.GetName())
+
+	node = NodeAt(root, int64(strings.Index(content, "to]")))
+	require.NotNil(t, node)
+	identifier, ok = node.(*PrimaryExpression)
+	require.True(t, ok, "got %T", node)
+	assert.Equal(t, "to", identifier.GetName())
+
+	node = NodeAt(root, int64(strings.Index(content, "external")))
+	require.NotNil(t, node)
+	fn, ok := node.(*Function)
+	require.True(t, ok, "got %T", node)
+	assert.Equal(t, "credit", fn.GetName())
+
+	assert.Nil(t, NodeAt(root, -1))
+	assert.Nil(t, NodeAt(root, int64(len(content)+100)))
+	assert.Nil(t, NodeAt(nil, 0))
+}
+
+func TestNodeAtPosition(t *testing.T) {
+	content := `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.4;
+
+contract Ledger {
+    mapping(address => uint256) public balances;
+
+    function credit(address to, uint256 amount) external {
+        balances[to] += amount;
+    }
+}
+`
+	astBuilder := buildAstFromSourceForTest(t, "Ledger", content)
+	root := astBuilder.GetRoot()
+
+	node := NodeAtPosition(root, "Ledger.sol", 8, 24)
+	require.NotNil(t, node)
+	identifier, ok := node.(*PrimaryExpression)
+	require.True(t, ok, "got %T", node)
+	assert.Equal(t, "amount", identifier.GetName())
+
+	// Paths are matched by file name.
+	assert.Equal(t, node, NodeAtPosition(root, "contracts/Ledger.sol", 8, 24))
+	assert.Equal(t, node, NodeAtPosition(root, "", 8, 24))
+
+	node = NodeAtPosition(root, "Ledger.sol", 5, 40)
+	require.NotNil(t, node)
+	stateVariable, ok := node.(*StateVariableDeclaration)
+	require.True(t, ok, "got %T", node)
+	assert.Equal(t, "balances", stateVariable.GetName())
+
+	// Nothing starts on the closing brace line, so the function spanning it is returned.
+	node = NodeAtPosition(root, "Ledger.sol", 9, 4)
+	require.NotNil(t, node)
+	fn, ok := node.(*Function)
+	require.True(t, ok, "got %T", node)
+	assert.Equal(t, "credit", fn.GetName())
+
+	assert.Nil(t, NodeAtPosition(root, "Other.sol", 8, 24))
+	assert.Nil(t, NodeAtPosition(root, "Ledger.sol", 0, 0))
+}
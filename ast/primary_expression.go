@@ -513,6 +513,22 @@ func (p *PrimaryExpression) Parse(
 		}
 	}
 
+	// Number literals with a subdenomination, such as `2 ether` or `1 days`, keep the unit in the value
+	// so that their type can be computed from it.
+	if subDenominationCtx := ctx.LiteralWithSubDenomination(); subDenominationCtx != nil {
+		p.NodeType = ast_pb.NodeType_LITERAL
+		p.Kind = ast_pb.NodeType_NUMBER
+		p.Pure = true
+		p.Name = ""
+		p.Value = fmt.Sprintf(
+			"%s %s",
+			subDenominationCtx.NumberLiteral().GetText(),
+			subDenominationCtx.SubDenomination().GetText(),
+		)
+		p.HexValue = hex.EncodeToString([]byte(p.Value))
+		p.TypeDescription = numberLiteralType(p.Value)
+	}
+
 	if fnNode != nil && p.TypeDescription == nil {
 		if fn, ok := fnNode.(*Function); ok {
 			if fn.GetParameters() != nil {
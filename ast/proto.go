@@ -0,0 +1,553 @@
+package ast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	v3 "github.com/cncf/xds/go/xds/type/v3"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// typedProtoNode pairs the message a TypedStruct decodes into with the node it is imported as.
+type typedProtoNode struct {
+	message protoreflect.ProtoMessage
+	node    Node[NodeType]
+}
+
+// typedProtoNodes maps the type names used in TypedStruct urls by ToProto to the message and node of
+// each type. Names shared by several nodes, such as Contract for interfaces and libraries, are told
+// apart after decoding by protoImporter.typedNode.
+var typedProtoNodes = map[string]typedProtoNode{
+	"AndOperation":                   {&ast_pb.AndOperation{}, &AndOperation{}},
+	"Assignment":                     {&ast_pb.Assignment{}, &Assignment{}},
+	"BinaryOperation":                {&ast_pb.BinaryOperation{}, &BinaryOperation{}},
+	"BitAndOperation":                {&ast_pb.BitAndOperation{}, &BitAndOperation{}},
+	"BitOrOperation":                 {&ast_pb.BitOrOperation{}, &BitOrOperation{}},
+	"BitXorOperation":                {&ast_pb.BitXorOperation{}, &BitXorOperation{}},
+	"Block":                          {&ast_pb.Body{}, &BodyNode{}},
+	"Break":                          {&ast_pb.Break{}, &BreakStatement{}},
+	"Catch":                          {&ast_pb.Catch{}, &CatchStatement{}},
+	"Conditional":                    {&ast_pb.Conditional{}, &Conditional{}},
+	"Continue":                       {&ast_pb.Continue{}, &ContinueStatement{}},
+	"Contract":                       {&ast_pb.Contract{}, &Contract{}},
+	"Do":                             {&ast_pb.Do{}, &DoWhileStatement{}},
+	"Emit":                           {&ast_pb.Emit{}, &Emit{}},
+	"Enum":                           {&ast_pb.Enum{}, &EnumDefinition{}},
+	"Error":                          {&ast_pb.Error{}, &ErrorDefinition{}},
+	"Event":                          {&ast_pb.Event{}, &EventDefinition{}},
+	"ExprOperation":                  {&ast_pb.ExprOperation{}, &ExprOperation{}},
+	"ExpressionContext":              {&ast_pb.Expression{}, &ExpressionContext{}},
+	"Fallback":                       {&ast_pb.Fallback{}, &Fallback{}},
+	"For":                            {&ast_pb.For{}, &ForStatement{}},
+	"Function":                       {&ast_pb.Function{}, &Function{}},
+	"FunctionCall":                   {&ast_pb.FunctionCall{}, &FunctionCall{}},
+	"FunctionCallOption":             {&ast_pb.FunctionCallOption{}, &FunctionCallOption{}},
+	"If":                             {&ast_pb.If{}, &IfStatement{}},
+	"Import":                         {&ast_pb.Import{}, &Import{}},
+	"IndexAccess":                    {&ast_pb.IndexAccess{}, &IndexAccess{}},
+	"IndexRange":                     {&ast_pb.IndexRange{}, &IndexRange{}},
+	"InlineArray":                    {&ast_pb.InlineArray{}, &InlineArray{}},
+	"MemberAccess":                   {&ast_pb.MemberAccess{}, &MemberAccessExpression{}},
+	"MetaType":                       {&ast_pb.MetaType{}, &MetaType{}},
+	"Modifier":                       {&ast_pb.Modifier{}, &ModifierDefinition{}},
+	"NewExpression":                  {&ast_pb.NewExpression{}, &NewExpr{}},
+	"PayableConversion":              {&ast_pb.PayableConversion{}, &PayableConversion{}},
+	"Pragma":                         {&ast_pb.Pragma{}, &Pragma{}},
+	"PrimaryExpression":              {&ast_pb.PrimaryExpression{}, &PrimaryExpression{}},
+	"Receive":                        {&ast_pb.Receive{}, &Receive{}},
+	"Return":                         {&ast_pb.Return{}, &ReturnStatement{}},
+	"Revert":                         {&ast_pb.Revert{}, &RevertStatement{}},
+	"ShiftOperation":                 {&ast_pb.ShiftOperation{}, &ShiftOperation{}},
+	"Struct":                         {&ast_pb.Struct{}, &StructDefinition{}},
+	"Try":                            {&ast_pb.Try{}, &TryStatement{}},
+	"Tuple":                          {&ast_pb.Tuple{}, &TupleExpression{}},
+	"UncheckedBlock":                 {&ast_pb.Body{}, &UncheckedBlock{}},
+	"UnaryPrefix":                    {&ast_pb.UnaryPrefix{}, &UnaryPrefix{}},
+	"UnarySuffix":                    {&ast_pb.UnarySuffix{}, &UnarySuffix{}},
+	"UserDefinedValueTypeDefinition": {&ast_pb.UserDefinedValueTypeDefinition{}, &UserDefinedValueTypeDefinition{}},
+	"Using":                          {&ast_pb.Using{}, &UsingDirective{}},
+	"Variable":                       {&ast_pb.Variable{}, &VariableDeclaration{}},
+	"While":                          {&ast_pb.While{}, &WhileStatement{}},
+	"AssemblyStatement":              {&ast_pb.AssemblyStatement{}, &Yul{}},
+	"YulAssignmentStatement":         {&ast_pb.YulAssignmentStatement{}, &YulAssignment{}},
+	"YulBlockStatement":              {&ast_pb.YulBlockStatement{}, &YulBlockStatement{}},
+	"YulBreakStatement":              {&ast_pb.YulBreakStatement{}, &YulBreakStatement{}},
+	"YulContinueStatement":           {&ast_pb.YulContinueStatement{}, &YulContinueStatement{}},
+	"YulExpressionStatement":         {&ast_pb.YulExpressionStatement{}, &YulExpressionStatement{}},
+	"YulForStatement":                {&ast_pb.YulForStatement{}, &YulForStatement{}},
+	"YulFunctionDefinition":          {&ast_pb.YulFunctionDefinition{}, &YulFunctionDefinition{}},
+	"YulFunctionCallStatement":       {&ast_pb.YulFunctionCallStatement{}, &YulFunctionCallStatement{}},
+	"YulIdentifier":                  {&ast_pb.YulIdentifier{}, &YulIdentifier{}},
+	"YulIfStatement":                 {&ast_pb.YulIfStatement{}, &YulIfStatement{}},
+	"YulLeaveStatement":              {&ast_pb.YulLeaveStatement{}, &YulLeaveStatement{}},
+	"YulLiteralStatement":            {&ast_pb.YulLiteralStatement{}, &YulLiteralStatement{}},
+	"YulStatement":                   {&ast_pb.YulStatement{}, &YulStatement{}},
+	"YulSwitchStatement":             {&ast_pb.YulSwitchStatement{}, &YulSwitchStatement{}},
+	"YulVariableStatement":           {&ast_pb.YulVariableStatement{}, &YulVariable{}},
+}
+
+// plainProtoNodes maps the messages ToProto returns without a TypedStruct to the node they are
+// imported as, for fields that hold them as a Node.
+var plainProtoNodes = map[reflect.Type]Node[NodeType]{
+	reflect.TypeOf(&ast_pb.Body{}):                   &BodyNode{},
+	reflect.TypeOf(&ast_pb.Declaration{}):            &Declaration{},
+	reflect.TypeOf(&ast_pb.ModifierInvocation{}):     &ModifierInvocation{},
+	reflect.TypeOf(&ast_pb.OverrideSpecifier{}):      &OverrideSpecifier{},
+	reflect.TypeOf(&ast_pb.Parameter{}):              &Parameter{},
+	reflect.TypeOf(&ast_pb.PathNode{}):               &PathNode{},
+	reflect.TypeOf(&ast_pb.TypeName{}):               &TypeName{},
+	reflect.TypeOf(&ast_pb.YulSwitchCaseStatement{}): &YulSwitchCaseStatement{},
+}
+
+// protoFieldAliases maps proto fields to node fields of a different name.
+var protoFieldAliases = map[string]string{
+	"key_type_location":   "KeyNameLocation",
+	"mutability":          "StateMutability",
+	"value_type_location": "ValueNameLocation",
+}
+
+var (
+	typedStructType = reflect.TypeOf(&v3.TypedStruct{})
+	builderType     = reflect.TypeOf(&ASTBuilder{})
+)
+
+// protoImporter converts protobuf messages produced by ToProto back into AST nodes.
+type protoImporter struct {
+	*ASTBuilder
+	maxID int64
+}
+
+// FromProto reconstructs a RootNode from its protobuf representation as produced by RootNode.ToProto.
+// See ASTBuilder.ImportFromProto for details.
+func FromProto(root *ast_pb.RootSourceUnit) (*RootNode, error) {
+	return NewAstBuilder(nil, nil).ImportFromProto(context.Background(), root)
+}
+
+// ImportFromProto imports the AST from its protobuf representation, so that cached or streamed trees
+// can be reloaded without parsing the sources again. Converting the imported root back with ToProto
+// yields the same message.
+//
+// Only what the protobuf carries is restored: fields that ToProto leaves out, such as the name of a
+// constructor or the value of an expression context, stay empty, and like with ImportFromJSON the
+// parser contexts are not available.
+func (b *ASTBuilder) ImportFromProto(ctx context.Context, root *ast_pb.RootSourceUnit) (*RootNode, error) {
+	if root == nil {
+		return nil, errors.New("protobuf root source unit is nil")
+	}
+
+	importer := &protoImporter{ASTBuilder: b}
+	toReturn := &RootNode{
+		Id:              root.GetId(),
+		NodeType:        root.GetNodeType(),
+		EntrySourceUnit: root.GetEntrySourceUnit(),
+		SourceUnits:     make([]*SourceUnit[Node[ast_pb.SourceUnit]], 0, len(root.GetSourceUnits())),
+		Globals:         make([]Node[NodeType], 0, len(root.GetGlobalNodes())),
+		Comments:        make([]*Comment, 0, len(root.GetComments())),
+	}
+	importer.trackID(root.GetId())
+
+	for _, unit := range root.GetSourceUnits() {
+		sourceUnit, err := importer.sourceUnit(unit)
+		if err != nil {
+			return nil, err
+		}
+		toReturn.SourceUnits = append(toReturn.SourceUnits, sourceUnit)
+	}
+
+	for _, global := range root.GetGlobalNodes() {
+		node, err := importer.typedNode(global)
+		if err != nil {
+			return nil, err
+		}
+		toReturn.Globals = append(toReturn.Globals, node)
+	}
+
+	for _, comment := range root.GetComments() {
+		toReturn.Comments = append(toReturn.Comments, &Comment{
+			Id:       comment.GetId(),
+			Src:      srcFromProto(comment.GetSrc()),
+			NodeType: comment.GetNodeType(),
+			Text:     comment.GetText(),
+		})
+		importer.trackID(comment.GetId())
+	}
+
+	// New nodes get ids after the largest id of the imported tree.
+	if importer.maxID >= b.nextID {
+		b.nextID = importer.maxID + 1
+	}
+
+	if b.tree == nil {
+		b.tree = NewTree(b)
+	}
+
+	b.tree.SetRoot(toReturn)
+	b.symbolTable = nil
+	b.nodeIndex = nil
+
+	return toReturn, nil
+}
+
+// NodeFromProto reconstructs a single node from the TypedStruct its ToProto method returns.
+func NodeFromProto(node *v3.TypedStruct) (Node[NodeType], error) {
+	importer := &protoImporter{ASTBuilder: NewAstBuilder(nil, nil)}
+	return importer.typedNode(node)
+}
+
+// sourceUnit converts a source unit message into a SourceUnit with its nodes.
+func (p *protoImporter) sourceUnit(unit *ast_pb.SourceUnit) (*SourceUnit[Node[ast_pb.SourceUnit]], error) {
+	toReturn := &SourceUnit[Node[ast_pb.SourceUnit]]{
+		Id:              unit.GetId(),
+		License:         unit.GetLicense(),
+		AbsolutePath:    unit.GetAbsolutePath(),
+		Name:            unit.GetName(),
+		NodeType:        unit.GetNodeType(),
+		Src:             srcFromProto(unit.GetSrc()),
+		ExportedSymbols: make([]Symbol, 0, len(unit.GetExportedSymbols())),
+		BaseContracts:   make([]*BaseContract, 0),
+		Nodes:           make([]Node[NodeType], 0, len(unit.GetRoot().GetNodes())),
+	}
+	p.trackID(unit.GetId())
+
+	for _, symbol := range unit.GetExportedSymbols() {
+		toReturn.ExportedSymbols = append(toReturn.ExportedSymbols, Symbol{
+			Id:           symbol.GetId(),
+			Name:         symbol.GetName(),
+			AbsolutePath: symbol.GetAbsolutePath(),
+		})
+	}
+
+	for _, unitNode := range unit.GetRoot().GetNodes() {
+		node, err := p.typedNode(unitNode)
+		if err != nil {
+			return nil, err
+		}
+		toReturn.Nodes = append(toReturn.Nodes, node)
+
+		switch node.GetType() {
+		case ast_pb.NodeType_CONTRACT_DEFINITION:
+			toReturn.Contract = node
+			switch contract := node.(type) {
+			case *Contract:
+				toReturn.BaseContracts = contract.GetBaseContracts()
+			case *Interface:
+				toReturn.BaseContracts = contract.GetBaseContracts()
+			case *Library:
+				toReturn.BaseContracts = contract.GetBaseContracts()
+			}
+		}
+	}
+
+	return toReturn, nil
+}
+
+// typedNode decodes the TypedStruct and converts it into the node of its type.
+func (p *protoImporter) typedNode(typed *v3.TypedStruct) (Node[NodeType], error) {
+	if typed == nil {
+		return nil, errors.New("protobuf node is nil")
+	}
+
+	name := typed.GetTypeUrl()[strings.LastIndex(typed.GetTypeUrl(), ".")+1:]
+	kind, ok := typedProtoNodes[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported protobuf node type %q", typed.GetTypeUrl())
+	}
+
+	message := kind.message
+	node := kind.node
+
+	// Declarations of state variables share the Variable type with local ones, but carry a name.
+	if name == "Variable" {
+		if _, ok := typed.GetValue().GetFields()["name"]; ok {
+			message, node = &ast_pb.StateVariable{}, &StateVariableDeclaration{}
+		}
+	}
+
+	data, err := protojson.Marshal(typed.GetValue())
+	if err != nil {
+		return nil, err
+	}
+
+	decoded := message.ProtoReflect().New().Interface()
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, decoded); err != nil {
+		return nil, fmt.Errorf("failure to decode protobuf node %s: %w", name, err)
+	}
+
+	switch msg := decoded.(type) {
+	case *ast_pb.Contract:
+		switch msg.GetKind() {
+		case ast_pb.NodeType_KIND_INTERFACE:
+			node = &Interface{}
+		case ast_pb.NodeType_KIND_LIBRARY:
+			node = &Library{}
+		}
+	case *ast_pb.Function:
+		if msg.GetKind() == ast_pb.NodeType_CONSTRUCTOR {
+			node = &Constructor{}
+		}
+	case *ast_pb.Body:
+		// Unchecked blocks used to be exported with the Block type name.
+		if msg.GetNodeType() == ast_pb.NodeType_UNCHECKED_BLOCK {
+			node = &UncheckedBlock{}
+		}
+	}
+
+	toReturn, err := p.node(node, decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	// Blocks are only wrapped into a TypedStruct when they are statements of their own.
+	if body, ok := toReturn.(*BodyNode); ok {
+		body.TypedProto = true
+	}
+
+	// Switch statements carry their expression and default case outside of the protos message.
+	if switchNode, ok := toReturn.(*YulSwitchStatement); ok {
+		if err := p.yulSwitch(switchNode, typed.GetValue().GetFields()); err != nil {
+			return nil, err
+		}
+	}
+
+	return toReturn, nil
+}
+
+// yulSwitch restores the expression and default case of the switch statement, which
+// YulSwitchStatement.ToProto sets as fields of the TypedStruct value.
+func (p *protoImporter) yulSwitch(switchNode *YulSwitchStatement, fields map[string]*structpb.Value) error {
+	if expression, ok := fields["expression"]; ok {
+		data, err := protojson.Marshal(expression)
+		if err != nil {
+			return err
+		}
+
+		typed := &v3.TypedStruct{}
+		if err := protojson.Unmarshal(data, typed); err != nil {
+			return fmt.Errorf("failure to decode yul switch expression: %w", err)
+		}
+
+		if switchNode.Expression, err = p.typedNode(typed); err != nil {
+			return err
+		}
+	}
+
+	if defaultCase, ok := fields["default"]; ok {
+		data, err := protojson.Marshal(defaultCase)
+		if err != nil {
+			return err
+		}
+
+		message := &ast_pb.YulSwitchCaseStatement{}
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, message); err != nil {
+			return fmt.Errorf("failure to decode yul switch default case: %w", err)
+		}
+
+		if switchNode.Default, err = p.node(&YulSwitchCaseStatement{}, message); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// node creates a new node of the same type as the prototype and fills it from the message.
+func (p *protoImporter) node(prototype Node[NodeType], message protoreflect.ProtoMessage) (Node[NodeType], error) {
+	toReturn := reflect.New(reflect.TypeOf(prototype).Elem())
+	if err := p.fill(toReturn.Elem(), reflect.ValueOf(message)); err != nil {
+		return nil, err
+	}
+
+	// Operations derive their own type from the type descriptions of their operands.
+	switch node := toReturn.Interface().(type) {
+	case *ShiftOperation:
+		if typeDescription := shiftOperationType(node); typeDescription != nil {
+			node.TypeDescription = typeDescription
+		} else if len(node.TypeDescriptions) > 1 {
+			node.TypeDescription = node.TypeDescriptions[1]
+		}
+	case *BitXorOperation:
+		if typeDescription := bitXorOperationType(node); typeDescription != nil {
+			node.TypeDescription = typeDescription
+		} else if len(node.TypeDescriptions) > 1 {
+			node.TypeDescription = node.TypeDescriptions[1]
+		} else if len(node.TypeDescriptions) > 0 {
+			node.TypeDescription = node.TypeDescriptions[0]
+		}
+	case *Conditional:
+		node.TypeDescription = conditionalType(node)
+	}
+
+	return toReturn.Interface().(Node[NodeType]), nil
+}
+
+// fill sets the fields of the struct to the values of the message fields of the same name.
+func (p *protoImporter) fill(target reflect.Value, message reflect.Value) error {
+	if message.IsNil() {
+		return nil
+	}
+
+	if field := target.FieldByName("ASTBuilder"); field.IsValid() && field.Type() == builderType {
+		field.Set(reflect.ValueOf(p.ASTBuilder))
+	}
+
+	source := message.Elem()
+	for i := 0; i < source.NumField(); i++ {
+		field := source.Type().Field(i)
+		name := protoFieldName(field)
+		if name == "" || source.Field(i).IsZero() {
+			continue
+		}
+
+		if name == "id" && field.Type.Kind() == reflect.Int64 {
+			p.trackID(source.Field(i).Int())
+		}
+
+		targetField := protoTargetField(target, name, field.Name)
+		if !targetField.IsValid() {
+			// Operations describe their type by the first of the type descriptions they keep.
+			if descriptions := target.FieldByName("TypeDescriptions"); name == "type_description" && descriptions.IsValid() {
+				targetField = reflect.MakeSlice(descriptions.Type(), 1, 1)
+				descriptions.Set(targetField)
+				targetField = targetField.Index(0)
+			} else {
+				continue
+			}
+		}
+
+		if err := p.assign(targetField, source.Field(i)); err != nil {
+			return fmt.Errorf("failure to import %s.%s: %w", target.Type().Name(), field.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// assign converts the message value into the type of the target and sets it.
+func (p *protoImporter) assign(target reflect.Value, value reflect.Value) error {
+	switch {
+	case value.Type() == typedStructType:
+		node, err := p.typedNode(value.Interface().(*v3.TypedStruct))
+		if err != nil {
+			return err
+		}
+		if !reflect.TypeOf(node).AssignableTo(target.Type()) {
+			return fmt.Errorf("node %T cannot be assigned to %s", node, target.Type())
+		}
+		target.Set(reflect.ValueOf(node))
+
+	case value.Kind() == reflect.Slice && target.Kind() == reflect.Slice:
+		if value.Type().AssignableTo(target.Type()) {
+			target.Set(reflect.AppendSlice(reflect.MakeSlice(target.Type(), 0, value.Len()), value))
+			return nil
+		}
+
+		items := reflect.MakeSlice(target.Type(), value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			if value.Index(i).IsZero() {
+				continue
+			}
+			if err := p.assign(items.Index(i), value.Index(i)); err != nil {
+				return err
+			}
+		}
+		target.Set(items)
+
+	case value.Type().AssignableTo(target.Type()):
+		target.Set(value)
+
+	case value.Kind() == reflect.Ptr && value.Type().Elem().Kind() == reflect.Struct:
+		switch target.Kind() {
+		case reflect.Interface:
+			prototype, ok := plainProtoNodes[value.Type()]
+			if !ok {
+				return fmt.Errorf("unsupported protobuf message %s", value.Type())
+			}
+			node, err := p.node(prototype, value.Interface().(protoreflect.ProtoMessage))
+			if err != nil {
+				return err
+			}
+			target.Set(reflect.ValueOf(node))
+		case reflect.Ptr:
+			item := reflect.New(target.Type().Elem())
+			if err := p.fill(item.Elem(), value); err != nil {
+				return err
+			}
+			target.Set(item)
+		case reflect.Struct:
+			return p.fill(target, value)
+		default:
+			return fmt.Errorf("protobuf message %s cannot be assigned to %s", value.Type(), target.Type())
+		}
+
+	case value.Type().ConvertibleTo(target.Type()):
+		target.Set(value.Convert(target.Type()))
+
+	default:
+		return fmt.Errorf("protobuf value %s cannot be assigned to %s", value.Type(), target.Type())
+	}
+
+	return nil
+}
+
+// trackID records the id so that new nodes are given ids after those of the imported tree.
+func (p *protoImporter) trackID(id int64) {
+	if id > p.maxID {
+		p.maxID = id
+	}
+}
+
+// protoFieldName returns the name of the field in the proto definition, or an empty string for
+// fields that are not part of the message, such as its internal state.
+func protoFieldName(field reflect.StructField) string {
+	for _, option := range strings.Split(field.Tag.Get("protobuf"), ",") {
+		if strings.HasPrefix(option, "name=") {
+			return strings.TrimPrefix(option, "name=")
+		}
+	}
+	return ""
+}
+
+// protoTargetField returns the field of the node the proto field is imported into. Fields are matched
+// by their json name first and by their Go name otherwise, where node fields leave out the Is prefix of
+// boolean proto fields. Fields named differently altogether are looked up in protoFieldAliases.
+func protoTargetField(target reflect.Value, name string, goName string) reflect.Value {
+	var byName reflect.Value
+	for i := 0; i < target.NumField(); i++ {
+		field := target.Type().Field(i)
+		if field.Anonymous || !field.IsExported() {
+			continue
+		}
+
+		if strings.Split(field.Tag.Get("json"), ",")[0] == name {
+			return target.Field(i)
+		}
+
+		if !byName.IsValid() && (field.Name == goName || "Is"+field.Name == goName) {
+			byName = target.Field(i)
+		}
+	}
+
+	if alias, ok := protoFieldAliases[name]; ok && !byName.IsValid() {
+		return target.FieldByName(alias)
+	}
+
+	return byName
+}
+
+// srcFromProto converts the source location message into a SrcNode.
+func srcFromProto(src *ast_pb.Src) SrcNode {
+	return SrcNode{
+		Line:        src.GetLine(),
+		Column:      src.GetColumn(),
+		Start:       src.GetStart(),
+		End:         src.GetEnd(),
+		Length:      src.GetLength(),
+		ParentIndex: src.GetParentIndex(),
+	}
+}
@@ -0,0 +1,179 @@
+package ast
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+	"github.com/unpackdev/solgo"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	v3 "github.com/cncf/xds/go/xds/type/v3"
+)
+
+func TestFromProto(t *testing.T) {
+	// All of the defined test cases can be discovered in sources_test.go file
+	testCases := getSourceTestCases(t)
+
+	for _, testCase := range testCases {
+		if testCase.disabled || testCase.expectsErrors {
+			continue
+		}
+
+		t.Run(testCase.name, func(t *testing.T) {
+			parser, err := solgo.NewParserFromSources(context.TODO(), testCase.sources)
+			require.NoError(t, err)
+
+			astBuilder := NewAstBuilder(parser.GetParser(), parser.GetSources())
+			require.NoError(t, parser.RegisterListener(solgo.ListenerAst, astBuilder))
+			require.Empty(t, parser.Parse())
+			astBuilder.ResolveReferences()
+
+			expected := astBuilder.ToProto()
+
+			root, err := FromProto(expected)
+			require.NoError(t, err)
+			require.NotNil(t, root)
+
+			assert.True(t, proto.Equal(expected, root.ToProto()), "protobuf of the imported root differs")
+			assert.Equal(t, len(astBuilder.GetRoot().GetSourceUnits()), len(root.GetSourceUnits()))
+			assert.Equal(t, astBuilder.GetRoot().GetEntrySourceUnit(), root.GetEntrySourceUnit())
+		})
+	}
+}
+
+func TestImportFromProto(t *testing.T) {
+	astBuilder := buildAstFromSourceForTest(t, "Vault", `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.4;
+
+interface IToken {
+    function transfer(address to, uint256 amount) external returns (bool);
+}
+
+library Math {
+    function max(uint256 a, uint256 b) internal pure returns (uint256) {
+        return a >= b ? a : b;
+    }
+}
+
+contract Vault {
+    using Math for uint256;
+
+    enum State { Open, Closed }
+
+    struct Position {
+        uint256 amount;
+        uint256 since;
+    }
+
+    error Closed();
+
+    event Deposited(address indexed account, uint256 amount);
+
+    State public state;
+    mapping(address => Position) public positions;
+
+    constructor() {
+        state = State.Open;
+    }
+
+    modifier whenOpen() {
+        if (state != State.Open) {
+            revert Closed();
+        }
+        _;
+    }
+
+    function deposit() external payable whenOpen {
+        Position storage position = positions[msg.sender];
+        position.amount = position.amount.max(msg.value);
+        unchecked {
+            position.since++;
+        }
+        emit Deposited(msg.sender, msg.value);
+    }
+
+    function withdraw(IToken token) external {
+        delete positions[msg.sender];
+        try token.transfer(msg.sender, 1) returns (bool ok) {
+            require(ok, "transfer");
+        } catch {
+            assembly {
+                let size := extcodesize(token)
+                if iszero(size) { revert(0, 0) }
+            }
+        }
+    }
+
+    receive() external payable {}
+}
+`)
+
+	expected := astBuilder.ToProto()
+
+	imported := NewAstBuilder(nil, nil)
+	root, err := imported.ImportFromProto(context.Background(), expected)
+	require.NoError(t, err)
+	require.NotNil(t, root)
+
+	assert.Equal(t, root, imported.GetRoot())
+	assert.True(t, proto.Equal(expected, root.ToProto()), "protobuf of the imported root differs")
+
+	contract, ok := root.GetSourceUnits()[len(root.GetSourceUnits())-1].Contract.(*Contract)
+	require.True(t, ok)
+	assert.Equal(t, "Vault", contract.GetName())
+	assert.NotNil(t, contract.GetConstructor())
+	assert.NotNil(t, contract.GetReceive())
+	assert.Len(t, contract.GetFunctions(), 2)
+
+	_, ok = root.GetSourceUnits()[0].Contract.(*Interface)
+	assert.True(t, ok)
+	_, ok = root.GetSourceUnits()[1].Contract.(*Library)
+	assert.True(t, ok)
+
+	// Nodes created after the import do not reuse ids of the imported tree.
+	for _, node := range collectNodesForTest(astBuilder, ast_pb.NodeType_FUNCTION_DEFINITION) {
+		assert.Less(t, node.GetId(), imported.GetNextID())
+	}
+
+	_, err = imported.ImportFromProto(context.Background(), nil)
+	assert.Error(t, err)
+}
+
+func TestNodeFromProto(t *testing.T) {
+	astBuilder := buildAstFromSourceForTest(t, "Counter", `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.4;
+
+contract Counter {
+    uint256 public count;
+
+    function increment(uint256 by) public returns (uint256) {
+        count += by;
+        return count;
+    }
+}
+`)
+
+	functions := collectNodesForTest(astBuilder, ast_pb.NodeType_FUNCTION_DEFINITION)
+	require.Len(t, functions, 1)
+
+	expected := functions[0].ToProto().(*v3.TypedStruct)
+	node, err := NodeFromProto(expected)
+	require.NoError(t, err)
+
+	function, ok := node.(*Function)
+	require.True(t, ok)
+	assert.Equal(t, "increment", function.GetName())
+	assert.Len(t, function.GetParameters().GetParameters(), 1)
+	assert.Len(t, function.GetBody().GetStatements(), 2)
+	assert.True(t, proto.Equal(expected, function.ToProto().(*v3.TypedStruct)))
+
+	_, err = NodeFromProto(&v3.TypedStruct{
+		TypeUrl: "github.com/unpackdev/protos/unpack.v1.ast.Unknown",
+		Value:   &structpb.Struct{},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,477 @@
+package ast
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+)
+
+// Queryable is implemented by anything holding child nodes that can be queried, such as the
+// RootNode, a SourceUnit or any other Node.
+type Queryable interface {
+	GetNodes() []Node[NodeType]
+}
+
+// Query returns all nodes under root matching the given selector, in traversal order.
+//
+// The selector syntax is a subset of CSS selectors:
+//
+//	FunctionDefinition                           nodes of the given type (solc or proto enum names)
+//	*                                            any node
+//	[visibility=external]                        attribute equality, case-insensitive for enums
+//	[name!=transfer] [name^=_] [name$=Of] [name*=safe]
+//	[body]                                       attribute is present and non-zero
+//	[type_description.type_string="uint256"]     nested attributes are separated by dots
+//	ContractDefinition FunctionDefinition        descendant of an ancestor
+//	FunctionDefinition > IfStatement             direct child of a parent
+//	IfStatement, ForStatement                    union of several selectors
+//
+// Attribute names are matched against struct fields and their JSON names, ignoring case and
+// underscores, so [state_mutability=view] and [stateMutability=view] are equivalent. Parent and
+// child relations follow GetNodes, so for example function body statements are direct children
+// of the FunctionDefinition.
+func Query(root Queryable, selector string) ([]Node[NodeType], error) {
+	compiled, err := ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	return compiled.Select(root), nil
+}
+
+// Query returns all nodes of the tree matching the given selector. See the package level Query.
+func (t *Tree) Query(selector string) ([]Node[NodeType], error) {
+	return Query(t.astRoot, selector)
+}
+
+// Selector is a compiled node selector which can be reused across queries.
+type Selector struct {
+	raw   string
+	paths [][]*selectorStep
+}
+
+// selectorCombinator describes the relation between a compound selector and the one before it.
+type selectorCombinator int
+
+const (
+	selectorDescendant selectorCombinator = iota
+	selectorChild
+)
+
+// selectorStep is a single compound selector, a node type and a set of attribute conditions.
+type selectorStep struct {
+	combinator selectorCombinator
+	anyType    bool
+	nodeType   ast_pb.NodeType
+	attributes []*selectorAttribute
+}
+
+// selectorAttribute is a single [name op value] condition.
+type selectorAttribute struct {
+	path     []string
+	operator string
+	value    string
+}
+
+// ParseSelector compiles the selector string. See Query for the supported syntax.
+func ParseSelector(selector string) (*Selector, error) {
+	toReturn := &Selector{raw: selector}
+
+	for _, part := range splitSelector(selector) {
+		path, err := parseSelectorPath(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector %q: %w", selector, err)
+		}
+		toReturn.paths = append(toReturn.paths, path)
+	}
+
+	if len(toReturn.paths) == 0 {
+		return nil, fmt.Errorf("invalid selector %q: empty selector", selector)
+	}
+
+	return toReturn, nil
+}
+
+// String returns the selector as it was written.
+func (s *Selector) String() string {
+	return s.raw
+}
+
+// Select returns all nodes under root matching the selector, in traversal order. Each node is
+// returned once, even when reachable through several parents.
+func (s *Selector) Select(root Queryable) []Node[NodeType] {
+	toReturn := make([]Node[NodeType], 0)
+	seen := make(map[Node[NodeType]]bool)
+	ancestors := make([]Node[NodeType], 0)
+
+	var walk func(node Node[NodeType])
+	walk = func(node Node[NodeType]) {
+		if isNilNode(node) {
+			return
+		}
+
+		if !seen[node] && s.Matches(node, ancestors) {
+			seen[node] = true
+			toReturn = append(toReturn, node)
+		}
+
+		ancestors = append(ancestors, node)
+		for _, child := range node.GetNodes() {
+			walk(child)
+		}
+		ancestors = ancestors[:len(ancestors)-1]
+	}
+
+	if node, ok := root.(Node[NodeType]); ok {
+		walk(node)
+	} else {
+		for _, child := range root.GetNodes() {
+			walk(child)
+		}
+	}
+
+	return toReturn
+}
+
+// Matches reports whether the node matches the selector given its ancestors, ordered from the
+// outermost to the direct parent.
+func (s *Selector) Matches(node Node[NodeType], ancestors []Node[NodeType]) bool {
+	for _, path := range s.paths {
+		last := len(path) - 1
+		if path[last].matches(node) && matchSelectorAncestors(path, last, ancestors, len(ancestors)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSelectorAncestors checks that steps before index are satisfied by ancestors[:limit].
+func matchSelectorAncestors(path []*selectorStep, index int, ancestors []Node[NodeType], limit int) bool {
+	if index == 0 {
+		return true
+	}
+
+	previous := path[index-1]
+	switch path[index].combinator {
+	case selectorChild:
+		if limit == 0 || !previous.matches(ancestors[limit-1]) {
+			return false
+		}
+		return matchSelectorAncestors(path, index-1, ancestors, limit-1)
+	default:
+		for i := limit - 1; i >= 0; i-- {
+			if previous.matches(ancestors[i]) && matchSelectorAncestors(path, index-1, ancestors, i) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// matches reports whether the node satisfies the type and all attribute conditions of the step.
+func (s *selectorStep) matches(node Node[NodeType]) bool {
+	if !s.anyType && node.GetType() != s.nodeType {
+		return false
+	}
+
+	for _, attribute := range s.attributes {
+		if !attribute.matches(node) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matches reports whether the node attribute satisfies the condition.
+func (a *selectorAttribute) matches(node Node[NodeType]) bool {
+	value, ok := resolveSelectorAttribute(reflect.ValueOf(node), a.path)
+	if !ok {
+		return a.operator == "!="
+	}
+
+	if a.operator == "" {
+		return !value.IsZero()
+	}
+
+	actual, enum := formatSelectorValue(value)
+	expected := a.value
+	if enum {
+		actual, expected = strings.ToLower(actual), strings.ToLower(expected)
+	}
+
+	switch a.operator {
+	case "=":
+		return actual == expected
+	case "!=":
+		return actual != expected
+	case "^=":
+		return strings.HasPrefix(actual, expected)
+	case "$=":
+		return strings.HasSuffix(actual, expected)
+	case "*=":
+		return strings.Contains(actual, expected)
+	}
+
+	return false
+}
+
+// resolveSelectorAttribute follows the attribute path through struct fields of the value.
+func resolveSelectorAttribute(value reflect.Value, path []string) (reflect.Value, bool) {
+	for _, name := range path {
+		for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+			if value.IsNil() {
+				return reflect.Value{}, false
+			}
+			value = value.Elem()
+		}
+
+		if value.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+
+		field, ok := findSelectorField(value, name)
+		if !ok {
+			return reflect.Value{}, false
+		}
+		value = field
+	}
+
+	return value, true
+}
+
+// findSelectorField finds the exported field matching the attribute name by field or JSON name.
+func findSelectorField(value reflect.Value, name string) (reflect.Value, bool) {
+	wanted := normalizeSelectorName(name)
+	valueType := value.Type()
+
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if normalizeSelectorName(field.Name) == wanted || (jsonName != "" && jsonName != "-" && normalizeSelectorName(jsonName) == wanted) {
+			return value.Field(i), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// formatSelectorValue renders the value for comparison and reports whether it is an enum.
+func formatSelectorValue(value reflect.Value) (string, bool) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return "", false
+		}
+		value = value.Elem()
+	}
+
+	if stringer, ok := value.Interface().(fmt.Stringer); ok && value.Kind() == reflect.Int32 {
+		return stringer.String(), true
+	}
+
+	return fmt.Sprint(value.Interface()), false
+}
+
+// normalizeSelectorName lowercases the name and removes underscores so that snake and camel case match.
+func normalizeSelectorName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// splitSelector splits a selector list on top level commas, ignoring commas in brackets or quotes.
+func splitSelector(selector string) []string {
+	parts := make([]string, 0)
+	depth, quote, start := 0, rune(0), 0
+
+	for i, r := range selector {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		case r == ',' && depth == 0:
+			parts = append(parts, selector[start:i])
+			start = i + 1
+		}
+	}
+	parts = append(parts, selector[start:])
+
+	toReturn := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			toReturn = append(toReturn, part)
+		}
+	}
+	return toReturn
+}
+
+// parseSelectorPath parses a complex selector, a chain of compound selectors joined by combinators.
+func parseSelectorPath(selector string) ([]*selectorStep, error) {
+	steps := make([]*selectorStep, 0)
+	runes := []rune(selector)
+	combinator := selectorDescendant
+	pos := 0
+
+	for pos < len(runes) {
+		switch {
+		case unicode.IsSpace(runes[pos]):
+			pos++
+			continue
+		case runes[pos] == '>':
+			if len(steps) == 0 || combinator == selectorChild {
+				return nil, fmt.Errorf("unexpected '>' at position %d", pos)
+			}
+			combinator = selectorChild
+			pos++
+			continue
+		}
+
+		step, next, err := parseSelectorStep(runes, pos)
+		if err != nil {
+			return nil, err
+		}
+		step.combinator = combinator
+		steps = append(steps, step)
+		combinator = selectorDescendant
+		pos = next
+	}
+
+	if len(steps) == 0 || combinator == selectorChild {
+		return nil, fmt.Errorf("selector %q is incomplete", selector)
+	}
+
+	return steps, nil
+}
+
+// parseSelectorStep parses a compound selector starting at pos and returns the position after it.
+func parseSelectorStep(runes []rune, pos int) (*selectorStep, int, error) {
+	step := &selectorStep{attributes: make([]*selectorAttribute, 0)}
+
+	start := pos
+	for pos < len(runes) && (unicode.IsLetter(runes[pos]) || unicode.IsDigit(runes[pos]) || runes[pos] == '_' || runes[pos] == '*') {
+		pos++
+	}
+
+	switch name := string(runes[start:pos]); name {
+	case "":
+		if pos >= len(runes) || runes[pos] != '[' {
+			return nil, pos, fmt.Errorf("unexpected %q at position %d", runes[pos], pos)
+		}
+		step.anyType = true
+	case "*":
+		step.anyType = true
+	default:
+		nodeType, err := parseSelectorNodeType(name)
+		if err != nil {
+			return nil, pos, err
+		}
+		step.nodeType = nodeType
+	}
+
+	for pos < len(runes) && runes[pos] == '[' {
+		end := pos + 1
+		quote := rune(0)
+		for ; end < len(runes); end++ {
+			if quote != 0 {
+				if runes[end] == quote {
+					quote = 0
+				}
+				continue
+			}
+			if runes[end] == '"' || runes[end] == '\'' {
+				quote = runes[end]
+			} else if runes[end] == ']' {
+				break
+			}
+		}
+		if end >= len(runes) {
+			return nil, pos, fmt.Errorf("unterminated attribute selector at position %d", pos)
+		}
+
+		attribute, err := parseSelectorAttribute(string(runes[pos+1 : end]))
+		if err != nil {
+			return nil, pos, err
+		}
+		step.attributes = append(step.attributes, attribute)
+		pos = end + 1
+	}
+
+	return step, pos, nil
+}
+
+// parseSelectorAttribute parses the content of an attribute selector without the brackets.
+func parseSelectorAttribute(content string) (*selectorAttribute, error) {
+	name, operator, value := strings.TrimSpace(content), "", ""
+
+	if index := strings.IndexAny(content, "=!^$*"); index >= 0 {
+		name = strings.TrimSpace(content[:index])
+		rest := content[index:]
+		for _, candidate := range []string{"!=", "^=", "$=", "*=", "="} {
+			if strings.HasPrefix(rest, candidate) {
+				operator = candidate
+				value = strings.TrimSpace(rest[len(candidate):])
+				break
+			}
+		}
+		if operator == "" {
+			return nil, fmt.Errorf("invalid attribute selector [%s]", content)
+		}
+	}
+
+	if name == "" {
+		return nil, fmt.Errorf("missing attribute name in [%s]", content)
+	}
+
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	}
+
+	return &selectorAttribute{
+		path:     strings.Split(name, "."),
+		operator: operator,
+		value:    value,
+	}, nil
+}
+
+// parseSelectorNodeType resolves a node type from its solc style name, such as FunctionDefinition,
+// or its proto enum name, such as FUNCTION_DEFINITION.
+func parseSelectorNodeType(name string) (ast_pb.NodeType, error) {
+	if value, ok := ast_pb.NodeType_value[name]; ok {
+		return ast_pb.NodeType(value), nil
+	}
+
+	var builder strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			builder.WriteRune('_')
+		}
+		builder.WriteRune(unicode.ToUpper(r))
+	}
+
+	if value, ok := ast_pb.NodeType_value[builder.String()]; ok {
+		return ast_pb.NodeType(value), nil
+	}
+
+	return ast_pb.NodeType_NT_DEFAULT, fmt.Errorf("unknown node type %q", name)
+}
+
+// isNilNode reports whether the node is nil or a typed nil pointer wrapped in the interface.
+func isNilNode(node Node[NodeType]) bool {
+	if node == nil {
+		return true
+	}
+	value := reflect.ValueOf(node)
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
@@ -0,0 +1,94 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+)
+
+func TestQuery(t *testing.T) {
+	astBuilder := buildAstFromSourceForTest(t, "Token", `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+library SafeMath {
+    function add(uint256 a, uint256 b) external pure returns (uint256) {
+        return a + b;
+    }
+}
+
+contract Token {
+    error InsufficientBalance();
+
+    mapping(address => uint256) public balances;
+
+    function transfer(address to, uint256 amount) external returns (bool) {
+        if (balances[msg.sender] < amount) {
+            revert InsufficientBalance();
+        }
+        balances[to] += amount;
+        return true;
+    }
+
+    function balanceOf(address account) public view returns (uint256) {
+        return balances[account];
+    }
+
+    function _burn(address account) internal {
+        balances[account] = 0;
+    }
+}
+`)
+
+	names := func(nodes []Node[NodeType]) []string {
+		toReturn := make([]string, 0, len(nodes))
+		for _, node := range nodes {
+			toReturn = append(toReturn, node.(*Function).GetName())
+		}
+		return toReturn
+	}
+
+	nodes, err := Query(astBuilder.GetRoot(), "ContractDefinition[kind=KIND_CONTRACT] FunctionDefinition[visibility=external]")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"transfer"}, names(nodes))
+
+	nodes, err = astBuilder.GetTree().Query("FunctionDefinition[visibility=external]")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"add", "transfer"}, names(nodes))
+
+	nodes, err = Query(astBuilder.GetRoot(), "FUNCTION_DEFINITION[state_mutability=view], FunctionDefinition[name^=_]")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"balanceOf", "_burn"}, names(nodes))
+
+	nodes, err = Query(astBuilder.GetRoot(), `FunctionDefinition[name*="ance"][stateMutability!=view]`)
+	require.NoError(t, err)
+	assert.Empty(t, nodes)
+
+	nodes, err = Query(astBuilder.GetRoot(), "FunctionDefinition > IfStatement")
+	require.NoError(t, err)
+	require.Len(t, nodes, 1)
+	assert.Equal(t, ast_pb.NodeType_IF_STATEMENT, nodes[0].GetType())
+
+	// The revert is nested inside the if body, so it is not a direct child of the function.
+	nodes, err = Query(astBuilder.GetRoot(), "FunctionDefinition > RevertStatement")
+	require.NoError(t, err)
+	assert.Empty(t, nodes)
+
+	nodes, err = Query(astBuilder.GetRoot(), "FunctionDefinition[name=transfer] RevertStatement")
+	require.NoError(t, err)
+	assert.Len(t, nodes, 1)
+
+	// Querying from a node only searches its subtree.
+	contracts, err := Query(astBuilder.GetRoot(), "ContractDefinition[name=Token]")
+	require.NoError(t, err)
+	require.Len(t, contracts, 1)
+	nodes, err = Query(contracts[0], "* > FunctionDefinition[type_description.type_string='function(address)'][visibility=internal]")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"_burn"}, names(nodes))
+
+	for _, selector := range []string{"", "UnknownNode", "FunctionDefinition >", "FunctionDefinition[name", "[=x]", "> Block"} {
+		_, err := Query(astBuilder.GetRoot(), selector)
+		assert.Error(t, err, selector)
+	}
+}
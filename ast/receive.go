@@ -96,6 +96,7 @@ func (f *Receive) GetReturnParameters() *ParameterList {
 
 // GetBody returns the body of the Receive node.
 func (f *Receive) GetBody() *BodyNode {
+	f.Body.loadLazily()
 	return f.Body
 }
 
@@ -291,16 +292,10 @@ func (f *Receive) Parse(
 	f.Body = bodyNode
 
 	if ctx.Block() != nil && !ctx.Block().IsEmpty() {
-
-		bodyNode.ParseBlock(unit, contractNode, f, ctx.Block())
-		f.Body = bodyNode
-
-		if ctx.Block().AllUncheckedBlock() != nil {
-			for _, uncheckedCtx := range ctx.Block().AllUncheckedBlock() {
-				bodyNode := NewBodyNode(f.ASTBuilder, false)
-				bodyNode.ParseUncheckedBlock(unit, contractNode, f, uncheckedCtx)
-				f.Body.Statements = append(f.Body.Statements, bodyNode)
-			}
+		if lazyBody := f.parseLazyBlock(unit, contractNode, f, ctx.Block()); lazyBody != nil {
+			f.Body = lazyBody
+		} else {
+			bodyNode.ParseBlock(unit, contractNode, f, ctx.Block())
 		}
 	}
 
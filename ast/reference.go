@@ -82,7 +82,7 @@ func (r *Resolver) resolveByNode(name string, baseNode Node[NodeType]) (int64, *
 		return node, nodeType
 	}
 
-	if node, nodeType := r.byUserDefinedVariables(name); nodeType != nil {
+	if node, nodeType := r.byUserDefinedVariables(name, baseNode); nodeType != nil {
 		return node, nodeType
 	}
 
@@ -387,21 +387,48 @@ func (r *Resolver) bySourceUnit(name string) (int64, *TypeDescription) {
 	return 0, nil
 }
 
-func (r *Resolver) byUserDefinedVariables(name string) (int64, *TypeDescription) {
+// byUserDefinedVariables resolves user-defined value types by name. Contracts can each declare a type of
+// the same name, so a type declared in the contract enclosing the base node takes precedence.
+func (r *Resolver) byUserDefinedVariables(name string, baseNode Node[NodeType]) (int64, *TypeDescription) {
+	var match *UserDefinedValueTypeDefinition
 	for _, node := range r.currentUserDefinedVariables {
-		if node.GetName() == name {
-			// It could be that node got updated in the mean time so we're going to
-			// look up for the node and it's type description...
-			if node.GetTypeDescription() == nil {
-				if target := r.tree.GetById(node.GetId()); target != nil {
-					return target.GetId(), target.GetTypeDescription()
-				}
-			}
-			return node.GetId(), node.GetTypeDescription()
+		if node.GetName() != name {
+			continue
+		}
+
+		if match == nil || r.enclosesUserDefinedVariable(node, baseNode) {
+			match = node
 		}
 	}
 
-	return 0, nil
+	if match == nil {
+		return 0, nil
+	}
+
+	// It could be that node got updated in the mean time so we're going to
+	// look up for the node and it's type description...
+	if match.GetTypeDescription() == nil {
+		if target := r.tree.GetById(match.GetId()); target != nil {
+			return target.GetId(), target.GetTypeDescription()
+		}
+	}
+	return match.GetId(), match.GetTypeDescription()
+}
+
+// enclosesUserDefinedVariable reports whether the contract declaring the user-defined value type encloses the
+// base node. Contracts are added to the tree once parsed, and the nodes of a contract are numbered after it and
+// before the next one, so while the contract is being parsed the enclosing one is the latest declaring the type.
+func (r *Resolver) enclosesUserDefinedVariable(node *UserDefinedValueTypeDefinition, baseNode Node[NodeType]) bool {
+	contractId := node.GetSrc().ParentIndex
+	if contractId == 0 || baseNode == nil {
+		return false
+	}
+
+	if contract := r.tree.GetById(contractId); contract != nil {
+		return contract.GetSrc().Start <= baseNode.GetSrc().Start && baseNode.GetSrc().End <= contract.GetSrc().End
+	}
+
+	return contractId < baseNode.GetId()
 }
 
 func (r *Resolver) byStateVariables(name string) (int64, *TypeDescription) {
@@ -12,11 +12,12 @@ import (
 type RevertStatement struct {
 	*ASTBuilder
 
-	Id         int64            `json:"id"`         // Unique identifier for the RevertStatement node.
-	NodeType   ast_pb.NodeType  `json:"node_type"`  // Type of the AST node.
-	Src        SrcNode          `json:"src"`        // Source location information.
-	Arguments  []Node[NodeType] `json:"arguments"`  // List of argument expressions.
-	Expression Node[NodeType]   `json:"expression"` // Expression within the revert statement.
+	Id         int64            `json:"id"`              // Unique identifier for the RevertStatement node.
+	NodeType   ast_pb.NodeType  `json:"node_type"`       // Type of the AST node.
+	Src        SrcNode          `json:"src"`             // Source location information.
+	Arguments  []Node[NodeType] `json:"arguments"`       // List of argument expressions.
+	Names      []string         `json:"names,omitempty"` // Argument names when named arguments are used.
+	Expression Node[NodeType]   `json:"expression"`      // Expression within the revert statement.
 }
 
 // NewRevertStatement creates a new RevertStatement node with a given ASTBuilder.
@@ -55,6 +56,33 @@ func (r *RevertStatement) GetArguments() []Node[NodeType] {
 	return r.Arguments
 }
 
+// GetNames returns the argument names of the revert statement, or nil if positional arguments are used.
+func (r *RevertStatement) GetNames() []string {
+	return r.Names
+}
+
+// GetErrorName returns the name of the custom error being reverted with.
+func (r *RevertStatement) GetErrorName() string {
+	switch expr := r.Expression.(type) {
+	case *PrimaryExpression:
+		return expr.GetName()
+	case *MemberAccessExpression:
+		return expr.GetMemberName()
+	}
+	return ""
+}
+
+// GetReferencedDeclaration returns the id of the error definition the revert statement references.
+func (r *RevertStatement) GetReferencedDeclaration() int64 {
+	switch expr := r.Expression.(type) {
+	case *PrimaryExpression:
+		return expr.GetReferencedDeclaration()
+	case *MemberAccessExpression:
+		return expr.GetReferencedDeclaration()
+	}
+	return 0
+}
+
 // GetExpression returns the expression within the revert statement.
 func (r *RevertStatement) GetExpression() Node[NodeType] {
 	return r.Expression
@@ -118,6 +146,12 @@ func (r *RevertStatement) UnmarshalJSON(data []byte) error {
 		}
 	}
 
+	if names, ok := tempMap["names"]; ok {
+		if err := json.Unmarshal(names, &r.Names); err != nil {
+			return err
+		}
+	}
+
 	if expression, ok := tempMap["expression"]; ok {
 		if err := json.Unmarshal(expression, &r.Expression); err != nil {
 			var tempNodeMap map[string]json.RawMessage
@@ -195,6 +229,18 @@ func (r *RevertStatement) Parse(
 				),
 			)
 		}
+
+		// Named arguments, such as revert InsufficientBalance({available: 1, required: 2}).
+		for _, namedArgumentCtx := range ctx.CallArgumentList().AllNamedArgument() {
+			r.Names = append(r.Names, namedArgumentCtx.Identifier().GetText())
+			r.Arguments = append(
+				r.Arguments,
+				expression.Parse(
+					unit, contractNode, fnNode,
+					bodyNode, nil, r, r.GetId(), namedArgumentCtx.Expression(),
+				),
+			)
+		}
 	}
 
 	r.Expression = expression.Parse(unit, contractNode, fnNode, bodyNode, nil, r, r.GetId(), ctx.Expression())
@@ -0,0 +1,67 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+)
+
+func TestRevertWithCustomError(t *testing.T) {
+	astBuilder := buildAstFromSourceForTest(t, "Vault", `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.4;
+
+error Unauthorized(address caller);
+
+contract Vault {
+    error InsufficientBalance(uint256 available, uint256 required);
+
+    address owner;
+    mapping(address => uint256) balances;
+
+    function withdraw(uint256 amount) public {
+        if (msg.sender != owner) revert Unauthorized(msg.sender);
+        if (balances[msg.sender] < amount) {
+            revert InsufficientBalance({available: balances[msg.sender], required: amount});
+        }
+        revert InsufficientBalance(balances[msg.sender], amount);
+    }
+}
+`)
+
+	errorDefinitions := collectNodesForTest(astBuilder, ast_pb.NodeType_ERROR_DEFINITION)
+	require.Len(t, errorDefinitions, 1)
+	insufficient := errorDefinitions[0].(*ErrorDefinition)
+	assert.Equal(t, "error Vault.InsufficientBalance", insufficient.GetTypeDescription().GetString())
+
+	var unauthorized *ErrorDefinition
+	for _, node := range astBuilder.GetRoot().GetGlobalNodes() {
+		if errorNode, ok := node.(*ErrorDefinition); ok && errorNode.GetName() == "Unauthorized" {
+			unauthorized = errorNode
+		}
+	}
+	require.NotNil(t, unauthorized)
+
+	reverts := collectNodesForTest(astBuilder, ast_pb.NodeType_REVERT_STATEMENT)
+	require.Len(t, reverts, 3)
+
+	single := reverts[0].(*RevertStatement)
+	assert.Equal(t, "Unauthorized", single.GetErrorName())
+	assert.Equal(t, unauthorized.GetId(), single.GetReferencedDeclaration())
+	assert.Len(t, single.GetArguments(), 1)
+	assert.Nil(t, single.GetNames())
+
+	named := reverts[1].(*RevertStatement)
+	assert.Equal(t, insufficient.GetId(), named.GetReferencedDeclaration())
+	assert.Equal(t, []string{"available", "required"}, named.GetNames())
+	assert.Len(t, named.GetArguments(), 2)
+
+	positional := reverts[2].(*RevertStatement)
+	assert.Equal(t, insufficient.GetId(), positional.GetReferencedDeclaration())
+	assert.Len(t, positional.GetArguments(), 2)
+
+	assert.NotPanics(t, func() {
+		assert.NotNil(t, astBuilder.ToProto())
+	})
+}
@@ -0,0 +1,399 @@
+package ast
+
+import (
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+)
+
+// ScopeKind describes the kind of lexical scope in the symbol table.
+type ScopeKind string
+
+const (
+	// ScopeGlobal holds every top level declaration of all source units, as visible through imports.
+	ScopeGlobal ScopeKind = "global"
+
+	// ScopeFile holds the top level declarations of a single source unit.
+	ScopeFile ScopeKind = "file"
+
+	// ScopeContract holds the members of a contract, interface or library.
+	ScopeContract ScopeKind = "contract"
+
+	// ScopeFunction holds the parameters and top level locals of a function, modifier, constructor,
+	// fallback or receive function.
+	ScopeFunction ScopeKind = "function"
+
+	// ScopeBlock holds the locals of a nested block, unchecked block, for loop, try or catch clause.
+	ScopeBlock ScopeKind = "block"
+)
+
+// scopeSymbol is a declaration registered in a scope. Locals only become visible after the
+// statement declaring them, which is tracked with visibleFrom.
+type scopeSymbol struct {
+	node        Node[NodeType]
+	visibleFrom int64
+}
+
+// Scope is a single lexical scope mapping identifiers to their declaring nodes.
+type Scope struct {
+	Id       int64          `json:"id"`   // Id of the node opening the scope, 0 for the global scope.
+	Kind     ScopeKind      `json:"kind"` // Kind of the scope.
+	Node     Node[NodeType] `json:"-"`    // Node opening the scope, nil for the global scope.
+	Parent   *Scope         `json:"-"`    // Enclosing scope, nil for the global scope.
+	Children []*Scope       `json:"children,omitempty"`
+
+	symbols map[string][]*scopeSymbol
+	bases   []*Scope
+}
+
+// newScope creates a scope of the given kind opened by node and attaches it to the parent.
+func newScope(kind ScopeKind, node Node[NodeType], parent *Scope) *Scope {
+	scope := &Scope{
+		Kind:     kind,
+		Node:     node,
+		Parent:   parent,
+		Children: make([]*Scope, 0),
+		symbols:  make(map[string][]*scopeSymbol),
+	}
+
+	if node != nil {
+		scope.Id = node.GetId()
+	}
+
+	if parent != nil {
+		parent.Children = append(parent.Children, scope)
+	}
+
+	return scope
+}
+
+// GetId returns the id of the node opening the scope.
+func (s *Scope) GetId() int64 {
+	return s.Id
+}
+
+// GetKind returns the kind of the scope.
+func (s *Scope) GetKind() ScopeKind {
+	return s.Kind
+}
+
+// GetNode returns the node opening the scope, or nil for the global scope.
+func (s *Scope) GetNode() Node[NodeType] {
+	return s.Node
+}
+
+// GetParent returns the enclosing scope, or nil for the global scope.
+func (s *Scope) GetParent() *Scope {
+	return s.Parent
+}
+
+// GetChildren returns the scopes nested directly within this scope.
+func (s *Scope) GetChildren() []*Scope {
+	return s.Children
+}
+
+// GetBases returns the scopes of the base contracts of a contract scope, most derived first.
+func (s *Scope) GetBases() []*Scope {
+	return s.bases
+}
+
+// GetNames returns all identifiers declared directly in the scope.
+func (s *Scope) GetNames() []string {
+	toReturn := make([]string, 0, len(s.symbols))
+	for name := range s.symbols {
+		toReturn = append(toReturn, name)
+	}
+	return toReturn
+}
+
+// GetDeclarations returns every node declaring the name directly in the scope. Overloaded
+// functions and events result in more than one declaration.
+func (s *Scope) GetDeclarations(name string) []Node[NodeType] {
+	toReturn := make([]Node[NodeType], 0)
+	for _, symbol := range s.symbols[name] {
+		toReturn = append(toReturn, symbol.node)
+	}
+	return toReturn
+}
+
+// Lookup resolves the name as seen from the given source position within the scope. Enclosing
+// scopes and, for contracts, base contracts are searched until a declaration is found, so inner
+// declarations shadow outer ones. It returns nil if the name is not declared.
+func (s *Scope) Lookup(name string, position int64) Node[NodeType] {
+	for scope := s; scope != nil; scope = scope.Parent {
+		if node := scope.lookupLocal(name, position); node != nil {
+			return node
+		}
+
+		if node := scope.lookupBases(name, make(map[*Scope]bool)); node != nil {
+			return node
+		}
+	}
+	return nil
+}
+
+// lookupLocal resolves the name among declarations of this scope visible at the position. When a
+// name is redeclared, the latest visible declaration wins.
+func (s *Scope) lookupLocal(name string, position int64) Node[NodeType] {
+	var toReturn Node[NodeType]
+	for _, symbol := range s.symbols[name] {
+		if symbol.visibleFrom <= position {
+			if toReturn == nil || symbol.visibleFrom > 0 {
+				toReturn = symbol.node
+			}
+		}
+	}
+	return toReturn
+}
+
+// lookupBases resolves the name among the members of base contracts.
+func (s *Scope) lookupBases(name string, visited map[*Scope]bool) Node[NodeType] {
+	for _, base := range s.bases {
+		if visited[base] {
+			continue
+		}
+		visited[base] = true
+
+		if node := base.lookupLocal(name, 0); node != nil {
+			return node
+		}
+		if node := base.lookupBases(name, visited); node != nil {
+			return node
+		}
+	}
+	return nil
+}
+
+// declare registers the node under its name, visible from the given position onwards.
+func (s *Scope) declare(name string, node Node[NodeType], visibleFrom int64) {
+	if name == "" {
+		return
+	}
+
+	for _, symbol := range s.symbols[name] {
+		if symbol.node == node {
+			return
+		}
+	}
+
+	s.symbols[name] = append(s.symbols[name], &scopeSymbol{node: node, visibleFrom: visibleFrom})
+}
+
+// SymbolTable is a scope aware symbol table built over the whole AST. It maps every node to the
+// innermost scope containing it, and every scope to the identifiers it declares, which allows
+// identifiers to be resolved with proper shadowing instead of matching names globally.
+type SymbolTable struct {
+	global         *Scope
+	scopes         map[int64]*Scope
+	nodeScopes     map[int64]*Scope
+	nodes          map[int64]Node[NodeType]
+	linearizations map[*Scope][]*Scope // linearizations is filled lazily by Linearize.
+}
+
+// NewSymbolTable builds the symbol table for the given root node.
+func NewSymbolTable(root *RootNode) *SymbolTable {
+	table := &SymbolTable{
+		global:     newScope(ScopeGlobal, nil, nil),
+		scopes:     make(map[int64]*Scope),
+		nodeScopes: make(map[int64]*Scope),
+		nodes:      make(map[int64]Node[NodeType]),
+	}
+
+	if root == nil {
+		return table
+	}
+
+	for _, node := range root.GetGlobalNodes() {
+		table.declareMember(table.global, node)
+	}
+
+	for _, unit := range root.GetSourceUnits() {
+		for _, node := range unit.GetNodes() {
+			table.declareMember(table.global, node)
+		}
+	}
+
+	for _, node := range root.GetGlobalNodes() {
+		table.walk(node, table.global)
+	}
+
+	for _, unit := range root.GetSourceUnits() {
+		table.walk(unit, table.global)
+	}
+
+	for _, scope := range table.scopes {
+		if scope.Kind == ScopeContract {
+			table.linkBases(scope)
+		}
+	}
+
+	return table
+}
+
+// GetGlobalScope returns the outermost scope.
+func (t *SymbolTable) GetGlobalScope() *Scope {
+	return t.global
+}
+
+// GetScope returns the scope opened by the node with the given id, or nil if it opens none.
+func (t *SymbolTable) GetScope(nodeId int64) *Scope {
+	return t.scopes[nodeId]
+}
+
+// GetScopeOf returns the innermost scope containing the node with the given id, or nil if the
+// node is not part of the tree.
+func (t *SymbolTable) GetScopeOf(nodeId int64) *Scope {
+	return t.nodeScopes[nodeId]
+}
+
+// ResolveIdentifier returns the node declaring name as visible from the node with the given id,
+// honouring lexical scoping, shadowing, declaration order of locals and contract inheritance. It
+// returns nil for unknown nodes, builtins such as msg or block, and undeclared names.
+func (t *SymbolTable) ResolveIdentifier(nodeId int64, name string) Node[NodeType] {
+	scope, ok := t.nodeScopes[nodeId]
+	if !ok {
+		return nil
+	}
+
+	position := int64(0)
+	if node, ok := t.nodes[nodeId]; ok {
+		position = node.GetSrc().Start
+	}
+
+	return scope.Lookup(name, position)
+}
+
+// walk records the scope of the node and its descendants, opening new scopes where needed.
+func (t *SymbolTable) walk(node Node[NodeType], scope *Scope) {
+	if isNilNode(node) {
+		return
+	}
+
+	if _, ok := t.nodes[node.GetId()]; !ok {
+		t.nodes[node.GetId()] = node
+		t.nodeScopes[node.GetId()] = scope
+	}
+
+	if kind, ok := scopeKindOf(node); ok {
+		if _, exists := t.scopes[node.GetId()]; !exists {
+			inner := newScope(kind, node, scope)
+			t.scopes[node.GetId()] = inner
+			t.declareChildren(inner, node)
+			scope = inner
+		}
+	}
+
+	for _, child := range node.GetNodes() {
+		t.walk(child, scope)
+	}
+
+	for _, argument := range baseContractArguments(node) {
+		t.walk(argument, scope)
+	}
+}
+
+// declareChildren registers the declarations made directly by the node opening the scope.
+func (t *SymbolTable) declareChildren(scope *Scope, node Node[NodeType]) {
+	for _, child := range node.GetNodes() {
+		if isNilNode(child) {
+			continue
+		}
+
+		switch n := child.(type) {
+		case *VariableDeclaration:
+			// Locals are visible only after the statement declaring them.
+			for _, declaration := range n.GetDeclarations() {
+				scope.declare(declaration.GetName(), declaration, n.GetSrc().End+1)
+			}
+		default:
+			t.declareMember(scope, child)
+		}
+	}
+}
+
+// declareMember registers named declarations such as contracts, functions, state variables,
+// parameters, structs, enums, events, errors and user defined value types.
+func (t *SymbolTable) declareMember(scope *Scope, node Node[NodeType]) {
+	if isNilNode(node) {
+		return
+	}
+
+	switch node.GetType() {
+	case ast_pb.NodeType_CONTRACT_DEFINITION,
+		ast_pb.NodeType_INTERFACE_DEFINITION,
+		ast_pb.NodeType_LIBRARY_DEFINITION,
+		ast_pb.NodeType_FUNCTION_DEFINITION,
+		ast_pb.NodeType_MODIFIER_DEFINITION,
+		ast_pb.NodeType_EVENT_DEFINITION,
+		ast_pb.NodeType_ERROR_DEFINITION,
+		ast_pb.NodeType_STRUCT_DEFINITION,
+		ast_pb.NodeType_ENUM_DEFINITION,
+		ast_pb.NodeType_USER_DEFINED_VALUE_TYPE,
+		ast_pb.NodeType_VARIABLE_DECLARATION:
+		if named, ok := node.(interface{ GetName() string }); ok {
+			scope.declare(named.GetName(), node, 0)
+		}
+	}
+}
+
+// linkBases resolves the base contracts of a contract scope to their scopes, most derived first.
+func (t *SymbolTable) linkBases(scope *Scope) {
+	inherits, ok := scope.Node.(interface{ GetBaseContracts() []*BaseContract })
+	if !ok {
+		return
+	}
+
+	bases := inherits.GetBaseContracts()
+	for i := len(bases) - 1; i >= 0; i-- {
+		if bases[i] == nil || bases[i].BaseName == nil {
+			continue
+		}
+
+		var target Node[NodeType]
+		if bases[i].BaseName.ReferencedDeclaration > 0 {
+			if base, ok := t.scopes[bases[i].BaseName.ReferencedDeclaration]; ok && base.Kind == ScopeContract {
+				target = base.Node
+			}
+		}
+		if target == nil && scope.Parent != nil {
+			target = scope.Parent.Lookup(bases[i].BaseName.Name, 0)
+		}
+
+		if target == nil || target.GetId() == scope.Id {
+			continue
+		}
+
+		if base, ok := t.scopes[target.GetId()]; ok && base.Kind == ScopeContract {
+			scope.bases = append(scope.bases, base)
+		}
+	}
+}
+
+// scopeKindOf returns the kind of scope the node opens, if any.
+func scopeKindOf(node Node[NodeType]) (ScopeKind, bool) {
+	switch n := node.(type) {
+	case *SourceUnit[Node[ast_pb.SourceUnit]]:
+		return ScopeFile, true
+	case *Contract, *Interface, *Library:
+		return ScopeContract, true
+	case *Function, *ModifierDefinition, *Constructor, *Fallback, *Receive:
+		return ScopeFunction, true
+	case *BodyNode:
+		return ScopeBlock, n.GetType() == ast_pb.NodeType_BLOCK
+	case *UncheckedBlock, *ForStatement, *TryStatement, *CatchStatement:
+		return ScopeBlock, true
+	}
+	return "", false
+}
+
+// GetSymbolTable returns the scope aware symbol table of the AST, building it on first use.
+func (b *ASTBuilder) GetSymbolTable() *SymbolTable {
+	if b.symbolTable == nil {
+		b.symbolTable = NewSymbolTable(b.GetRoot())
+	}
+	return b.symbolTable
+}
+
+// ResolveIdentifier returns the node declaring name as visible from the node with the given id.
+// See SymbolTable.ResolveIdentifier.
+func (b *ASTBuilder) ResolveIdentifier(nodeId int64, name string) Node[NodeType] {
+	return b.GetSymbolTable().ResolveIdentifier(nodeId, name)
+}
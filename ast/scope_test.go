@@ -0,0 +1,103 @@
+package ast
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+)
+
+func TestSymbolTable(t *testing.T) {
+	astBuilder := buildAstFromSourceForTest(t, "Vault", `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+contract Base {
+    uint256 public fee;
+}
+
+contract Vault is Base {
+    uint256 public balance;
+
+    function deposit(uint256 amount) public returns (uint256) {
+        uint256 previous = balance;
+        uint256 balance = previous + amount;
+        {
+            uint256 amount = balance * fee;
+            balance = amount;
+        }
+        return balance + amount;
+    }
+}
+`)
+
+	table := astBuilder.GetSymbolTable()
+	require.NotNil(t, table)
+	assert.Same(t, table, astBuilder.GetSymbolTable())
+
+	contracts := collectNodesForTest(astBuilder, ast_pb.NodeType_CONTRACT_DEFINITION)
+	declarations := make(map[string]Node[NodeType])
+	for _, contract := range contracts {
+		require.NotNil(t, table.GetGlobalScope().Lookup(contract.(*Contract).GetName(), 0))
+
+		scope := table.GetScope(contract.GetId())
+		require.NotNil(t, scope)
+		assert.Equal(t, ScopeContract, scope.GetKind())
+
+		for _, name := range scope.GetNames() {
+			declarations[name] = scope.GetDeclarations(name)[0]
+		}
+	}
+	require.Contains(t, declarations, "fee")
+	require.Contains(t, declarations, "balance")
+
+	functions := collectNodesForTest(astBuilder, ast_pb.NodeType_FUNCTION_DEFINITION)
+	require.Len(t, functions, 1)
+	fnScope := table.GetScope(functions[0].GetId())
+	require.NotNil(t, fnScope)
+	assert.Equal(t, ScopeFunction, fnScope.GetKind())
+	require.Len(t, fnScope.GetDeclarations("amount"), 1)
+	require.Len(t, fnScope.GetDeclarations("balance"), 1)
+	require.Len(t, fnScope.GetChildren(), 1)
+	blockScope := fnScope.GetChildren()[0]
+	assert.Equal(t, ScopeBlock, blockScope.GetKind())
+	require.Len(t, blockScope.GetDeclarations("amount"), 1)
+
+	parameter := fnScope.GetDeclarations("amount")[0]
+	localBalance := fnScope.GetDeclarations("balance")[0]
+	blockAmount := blockScope.GetDeclarations("amount")[0]
+
+	identifiers := make(map[string][]Node[NodeType])
+	for _, node := range collectNodesForTest(astBuilder, ast_pb.NodeType_IDENTIFIER) {
+		if expr, ok := node.(*PrimaryExpression); ok {
+			identifiers[expr.GetName()] = append(identifiers[expr.GetName()], node)
+		}
+	}
+	for _, nodes := range identifiers {
+		sort.Slice(nodes, func(i, j int) bool { return nodes[i].GetSrc().Start < nodes[j].GetSrc().Start })
+	}
+
+	// Used before the local declaration, balance still refers to the state variable.
+	balances := identifiers["balance"]
+	require.Len(t, balances, 4)
+	assert.Same(t, declarations["balance"], astBuilder.ResolveIdentifier(balances[0].GetId(), "balance"))
+	assert.Same(t, localBalance, astBuilder.ResolveIdentifier(balances[1].GetId(), "balance"))
+	assert.Same(t, localBalance, astBuilder.ResolveIdentifier(balances[2].GetId(), "balance"))
+	assert.Same(t, localBalance, astBuilder.ResolveIdentifier(balances[3].GetId(), "balance"))
+
+	// The block local shadows the parameter only within the block.
+	amounts := identifiers["amount"]
+	require.Len(t, amounts, 3)
+	assert.Same(t, parameter, astBuilder.ResolveIdentifier(amounts[0].GetId(), "amount"))
+	assert.Same(t, blockAmount, astBuilder.ResolveIdentifier(amounts[1].GetId(), "amount"))
+	assert.Same(t, parameter, astBuilder.ResolveIdentifier(amounts[2].GetId(), "amount"))
+
+	// Inherited members are resolved through the base contract.
+	fees := identifiers["fee"]
+	require.Len(t, fees, 1)
+	assert.Same(t, declarations["fee"], astBuilder.ResolveIdentifier(fees[0].GetId(), "fee"))
+
+	assert.Nil(t, astBuilder.ResolveIdentifier(fees[0].GetId(), "unknown"))
+	assert.Nil(t, astBuilder.ResolveIdentifier(-1, "fee"))
+}
@@ -0,0 +1,2030 @@
+package ast
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+)
+
+var (
+	// solcHeaderRegex matches the `======= path =======` headers solc prints in front of every
+	// source unit when the AST is requested through the command line.
+	solcHeaderRegex = regexp.MustCompile(`(?m)^=+ (.+) =+\s*$`)
+
+	// solcIdRegex matches node ids of the solc AST, used to keep generated ids from colliding with them.
+	solcIdRegex = regexp.MustCompile(`"id"\s*:\s*(\d+)`)
+)
+
+// solcTypeDescriptions is the type information solc attaches to expressions and type names.
+type solcTypeDescriptions struct {
+	TypeIdentifier string `json:"typeIdentifier"`
+	TypeString     string `json:"typeString"`
+}
+
+// solcNode is a node of the solc compact AST JSON. It carries the union of the fields of the node
+// types converted by the importer; fields whose shape differs between node types are kept raw.
+type solcNode struct {
+	Id                       int64                 `json:"id"`
+	NodeType                 string                `json:"nodeType"`
+	Src                      string                `json:"src"`
+	Name                     string                `json:"name"`
+	NameLocation             string                `json:"nameLocation"`
+	AbsolutePath             string                `json:"absolutePath"`
+	License                  string                `json:"license"`
+	ExportedSymbols          map[string][]int64    `json:"exportedSymbols"`
+	Nodes                    []*solcNode           `json:"nodes"`
+	Literals                 []string              `json:"literals"`
+	File                     string                `json:"file"`
+	Scope                    int64                 `json:"scope"`
+	SourceUnit               int64                 `json:"sourceUnit"`
+	UnitAlias                string                `json:"unitAlias"`
+	SymbolAliases            []*solcSymbolAlias    `json:"symbolAliases"`
+	ContractKind             string                `json:"contractKind"`
+	Abstract                 bool                  `json:"abstract"`
+	FullyImplemented         bool                  `json:"fullyImplemented"`
+	LinearizedBaseContracts  []int64               `json:"linearizedBaseContracts"`
+	ContractDependencies     []int64               `json:"contractDependencies"`
+	BaseContracts            []*solcNode           `json:"baseContracts"`
+	BaseName                 *solcNode             `json:"baseName"`
+	Kind                     string                `json:"kind"`
+	Visibility               string                `json:"visibility"`
+	StateMutability          string                `json:"stateMutability"`
+	Mutability               string                `json:"mutability"`
+	Constant                 bool                  `json:"constant"`
+	StateVariable            bool                  `json:"stateVariable"`
+	StorageLocation          string                `json:"storageLocation"`
+	Indexed                  bool                  `json:"indexed"`
+	Virtual                  bool                  `json:"virtual"`
+	Implemented              bool                  `json:"implemented"`
+	Anonymous                bool                  `json:"anonymous"`
+	Parameters               solcParameters        `json:"parameters"`
+	ReturnParameters         *solcNode             `json:"returnParameters"`
+	Body                     *solcNode             `json:"body"`
+	Modifiers                []*solcNode           `json:"modifiers"`
+	ModifierName             *solcNode             `json:"modifierName"`
+	Arguments                []*solcNode           `json:"arguments"`
+	Names                    []string              `json:"names"`
+	Options                  []*solcNode           `json:"options"`
+	Overrides                json.RawMessage       `json:"overrides"`
+	Documentation            json.RawMessage       `json:"documentation"`
+	Text                     string                `json:"text"`
+	Members                  []*solcNode           `json:"members"`
+	CanonicalName            string                `json:"canonicalName"`
+	UnderlyingType           *solcNode             `json:"underlyingType"`
+	TypeName                 *solcNode             `json:"typeName"`
+	LibraryName              *solcNode             `json:"libraryName"`
+	PathNode                 *solcNode             `json:"pathNode"`
+	KeyType                  *solcNode             `json:"keyType"`
+	KeyNameLocation          string                `json:"keyNameLocation"`
+	ValueType                *solcNode             `json:"valueType"`
+	ValueNameLocation        string                `json:"valueNameLocation"`
+	BaseType                 *solcNode             `json:"baseType"`
+	Length                   *solcNode             `json:"length"`
+	ReferencedDeclaration    int64                 `json:"referencedDeclaration"`
+	OverloadedDeclarations   []int64               `json:"overloadedDeclarations"`
+	TypeDescriptions         *solcTypeDescriptions `json:"typeDescriptions"`
+	Value                    string                `json:"value"`
+	HexValue                 string                `json:"hexValue"`
+	Statements               []*solcNode           `json:"statements"`
+	Declarations             []*solcNode           `json:"declarations"`
+	Assignments              []int64               `json:"assignments"`
+	InitialValue             *solcNode             `json:"initialValue"`
+	Expression               *solcNode             `json:"expression"`
+	Condition                *solcNode             `json:"condition"`
+	TrueBody                 *solcNode             `json:"trueBody"`
+	TrueExpression           *solcNode             `json:"trueExpression"`
+	FalseExpression          *solcNode             `json:"falseExpression"`
+	InitializationExpression *solcNode             `json:"initializationExpression"`
+	LoopExpression           *solcNode             `json:"loopExpression"`
+	LeftHandSide             *solcNode             `json:"leftHandSide"`
+	RightHandSide            *solcNode             `json:"rightHandSide"`
+	LeftExpression           *solcNode             `json:"leftExpression"`
+	RightExpression          *solcNode             `json:"rightExpression"`
+	Operator                 string                `json:"operator"`
+	Prefix                   bool                  `json:"prefix"`
+	SubExpression            *solcNode             `json:"subExpression"`
+	BaseExpression           *solcNode             `json:"baseExpression"`
+	IndexExpression          *solcNode             `json:"indexExpression"`
+	StartExpression          *solcNode             `json:"startExpression"`
+	EndExpression            *solcNode             `json:"endExpression"`
+	MemberName               string                `json:"memberName"`
+	MemberLocation           string                `json:"memberLocation"`
+	Components               []*solcNode           `json:"components"`
+	IsInlineArray            bool                  `json:"isInlineArray"`
+	IsConstant               bool                  `json:"isConstant"`
+	IsPure                   bool                  `json:"isPure"`
+	IsLValue                 bool                  `json:"isLValue"`
+	LValueRequested          bool                  `json:"lValueRequested"`
+	EventCall                *solcNode             `json:"eventCall"`
+	ErrorCall                *solcNode             `json:"errorCall"`
+	ExternalCall             *solcNode             `json:"externalCall"`
+	Clauses                  []*solcNode           `json:"clauses"`
+	ErrorName                string                `json:"errorName"`
+	Block                    *solcNode             `json:"block"`
+	FunctionReturnParameters int64                 `json:"functionReturnParameters"`
+	YulAST                   *solcYulNode          `json:"AST"`
+}
+
+// solcParameters holds the parameters field, which is a ParameterList node on definitions and
+// the list of declarations on the ParameterList node itself.
+type solcParameters struct {
+	List  *solcNode
+	Items []*solcNode
+}
+
+// UnmarshalJSON decodes either shape of the parameters field.
+func (p *solcParameters) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		return json.Unmarshal(data, &p.Items)
+	}
+	return json.Unmarshal(data, &p.List)
+}
+
+// solcSymbolAlias is a symbol imported by name, as in `import {A as B} from "file.sol"`.
+type solcSymbolAlias struct {
+	Foreign *solcNode `json:"foreign"`
+	Local   string    `json:"local"`
+}
+
+// solcYulNode is a node of the Yul AST solc attaches to inline assembly blocks.
+type solcYulNode struct {
+	NodeType        string          `json:"nodeType"`
+	Src             string          `json:"src"`
+	Name            string          `json:"name"`
+	Kind            string          `json:"kind"`
+	HexValue        string          `json:"hexValue"`
+	Value           json.RawMessage `json:"value"`
+	Statements      []*solcYulNode  `json:"statements"`
+	Expression      *solcYulNode    `json:"expression"`
+	FunctionName    *solcYulNode    `json:"functionName"`
+	Arguments       []*solcYulNode  `json:"arguments"`
+	VariableNames   []*solcYulNode  `json:"variableNames"`
+	Variables       []*solcYulNode  `json:"variables"`
+	Parameters      []*solcYulNode  `json:"parameters"`
+	ReturnVariables []*solcYulNode  `json:"returnVariables"`
+	Condition       *solcYulNode    `json:"condition"`
+	Body            *solcYulNode    `json:"body"`
+	Pre             *solcYulNode    `json:"pre"`
+	Post            *solcYulNode    `json:"post"`
+	Cases           []*solcYulNode  `json:"cases"`
+}
+
+// solcImporter converts solc compact AST nodes into solgo AST nodes.
+type solcImporter struct {
+	*ASTBuilder
+	comments []*Comment
+}
+
+// FromSolcJSON reconstructs a RootNode from the AST produced by solc with --ast-compact-json.
+// See ASTBuilder.ImportFromSolcJSON for the accepted formats.
+func FromSolcJSON(data []byte) (*RootNode, error) {
+	return NewAstBuilder(nil, nil).ImportFromSolcJSON(context.Background(), data)
+}
+
+// ImportFromSolcJSON imports the AST from the compact JSON AST produced by the solc compiler.
+// It accepts the command line output of `solc --ast-compact-json`, a single SourceUnit, an array of
+// SourceUnits or standard JSON output with the ast of every source.
+//
+// Node ids and referenced declarations are kept as assigned by solc, while source locations keep
+// solc's per file offsets as no source code is available to compute lines and columns. As with the
+// parser, every contract gets a source unit of its own and file level definitions become globals.
+func (b *ASTBuilder) ImportFromSolcJSON(ctx context.Context, data []byte) (*RootNode, error) {
+	units, err := decodeSolcSourceUnits(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ids of nodes solc does not have, such as the per contract source units, are generated after
+	// the largest id used by solc.
+	for _, match := range solcIdRegex.FindAllSubmatch(data, -1) {
+		if id, err := strconv.ParseInt(string(match[1]), 10, 64); err == nil && id >= b.nextID {
+			b.nextID = id + 1
+		}
+	}
+
+	importer := &solcImporter{
+		ASTBuilder: b,
+		comments:   make([]*Comment, 0),
+	}
+
+	root := NewRootNode(b, 0, make([]*SourceUnit[Node[ast_pb.SourceUnit]], 0), nil)
+	for _, unit := range units {
+		sourceUnits, globals := importer.sourceUnit(unit, root.GetId())
+		root.SourceUnits = append(root.SourceUnits, sourceUnits...)
+		root.Globals = append(root.Globals, globals...)
+	}
+	root.Comments = importer.comments
+
+	if len(root.SourceUnits) > 0 {
+		root.EntrySourceUnit = root.SourceUnits[len(root.SourceUnits)-1].GetId()
+	}
+
+	if b.tree == nil {
+		b.tree = NewTree(b)
+	}
+
+	b.tree.SetRoot(root)
+	b.symbolTable = nil
+	b.nodeIndex = nil
+
+	return root, nil
+}
+
+// decodeSolcSourceUnits extracts the SourceUnit nodes from any of the formats solc outputs the AST in.
+func decodeSolcSourceUnits(data []byte) ([]*solcNode, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, errors.New("solc ast is empty")
+	}
+
+	units := make([]*solcNode, 0)
+
+	switch trimmed[0] {
+	case '[':
+		if err := json.Unmarshal(trimmed, &units); err != nil {
+			return nil, err
+		}
+	case '{':
+		var output struct {
+			NodeType string `json:"nodeType"`
+			Sources  map[string]struct {
+				Id  int64     `json:"id"`
+				AST *solcNode `json:"ast"`
+			} `json:"sources"`
+		}
+		if err := json.Unmarshal(trimmed, &output); err != nil {
+			return nil, err
+		}
+
+		if output.NodeType != "" {
+			var unit *solcNode
+			if err := json.Unmarshal(trimmed, &unit); err != nil {
+				return nil, err
+			}
+			units = append(units, unit)
+			break
+		}
+
+		for _, source := range output.Sources {
+			if source.AST != nil {
+				units = append(units, source.AST)
+			}
+		}
+		sort.SliceStable(units, func(i, j int) bool {
+			return units[i].Id < units[j].Id
+		})
+	default:
+		headers := solcHeaderRegex.FindAllIndex(trimmed, -1)
+		for i, header := range headers {
+			end := len(trimmed)
+			if i+1 < len(headers) {
+				end = headers[i+1][0]
+			}
+
+			var unit *solcNode
+			if err := json.Unmarshal(bytes.TrimSpace(trimmed[header[1]:end]), &unit); err != nil {
+				return nil, err
+			}
+			units = append(units, unit)
+		}
+	}
+
+	toReturn := make([]*solcNode, 0, len(units))
+	for _, unit := range units {
+		if unit == nil {
+			continue
+		}
+		if unit.NodeType != "SourceUnit" {
+			return nil, fmt.Errorf("expected solc SourceUnit node, got %q", unit.NodeType)
+		}
+		toReturn = append(toReturn, unit)
+	}
+
+	if len(toReturn) == 0 {
+		return nil, errors.New("no source units found in solc ast")
+	}
+
+	return toReturn, nil
+}
+
+// sourceUnit converts a solc source unit into a source unit per contract it defines, together with
+// the file level definitions.
+func (i *solcImporter) sourceUnit(node *solcNode, rootId int64) ([]*SourceUnit[Node[ast_pb.SourceUnit]], []Node[NodeType]) {
+	sourceUnits := make([]*SourceUnit[Node[ast_pb.SourceUnit]], 0)
+	globals := make([]Node[NodeType], 0)
+	directives := make([]Node[NodeType], 0)
+
+	exportedSymbols := make([]Symbol, 0, len(node.ExportedSymbols))
+	for name, ids := range node.ExportedSymbols {
+		if len(ids) > 0 {
+			exportedSymbols = append(exportedSymbols, Symbol{
+				Id:           ids[0],
+				Name:         name,
+				AbsolutePath: filepath.Base(node.AbsolutePath),
+			})
+		}
+	}
+	sort.Slice(exportedSymbols, func(a, b int) bool {
+		return exportedSymbols[a].Name < exportedSymbols[b].Name
+	})
+
+	for _, child := range node.Nodes {
+		switch child.NodeType {
+		case "PragmaDirective":
+			directives = append(directives, i.pragma(child, node.Id))
+		case "ImportDirective":
+			directives = append(directives, i.importDirective(child, node.Id))
+		case "ContractDefinition":
+			unit := &SourceUnit[Node[ast_pb.SourceUnit]]{
+				Id:              i.GetNextID(),
+				License:         node.License,
+				ExportedSymbols: exportedSymbols,
+				AbsolutePath:    filepath.Base(node.AbsolutePath),
+				Name:            child.Name,
+				NodeType:        ast_pb.NodeType_SOURCE_UNIT,
+				Src:             i.src(node.Src, rootId),
+			}
+
+			contract := i.contract(child, unit.GetId())
+			unit.Nodes = append(append(make([]Node[NodeType], 0), directives...), contract)
+			unit.Contract = contract
+			unit.BaseContracts = contractBaseContracts(contract)
+			sourceUnits = append(sourceUnits, unit)
+		default:
+			if global := i.definition(child, nil, node.Id); global != nil {
+				globals = append(globals, global)
+			}
+		}
+	}
+
+	return sourceUnits, globals
+}
+
+// pragma converts a PragmaDirective node.
+func (i *solcImporter) pragma(node *solcNode, parentId int64) *Pragma {
+	text := "pragma" + strings.Join(node.Literals, "") + ";"
+	return &Pragma{
+		Id:       node.Id,
+		NodeType: ast_pb.NodeType_PRAGMA_DIRECTIVE,
+		Src:      i.src(node.Src, parentId),
+		Literals: getLiterals(text),
+		Text:     text,
+	}
+}
+
+// importDirective converts an ImportDirective node.
+func (i *solcImporter) importDirective(node *solcNode, parentId int64) *Import {
+	toReturn := &Import{
+		Id:           node.Id,
+		NodeType:     ast_pb.NodeType_IMPORT_DIRECTIVE,
+		Src:          i.src(node.Src, parentId),
+		AbsolutePath: filepath.Base(node.AbsolutePath),
+		File:         node.File,
+		Scope:        node.Scope,
+		UnitAlias:    node.UnitAlias,
+		UnitAliases:  make([]string, 0),
+		SourceUnit:   node.SourceUnit,
+	}
+
+	if node.NameLocation != "" {
+		nameLocation := i.src(node.NameLocation, node.Id)
+		toReturn.NameLocation = &nameLocation
+	}
+
+	for _, alias := range node.SymbolAliases {
+		if alias.Local != "" {
+			toReturn.UnitAliases = append(toReturn.UnitAliases, alias.Local)
+		} else if alias.Foreign != nil {
+			toReturn.UnitAliases = append(toReturn.UnitAliases, alias.Foreign.Name)
+		}
+	}
+
+	return toReturn
+}
+
+// contract converts a ContractDefinition node into a Contract, Interface or Library.
+func (i *solcImporter) contract(node *solcNode, parentId int64) Node[NodeType] {
+	i.documentation(node)
+
+	baseContracts := make([]*BaseContract, 0, len(node.BaseContracts))
+	for _, base := range node.BaseContracts {
+		baseContract := &BaseContract{
+			Id:       base.Id,
+			NodeType: ast_pb.NodeType_INHERITANCE_SPECIFIER,
+			Src:      i.src(base.Src, node.Id),
+		}
+		if base.BaseName != nil {
+			baseContract.BaseName = &BaseContractName{
+				Id:                            base.BaseName.Id,
+				NodeType:                      ast_pb.NodeType_IDENTIFIER_PATH,
+				Src:                           i.src(base.BaseName.Src, base.Id),
+				Name:                          base.BaseName.Name,
+				ReferencedDeclaration:         base.BaseName.ReferencedDeclaration,
+				ContractReferencedDeclaration: node.Id,
+			}
+		}
+		if len(base.Arguments) > 0 {
+			baseContract.Arguments = i.expressions(base.Arguments, base.Id)
+		}
+		baseContracts = append(baseContracts, baseContract)
+	}
+
+	nodes := make([]Node[NodeType], 0, len(node.Nodes))
+	for _, child := range node.Nodes {
+		if member := i.definition(child, node, node.Id); member != nil {
+			nodes = append(nodes, member)
+		}
+	}
+
+	linearizedBaseContracts := append(make([]int64, 0), node.LinearizedBaseContracts...)
+	contractDependencies := append(make([]int64, 0), node.ContractDependencies...)
+
+	switch node.ContractKind {
+	case "interface":
+		return &Interface{
+			ASTBuilder:              i.ASTBuilder,
+			Id:                      node.Id,
+			Name:                    node.Name,
+			NodeType:                ast_pb.NodeType_CONTRACT_DEFINITION,
+			Src:                     i.src(node.Src, parentId),
+			NameLocation:            i.src(node.NameLocation, node.Id),
+			Abstract:                node.Abstract,
+			Kind:                    ast_pb.NodeType_KIND_INTERFACE,
+			FullyImplemented:        node.FullyImplemented,
+			Nodes:                   nodes,
+			LinearizedBaseContracts: linearizedBaseContracts,
+			BaseContracts:           baseContracts,
+			ContractDependencies:    contractDependencies,
+		}
+	case "library":
+		return &Library{
+			ASTBuilder:              i.ASTBuilder,
+			Id:                      node.Id,
+			Name:                    node.Name,
+			NodeType:                ast_pb.NodeType_CONTRACT_DEFINITION,
+			Src:                     i.src(node.Src, parentId),
+			NameLocation:            i.src(node.NameLocation, node.Id),
+			Abstract:                node.Abstract,
+			Kind:                    ast_pb.NodeType_KIND_LIBRARY,
+			FullyImplemented:        node.FullyImplemented,
+			Nodes:                   nodes,
+			LinearizedBaseContracts: linearizedBaseContracts,
+			BaseContracts:           baseContracts,
+			ContractDependencies:    contractDependencies,
+		}
+	default:
+		return &Contract{
+			ASTBuilder:              i.ASTBuilder,
+			Id:                      node.Id,
+			Name:                    node.Name,
+			NodeType:                ast_pb.NodeType_CONTRACT_DEFINITION,
+			Src:                     i.src(node.Src, parentId),
+			NameLocation:            i.src(node.NameLocation, node.Id),
+			Abstract:                node.Abstract,
+			Kind:                    ast_pb.NodeType_KIND_CONTRACT,
+			FullyImplemented:        node.FullyImplemented,
+			Nodes:                   nodes,
+			LinearizedBaseContracts: linearizedBaseContracts,
+			BaseContracts:           baseContracts,
+			ContractDependencies:    contractDependencies,
+		}
+	}
+}
+
+// definition converts a node declared at contract or file level. The contract is nil for file
+// level definitions. Unsupported nodes are skipped.
+func (i *solcImporter) definition(node *solcNode, contract *solcNode, parentId int64) Node[NodeType] {
+	i.documentation(node)
+
+	scopeName := ""
+	if contract != nil {
+		scopeName = contract.Name
+	}
+
+	switch node.NodeType {
+	case "VariableDeclaration":
+		return i.stateVariable(node, parentId)
+	case "FunctionDefinition":
+		return i.function(node, parentId)
+	case "ModifierDefinition":
+		return &ModifierDefinition{
+			ASTBuilder:   i.ASTBuilder,
+			Id:           node.Id,
+			Name:         node.Name,
+			NodeType:     ast_pb.NodeType_MODIFIER_DEFINITION,
+			Src:          i.src(node.Src, parentId),
+			NameLocation: i.src(node.NameLocation, node.Id),
+			Visibility:   solcVisibility(node.Visibility),
+			Virtual:      node.Virtual,
+			Parameters:   i.parameterList(node.Parameters.List, node.Id),
+			Body:         i.body(node.Body, node.Id, node.Src),
+		}
+	case "EventDefinition":
+		return &EventDefinition{
+			ASTBuilder:     i.ASTBuilder,
+			SourceUnitName: scopeName,
+			Id:             node.Id,
+			NodeType:       ast_pb.NodeType_EVENT_DEFINITION,
+			Src:            i.src(node.Src, parentId),
+			Parameters:     i.parameterList(node.Parameters.List, node.Id),
+			Name:           node.Name,
+			Anonymous:      node.Anonymous,
+			TypeDescription: &TypeDescription{
+				TypeIdentifier: fmt.Sprintf("t_event&_%s_%s_&%d", scopeName, node.Name, node.Id),
+				TypeString:     fmt.Sprintf("event %s.%s", scopeName, node.Name),
+			},
+		}
+	case "ErrorDefinition":
+		return &ErrorDefinition{
+			ASTBuilder:     i.ASTBuilder,
+			SourceUnitName: scopeName,
+			Id:             node.Id,
+			NodeType:       ast_pb.NodeType_ERROR_DEFINITION,
+			Src:            i.src(node.Src, parentId),
+			Name:           node.Name,
+			NameLocation:   i.src(node.NameLocation, node.Id),
+			Parameters:     i.parameterList(node.Parameters.List, node.Id),
+			TypeDescription: &TypeDescription{
+				TypeIdentifier: fmt.Sprintf("t_error$_%s_%s_$%d", scopeName, node.Name, node.Id),
+				TypeString:     fmt.Sprintf("error %s.%s", scopeName, node.Name),
+			},
+		}
+	case "StructDefinition":
+		members := make([]Node[NodeType], 0, len(node.Members))
+		for _, member := range node.Members {
+			members = append(members, i.parameter(member, node.Id))
+		}
+		return &StructDefinition{
+			ASTBuilder:      i.ASTBuilder,
+			SourceUnitName:  scopeName,
+			Id:              node.Id,
+			NodeType:        ast_pb.NodeType_STRUCT_DEFINITION,
+			Src:             i.src(node.Src, parentId),
+			Name:            node.Name,
+			NameLocation:    i.src(node.NameLocation, node.Id),
+			CanonicalName:   node.CanonicalName,
+			Members:         members,
+			Visibility:      solcVisibility(node.Visibility),
+			StorageLocation: ast_pb.StorageLocation_DEFAULT,
+			TypeDescription: &TypeDescription{
+				TypeIdentifier: fmt.Sprintf("t_struct$_%s_%s_$%d", scopeName, node.Name, node.Id),
+				TypeString:     fmt.Sprintf("struct %s.%s", scopeName, node.Name),
+			},
+		}
+	case "EnumDefinition":
+		members := make([]Node[NodeType], 0, len(node.Members))
+		for _, member := range node.Members {
+			members = append(members, &Parameter{
+				ASTBuilder: i.ASTBuilder,
+				Id:         member.Id,
+				NodeType:   ast_pb.NodeType_ENUM_VALUE,
+				Src:        i.src(member.Src, node.Id),
+				Name:       member.Name,
+				TypeDescription: &TypeDescription{
+					TypeIdentifier: fmt.Sprintf("t_enum_$_%s$_%s_$%d", node.Name, member.Name, member.Id),
+					TypeString:     fmt.Sprintf("enum %s.%s", node.CanonicalName, member.Name),
+				},
+			})
+		}
+		return &EnumDefinition{
+			ASTBuilder:     i.ASTBuilder,
+			SourceUnitName: scopeName,
+			Id:             node.Id,
+			NodeType:       ast_pb.NodeType_ENUM_DEFINITION,
+			Src:            i.src(node.Src, parentId),
+			NameLocation:   i.src(node.NameLocation, node.Id),
+			Name:           node.Name,
+			CanonicalName:  node.CanonicalName,
+			Members:        members,
+			TypeDescription: &TypeDescription{
+				TypeIdentifier: fmt.Sprintf("t_enum_$_%s_$%d", node.Name, node.Id),
+				TypeString:     fmt.Sprintf("enum %s", node.CanonicalName),
+			},
+		}
+	case "UserDefinedValueTypeDefinition":
+		typeString := node.Name
+		if scopeName != "" {
+			typeString = scopeName + "." + typeString
+		}
+		toReturn := &UserDefinedValueTypeDefinition{
+			ASTBuilder:   i.ASTBuilder,
+			Id:           node.Id,
+			NodeType:     ast_pb.NodeType_USER_DEFINED_VALUE_TYPE,
+			Src:          i.src(node.Src, parentId),
+			Is:           true,
+			Name:         node.Name,
+			NameLocation: i.src(node.NameLocation, node.Id),
+			TypeName:     i.typeName(node.UnderlyingType, node.Id),
+			TypeDescription: &TypeDescription{
+				TypeIdentifier: fmt.Sprintf("t_userDefinedValueType$_%s_$%d", node.Name, node.Id),
+				TypeString:     typeString,
+			},
+		}
+		if toReturn.TypeName != nil {
+			toReturn.Type = toReturn.TypeName.Name
+			toReturn.TypeLocation = toReturn.TypeName.Src
+			toReturn.UnderlyingType = toReturn.TypeName.TypeDescription
+		}
+		return toReturn
+	case "UsingForDirective":
+		toReturn := &UsingDirective{
+			ASTBuilder: i.ASTBuilder,
+			Id:         node.Id,
+			NodeType:   ast_pb.NodeType_USING_FOR_DIRECTIVE,
+			Src:        i.src(node.Src, parentId),
+			TypeName:   i.typeName(node.TypeName, node.Id),
+		}
+		if toReturn.TypeName != nil {
+			toReturn.TypeDescription = toReturn.TypeName.TypeDescription
+		}
+		if node.LibraryName != nil {
+			toReturn.LibraryName = &LibraryName{
+				ASTBuilder:            i.ASTBuilder,
+				Id:                    node.LibraryName.Id,
+				NodeType:              ast_pb.NodeType_IDENTIFIER_PATH,
+				Src:                   i.src(node.LibraryName.Src, node.Id),
+				Name:                  node.LibraryName.Name,
+				ReferencedDeclaration: node.LibraryName.ReferencedDeclaration,
+			}
+		}
+		return toReturn
+	}
+
+	return nil
+}
+
+// stateVariable converts a state variable or file level constant declaration.
+func (i *solcImporter) stateVariable(node *solcNode, parentId int64) *StateVariableDeclaration {
+	return &StateVariableDeclaration{
+		ASTBuilder:      i.ASTBuilder,
+		Id:              node.Id,
+		Name:            node.Name,
+		Constant:        node.Constant || node.Mutability == "constant",
+		StateVariable:   node.StateVariable,
+		NodeType:        ast_pb.NodeType_VARIABLE_DECLARATION,
+		Src:             i.src(node.Src, parentId),
+		Scope:           node.Scope,
+		TypeDescription: i.typeDescription(node.TypeDescriptions),
+		Visibility:      solcVisibility(node.Visibility),
+		StorageLocation: ast_pb.StorageLocation_DEFAULT,
+		StateMutability: solcVariableMutability(node.Mutability),
+		TypeName:        i.typeName(node.TypeName, node.Id),
+		InitialValue:    i.expression(node.InitialValue, node.Id),
+	}
+}
+
+// function converts a FunctionDefinition node into a Function, Constructor, Fallback or Receive.
+func (i *solcImporter) function(node *solcNode, parentId int64) Node[NodeType] {
+	modifiers := make([]*ModifierInvocation, 0, len(node.Modifiers))
+	for _, modifier := range node.Modifiers {
+		modifiers = append(modifiers, i.modifierInvocation(modifier, node.Id))
+	}
+
+	overrides := make([]*OverrideSpecifier, 0)
+	if override := i.overrideSpecifier(node.Overrides, node.Id); override != nil {
+		overrides = append(overrides, override)
+	}
+
+	src := i.src(node.Src, parentId)
+	parameters := i.parameterList(node.Parameters.List, node.Id)
+	returnParameters := i.parameterList(node.ReturnParameters, node.Id)
+	body := i.body(node.Body, node.Id, node.Src)
+	visibility := solcVisibility(node.Visibility)
+	mutability := solcFunctionMutability(node.StateMutability)
+
+	switch node.Kind {
+	case "constructor":
+		return &Constructor{
+			ASTBuilder:       i.ASTBuilder,
+			Id:               node.Id,
+			NodeType:         ast_pb.NodeType_FUNCTION_DEFINITION,
+			Src:              src,
+			Kind:             ast_pb.NodeType_CONSTRUCTOR,
+			StateMutability:  mutability,
+			Visibility:       visibility,
+			Implemented:      node.Implemented,
+			Modifiers:        modifiers,
+			Parameters:       parameters,
+			ReturnParameters: returnParameters,
+			Scope:            node.Scope,
+			Body:             body,
+		}
+	case "fallback":
+		return &Fallback{
+			ASTBuilder:       i.ASTBuilder,
+			Id:               node.Id,
+			NodeType:         ast_pb.NodeType_FUNCTION_DEFINITION,
+			Kind:             ast_pb.NodeType_FALLBACK,
+			Src:              src,
+			Implemented:      node.Implemented,
+			Visibility:       visibility,
+			StateMutability:  mutability,
+			Modifiers:        modifiers,
+			Overrides:        overrides,
+			Parameters:       parameters,
+			ReturnParameters: returnParameters,
+			Body:             body,
+			Virtual:          node.Virtual,
+		}
+	case "receive":
+		return &Receive{
+			ASTBuilder:       i.ASTBuilder,
+			Id:               node.Id,
+			NodeType:         ast_pb.NodeType_FUNCTION_DEFINITION,
+			Kind:             ast_pb.NodeType_RECEIVE,
+			Src:              src,
+			Implemented:      node.Implemented,
+			Visibility:       visibility,
+			StateMutability:  mutability,
+			Modifiers:        modifiers,
+			Overrides:        overrides,
+			Parameters:       parameters,
+			ReturnParameters: returnParameters,
+			Body:             body,
+			Virtual:          node.Virtual,
+			Payable:          mutability == ast_pb.Mutability_PAYABLE,
+		}
+	}
+
+	fn := &Function{
+		ASTBuilder:       i.ASTBuilder,
+		Id:               node.Id,
+		Name:             node.Name,
+		NodeType:         ast_pb.NodeType_FUNCTION_DEFINITION,
+		Kind:             ast_pb.NodeType_KIND_FUNCTION,
+		Src:              src,
+		NameLocation:     i.src(node.NameLocation, node.Id),
+		Body:             body,
+		Implemented:      node.Implemented,
+		Visibility:       visibility,
+		StateMutability:  mutability,
+		Virtual:          node.Virtual,
+		Modifiers:        modifiers,
+		Overrides:        overrides,
+		Parameters:       parameters,
+		ReturnParameters: returnParameters,
+		Scope:            node.Scope,
+	}
+	fn.ComputeSignature()
+	fn.TypeDescription = fn.buildTypeDescription()
+
+	return fn
+}
+
+// modifierInvocation converts a ModifierInvocation node, which also covers base constructor calls.
+func (i *solcImporter) modifierInvocation(node *solcNode, parentId int64) *ModifierInvocation {
+	toReturn := &ModifierInvocation{
+		ASTBuilder:    i.ASTBuilder,
+		Id:            node.Id,
+		NodeType:      ast_pb.NodeType_MODIFIER_INVOCATION,
+		Kind:          ast_pb.NodeType_MODIFIER_INVOCATION,
+		Src:           i.src(node.Src, parentId),
+		ArgumentTypes: make([]*TypeDescription, 0),
+		Arguments:     make([]Node[NodeType], 0),
+	}
+
+	if node.ModifierName != nil {
+		toReturn.Name = node.ModifierName.Name
+		toReturn.ModifierName = &ModifierName{
+			Id:       node.ModifierName.Id,
+			Name:     node.ModifierName.Name,
+			NodeType: ast_pb.NodeType_IDENTIFIER,
+			Src:      i.src(node.ModifierName.Src, node.Id),
+		}
+	}
+
+	for _, argument := range node.Arguments {
+		if expr := i.expression(argument, node.Id); expr != nil {
+			toReturn.Arguments = append(toReturn.Arguments, expr)
+			toReturn.ArgumentTypes = append(toReturn.ArgumentTypes, expr.GetTypeDescription())
+		}
+	}
+
+	return toReturn
+}
+
+// overrideSpecifier converts the raw overrides field of a function, modifier or state variable.
+func (i *solcImporter) overrideSpecifier(raw json.RawMessage, parentId int64) *OverrideSpecifier {
+	if len(raw) == 0 || raw[0] != '{' {
+		return nil
+	}
+
+	var node *solcNode
+	if err := json.Unmarshal(raw, &node); err != nil || node == nil {
+		return nil
+	}
+
+	var paths []*solcNode
+	if len(node.Overrides) > 0 {
+		_ = json.Unmarshal(node.Overrides, &paths)
+	}
+
+	toReturn := &OverrideSpecifier{
+		ASTBuilder: i.ASTBuilder,
+		Id:         node.Id,
+		NodeType:   ast_pb.NodeType_OVERRIDE_SPECIFIER,
+		Src:        i.src(node.Src, parentId),
+		Overrides:  make([]*OverridePath, 0, len(paths)),
+	}
+
+	for _, path := range paths {
+		name, refId := path.Name, path.ReferencedDeclaration
+		if path.PathNode != nil {
+			name = path.PathNode.Name
+		}
+		toReturn.Overrides = append(toReturn.Overrides, &OverridePath{
+			Id:                    path.Id,
+			Name:                  name,
+			NodeType:              ast_pb.NodeType_OVERRIDE_PATH,
+			Src:                   i.src(path.Src, node.Id),
+			ReferencedDeclaration: refId,
+			TypeDescription:       i.typeDescription(path.TypeDescriptions),
+		})
+	}
+
+	return toReturn
+}
+
+// parameterList converts a ParameterList node. Missing lists are returned empty, as the parser does.
+func (i *solcImporter) parameterList(node *solcNode, parentId int64) *ParameterList {
+	toReturn := &ParameterList{
+		ASTBuilder:     i.ASTBuilder,
+		NodeType:       ast_pb.NodeType_PARAMETER_LIST,
+		Parameters:     make([]*Parameter, 0),
+		ParameterTypes: make([]*TypeDescription, 0),
+	}
+
+	if node == nil {
+		toReturn.Id = i.GetNextID()
+		return toReturn
+	}
+
+	toReturn.Id = node.Id
+	toReturn.Src = i.src(node.Src, parentId)
+	for _, parameter := range node.Parameters.Items {
+		param := i.parameter(parameter, node.Id)
+		toReturn.Parameters = append(toReturn.Parameters, param)
+		toReturn.ParameterTypes = append(toReturn.ParameterTypes, param.TypeDescription)
+	}
+
+	return toReturn
+}
+
+// parameter converts a VariableDeclaration node used as a parameter or struct member.
+func (i *solcImporter) parameter(node *solcNode, parentId int64) *Parameter {
+	toReturn := &Parameter{
+		ASTBuilder:      i.ASTBuilder,
+		Id:              node.Id,
+		NodeType:        ast_pb.NodeType_VARIABLE_DECLARATION,
+		Src:             i.src(node.Src, parentId),
+		Scope:           node.Scope,
+		Name:            node.Name,
+		TypeName:        i.typeName(node.TypeName, node.Id),
+		StorageLocation: solcStorageLocation(node.StorageLocation),
+		Visibility:      solcVisibility(node.Visibility),
+		StateMutability: solcVariableMutability(node.Mutability),
+		Constant:        node.Constant,
+		StateVariable:   node.StateVariable,
+		TypeDescription: i.typeDescription(node.TypeDescriptions),
+		Indexed:         node.Indexed,
+	}
+
+	if node.NameLocation != "" {
+		nameLocation := i.src(node.NameLocation, node.Id)
+		toReturn.NameLocation = &nameLocation
+	}
+
+	return toReturn
+}
+
+// typeName converts an ElementaryTypeName, UserDefinedTypeName, Mapping, ArrayTypeName or
+// FunctionTypeName node.
+func (i *solcImporter) typeName(node *solcNode, parentId int64) *TypeName {
+	if node == nil {
+		return nil
+	}
+
+	toReturn := &TypeName{
+		ASTBuilder:      i.ASTBuilder,
+		Id:              node.Id,
+		Src:             i.src(node.Src, parentId),
+		Name:            node.Name,
+		TypeDescription: i.typeDescription(node.TypeDescriptions),
+		ParentNode:      make([]Node[NodeType], 0),
+	}
+
+	switch node.NodeType {
+	case "ElementaryTypeName":
+		toReturn.NodeType = ast_pb.NodeType_ELEMENTARY_TYPE_NAME
+		if node.StateMutability == "payable" {
+			toReturn.StateMutability = ast_pb.Mutability_PAYABLE
+		}
+	case "UserDefinedTypeName":
+		toReturn.NodeType = ast_pb.NodeType_USER_DEFINED_PATH_NAME
+		toReturn.ReferencedDeclaration = node.ReferencedDeclaration
+
+		path := node.PathNode
+		if path == nil {
+			path = &solcNode{Id: i.GetNextID(), Name: node.Name, Src: node.Src, ReferencedDeclaration: node.ReferencedDeclaration}
+		}
+		toReturn.PathNode = &PathNode{
+			Id:                    path.Id,
+			Name:                  path.Name,
+			NodeType:              ast_pb.NodeType_IDENTIFIER_PATH,
+			ReferencedDeclaration: path.ReferencedDeclaration,
+			Src:                   i.src(path.Src, node.Id),
+		}
+
+		// Qualified names are named after their last segment, as the parser does.
+		parts := strings.Split(path.Name, ".")
+		toReturn.Name = parts[len(parts)-1]
+	case "Mapping":
+		toReturn.NodeType = ast_pb.NodeType_MAPPING_TYPE_NAME
+		toReturn.Name = "mapping"
+		toReturn.KeyType = i.typeName(node.KeyType, node.Id)
+		toReturn.ValueType = i.typeName(node.ValueType, node.Id)
+		if node.KeyNameLocation != "" {
+			location := i.src(node.KeyNameLocation, node.Id)
+			toReturn.KeyNameLocation = &location
+		}
+		if node.ValueNameLocation != "" {
+			location := i.src(node.ValueNameLocation, node.Id)
+			toReturn.ValueNameLocation = &location
+		}
+	case "ArrayTypeName":
+		// Arrays are described by their base type, named after the array type as written.
+		base := i.typeName(node.BaseType, node.Id)
+		length := ""
+		if node.Length != nil {
+			length = node.Length.Value
+		}
+		if base != nil {
+			toReturn.NodeType = base.NodeType
+			toReturn.Name = fmt.Sprintf("%s[%s]", base.Name, length)
+			toReturn.PathNode = base.PathNode
+			toReturn.ReferencedDeclaration = base.ReferencedDeclaration
+		}
+	case "FunctionTypeName":
+		toReturn.NodeType = ast_pb.NodeType_FUNCTION_TYPE_NAME
+		toReturn.Name = "function"
+		toReturn.StateMutability = solcFunctionMutability(node.StateMutability)
+	default:
+		toReturn.NodeType = ast_pb.NodeType_UNKNOWN_TYPE_NAME
+	}
+
+	return toReturn
+}
+
+// body converts a Block node. Functions without implementation get an empty body located at the
+// function, as the parser does.
+func (i *solcImporter) body(node *solcNode, parentId int64, parentSrc string) *BodyNode {
+	if node == nil {
+		return &BodyNode{
+			ASTBuilder: i.ASTBuilder,
+			Id:         i.GetNextID(),
+			NodeType:   ast_pb.NodeType_BLOCK,
+			Src:        i.src(parentSrc, parentId),
+			Statements: make([]Node[NodeType], 0),
+		}
+	}
+
+	if node.NodeType != "Block" {
+		// Single statement bodies of control flow statements are wrapped into a block.
+		toReturn := i.body(nil, parentId, node.Src)
+		toReturn.Implemented = true
+		if statement := i.statement(node, toReturn.GetId()); statement != nil {
+			toReturn.Statements = append(toReturn.Statements, statement)
+		}
+		return toReturn
+	}
+
+	return &BodyNode{
+		ASTBuilder:  i.ASTBuilder,
+		Id:          node.Id,
+		NodeType:    ast_pb.NodeType_BLOCK,
+		Src:         i.src(node.Src, parentId),
+		Implemented: true,
+		Statements:  i.statements(node.Statements, node.Id),
+	}
+}
+
+// statements converts the statements of a block.
+func (i *solcImporter) statements(nodes []*solcNode, parentId int64) []Node[NodeType] {
+	toReturn := make([]Node[NodeType], 0, len(nodes))
+	for _, node := range nodes {
+		if statement := i.statement(node, parentId); statement != nil {
+			toReturn = append(toReturn, statement)
+		}
+	}
+	return toReturn
+}
+
+// statement converts a statement node. Expression statements are flattened into their expression,
+// the same way the parser represents them.
+func (i *solcImporter) statement(node *solcNode, parentId int64) Node[NodeType] {
+	if node == nil {
+		return nil
+	}
+
+	src := i.src(node.Src, parentId)
+
+	switch node.NodeType {
+	case "Block":
+		return i.body(node, parentId, node.Src)
+	case "UncheckedBlock":
+		return &UncheckedBlock{
+			ASTBuilder: i.ASTBuilder,
+			Id:         node.Id,
+			NodeType:   ast_pb.NodeType_UNCHECKED_BLOCK,
+			Src:        src,
+			Statements: i.statements(node.Statements, node.Id),
+		}
+	case "ExpressionStatement":
+		return i.expression(node.Expression, parentId)
+	case "VariableDeclarationStatement":
+		toReturn := &VariableDeclaration{
+			ASTBuilder:   i.ASTBuilder,
+			Id:           node.Id,
+			NodeType:     ast_pb.NodeType_VARIABLE_DECLARATION,
+			Src:          src,
+			Assignments:  append(make([]int64, 0), node.Assignments...),
+			Declarations: make([]*Declaration, 0, len(node.Declarations)),
+			InitialValue: i.expression(node.InitialValue, node.Id),
+		}
+		for _, declaration := range node.Declarations {
+			// Components skipped in tuple destructuring are null.
+			if declaration == nil {
+				continue
+			}
+			toReturn.Declarations = append(toReturn.Declarations, &Declaration{
+				ASTBuilder:      i.ASTBuilder,
+				Id:              declaration.Id,
+				StateMutability: ast_pb.Mutability_MUTABLE,
+				Name:            declaration.Name,
+				NodeType:        ast_pb.NodeType_VARIABLE_DECLARATION,
+				Scope:           declaration.Scope,
+				Src:             i.src(declaration.Src, node.Id),
+				NameLocation:    i.src(declaration.NameLocation, declaration.Id),
+				StorageLocation: solcStorageLocation(declaration.StorageLocation),
+				TypeName:        i.typeName(declaration.TypeName, declaration.Id),
+				Visibility:      ast_pb.Visibility_INTERNAL,
+			})
+		}
+		return toReturn
+	case "IfStatement":
+		// The parser only keeps the true branch of if statements, so does the importer.
+		return &IfStatement{
+			ASTBuilder: i.ASTBuilder,
+			Id:         node.Id,
+			NodeType:   ast_pb.NodeType_IF_STATEMENT,
+			Src:        src,
+			Condition:  i.expression(node.Condition, node.Id),
+			Body:       i.body(node.TrueBody, node.Id, node.Src),
+		}
+	case "ForStatement":
+		return &ForStatement{
+			ASTBuilder:  i.ASTBuilder,
+			Id:          node.Id,
+			NodeType:    ast_pb.NodeType_FOR_STATEMENT,
+			Src:         src,
+			Initialiser: i.statement(node.InitializationExpression, node.Id),
+			Condition:   i.expression(node.Condition, node.Id),
+			Closure:     i.statement(node.LoopExpression, node.Id),
+			Body:        i.body(node.Body, node.Id, node.Src),
+		}
+	case "WhileStatement":
+		return &WhileStatement{
+			ASTBuilder: i.ASTBuilder,
+			Id:         node.Id,
+			NodeType:   ast_pb.NodeType_WHILE_STATEMENT,
+			Kind:       ast_pb.NodeType_WHILE,
+			Src:        src,
+			Condition:  i.expression(node.Condition, node.Id),
+			Body:       i.body(node.Body, node.Id, node.Src),
+		}
+	case "DoWhileStatement":
+		return &DoWhileStatement{
+			ASTBuilder: i.ASTBuilder,
+			Id:         node.Id,
+			NodeType:   ast_pb.NodeType_DO_WHILE_STATEMENT,
+			Src:        src,
+			Condition:  i.expression(node.Condition, node.Id),
+			Body:       i.body(node.Body, node.Id, node.Src),
+		}
+	case "Return":
+		return &ReturnStatement{
+			ASTBuilder:               i.ASTBuilder,
+			Id:                       node.Id,
+			NodeType:                 ast_pb.NodeType_RETURN_STATEMENT,
+			Src:                      src,
+			FunctionReturnParameters: node.FunctionReturnParameters,
+			Expression:               i.expression(node.Expression, node.Id),
+		}
+	case "EmitStatement":
+		toReturn := &Emit{
+			ASTBuilder: i.ASTBuilder,
+			Id:         node.Id,
+			NodeType:   ast_pb.NodeType_EMIT_STATEMENT,
+			Src:        src,
+			Arguments:  make([]Node[NodeType], 0),
+		}
+		if node.EventCall != nil {
+			toReturn.Expression = i.expression(node.EventCall.Expression, node.Id)
+			toReturn.Arguments = i.expressions(node.EventCall.Arguments, node.Id)
+		}
+		return toReturn
+	case "RevertStatement":
+		toReturn := &RevertStatement{
+			ASTBuilder: i.ASTBuilder,
+			Id:         node.Id,
+			NodeType:   ast_pb.NodeType_REVERT_STATEMENT,
+			Src:        src,
+			Arguments:  make([]Node[NodeType], 0),
+		}
+		if node.ErrorCall != nil {
+			toReturn.Expression = i.expression(node.ErrorCall.Expression, node.Id)
+			toReturn.Arguments = i.expressions(node.ErrorCall.Arguments, node.Id)
+			toReturn.Names = node.ErrorCall.Names
+		}
+		return toReturn
+	case "TryStatement":
+		return i.tryStatement(node, parentId)
+	case "Break":
+		return &BreakStatement{
+			ASTBuilder: i.ASTBuilder,
+			Id:         node.Id,
+			NodeType:   ast_pb.NodeType_BREAK,
+			Src:        src,
+		}
+	case "Continue":
+		return &ContinueStatement{
+			ASTBuilder: i.ASTBuilder,
+			Id:         node.Id,
+			NodeType:   ast_pb.NodeType_CONTINUE,
+			Src:        src,
+		}
+	case "PlaceholderStatement":
+		return &PrimaryExpression{
+			ASTBuilder: i.ASTBuilder,
+			Id:         node.Id,
+			NodeType:   ast_pb.NodeType_PLACEHOLDER_STATEMENT,
+			Src:        src,
+			Name:       "_",
+			TypeDescription: &TypeDescription{
+				TypeIdentifier: "t_placeholder_literal",
+				TypeString:     "t_placeholder",
+			},
+		}
+	case "InlineAssembly":
+		return i.assembly(node, parentId)
+	}
+
+	return i.expression(node, parentId)
+}
+
+// tryStatement converts a TryStatement node. The first clause is the success clause, holding the
+// body and the returned parameters, while the remaining ones are catch clauses.
+func (i *solcImporter) tryStatement(node *solcNode, parentId int64) *TryStatement {
+	toReturn := &TryStatement{
+		ASTBuilder:  i.ASTBuilder,
+		Id:          node.Id,
+		NodeType:    ast_pb.NodeType_TRY_STATEMENT,
+		Src:         i.src(node.Src, parentId),
+		Kind:        ast_pb.NodeType_TRY,
+		Expression:  i.expression(node.ExternalCall, node.Id),
+		Clauses:     make([]Node[NodeType], 0),
+		Implemented: true,
+	}
+
+	for index, clause := range node.Clauses {
+		if index == 0 {
+			toReturn.Body = i.body(clause.Block, node.Id, clause.Src)
+			if clause.Parameters.List != nil {
+				toReturn.Returns = true
+				toReturn.ReturnParameters = i.parameterList(clause.Parameters.List, node.Id)
+			}
+			continue
+		}
+
+		toReturn.Clauses = append(toReturn.Clauses, &CatchStatement{
+			ASTBuilder: i.ASTBuilder,
+			Id:         clause.Id,
+			Name:       clause.ErrorName,
+			NodeType:   ast_pb.NodeType_TRY_CATCH_CLAUSE,
+			Kind:       ast_pb.NodeType_CATCH,
+			Src:        i.src(clause.Src, node.Id),
+			Body:       i.body(clause.Block, clause.Id, clause.Src),
+			Parameters: i.parameterList(clause.Parameters.List, clause.Id),
+		})
+	}
+
+	if toReturn.Body == nil {
+		toReturn.Body = i.body(nil, node.Id, node.Src)
+	}
+
+	return toReturn
+}
+
+// expressions converts a list of expressions, skipping the ones that cannot be converted.
+func (i *solcImporter) expressions(nodes []*solcNode, parentId int64) []Node[NodeType] {
+	toReturn := make([]Node[NodeType], 0, len(nodes))
+	for _, node := range nodes {
+		if expr := i.expression(node, parentId); expr != nil {
+			toReturn = append(toReturn, expr)
+		}
+	}
+	return toReturn
+}
+
+// expression converts an expression node. Binary operations are mapped onto the node types the
+// parser produces for the operator.
+func (i *solcImporter) expression(node *solcNode, parentId int64) Node[NodeType] {
+	if node == nil {
+		return nil
+	}
+
+	src := i.src(node.Src, parentId)
+	typeDescription := i.typeDescription(node.TypeDescriptions)
+
+	switch node.NodeType {
+	case "Identifier":
+		return &PrimaryExpression{
+			ASTBuilder:             i.ASTBuilder,
+			Id:                     node.Id,
+			NodeType:               ast_pb.NodeType_IDENTIFIER,
+			Src:                    src,
+			Name:                   node.Name,
+			TypeDescription:        typeDescription,
+			OverloadedDeclarations: append(make([]int64, 0), node.OverloadedDeclarations...),
+			ReferencedDeclaration:  node.ReferencedDeclaration,
+		}
+	case "Literal":
+		toReturn := &PrimaryExpression{
+			ASTBuilder:             i.ASTBuilder,
+			Id:                     node.Id,
+			NodeType:               ast_pb.NodeType_LITERAL,
+			Kind:                   solcLiteralKind(node.Kind),
+			Value:                  node.Value,
+			HexValue:               node.HexValue,
+			Src:                    src,
+			TypeDescription:        typeDescription,
+			OverloadedDeclarations: make([]int64, 0),
+			Pure:                   true,
+		}
+		return toReturn
+	case "ElementaryTypeNameExpression":
+		toReturn := &PrimaryExpression{
+			ASTBuilder:             i.ASTBuilder,
+			Id:                     node.Id,
+			NodeType:               ast_pb.NodeType_IDENTIFIER,
+			Src:                    src,
+			TypeName:               i.typeName(node.TypeName, node.Id),
+			TypeDescription:        typeDescription,
+			OverloadedDeclarations: make([]int64, 0),
+			Pure:                   node.IsPure,
+		}
+		if toReturn.TypeName != nil {
+			toReturn.Name = toReturn.TypeName.Name
+		}
+		return toReturn
+	case "MemberAccess":
+		toReturn := &MemberAccessExpression{
+			ASTBuilder:            i.ASTBuilder,
+			Id:                    node.Id,
+			Constant:              node.IsConstant,
+			LValue:                node.IsLValue,
+			Pure:                  node.IsPure,
+			LValueRequested:       node.LValueRequested,
+			NodeType:              ast_pb.NodeType_MEMBER_ACCESS,
+			Src:                   src,
+			Expression:            i.expression(node.Expression, node.Id),
+			MemberName:            node.MemberName,
+			ArgumentTypes:         make([]*TypeDescription, 0),
+			ReferencedDeclaration: node.ReferencedDeclaration,
+			TypeDescription:       typeDescription,
+		}
+		if node.MemberLocation != "" {
+			toReturn.MemberLocation = i.src(node.MemberLocation, node.Id)
+		}
+		return toReturn
+	case "IndexAccess":
+		toReturn := &IndexAccess{
+			ASTBuilder:       i.ASTBuilder,
+			Id:               node.Id,
+			NodeType:         ast_pb.NodeType_INDEX_ACCESS,
+			Src:              src,
+			BaseExpression:   i.expression(node.BaseExpression, node.Id),
+			IndexExpression:  i.expression(node.IndexExpression, node.Id),
+			TypeDescriptions: make([]*TypeDescription, 0),
+			TypeDescription:  typeDescription,
+		}
+		for _, expr := range []Node[NodeType]{toReturn.BaseExpression, toReturn.IndexExpression} {
+			if expr != nil {
+				toReturn.TypeDescriptions = append(toReturn.TypeDescriptions, expr.GetTypeDescription())
+			}
+		}
+		return toReturn
+	case "IndexRangeAccess":
+		return &IndexRange{
+			ASTBuilder:       i.ASTBuilder,
+			Id:               node.Id,
+			NodeType:         ast_pb.NodeType_INDEX_RANGE_ACCESS,
+			Src:              src,
+			LeftExpression:   i.expression(node.StartExpression, node.Id),
+			RightExpression:  i.expression(node.EndExpression, node.Id),
+			TypeDescriptions: []*TypeDescription{typeDescription},
+		}
+	case "FunctionCall":
+		return i.functionCall(node, parentId)
+	case "FunctionCallOptions":
+		return &FunctionCallOption{
+			ASTBuilder:      i.ASTBuilder,
+			Id:              node.Id,
+			NodeType:        ast_pb.NodeType_FUNCTION_CALL_OPTION,
+			Kind:            ast_pb.NodeType_FUNCTION_CALL_OPTION,
+			Src:             src,
+			Expression:      i.expression(node.Expression, node.Id),
+			Names:           node.Names,
+			Options:         i.expressions(node.Options, node.Id),
+			TypeDescription: typeDescription,
+		}
+	case "Assignment":
+		return &Assignment{
+			ASTBuilder:      i.ASTBuilder,
+			Id:              node.Id,
+			NodeType:        ast_pb.NodeType_ASSIGNMENT,
+			Src:             src,
+			Operator:        solcOperator(node.Operator),
+			LeftExpression:  i.expression(node.LeftHandSide, node.Id),
+			RightExpression: i.expression(node.RightHandSide, node.Id),
+			TypeDescription: typeDescription,
+		}
+	case "BinaryOperation":
+		return i.binaryOperation(node, parentId)
+	case "UnaryOperation":
+		if node.Prefix {
+			operator := solcOperator(node.Operator)
+			if node.Operator == "-" {
+				operator = ast_pb.Operator_SUBTRACT
+			}
+
+			return &UnaryPrefix{
+				ASTBuilder:      i.ASTBuilder,
+				Id:              node.Id,
+				NodeType:        ast_pb.NodeType_UNARY_OPERATION,
+				Kind:            ast_pb.NodeType_KIND_UNARY_PREFIX,
+				Src:             src,
+				Operator:        operator,
+				Prefix:          true,
+				Constant:        node.IsConstant,
+				LValue:          node.IsLValue,
+				Pure:            node.IsPure,
+				LValueRequested: node.LValueRequested,
+				Expression:      i.expression(node.SubExpression, node.Id),
+				TypeDescription: typeDescription,
+			}
+		}
+		return &UnarySuffix{
+			ASTBuilder:      i.ASTBuilder,
+			Id:              node.Id,
+			NodeType:        ast_pb.NodeType_UNARY_OPERATION,
+			Kind:            ast_pb.NodeType_KIND_UNARY_SUFFIX,
+			Src:             src,
+			Operator:        solcOperator(node.Operator),
+			Expression:      i.expression(node.SubExpression, node.Id),
+			TypeDescription: typeDescription,
+			Constant:        node.IsConstant,
+			LValue:          node.IsLValue,
+			Pure:            node.IsPure,
+			LValueRequested: node.LValueRequested,
+		}
+	case "Conditional":
+		expressions := []Node[NodeType]{
+			i.expression(node.Condition, node.Id),
+			i.expression(node.TrueExpression, node.Id),
+			i.expression(node.FalseExpression, node.Id),
+		}
+		typeDescriptions := make([]*TypeDescription, 0, len(expressions))
+		for _, expr := range expressions {
+			if expr != nil {
+				typeDescriptions = append(typeDescriptions, expr.GetTypeDescription())
+			}
+		}
+		return &Conditional{
+			ASTBuilder:       i.ASTBuilder,
+			Id:               node.Id,
+			NodeType:         ast_pb.NodeType_CONDITIONAL_EXPRESSION,
+			Src:              src,
+			Expressions:      expressions,
+			TypeDescriptions: typeDescriptions,
+			TypeDescription:  typeDescription,
+		}
+	case "TupleExpression":
+		components := i.expressions(node.Components, node.Id)
+		if node.IsInlineArray {
+			typeDescriptions := make([]*TypeDescription, 0, len(components))
+			for _, component := range components {
+				typeDescriptions = append(typeDescriptions, component.GetTypeDescription())
+			}
+			return &InlineArray{
+				ASTBuilder:       i.ASTBuilder,
+				Id:               node.Id,
+				NodeType:         ast_pb.NodeType_INLINE_ARRAY,
+				Src:              src,
+				TypeDescriptions: typeDescriptions,
+				Expressions:      components,
+				Empty:            len(components) == 0,
+				TypeDescription:  typeDescription,
+			}
+		}
+		return &TupleExpression{
+			ASTBuilder:      i.ASTBuilder,
+			Id:              node.Id,
+			NodeType:        ast_pb.NodeType_TUPLE_EXPRESSION,
+			Src:             src,
+			Constant:        node.IsConstant,
+			Pure:            node.IsPure,
+			Components:      components,
+			TypeDescription: typeDescription,
+		}
+	case "NewExpression":
+		return &NewExpr{
+			ASTBuilder:      i.ASTBuilder,
+			Id:              node.Id,
+			NodeType:        ast_pb.NodeType_NEW_EXPRESSION,
+			Src:             src,
+			ArgumentTypes:   make([]*TypeDescription, 0),
+			TypeName:        i.typeName(node.TypeName, node.Id),
+			TypeDescription: typeDescription,
+		}
+	}
+
+	return nil
+}
+
+// functionCall converts a FunctionCall node. Conversions to address payable become a
+// PayableConversion, as they do when parsed.
+func (i *solcImporter) functionCall(node *solcNode, parentId int64) Node[NodeType] {
+	src := i.src(node.Src, parentId)
+	arguments := i.expressions(node.Arguments, node.Id)
+	argumentTypes := make([]*TypeDescription, 0, len(arguments))
+	for _, argument := range arguments {
+		argumentTypes = append(argumentTypes, argument.GetTypeDescription())
+	}
+
+	if callee := node.Expression; callee != nil && node.Kind == "typeConversion" &&
+		callee.NodeType == "ElementaryTypeNameExpression" && callee.TypeName != nil &&
+		callee.TypeName.StateMutability == "payable" {
+		return &PayableConversion{
+			ASTBuilder:      i.ASTBuilder,
+			Id:              node.Id,
+			NodeType:        ast_pb.NodeType_PAYABLE_CONVERSION,
+			Src:             src,
+			Arguments:       arguments,
+			ArgumentTypes:   argumentTypes,
+			TypeDescription: i.typeDescription(node.TypeDescriptions),
+			Payable:         true,
+		}
+	}
+
+	toReturn := &FunctionCall{
+		ASTBuilder:      i.ASTBuilder,
+		Id:              node.Id,
+		NodeType:        ast_pb.NodeType_FUNCTION_CALL,
+		Kind:            ast_pb.NodeType_FUNCTION_CALL,
+		Src:             src,
+		ArgumentTypes:   argumentTypes,
+		Arguments:       arguments,
+		Expression:      i.expression(node.Expression, node.Id),
+		TypeDescription: i.typeDescription(node.TypeDescriptions),
+	}
+
+	if node.Expression != nil {
+		toReturn.ReferencedDeclaration = node.Expression.ReferencedDeclaration
+	}
+
+	return toReturn
+}
+
+// binaryOperation converts a BinaryOperation node into the node type the parser uses for its operator.
+func (i *solcImporter) binaryOperation(node *solcNode, parentId int64) Node[NodeType] {
+	src := i.src(node.Src, parentId)
+	left := i.expression(node.LeftExpression, node.Id)
+	right := i.expression(node.RightExpression, node.Id)
+	typeDescription := i.typeDescription(node.TypeDescriptions)
+
+	expressions := []Node[NodeType]{left, right}
+	typeDescriptions := make([]*TypeDescription, 0, len(expressions))
+	for _, expr := range expressions {
+		if expr != nil {
+			typeDescriptions = append(typeDescriptions, expr.GetTypeDescription())
+		}
+	}
+
+	switch node.Operator {
+	case "&&":
+		return &AndOperation{
+			ASTBuilder:       i.ASTBuilder,
+			Id:               node.Id,
+			NodeType:         ast_pb.NodeType_AND_OPERATION,
+			Src:              src,
+			Expressions:      expressions,
+			TypeDescriptions: typeDescriptions,
+		}
+	case "&":
+		return &BitAndOperation{
+			ASTBuilder:       i.ASTBuilder,
+			Id:               node.Id,
+			NodeType:         ast_pb.NodeType_BIT_AND_OPERATION,
+			Src:              src,
+			Expressions:      expressions,
+			TypeDescriptions: typeDescriptions,
+		}
+	case "|":
+		return &BitOrOperation{
+			ASTBuilder:       i.ASTBuilder,
+			Id:               node.Id,
+			NodeType:         ast_pb.NodeType_BIT_OR_OPERATION,
+			Src:              src,
+			Expressions:      expressions,
+			TypeDescriptions: typeDescriptions,
+		}
+	case "^":
+		return &BitXorOperation{
+			ASTBuilder:       i.ASTBuilder,
+			Id:               node.Id,
+			NodeType:         ast_pb.NodeType_BIT_XOR_OPERATION,
+			Src:              src,
+			Expressions:      expressions,
+			TypeDescriptions: typeDescriptions,
+			TypeDescription:  typeDescription,
+		}
+	case "<<", ">>":
+		operator := ast_pb.NodeType_SHIFT_LEFT_OPERATION
+		if node.Operator == ">>" {
+			operator = ast_pb.NodeType_SHIFT_RIGHT_OPERATION
+		}
+		return &ShiftOperation{
+			ASTBuilder:       i.ASTBuilder,
+			Id:               node.Id,
+			NodeType:         ast_pb.NodeType_SHIFT_OPERATION,
+			Src:              src,
+			Operator:         operator,
+			Expressions:      expressions,
+			TypeDescriptions: typeDescriptions,
+			TypeDescription:  typeDescription,
+		}
+	case "**":
+		return &ExprOperation{
+			ASTBuilder:       i.ASTBuilder,
+			Id:               node.Id,
+			NodeType:         ast_pb.NodeType_EXPRESSION_OPERATION,
+			Src:              src,
+			LeftExpression:   left,
+			RightExpression:  right,
+			TypeDescriptions: typeDescriptions,
+		}
+	}
+
+	return &BinaryOperation{
+		ASTBuilder:      i.ASTBuilder,
+		Id:              node.Id,
+		Constant:        node.IsConstant,
+		Pure:            node.IsPure,
+		NodeType:        ast_pb.NodeType_BINARY_OPERATION,
+		Src:             src,
+		Operator:        solcOperator(node.Operator),
+		LeftExpression:  left,
+		RightExpression: right,
+		TypeDescription: typeDescription,
+	}
+}
+
+// assembly converts an InlineAssembly node and its Yul AST. Yul nodes have no ids in solc's output,
+// so they get generated ones.
+func (i *solcImporter) assembly(node *solcNode, parentId int64) *Yul {
+	toReturn := &Yul{
+		ASTBuilder: i.ASTBuilder,
+		Id:         node.Id,
+		NodeType:   ast_pb.NodeType_ASSEMBLY_STATEMENT,
+		Src:        i.src(node.Src, parentId),
+	}
+
+	toReturn.Body = &BodyNode{
+		ASTBuilder: i.ASTBuilder,
+		Id:         i.GetNextID(),
+		NodeType:   ast_pb.NodeType_YUL_BLOCK,
+		Src:        i.src(node.Src, node.Id),
+		Statements: make([]Node[NodeType], 0),
+	}
+
+	if node.YulAST != nil {
+		for _, statement := range node.YulAST.Statements {
+			wrapper := &YulStatement{
+				ASTBuilder: i.ASTBuilder,
+				Id:         i.GetNextID(),
+				NodeType:   ast_pb.NodeType_YUL_STATEMENT,
+				Src:        i.src(statement.Src, toReturn.Body.GetId()),
+				Statements: make([]Node[NodeType], 0, 1),
+			}
+			if yul := i.yul(statement, wrapper.GetId()); yul != nil {
+				wrapper.Statements = append(wrapper.Statements, yul)
+			}
+			toReturn.Body.Statements = append(toReturn.Body.Statements, wrapper)
+		}
+	}
+
+	return toReturn
+}
+
+// yul converts a Yul statement or expression node.
+func (i *solcImporter) yul(node *solcYulNode, parentId int64) Node[NodeType] {
+	if node == nil {
+		return nil
+	}
+
+	id := i.GetNextID()
+	src := i.src(node.Src, parentId)
+
+	switch node.NodeType {
+	case "YulBlock":
+		toReturn := &YulBlockStatement{
+			ASTBuilder: i.ASTBuilder,
+			Id:         id,
+			NodeType:   ast_pb.NodeType_YUL_BLOCK,
+			Src:        src,
+			Statements: make([]Node[NodeType], 0, len(node.Statements)),
+		}
+		for _, statement := range node.Statements {
+			if yul := i.yul(statement, id); yul != nil {
+				toReturn.Statements = append(toReturn.Statements, yul)
+			}
+		}
+		return toReturn
+	case "YulExpressionStatement":
+		return i.yul(node.Expression, parentId)
+	case "YulVariableDeclaration":
+		return &YulVariable{
+			ASTBuilder: i.ASTBuilder,
+			Id:         id,
+			NodeType:   ast_pb.NodeType_YUL_VARIABLE_DECLARATION,
+			Src:        src,
+			Let:        true,
+			Value:      i.yulValue(node.Value, id),
+			Variables:  i.yulIdentifiers(node.Variables, id),
+		}
+	case "YulAssignment":
+		return &YulAssignment{
+			ASTBuilder:    i.ASTBuilder,
+			Id:            id,
+			NodeType:      ast_pb.NodeType_YUL_ASSIGNMENT,
+			Src:           src,
+			VariableNames: i.yulIdentifiers(node.VariableNames, id),
+			Value:         i.yulValue(node.Value, id),
+		}
+	case "YulFunctionCall":
+		toReturn := &YulFunctionCallStatement{
+			ASTBuilder: i.ASTBuilder,
+			Id:         id,
+			NodeType:   ast_pb.NodeType_YUL_FUNCTION_CALL,
+			Src:        src,
+			Arguments:  make([]Node[NodeType], 0, len(node.Arguments)),
+		}
+		if node.FunctionName != nil {
+			toReturn.FunctionName = i.yulIdentifiers([]*solcYulNode{node.FunctionName}, id)[0]
+		}
+		for _, argument := range node.Arguments {
+			if yul := i.yul(argument, id); yul != nil {
+				toReturn.Arguments = append(toReturn.Arguments, yul)
+			}
+		}
+		return toReturn
+	case "YulIdentifier", "YulTypedName":
+		return i.yulIdentifiers([]*solcYulNode{node}, parentId)[0]
+	case "YulLiteral":
+		var value string
+		_ = json.Unmarshal(node.Value, &value)
+		return &YulLiteralStatement{
+			ASTBuilder: i.ASTBuilder,
+			Id:         id,
+			NodeType:   ast_pb.NodeType_YUL_LITERAL,
+			Kind:       solcYulLiteralKind(node.Kind, value),
+			Src:        src,
+			Value:      value,
+			HexValue:   node.HexValue,
+		}
+	case "YulIf":
+		return &YulIfStatement{
+			ASTBuilder: i.ASTBuilder,
+			Id:         id,
+			NodeType:   ast_pb.NodeType_YUL_IF,
+			Src:        src,
+			Condition:  i.yul(node.Condition, id),
+			Body:       i.yul(node.Body, id),
+		}
+	case "YulForLoop":
+		return &YulForStatement{
+			ASTBuilder: i.ASTBuilder,
+			Id:         id,
+			NodeType:   ast_pb.NodeType_YUL_FOR,
+			Src:        src,
+			Pre:        i.yul(node.Pre, id),
+			Post:       i.yul(node.Post, id),
+			Condition:  i.yul(node.Condition, id),
+			Body:       i.yul(node.Body, id),
+		}
+	case "YulSwitch":
+		toReturn := &YulSwitchStatement{
+			ASTBuilder: i.ASTBuilder,
+			Id:         id,
+			NodeType:   ast_pb.NodeType_YUL_SWITCH,
+			Src:        src,
+			Expression: i.yul(node.Expression, id),
+			Cases:      make([]Node[NodeType], 0, len(node.Cases)),
+		}
+		for _, switchCase := range node.Cases {
+			caseId := i.GetNextID()
+			caseStatement := &YulSwitchCaseStatement{
+				ASTBuilder: i.ASTBuilder,
+				Id:         caseId,
+				NodeType:   ast_pb.NodeType_YUL_SWITCH_CASE,
+				Src:        i.src(switchCase.Src, id),
+				Body:       i.yul(switchCase.Body, caseId),
+			}
+
+			// The default case has the "default" string as its value instead of a literal.
+			if len(switchCase.Value) > 0 && switchCase.Value[0] == '{' {
+				caseStatement.Case = i.yulValue(switchCase.Value, caseId)
+				toReturn.Cases = append(toReturn.Cases, caseStatement)
+			} else {
+				toReturn.Default = caseStatement
+			}
+		}
+		return toReturn
+	case "YulFunctionDefinition":
+		return &YulFunctionDefinition{
+			ASTBuilder:       i.ASTBuilder,
+			Id:               id,
+			NodeType:         ast_pb.NodeType_YUL_FUNCTION_DEFINITION,
+			Src:              src,
+			Arguments:        i.yulIdentifiers(node.Parameters, id),
+			Body:             i.yul(node.Body, id),
+			ReturnParameters: i.yulIdentifiers(node.ReturnVariables, id),
+		}
+	case "YulLeave":
+		return &YulLeaveStatement{ASTBuilder: i.ASTBuilder, Id: id, NodeType: ast_pb.NodeType_YUL_LEAVE, Src: src}
+	case "YulBreak":
+		return &YulBreakStatement{ASTBuilder: i.ASTBuilder, Id: id, NodeType: ast_pb.NodeType_YUL_BREAK, Src: src}
+	case "YulContinue":
+		return &YulContinueStatement{ASTBuilder: i.ASTBuilder, Id: id, NodeType: ast_pb.NodeType_YUL_CONTINUE, Src: src}
+	}
+
+	return nil
+}
+
+// yulValue converts the raw value of a Yul variable declaration, assignment or switch case.
+func (i *solcImporter) yulValue(raw json.RawMessage, parentId int64) Node[NodeType] {
+	if len(raw) == 0 || raw[0] != '{' {
+		return nil
+	}
+
+	var node *solcYulNode
+	if err := json.Unmarshal(raw, &node); err != nil {
+		return nil
+	}
+
+	return i.yul(node, parentId)
+}
+
+// yulIdentifiers converts Yul identifiers and typed names.
+func (i *solcImporter) yulIdentifiers(nodes []*solcYulNode, parentId int64) []*YulIdentifier {
+	toReturn := make([]*YulIdentifier, 0, len(nodes))
+	for _, node := range nodes {
+		toReturn = append(toReturn, &YulIdentifier{
+			ASTBuilder: i.ASTBuilder,
+			Id:         i.GetNextID(),
+			NodeType:   ast_pb.NodeType_YUL_IDENTIFIER,
+			Src:        i.src(node.Src, parentId),
+			Name:       node.Name,
+		})
+	}
+	return toReturn
+}
+
+// documentation records the NatSpec documentation of the node as a comment. Older compilers output
+// documentation as plain strings without a location, which are skipped.
+func (i *solcImporter) documentation(node *solcNode) {
+	if len(node.Documentation) == 0 || node.Documentation[0] != '{' {
+		return
+	}
+
+	var documentation *solcNode
+	if err := json.Unmarshal(node.Documentation, &documentation); err != nil || documentation == nil {
+		return
+	}
+
+	comment := &Comment{
+		Id:       documentation.Id,
+		Src:      i.src(documentation.Src, node.Id),
+		NodeType: ast_pb.NodeType_COMMENT,
+		Text:     "///" + documentation.Text,
+	}
+
+	if strings.Contains(documentation.Text, "\n") {
+		comment.NodeType = ast_pb.NodeType_COMMENT_MULTILINE
+		comment.Text = "/**" + documentation.Text + "*/"
+	}
+
+	i.comments = append(i.comments, comment)
+}
+
+// src converts a solc source location in the `start:length:sourceIndex` format.
+func (i *solcImporter) src(raw string, parentId int64) SrcNode {
+	parts := strings.Split(raw, ":")
+	if len(parts) < 2 {
+		return SrcNode{ParentIndex: parentId}
+	}
+
+	start, _ := strconv.ParseInt(parts[0], 10, 64)
+	length, _ := strconv.ParseInt(parts[1], 10, 64)
+
+	return SrcNode{
+		Start:       start,
+		End:         start + length - 1,
+		Length:      length,
+		ParentIndex: parentId,
+	}
+}
+
+// typeDescription converts solc type descriptions, which are missing on a few nodes.
+func (i *solcImporter) typeDescription(descriptions *solcTypeDescriptions) *TypeDescription {
+	if descriptions == nil {
+		return &TypeDescription{}
+	}
+
+	return &TypeDescription{
+		TypeIdentifier: descriptions.TypeIdentifier,
+		TypeString:     descriptions.TypeString,
+	}
+}
+
+// contractBaseContracts returns the base contracts of a contract, interface or library.
+func contractBaseContracts(contract Node[NodeType]) []*BaseContract {
+	if based, ok := contract.(interface{ GetBaseContracts() []*BaseContract }); ok {
+		return based.GetBaseContracts()
+	}
+	return make([]*BaseContract, 0)
+}
+
+// solcVisibility converts a solc visibility.
+func solcVisibility(visibility string) ast_pb.Visibility {
+	switch visibility {
+	case "public":
+		return ast_pb.Visibility_PUBLIC
+	case "private":
+		return ast_pb.Visibility_PRIVATE
+	case "external":
+		return ast_pb.Visibility_EXTERNAL
+	default:
+		return ast_pb.Visibility_INTERNAL
+	}
+}
+
+// solcFunctionMutability converts the state mutability of a function.
+func solcFunctionMutability(mutability string) ast_pb.Mutability {
+	switch mutability {
+	case "payable":
+		return ast_pb.Mutability_PAYABLE
+	case "view":
+		return ast_pb.Mutability_VIEW
+	case "pure":
+		return ast_pb.Mutability_PURE
+	default:
+		return ast_pb.Mutability_NONPAYABLE
+	}
+}
+
+// solcVariableMutability converts the mutability of a variable declaration.
+func solcVariableMutability(mutability string) ast_pb.Mutability {
+	if mutability == "immutable" {
+		return ast_pb.Mutability_IMMUTABLE
+	}
+	return ast_pb.Mutability_MUTABLE
+}
+
+// solcStorageLocation converts the data location of a variable declaration.
+func solcStorageLocation(location string) ast_pb.StorageLocation {
+	switch location {
+	case "memory":
+		return ast_pb.StorageLocation_MEMORY
+	case "storage":
+		return ast_pb.StorageLocation_STORAGE
+	case "calldata":
+		return ast_pb.StorageLocation_CALLDATA
+	default:
+		return ast_pb.StorageLocation_DEFAULT
+	}
+}
+
+// solcLiteralKind converts the kind of a literal.
+func solcLiteralKind(kind string) ast_pb.NodeType {
+	switch kind {
+	case "bool":
+		return ast_pb.NodeType_BOOLEAN
+	case "string":
+		return ast_pb.NodeType_STRING
+	case "hexString":
+		return ast_pb.NodeType_HEX_STRING
+	case "unicodeString":
+		return ast_pb.NodeType_UNICODE_STRING_LITERAL
+	default:
+		return ast_pb.NodeType_NUMBER
+	}
+}
+
+// solcYulLiteralKind converts the kind of a Yul literal.
+func solcYulLiteralKind(kind string, value string) ast_pb.NodeType {
+	switch kind {
+	case "bool":
+		return ast_pb.NodeType_BOOLEAN
+	case "string":
+		return ast_pb.NodeType_STRING
+	}
+
+	if strings.HasPrefix(value, "0x") {
+		return ast_pb.NodeType_HEX_NUMBER
+	}
+	return ast_pb.NodeType_DECIMAL_NUMBER
+}
+
+// solcOperator converts the operator of an assignment, binary or unary operation.
+func solcOperator(operator string) ast_pb.Operator {
+	switch operator {
+	case "+":
+		return ast_pb.Operator_ADDITION
+	case "-":
+		return ast_pb.Operator_SUBTRACTION
+	case "*":
+		return ast_pb.Operator_MULTIPLICATION
+	case "/":
+		return ast_pb.Operator_DIVISION
+	case "%":
+		return ast_pb.Operator_MODULO
+	case "**":
+		return ast_pb.Operator_EXPONENTIATION
+	case ">":
+		return ast_pb.Operator_GREATER_THAN
+	case ">=":
+		return ast_pb.Operator_GREATER_THAN_OR_EQUAL
+	case "<":
+		return ast_pb.Operator_LESS_THAN
+	case "<=":
+		return ast_pb.Operator_LESS_THAN_OR_EQUAL
+	case "==", "=":
+		return ast_pb.Operator_EQUAL
+	case "!=":
+		return ast_pb.Operator_NOT_EQUAL
+	case "||":
+		return ast_pb.Operator_OR
+	case "+=":
+		return ast_pb.Operator_PLUS_EQUAL
+	case "-=":
+		return ast_pb.Operator_MINUS_EQUAL
+	case "*=":
+		return ast_pb.Operator_MUL_EQUAL
+	case "/=":
+		return ast_pb.Operator_DIV_EQUAL
+	case "%=":
+		return ast_pb.Operator_MOD_EQUAL
+	case "&=":
+		return ast_pb.Operator_AND_EQUAL
+	case "|=":
+		return ast_pb.Operator_OR_EQUAL
+	case "^=":
+		return ast_pb.Operator_XOR_EQUAL
+	case "<<=":
+		return ast_pb.Operator_SHIFT_LEFT_EQUAL
+	case ">>=":
+		return ast_pb.Operator_SHIFT_RIGHT_EQUAL
+	case "++":
+		return ast_pb.Operator_INCREMENT
+	case "--":
+		return ast_pb.Operator_DECREMENT
+	case "!":
+		return ast_pb.Operator_NOT
+	case "~":
+		return ast_pb.Operator_BIT_NOT
+	}
+
+	return ast_pb.Operator_O_DEFAULT
+}
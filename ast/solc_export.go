@@ -0,0 +1,1316 @@
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-json"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+)
+
+// solcOperators maps operators onto the symbols solc uses for them. Assignments use "=" for
+// Operator_EQUAL, see solcOperatorSymbol.
+var solcOperators = map[ast_pb.Operator]string{
+	ast_pb.Operator_ADDITION:              "+",
+	ast_pb.Operator_SUBTRACTION:           "-",
+	ast_pb.Operator_SUBTRACT:              "-",
+	ast_pb.Operator_MULTIPLICATION:        "*",
+	ast_pb.Operator_DIVISION:              "/",
+	ast_pb.Operator_MODULO:                "%",
+	ast_pb.Operator_EXPONENTIATION:        "**",
+	ast_pb.Operator_GREATER_THAN:          ">",
+	ast_pb.Operator_GREATER_THAN_OR_EQUAL: ">=",
+	ast_pb.Operator_LESS_THAN:             "<",
+	ast_pb.Operator_LESS_THAN_OR_EQUAL:    "<=",
+	ast_pb.Operator_EQUAL:                 "==",
+	ast_pb.Operator_NOT_EQUAL:             "!=",
+	ast_pb.Operator_OR:                    "||",
+	ast_pb.Operator_BIT_AND:               "&",
+	ast_pb.Operator_PLUS_EQUAL:            "+=",
+	ast_pb.Operator_MINUS_EQUAL:           "-=",
+	ast_pb.Operator_MUL_EQUAL:             "*=",
+	ast_pb.Operator_DIV_EQUAL:             "/=",
+	ast_pb.Operator_MOD_EQUAL:             "%=",
+	ast_pb.Operator_AND_EQUAL:             "&=",
+	ast_pb.Operator_BIT_AND_EQUAL:         "&=",
+	ast_pb.Operator_OR_EQUAL:              "|=",
+	ast_pb.Operator_BIT_OR_EQUAL:          "|=",
+	ast_pb.Operator_XOR_EQUAL:             "^=",
+	ast_pb.Operator_BIT_XOR_EQUAL:         "^=",
+	ast_pb.Operator_SHIFT_LEFT_EQUAL:      "<<=",
+	ast_pb.Operator_SHIFT_RIGHT_EQUAL:     ">>=",
+	ast_pb.Operator_POW_EQUAL:             "**=",
+	ast_pb.Operator_INCREMENT:             "++",
+	ast_pb.Operator_DECREMENT:             "--",
+	ast_pb.Operator_NOT:                   "!",
+	ast_pb.Operator_BIT_NOT:               "~",
+}
+
+// solcObject is a node of the solc compact AST JSON being exported.
+type solcObject map[string]any
+
+// solcExporter converts solgo AST nodes into solc compact AST nodes.
+type solcExporter struct {
+	fileIndex int64          // Index of the file being exported, used in source locations.
+	maxId     int64          // Largest node id exported so far.
+	ids       map[int64]bool // Node ids exported so far.
+	synthetic []solcObject   // Nodes solgo does not have, given ids once every other node is exported.
+}
+
+// ToSolcJSON exports the AST in the compact JSON format of the solc compiler, as standard JSON
+// output with the ast of every source file, so that tools consuming solc's AST can process it.
+//
+// The per contract source units are merged back into a SourceUnit per file and file level
+// definitions are placed in the file of the entry source unit. Node ids and referenced declarations
+// are kept, while nodes solc has and solgo does not, such as expression statements, get ids after
+// the largest one in use, as do nodes sharing an id with another node. Source locations are written
+// as `start:length:fileIndex`, with the offsets the AST was built with.
+func (r *RootNode) ToSolcJSON() ([]byte, error) {
+	if r == nil || len(r.SourceUnits) == 0 {
+		return nil, errors.New("root node has no source units to export")
+	}
+
+	type solcFile struct {
+		path  string
+		units []*SourceUnit[Node[ast_pb.SourceUnit]]
+	}
+
+	files := make([]*solcFile, 0)
+	filesByPath := make(map[string]*solcFile)
+	entryPath := ""
+	for _, unit := range r.SourceUnits {
+		path := unit.AbsolutePath
+		if path == "" {
+			path = unit.Name + ".sol"
+		}
+		if unit.Id == r.EntrySourceUnit {
+			entryPath = path
+		}
+
+		file, ok := filesByPath[path]
+		if !ok {
+			file = &solcFile{path: path}
+			filesByPath[path] = file
+			files = append(files, file)
+		}
+		file.units = append(file.units, unit)
+	}
+	if entryPath == "" {
+		entryPath = files[len(files)-1].path
+	}
+
+	exporter := &solcExporter{
+		ids:       make(map[int64]bool),
+		synthetic: make([]solcObject, 0),
+	}
+	sources := make(map[string]any, len(files))
+	for index, file := range files {
+		exporter.fileIndex = int64(index)
+
+		var globals []Node[NodeType]
+		if file.path == entryPath {
+			globals = r.Globals
+		}
+
+		sources[file.path] = solcObject{
+			"id":  index,
+			"ast": exporter.sourceUnit(file.path, file.units, globals),
+		}
+	}
+
+	for _, node := range exporter.synthetic {
+		exporter.maxId++
+		node["id"] = exporter.maxId
+	}
+
+	return json.Marshal(solcObject{"sources": sources})
+}
+
+// sourceUnit exports the source units of a file, together with the given file level definitions,
+// as a single SourceUnit. Directives shared by the source units are exported once.
+func (e *solcExporter) sourceUnit(path string, units []*SourceUnit[Node[ast_pb.SourceUnit]], globals []Node[NodeType]) solcObject {
+	toReturn := e.node(units[0].Id, "SourceUnit", units[0].Src)
+	toReturn["absolutePath"] = path
+	if units[0].License != "" {
+		toReturn["license"] = units[0].License
+	}
+
+	nodes := make([]any, 0)
+	exportedSymbols := make(map[string][]int64)
+	seen := make(map[int64]bool)
+
+	add := func(node Node[NodeType]) {
+		if node == nil || seen[node.GetId()] {
+			return
+		}
+		seen[node.GetId()] = true
+
+		if exported := e.definition(node); exported != nil {
+			if name, ok := exported["name"].(string); ok && name != "" {
+				exportedSymbols[name] = []int64{node.GetId()}
+			}
+			nodes = append(nodes, exported)
+		}
+	}
+
+	for _, unit := range units {
+		for _, node := range unit.Nodes {
+			add(node)
+		}
+	}
+	for _, global := range globals {
+		add(global)
+	}
+
+	toReturn["exportedSymbols"] = exportedSymbols
+	toReturn["nodes"] = nodes
+	return toReturn
+}
+
+// definition exports a directive or a node declared at file or contract level. Unsupported nodes
+// are skipped.
+func (e *solcExporter) definition(node Node[NodeType]) solcObject {
+	switch n := node.(type) {
+	case *Pragma:
+		toReturn := e.node(n.Id, "PragmaDirective", n.Src)
+		toReturn["literals"] = n.Literals
+		return toReturn
+	case *Import:
+		toReturn := e.node(n.Id, "ImportDirective", n.Src)
+		toReturn["absolutePath"] = n.AbsolutePath
+		toReturn["file"] = n.File
+		toReturn["scope"] = n.Scope
+		toReturn["sourceUnit"] = n.SourceUnit
+		toReturn["unitAlias"] = n.UnitAlias
+		if n.NameLocation != nil {
+			toReturn["nameLocation"] = e.src(*n.NameLocation)
+		}
+
+		aliases := make([]any, 0, len(n.UnitAliases))
+		for _, alias := range n.UnitAliases {
+			foreign := e.syntheticNode("Identifier", n.Src)
+			foreign["name"] = alias
+			foreign["overloadedDeclarations"] = []int64{}
+			aliases = append(aliases, solcObject{"foreign": foreign, "local": nil})
+		}
+		toReturn["symbolAliases"] = aliases
+		return toReturn
+	case *Contract:
+		return e.contract(n)
+	case *Interface:
+		return e.contract((*Contract)(n))
+	case *Library:
+		return e.contract((*Contract)(n))
+	case *StateVariableDeclaration:
+		toReturn := e.node(n.Id, "VariableDeclaration", n.Src)
+		toReturn["name"] = n.Name
+		toReturn["constant"] = n.Constant
+		toReturn["mutability"] = solcVariableMutabilityName(n.StateMutability, n.Constant)
+		toReturn["scope"] = n.Scope
+		toReturn["stateVariable"] = true
+		toReturn["storageLocation"] = solcStorageLocationName(n.StorageLocation)
+		toReturn["visibility"] = solcVisibilityName(n.Visibility)
+		toReturn["typeName"] = e.typeName(n.TypeName)
+		toReturn["typeDescriptions"] = solcTypeDescriptionsOf(n.TypeDescription)
+		if value := e.expression(n.InitialValue); value != nil {
+			toReturn["value"] = value
+		}
+		return toReturn
+	case *Function:
+		return e.function(n, "function")
+	case *Constructor:
+		return e.function(&Function{
+			Id:               n.Id,
+			Src:              n.Src,
+			Body:             n.Body,
+			Implemented:      n.Implemented,
+			Visibility:       n.Visibility,
+			StateMutability:  n.StateMutability,
+			Modifiers:        n.Modifiers,
+			Parameters:       n.Parameters,
+			ReturnParameters: n.ReturnParameters,
+			Scope:            n.Scope,
+		}, "constructor")
+	case *Fallback:
+		return e.function(&Function{
+			Id:               n.Id,
+			Src:              n.Src,
+			Body:             n.Body,
+			Implemented:      n.Implemented,
+			Visibility:       n.Visibility,
+			StateMutability:  n.StateMutability,
+			Virtual:          n.Virtual,
+			Modifiers:        n.Modifiers,
+			Overrides:        n.Overrides,
+			Parameters:       n.Parameters,
+			ReturnParameters: n.ReturnParameters,
+		}, "fallback")
+	case *Receive:
+		return e.function(&Function{
+			Id:               n.Id,
+			Src:              n.Src,
+			Body:             n.Body,
+			Implemented:      n.Implemented,
+			Visibility:       n.Visibility,
+			StateMutability:  n.StateMutability,
+			Virtual:          n.Virtual,
+			Modifiers:        n.Modifiers,
+			Overrides:        n.Overrides,
+			Parameters:       n.Parameters,
+			ReturnParameters: n.ReturnParameters,
+		}, "receive")
+	case *ModifierDefinition:
+		toReturn := e.node(n.Id, "ModifierDefinition", n.Src)
+		toReturn["name"] = n.Name
+		toReturn["nameLocation"] = e.src(n.NameLocation)
+		toReturn["visibility"] = solcVisibilityName(n.Visibility)
+		toReturn["virtual"] = n.Virtual
+		toReturn["parameters"] = e.parameterList(n.Parameters, n.Src)
+		if body := e.block(n.Body); body != nil {
+			toReturn["body"] = body
+		}
+		return toReturn
+	case *EventDefinition:
+		toReturn := e.node(n.Id, "EventDefinition", n.Src)
+		toReturn["name"] = n.Name
+		toReturn["anonymous"] = n.Anonymous
+		toReturn["parameters"] = e.parameterList(n.Parameters, n.Src)
+		return toReturn
+	case *ErrorDefinition:
+		toReturn := e.node(n.Id, "ErrorDefinition", n.Src)
+		toReturn["name"] = n.Name
+		toReturn["nameLocation"] = e.src(n.NameLocation)
+		toReturn["parameters"] = e.parameterList(n.Parameters, n.Src)
+		return toReturn
+	case *StructDefinition:
+		toReturn := e.node(n.Id, "StructDefinition", n.Src)
+		toReturn["name"] = n.Name
+		toReturn["nameLocation"] = e.src(n.NameLocation)
+		toReturn["canonicalName"] = n.CanonicalName
+		toReturn["visibility"] = solcVisibilityName(n.Visibility)
+		members := make([]any, 0, len(n.Members))
+		for _, member := range n.Members {
+			if parameter, ok := member.(*Parameter); ok {
+				members = append(members, e.parameter(parameter))
+			}
+		}
+		toReturn["members"] = members
+		return toReturn
+	case *EnumDefinition:
+		toReturn := e.node(n.Id, "EnumDefinition", n.Src)
+		toReturn["name"] = n.Name
+		toReturn["nameLocation"] = e.src(n.NameLocation)
+		toReturn["canonicalName"] = n.CanonicalName
+		members := make([]any, 0, len(n.Members))
+		for _, member := range n.Members {
+			value := e.node(member.GetId(), "EnumValue", member.GetSrc())
+			if parameter, ok := member.(*Parameter); ok {
+				value["name"] = parameter.Name
+			}
+			members = append(members, value)
+		}
+		toReturn["members"] = members
+		return toReturn
+	case *UserDefinedValueTypeDefinition:
+		toReturn := e.node(n.Id, "UserDefinedValueTypeDefinition", n.Src)
+		toReturn["name"] = n.Name
+		toReturn["nameLocation"] = e.src(n.NameLocation)
+		toReturn["underlyingType"] = e.typeName(n.TypeName)
+		return toReturn
+	case *UsingDirective:
+		toReturn := e.node(n.Id, "UsingForDirective", n.Src)
+		if n.LibraryName != nil {
+			libraryName := e.node(n.LibraryName.Id, "IdentifierPath", n.LibraryName.Src)
+			libraryName["name"] = n.LibraryName.Name
+			libraryName["referencedDeclaration"] = n.LibraryName.ReferencedDeclaration
+			toReturn["libraryName"] = libraryName
+		}
+		if typeName := e.typeName(n.TypeName); typeName != nil {
+			toReturn["typeName"] = typeName
+		}
+		return toReturn
+	}
+
+	return nil
+}
+
+// contract exports a contract, interface or library as a ContractDefinition.
+func (e *solcExporter) contract(contract *Contract) solcObject {
+	toReturn := e.node(contract.Id, "ContractDefinition", contract.Src)
+	toReturn["name"] = contract.Name
+	toReturn["nameLocation"] = e.src(contract.NameLocation)
+	toReturn["abstract"] = contract.Abstract
+	toReturn["fullyImplemented"] = contract.FullyImplemented
+	toReturn["linearizedBaseContracts"] = solcIds(contract.LinearizedBaseContracts)
+	toReturn["contractDependencies"] = solcIds(contract.ContractDependencies)
+	toReturn["scope"] = contract.Src.ParentIndex
+
+	switch contract.Kind {
+	case ast_pb.NodeType_KIND_INTERFACE:
+		toReturn["contractKind"] = "interface"
+	case ast_pb.NodeType_KIND_LIBRARY:
+		toReturn["contractKind"] = "library"
+	default:
+		toReturn["contractKind"] = "contract"
+	}
+
+	baseContracts := make([]any, 0, len(contract.BaseContracts))
+	for _, base := range contract.BaseContracts {
+		specifier := e.node(base.Id, "InheritanceSpecifier", base.Src)
+		if base.BaseName != nil {
+			baseName := e.node(base.BaseName.Id, "IdentifierPath", base.BaseName.Src)
+			baseName["name"] = base.BaseName.Name
+			baseName["referencedDeclaration"] = base.BaseName.ReferencedDeclaration
+			specifier["baseName"] = baseName
+		}
+		if len(base.Arguments) > 0 {
+			specifier["arguments"] = e.expressions(base.Arguments)
+		}
+		baseContracts = append(baseContracts, specifier)
+	}
+	toReturn["baseContracts"] = baseContracts
+
+	nodes := make([]any, 0, len(contract.Nodes))
+	for _, node := range contract.Nodes {
+		if exported := e.definition(node); exported != nil {
+			nodes = append(nodes, exported)
+		}
+	}
+	toReturn["nodes"] = nodes
+
+	return toReturn
+}
+
+// function exports a function, constructor, fallback or receive function as a FunctionDefinition
+// of the given kind.
+func (e *solcExporter) function(fn *Function, kind string) solcObject {
+	toReturn := e.node(fn.Id, "FunctionDefinition", fn.Src)
+	toReturn["kind"] = kind
+	toReturn["name"] = fn.Name
+	toReturn["nameLocation"] = e.src(fn.NameLocation)
+	toReturn["implemented"] = fn.Implemented
+	toReturn["visibility"] = solcVisibilityName(fn.Visibility)
+	toReturn["stateMutability"] = solcFunctionMutabilityName(fn.StateMutability)
+	toReturn["virtual"] = fn.Virtual
+	toReturn["scope"] = fn.Scope
+	toReturn["parameters"] = e.parameterList(fn.Parameters, fn.Src)
+	toReturn["returnParameters"] = e.parameterList(fn.ReturnParameters, fn.Src)
+
+	if fn.Signature != "" && (fn.Visibility == ast_pb.Visibility_PUBLIC || fn.Visibility == ast_pb.Visibility_EXTERNAL) {
+		toReturn["functionSelector"] = fn.Signature
+	}
+
+	modifiers := make([]any, 0, len(fn.Modifiers))
+	for _, modifier := range fn.Modifiers {
+		invocation := e.node(modifier.Id, "ModifierInvocation", modifier.Src)
+		invocation["kind"] = "modifierInvocation"
+
+		var modifierName solcObject
+		if modifier.ModifierName != nil {
+			modifierName = e.node(modifier.ModifierName.Id, "IdentifierPath", modifier.ModifierName.Src)
+		} else {
+			modifierName = e.syntheticNode("IdentifierPath", modifier.Src)
+		}
+		modifierName["name"] = modifier.Name
+		invocation["modifierName"] = modifierName
+
+		if len(modifier.Arguments) > 0 {
+			invocation["arguments"] = e.expressions(modifier.Arguments)
+		}
+		modifiers = append(modifiers, invocation)
+	}
+	toReturn["modifiers"] = modifiers
+
+	if len(fn.Overrides) > 0 && fn.Overrides[0] != nil {
+		override := e.node(fn.Overrides[0].Id, "OverrideSpecifier", fn.Overrides[0].Src)
+		paths := make([]any, 0, len(fn.Overrides[0].Overrides))
+		for _, path := range fn.Overrides[0].Overrides {
+			identifierPath := e.node(path.Id, "IdentifierPath", path.Src)
+			identifierPath["name"] = path.Name
+			identifierPath["referencedDeclaration"] = path.ReferencedDeclaration
+			paths = append(paths, identifierPath)
+		}
+		override["overrides"] = paths
+		toReturn["overrides"] = override
+	}
+
+	if fn.Implemented {
+		if body := e.block(fn.Body); body != nil {
+			toReturn["body"] = body
+		}
+	}
+
+	return toReturn
+}
+
+// parameterList exports a ParameterList. Missing lists are exported empty, located at the parent.
+func (e *solcExporter) parameterList(list *ParameterList, parentSrc SrcNode) solcObject {
+	if list == nil {
+		toReturn := e.syntheticNode("ParameterList", parentSrc)
+		toReturn["parameters"] = []any{}
+		return toReturn
+	}
+
+	toReturn := e.node(list.Id, "ParameterList", list.Src)
+	parameters := make([]any, 0, len(list.Parameters))
+	for _, parameter := range list.Parameters {
+		parameters = append(parameters, e.parameter(parameter))
+	}
+	toReturn["parameters"] = parameters
+
+	return toReturn
+}
+
+// parameter exports a parameter or struct member as a VariableDeclaration.
+func (e *solcExporter) parameter(parameter *Parameter) solcObject {
+	toReturn := e.node(parameter.Id, "VariableDeclaration", parameter.Src)
+	toReturn["name"] = parameter.Name
+	toReturn["constant"] = parameter.Constant
+	toReturn["indexed"] = parameter.Indexed
+	toReturn["mutability"] = solcVariableMutabilityName(parameter.StateMutability, parameter.Constant)
+	toReturn["scope"] = parameter.Scope
+	toReturn["stateVariable"] = parameter.StateVariable
+	toReturn["storageLocation"] = solcStorageLocationName(parameter.StorageLocation)
+	toReturn["visibility"] = solcVisibilityName(parameter.Visibility)
+	toReturn["typeName"] = e.typeName(parameter.TypeName)
+	toReturn["typeDescriptions"] = solcTypeDescriptionsOf(parameter.TypeDescription)
+	if parameter.NameLocation != nil {
+		toReturn["nameLocation"] = e.src(*parameter.NameLocation)
+	}
+	return toReturn
+}
+
+// typeName exports a type name. Arrays, which solgo names after the array type as written, become
+// an ArrayTypeName around the type name of their base type. Mappings are recognised by their key
+// and value types, as the parser does not always mark them as such.
+func (e *solcExporter) typeName(typeName *TypeName) solcObject {
+	if typeName == nil {
+		return nil
+	}
+
+	mapping := typeName.NodeType == ast_pb.NodeType_MAPPING_TYPE_NAME || (typeName.KeyType != nil && typeName.ValueType != nil)
+
+	if open := strings.LastIndex(typeName.Name, "["); open > 0 && strings.HasSuffix(typeName.Name, "]") && !mapping {
+		toReturn := e.node(typeName.Id, "ArrayTypeName", typeName.Src)
+		toReturn["typeDescriptions"] = solcTypeDescriptionsOf(typeName.TypeDescription)
+
+		base := *typeName
+		base.Name = typeName.Name[:open]
+		base.TypeDescription = &TypeDescription{}
+		baseType := e.typeName(&base)
+		e.synthetic = append(e.synthetic, baseType)
+		toReturn["baseType"] = baseType
+
+		if length := typeName.Name[open+1 : len(typeName.Name)-1]; length != "" {
+			literal := e.syntheticNode("Literal", typeName.Src)
+			literal["kind"] = "number"
+			literal["value"] = length
+			literal["typeDescriptions"] = solcTypeDescriptionsOf(&TypeDescription{
+				TypeIdentifier: "t_rational_" + length + "_by_1",
+				TypeString:     "int_const " + length,
+			})
+			toReturn["length"] = literal
+		}
+
+		return toReturn
+	}
+
+	var toReturn solcObject
+	switch {
+	case mapping:
+		toReturn = e.node(typeName.Id, "Mapping", typeName.Src)
+		toReturn["keyType"] = e.typeName(typeName.KeyType)
+		toReturn["valueType"] = e.typeName(typeName.ValueType)
+		if typeName.KeyNameLocation != nil {
+			toReturn["keyNameLocation"] = e.src(*typeName.KeyNameLocation)
+		}
+		if typeName.ValueNameLocation != nil {
+			toReturn["valueNameLocation"] = e.src(*typeName.ValueNameLocation)
+		}
+	case typeName.NodeType == ast_pb.NodeType_USER_DEFINED_PATH_NAME:
+		toReturn = e.node(typeName.Id, "UserDefinedTypeName", typeName.Src)
+		toReturn["referencedDeclaration"] = typeName.ReferencedDeclaration
+
+		var pathNode solcObject
+		if typeName.PathNode != nil {
+			pathNode = e.node(typeName.PathNode.Id, "IdentifierPath", typeName.PathNode.Src)
+			pathNode["name"] = typeName.PathNode.Name
+			pathNode["referencedDeclaration"] = typeName.PathNode.ReferencedDeclaration
+		} else {
+			pathNode = e.syntheticNode("IdentifierPath", typeName.Src)
+			pathNode["name"] = typeName.Name
+			pathNode["referencedDeclaration"] = typeName.ReferencedDeclaration
+		}
+		toReturn["pathNode"] = pathNode
+	case typeName.NodeType == ast_pb.NodeType_FUNCTION_TYPE_NAME:
+		toReturn = e.node(typeName.Id, "FunctionTypeName", typeName.Src)
+		toReturn["stateMutability"] = solcFunctionMutabilityName(typeName.StateMutability)
+		toReturn["visibility"] = "internal"
+		for _, key := range []string{"parameterTypes", "returnParameterTypes"} {
+			list := e.syntheticNode("ParameterList", typeName.Src)
+			list["parameters"] = []any{}
+			toReturn[key] = list
+		}
+	default:
+		toReturn = e.node(typeName.Id, "ElementaryTypeName", typeName.Src)
+		toReturn["name"] = typeName.Name
+		if typeName.StateMutability == ast_pb.Mutability_PAYABLE {
+			toReturn["stateMutability"] = "payable"
+		}
+	}
+
+	toReturn["typeDescriptions"] = solcTypeDescriptionsOf(typeName.TypeDescription)
+	return toReturn
+}
+
+// block exports a body as a Block.
+func (e *solcExporter) block(body *BodyNode) solcObject {
+	if body == nil {
+		return nil
+	}
+
+	toReturn := e.node(body.Id, "Block", body.Src)
+	toReturn["statements"] = e.statements(body.Statements)
+	return toReturn
+}
+
+// statements exports the statements of a block, skipping the ones that cannot be exported.
+func (e *solcExporter) statements(nodes []Node[NodeType]) []any {
+	toReturn := make([]any, 0, len(nodes))
+	for _, node := range nodes {
+		if statement := e.statement(node); statement != nil {
+			toReturn = append(toReturn, statement)
+		}
+	}
+	return toReturn
+}
+
+// statement exports a statement. Expressions used as statements are wrapped into an
+// ExpressionStatement, as solc represents them.
+func (e *solcExporter) statement(node Node[NodeType]) solcObject {
+	switch n := node.(type) {
+	case nil:
+		return nil
+	case *BodyNode:
+		return e.block(n)
+	case *UncheckedBlock:
+		toReturn := e.node(n.Id, "UncheckedBlock", n.Src)
+		toReturn["statements"] = e.statements(n.Statements)
+		return toReturn
+	case *VariableDeclaration:
+		toReturn := e.node(n.Id, "VariableDeclarationStatement", n.Src)
+		toReturn["assignments"] = solcIds(n.Assignments)
+		declarations := make([]any, 0, len(n.Declarations))
+		for _, declaration := range n.Declarations {
+			variable := e.node(declaration.Id, "VariableDeclaration", declaration.Src)
+			variable["name"] = declaration.Name
+			variable["nameLocation"] = e.src(declaration.NameLocation)
+			variable["constant"] = false
+			variable["mutability"] = "mutable"
+			variable["scope"] = declaration.Scope
+			variable["stateVariable"] = false
+			variable["storageLocation"] = solcStorageLocationName(declaration.StorageLocation)
+			variable["visibility"] = "internal"
+			variable["typeName"] = e.typeName(declaration.TypeName)
+			if declaration.TypeName != nil {
+				variable["typeDescriptions"] = solcTypeDescriptionsOf(declaration.TypeName.TypeDescription)
+			} else {
+				variable["typeDescriptions"] = solcTypeDescriptionsOf(nil)
+			}
+			declarations = append(declarations, variable)
+		}
+		toReturn["declarations"] = declarations
+		if initialValue := e.expression(n.InitialValue); initialValue != nil {
+			toReturn["initialValue"] = initialValue
+		}
+		return toReturn
+	case *IfStatement:
+		toReturn := e.node(n.Id, "IfStatement", n.Src)
+		toReturn["condition"] = e.expression(n.Condition)
+		toReturn["trueBody"] = e.statement(n.Body)
+		return toReturn
+	case *ForStatement:
+		toReturn := e.node(n.Id, "ForStatement", n.Src)
+		if initialization := e.statement(n.Initialiser); initialization != nil {
+			toReturn["initializationExpression"] = initialization
+		}
+		if condition := e.expression(n.Condition); condition != nil {
+			toReturn["condition"] = condition
+		}
+		if loop := e.statement(n.Closure); loop != nil {
+			toReturn["loopExpression"] = loop
+		}
+		toReturn["body"] = e.block(n.Body)
+		return toReturn
+	case *WhileStatement:
+		toReturn := e.node(n.Id, "WhileStatement", n.Src)
+		toReturn["condition"] = e.expression(n.Condition)
+		toReturn["body"] = e.block(n.Body)
+		return toReturn
+	case *DoWhileStatement:
+		toReturn := e.node(n.Id, "DoWhileStatement", n.Src)
+		toReturn["condition"] = e.expression(n.Condition)
+		toReturn["body"] = e.block(n.Body)
+		return toReturn
+	case *ReturnStatement:
+		toReturn := e.node(n.Id, "Return", n.Src)
+		toReturn["functionReturnParameters"] = n.FunctionReturnParameters
+		if expression := e.expression(n.Expression); expression != nil {
+			toReturn["expression"] = expression
+		}
+		return toReturn
+	case *Emit:
+		toReturn := e.node(n.Id, "EmitStatement", n.Src)
+		toReturn["eventCall"] = e.call(n.Src, n.Expression, n.Arguments, nil)
+		return toReturn
+	case *RevertStatement:
+		toReturn := e.node(n.Id, "RevertStatement", n.Src)
+		toReturn["errorCall"] = e.call(n.Src, n.Expression, n.Arguments, n.Names)
+		return toReturn
+	case *TryStatement:
+		return e.tryStatement(n)
+	case *BreakStatement:
+		return e.node(n.Id, "Break", n.Src)
+	case *ContinueStatement:
+		return e.node(n.Id, "Continue", n.Src)
+	case *PrimaryExpression:
+		if n.NodeType == ast_pb.NodeType_PLACEHOLDER_STATEMENT {
+			return e.node(n.Id, "PlaceholderStatement", n.Src)
+		}
+	case *Yul:
+		toReturn := e.node(n.Id, "InlineAssembly", n.Src)
+		toReturn["externalReferences"] = []any{}
+		if n.Body != nil {
+			toReturn["AST"] = e.yulBlock(n.Body.Src, n.Body.Statements)
+		} else {
+			toReturn["AST"] = e.yulBlock(n.Src, nil)
+		}
+		return toReturn
+	}
+
+	expression := e.expression(node)
+	if expression == nil {
+		return nil
+	}
+
+	toReturn := e.syntheticNode("ExpressionStatement", node.GetSrc())
+	toReturn["expression"] = expression
+	return toReturn
+}
+
+// tryStatement exports a TryStatement. The body of the try block becomes the first clause, followed
+// by the catch clauses.
+func (e *solcExporter) tryStatement(statement *TryStatement) solcObject {
+	toReturn := e.node(statement.Id, "TryStatement", statement.Src)
+	toReturn["externalCall"] = e.expression(statement.Expression)
+
+	clauses := make([]any, 0, len(statement.Clauses)+1)
+
+	success := e.syntheticNode("TryCatchClause", statement.Src)
+	success["errorName"] = ""
+	success["block"] = e.block(statement.Body)
+	if statement.Returns && statement.ReturnParameters != nil {
+		success["parameters"] = e.parameterList(statement.ReturnParameters, statement.Src)
+	}
+	clauses = append(clauses, success)
+
+	for _, clause := range statement.Clauses {
+		catch, ok := clause.(*CatchStatement)
+		if !ok {
+			continue
+		}
+
+		exported := e.node(catch.Id, "TryCatchClause", catch.Src)
+		exported["errorName"] = catch.Name
+		exported["block"] = e.block(catch.Body)
+		if catch.Parameters != nil && len(catch.Parameters.Parameters) > 0 {
+			exported["parameters"] = e.parameterList(catch.Parameters, catch.Src)
+		}
+		clauses = append(clauses, exported)
+	}
+
+	toReturn["clauses"] = clauses
+	return toReturn
+}
+
+// call exports the event or error call of an emit or revert statement, which solgo stores on the
+// statement itself.
+func (e *solcExporter) call(src SrcNode, callee Node[NodeType], arguments []Node[NodeType], names []string) solcObject {
+	toReturn := e.syntheticNode("FunctionCall", src)
+	toReturn["kind"] = "functionCall"
+	toReturn["expression"] = e.expression(callee)
+	toReturn["arguments"] = e.expressions(arguments)
+	toReturn["names"] = append(make([]string, 0, len(names)), names...)
+	toReturn["tryCall"] = false
+	if callee != nil {
+		toReturn["typeDescriptions"] = solcTypeDescriptionsOf(&TypeDescription{TypeIdentifier: "t_tuple$__$", TypeString: "tuple()"})
+	}
+	return toReturn
+}
+
+// expressions exports a list of expressions, skipping the ones that cannot be exported.
+func (e *solcExporter) expressions(nodes []Node[NodeType]) []any {
+	toReturn := make([]any, 0, len(nodes))
+	for _, node := range nodes {
+		if expression := e.expression(node); expression != nil {
+			toReturn = append(toReturn, expression)
+		}
+	}
+	return toReturn
+}
+
+// expression exports an expression. The node types the parser uses for logical, bitwise, shift
+// and exponentiation operators are exported as a BinaryOperation.
+func (e *solcExporter) expression(node Node[NodeType]) solcObject {
+	var toReturn solcObject
+
+	switch n := node.(type) {
+	case nil:
+		return nil
+	case *PrimaryExpression:
+		switch {
+		case n.NodeType == ast_pb.NodeType_LITERAL:
+			toReturn = e.node(n.Id, "Literal", n.Src)
+			toReturn["kind"] = solcLiteralKindName(n.Kind)
+			toReturn["value"] = n.Value
+			toReturn["hexValue"] = n.HexValue
+			toReturn["isPure"] = true
+		case n.TypeName != nil:
+			toReturn = e.node(n.Id, "ElementaryTypeNameExpression", n.Src)
+			toReturn["typeName"] = e.typeName(n.TypeName)
+			toReturn["isPure"] = true
+		default:
+			toReturn = e.node(n.Id, "Identifier", n.Src)
+			toReturn["name"] = n.Name
+			toReturn["referencedDeclaration"] = n.ReferencedDeclaration
+			toReturn["overloadedDeclarations"] = solcIds(n.OverloadedDeclarations)
+		}
+	case *MetaType:
+		toReturn = e.node(n.Id, "Identifier", n.Src)
+		toReturn["name"] = n.Name
+		toReturn["referencedDeclaration"] = n.ReferencedDeclaration
+		toReturn["overloadedDeclarations"] = []int64{}
+	case *MemberAccessExpression:
+		toReturn = e.node(n.Id, "MemberAccess", n.Src)
+		toReturn["expression"] = e.expression(n.Expression)
+		toReturn["memberName"] = n.MemberName
+		toReturn["memberLocation"] = e.src(n.MemberLocation)
+		toReturn["isConstant"] = n.Constant
+		toReturn["isLValue"] = n.LValue
+		toReturn["isPure"] = n.Pure
+		toReturn["lValueRequested"] = n.LValueRequested
+		if n.ReferencedDeclaration != 0 {
+			toReturn["referencedDeclaration"] = n.ReferencedDeclaration
+		}
+	case *IndexAccess:
+		toReturn = e.node(n.Id, "IndexAccess", n.Src)
+		toReturn["baseExpression"] = e.expression(n.BaseExpression)
+		if index := e.expression(n.IndexExpression); index != nil {
+			toReturn["indexExpression"] = index
+		}
+	case *IndexRange:
+		toReturn = e.node(n.Id, "IndexRangeAccess", n.Src)
+		if start := e.expression(n.LeftExpression); start != nil {
+			toReturn["startExpression"] = start
+		}
+		if end := e.expression(n.RightExpression); end != nil {
+			toReturn["endExpression"] = end
+		}
+	case *FunctionCall:
+		toReturn = e.node(n.Id, "FunctionCall", n.Src)
+		toReturn["kind"] = solcFunctionCallKind(n.Expression)
+		toReturn["expression"] = e.expression(n.Expression)
+		toReturn["arguments"] = e.expressions(n.Arguments)
+		toReturn["names"] = []string{}
+		toReturn["tryCall"] = false
+	case *PayableConversion:
+		typeName := e.syntheticNode("ElementaryTypeName", n.Src)
+		typeName["name"] = "address"
+		typeName["stateMutability"] = "payable"
+		typeName["typeDescriptions"] = solcTypeDescriptionsOf(nil)
+
+		callee := e.syntheticNode("ElementaryTypeNameExpression", n.Src)
+		callee["typeName"] = typeName
+		callee["isPure"] = true
+		callee["typeDescriptions"] = solcTypeDescriptionsOf(&TypeDescription{
+			TypeIdentifier: "t_type$_t_address_payable_$",
+			TypeString:     "type(address payable)",
+		})
+
+		toReturn = e.node(n.Id, "FunctionCall", n.Src)
+		toReturn["kind"] = "typeConversion"
+		toReturn["expression"] = callee
+		toReturn["arguments"] = e.expressions(n.Arguments)
+		toReturn["names"] = []string{}
+		toReturn["tryCall"] = false
+	case *FunctionCallOption:
+		toReturn = e.node(n.Id, "FunctionCallOptions", n.Src)
+		toReturn["expression"] = e.expression(n.Expression)
+		toReturn["names"] = n.Names
+		toReturn["options"] = e.expressions(n.Options)
+		if n.Names == nil {
+			toReturn["names"] = []string{}
+		}
+	case *Assignment:
+		if n.LeftExpression == nil && n.RightExpression == nil {
+			return e.expression(n.Expression)
+		}
+		toReturn = e.node(n.Id, "Assignment", n.Src)
+		toReturn["operator"] = solcOperatorSymbol(n.Operator, true)
+		toReturn["leftHandSide"] = e.expression(n.LeftExpression)
+		toReturn["rightHandSide"] = e.expression(n.RightExpression)
+	case *BinaryOperation:
+		toReturn = e.binaryOperation(n.Id, n.Src, solcOperatorSymbol(n.Operator, false), n.LeftExpression, n.RightExpression)
+		toReturn["isConstant"] = n.Constant
+		toReturn["isPure"] = n.Pure
+	case *ExprOperation:
+		toReturn = e.binaryOperation(n.Id, n.Src, "**", n.LeftExpression, n.RightExpression)
+	case *AndOperation:
+		toReturn = e.foldOperation(n.Id, n.Src, "&&", n.Expressions)
+	case *BitAndOperation:
+		toReturn = e.foldOperation(n.Id, n.Src, "&", n.Expressions)
+	case *BitOrOperation:
+		toReturn = e.foldOperation(n.Id, n.Src, "|", n.Expressions)
+	case *BitXorOperation:
+		toReturn = e.foldOperation(n.Id, n.Src, "^", n.Expressions)
+	case *ShiftOperation:
+		operator := "<<"
+		if n.Operator == ast_pb.NodeType_SHIFT_RIGHT_OPERATION {
+			operator = ">>"
+		}
+		toReturn = e.foldOperation(n.Id, n.Src, operator, n.Expressions)
+	case *UnaryPrefix:
+		toReturn = e.node(n.Id, "UnaryOperation", n.Src)
+		toReturn["operator"] = solcOperatorSymbol(n.Operator, false)
+		if n.Operator == ast_pb.Operator_O_DEFAULT {
+			toReturn["operator"] = "delete"
+		}
+		toReturn["prefix"] = true
+		toReturn["subExpression"] = e.expression(n.Expression)
+		toReturn["isConstant"] = n.Constant
+		toReturn["isLValue"] = n.LValue
+		toReturn["isPure"] = n.Pure
+		toReturn["lValueRequested"] = n.LValueRequested
+	case *UnarySuffix:
+		toReturn = e.node(n.Id, "UnaryOperation", n.Src)
+		toReturn["operator"] = solcOperatorSymbol(n.Operator, false)
+		toReturn["prefix"] = false
+		toReturn["subExpression"] = e.expression(n.Expression)
+		toReturn["isConstant"] = n.Constant
+		toReturn["isLValue"] = n.LValue
+		toReturn["isPure"] = n.Pure
+		toReturn["lValueRequested"] = n.LValueRequested
+	case *Conditional:
+		if len(n.Expressions) != 3 {
+			return nil
+		}
+		toReturn = e.node(n.Id, "Conditional", n.Src)
+		toReturn["condition"] = e.expression(n.Expressions[0])
+		toReturn["trueExpression"] = e.expression(n.Expressions[1])
+		toReturn["falseExpression"] = e.expression(n.Expressions[2])
+	case *TupleExpression:
+		toReturn = e.node(n.Id, "TupleExpression", n.Src)
+		toReturn["components"] = e.expressions(n.Components)
+		toReturn["isInlineArray"] = false
+		toReturn["isConstant"] = n.Constant
+		toReturn["isPure"] = n.Pure
+	case *InlineArray:
+		toReturn = e.node(n.Id, "TupleExpression", n.Src)
+		toReturn["components"] = e.expressions(n.Expressions)
+		toReturn["isInlineArray"] = true
+	case *NewExpr:
+		toReturn = e.node(n.Id, "NewExpression", n.Src)
+		toReturn["typeName"] = e.typeName(n.TypeName)
+	default:
+		return nil
+	}
+
+	toReturn["typeDescriptions"] = solcTypeDescriptionsOf(node.GetTypeDescription())
+	return toReturn
+}
+
+// binaryOperation exports a BinaryOperation with the given operands.
+func (e *solcExporter) binaryOperation(id int64, src SrcNode, operator string, left Node[NodeType], right Node[NodeType]) solcObject {
+	toReturn := e.node(id, "BinaryOperation", src)
+	toReturn["operator"] = operator
+	toReturn["leftExpression"] = e.expression(left)
+	toReturn["rightExpression"] = e.expression(right)
+	return toReturn
+}
+
+// foldOperation exports an operation the parser stores as a list of operands. Lists of more than
+// two operands are folded from the left into nested binary operations.
+func (e *solcExporter) foldOperation(id int64, src SrcNode, operator string, expressions []Node[NodeType]) solcObject {
+	if len(expressions) == 0 {
+		return e.node(id, "BinaryOperation", src)
+	}
+
+	left := e.expression(expressions[0])
+	for index := 1; index < len(expressions); index++ {
+		var operation solcObject
+		if index == len(expressions)-1 {
+			operation = e.node(id, "BinaryOperation", src)
+		} else {
+			operation = e.syntheticNode("BinaryOperation", src)
+		}
+		operation["operator"] = operator
+		operation["leftExpression"] = left
+		operation["rightExpression"] = e.expression(expressions[index])
+		if index < len(expressions)-1 {
+			operation["typeDescriptions"] = solcTypeDescriptionsOf(expressions[index].GetTypeDescription())
+		}
+		left = operation
+	}
+
+	return left
+}
+
+// yulBlock exports the statements of an assembly block as a YulBlock.
+func (e *solcExporter) yulBlock(src SrcNode, statements []Node[NodeType]) solcObject {
+	toReturn := e.yulNode("YulBlock", src)
+	toReturn["statements"] = e.yulStatements(statements)
+	return toReturn
+}
+
+// yulStatements exports Yul statements, flattening the statement wrappers of the parser and
+// wrapping bare expressions into a YulExpressionStatement.
+func (e *solcExporter) yulStatements(nodes []Node[NodeType]) []any {
+	toReturn := make([]any, 0, len(nodes))
+	for _, node := range nodes {
+		if wrapper, ok := node.(*YulStatement); ok {
+			toReturn = append(toReturn, e.yulStatements(wrapper.Statements)...)
+			continue
+		}
+
+		statement := e.yul(node)
+		if statement == nil {
+			continue
+		}
+
+		switch statement["nodeType"] {
+		case "YulFunctionCall", "YulIdentifier", "YulLiteral":
+			wrapper := e.yulNode("YulExpressionStatement", node.GetSrc())
+			wrapper["expression"] = statement
+			statement = wrapper
+		}
+		toReturn = append(toReturn, statement)
+	}
+	return toReturn
+}
+
+// yul exports a Yul statement or expression. Yul nodes carry no ids in solc's output.
+func (e *solcExporter) yul(node Node[NodeType]) solcObject {
+	switch n := node.(type) {
+	case nil:
+		return nil
+	case *YulStatement:
+		if len(n.Statements) == 1 {
+			return e.yul(n.Statements[0])
+		}
+		return e.yulBlock(n.Src, n.Statements)
+	case *YulBlockStatement:
+		return e.yulBlock(n.Src, n.Statements)
+	case *BodyNode:
+		return e.yulBlock(n.Src, n.Statements)
+	case *YulExpressionStatement:
+		return e.yul(n.Expression)
+	case *YulVariable:
+		toReturn := e.yulNode("YulVariableDeclaration", n.Src)
+		variables := make([]any, 0, len(n.Variables))
+		for _, variable := range n.Variables {
+			typedName := e.yulNode("YulTypedName", variable.Src)
+			typedName["name"] = variable.Name
+			typedName["type"] = ""
+			variables = append(variables, typedName)
+		}
+		toReturn["variables"] = variables
+		if value := e.yul(n.Value); value != nil {
+			toReturn["value"] = value
+		}
+		return toReturn
+	case *YulAssignment:
+		toReturn := e.yulNode("YulAssignment", n.Src)
+		toReturn["variableNames"] = e.yulIdentifiers(n.VariableNames)
+		toReturn["value"] = e.yul(n.Value)
+		return toReturn
+	case *YulFunctionCallStatement:
+		toReturn := e.yulNode("YulFunctionCall", n.Src)
+		if n.FunctionName != nil {
+			toReturn["functionName"] = e.yul(n.FunctionName)
+		}
+		arguments := make([]any, 0, len(n.Arguments))
+		for _, argument := range n.Arguments {
+			if exported := e.yul(argument); exported != nil {
+				arguments = append(arguments, exported)
+			}
+		}
+		toReturn["arguments"] = arguments
+		return toReturn
+	case *YulIdentifier:
+		toReturn := e.yulNode("YulIdentifier", n.Src)
+		toReturn["name"] = n.Name
+		return toReturn
+	case *YulLiteralStatement:
+		toReturn := e.yulNode("YulLiteral", n.Src)
+		toReturn["kind"] = solcYulLiteralKindName(n.Kind)
+		toReturn["value"] = n.Value
+		toReturn["type"] = ""
+		if n.HexValue != "" {
+			toReturn["hexValue"] = n.HexValue
+		}
+		return toReturn
+	case *YulIfStatement:
+		toReturn := e.yulNode("YulIf", n.Src)
+		toReturn["condition"] = e.yul(n.Condition)
+		toReturn["body"] = e.yulBody(n.Body, n.Src)
+		return toReturn
+	case *YulForStatement:
+		toReturn := e.yulNode("YulForLoop", n.Src)
+		toReturn["pre"] = e.yulBody(n.Pre, n.Src)
+		toReturn["condition"] = e.yul(n.Condition)
+		toReturn["post"] = e.yulBody(n.Post, n.Src)
+		toReturn["body"] = e.yulBody(n.Body, n.Src)
+		return toReturn
+	case *YulSwitchStatement:
+		toReturn := e.yulNode("YulSwitch", n.Src)
+		toReturn["expression"] = e.yul(n.Expression)
+		cases := make([]any, 0, len(n.Cases)+1)
+		for _, switchCase := range append(append(make([]Node[NodeType], 0, len(n.Cases)+1), n.Cases...), n.Default) {
+			caseStatement, ok := switchCase.(*YulSwitchCaseStatement)
+			if !ok || caseStatement == nil {
+				continue
+			}
+			exported := e.yulNode("YulCase", caseStatement.Src)
+			exported["body"] = e.yulBody(caseStatement.Body, caseStatement.Src)
+			if value := e.yul(caseStatement.Case); value != nil && switchCase != n.Default {
+				exported["value"] = value
+			} else {
+				exported["value"] = "default"
+			}
+			cases = append(cases, exported)
+		}
+		toReturn["cases"] = cases
+		return toReturn
+	case *YulFunctionDefinition:
+		toReturn := e.yulNode("YulFunctionDefinition", n.Src)
+		toReturn["name"] = ""
+		toReturn["parameters"] = e.yulIdentifiers(n.Arguments)
+		toReturn["returnVariables"] = e.yulIdentifiers(n.ReturnParameters)
+		toReturn["body"] = e.yulBody(n.Body, n.Src)
+		return toReturn
+	case *YulLeaveStatement:
+		return e.yulNode("YulLeave", n.Src)
+	case *YulBreakStatement:
+		return e.yulNode("YulBreak", n.Src)
+	case *YulContinueStatement:
+		return e.yulNode("YulContinue", n.Src)
+	}
+
+	return nil
+}
+
+// yulBody exports the body of a Yul control flow statement or function, which solc always
+// represents as a YulBlock.
+func (e *solcExporter) yulBody(node Node[NodeType], parentSrc SrcNode) solcObject {
+	body := e.yul(node)
+	if body == nil {
+		return e.yulBlock(parentSrc, nil)
+	}
+	if body["nodeType"] != "YulBlock" {
+		block := e.yulNode("YulBlock", node.GetSrc())
+		block["statements"] = e.yulStatements([]Node[NodeType]{node})
+		return block
+	}
+	return body
+}
+
+// yulIdentifiers exports Yul identifiers.
+func (e *solcExporter) yulIdentifiers(identifiers []*YulIdentifier) []any {
+	toReturn := make([]any, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		toReturn = append(toReturn, e.yul(identifier))
+	}
+	return toReturn
+}
+
+// node creates an exported node with the given id. Nodes without an id, or sharing the id of a node
+// exported before, get a new one as solc ids are unique.
+func (e *solcExporter) node(id int64, nodeType string, src SrcNode) solcObject {
+	if id <= 0 || e.ids[id] {
+		return e.syntheticNode(nodeType, src)
+	}
+
+	e.ids[id] = true
+	if id > e.maxId {
+		e.maxId = id
+	}
+	return solcObject{
+		"id":       id,
+		"nodeType": nodeType,
+		"src":      e.src(src),
+	}
+}
+
+// syntheticNode creates an exported node solgo has no counterpart for. Its id is assigned once
+// all other nodes are exported.
+func (e *solcExporter) syntheticNode(nodeType string, src SrcNode) solcObject {
+	toReturn := solcObject{
+		"nodeType": nodeType,
+		"src":      e.src(src),
+	}
+	e.synthetic = append(e.synthetic, toReturn)
+	return toReturn
+}
+
+// yulNode creates an exported Yul node.
+func (e *solcExporter) yulNode(nodeType string, src SrcNode) solcObject {
+	return solcObject{
+		"nodeType": nodeType,
+		"src":      e.src(src),
+	}
+}
+
+// src converts a source location into solc's `start:length:fileIndex` format.
+func (e *solcExporter) src(src SrcNode) string {
+	length := src.Length
+	if length == 0 && src.End >= src.Start && (src.End != 0 || src.Start != 0) {
+		length = src.End - src.Start + 1
+	}
+	if length < 0 {
+		length = 0
+	}
+	return fmt.Sprintf("%d:%d:%d", src.Start, length, e.fileIndex)
+}
+
+// solcTypeDescriptionsOf converts a type description into solc type descriptions.
+func solcTypeDescriptionsOf(typeDescription *TypeDescription) solcObject {
+	if typeDescription == nil {
+		return solcObject{"typeIdentifier": nil, "typeString": nil}
+	}
+	return solcObject{
+		"typeIdentifier": typeDescription.TypeIdentifier,
+		"typeString":     typeDescription.TypeString,
+	}
+}
+
+// solcIds returns a sorted copy of the ids, never nil so that empty lists are exported as such.
+func solcIds(ids []int64) []int64 {
+	toReturn := append(make([]int64, 0, len(ids)), ids...)
+	sort.Slice(toReturn, func(i, j int) bool {
+		return toReturn[i] < toReturn[j]
+	})
+	return toReturn
+}
+
+// solcFunctionCallKind returns the kind of a function call from its callee. Calls of elementary
+// types, contracts and enums are conversions, while calls of structs construct them.
+func solcFunctionCallKind(callee Node[NodeType]) string {
+	if primary, ok := callee.(*PrimaryExpression); ok && primary.TypeName != nil {
+		return "typeConversion"
+	}
+
+	if callee != nil && callee.GetTypeDescription() != nil {
+		identifier := callee.GetTypeDescription().GetIdentifier()
+		switch {
+		case strings.HasPrefix(identifier, "t_type$_t_struct"):
+			return "structConstructorCall"
+		case strings.HasPrefix(identifier, "t_type$_"):
+			return "typeConversion"
+		}
+	}
+
+	return "functionCall"
+}
+
+// solcOperatorSymbol returns the symbol of an operator. Operator_EQUAL is the "=" operator of
+// assignments and the "==" operator of comparisons.
+func solcOperatorSymbol(operator ast_pb.Operator, assignment bool) string {
+	if assignment && operator == ast_pb.Operator_EQUAL {
+		return "="
+	}
+	return solcOperators[operator]
+}
+
+// solcVisibilityName returns the solc name of a visibility.
+func solcVisibilityName(visibility ast_pb.Visibility) string {
+	switch visibility {
+	case ast_pb.Visibility_PUBLIC:
+		return "public"
+	case ast_pb.Visibility_PRIVATE:
+		return "private"
+	case ast_pb.Visibility_EXTERNAL:
+		return "external"
+	default:
+		return "internal"
+	}
+}
+
+// solcFunctionMutabilityName returns the solc name of the state mutability of a function.
+func solcFunctionMutabilityName(mutability ast_pb.Mutability) string {
+	switch mutability {
+	case ast_pb.Mutability_PAYABLE:
+		return "payable"
+	case ast_pb.Mutability_VIEW:
+		return "view"
+	case ast_pb.Mutability_PURE:
+		return "pure"
+	default:
+		return "nonpayable"
+	}
+}
+
+// solcVariableMutabilityName returns the solc name of the mutability of a variable declaration.
+func solcVariableMutabilityName(mutability ast_pb.Mutability, constant bool) string {
+	switch {
+	case constant:
+		return "constant"
+	case mutability == ast_pb.Mutability_IMMUTABLE:
+		return "immutable"
+	default:
+		return "mutable"
+	}
+}
+
+// solcStorageLocationName returns the solc name of the data location of a variable declaration.
+func solcStorageLocationName(location ast_pb.StorageLocation) string {
+	switch location {
+	case ast_pb.StorageLocation_MEMORY:
+		return "memory"
+	case ast_pb.StorageLocation_STORAGE:
+		return "storage"
+	case ast_pb.StorageLocation_CALLDATA:
+		return "calldata"
+	default:
+		return "default"
+	}
+}
+
+// solcLiteralKindName returns the solc kind of a literal.
+func solcLiteralKindName(kind ast_pb.NodeType) string {
+	switch kind {
+	case ast_pb.NodeType_BOOLEAN:
+		return "bool"
+	case ast_pb.NodeType_STRING:
+		return "string"
+	case ast_pb.NodeType_HEX_STRING:
+		return "hexString"
+	case ast_pb.NodeType_UNICODE_STRING_LITERAL:
+		return "unicodeString"
+	default:
+		return "number"
+	}
+}
+
+// solcYulLiteralKindName returns the solc kind of a Yul literal.
+func solcYulLiteralKindName(kind ast_pb.NodeType) string {
+	switch kind {
+	case ast_pb.NodeType_BOOLEAN:
+		return "bool"
+	case ast_pb.NodeType_STRING:
+		return "string"
+	default:
+		return "number"
+	}
+}
@@ -0,0 +1,178 @@
+package ast
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+)
+
+// collectSolcNodesForTest walks decoded solc JSON and returns every object carrying a nodeType.
+func collectSolcNodesForTest(value any, nodes *[]map[string]any) {
+	switch v := value.(type) {
+	case map[string]any:
+		if _, ok := v["nodeType"]; ok {
+			*nodes = append(*nodes, v)
+		}
+		for _, child := range v {
+			collectSolcNodesForTest(child, nodes)
+		}
+	case []any:
+		for _, child := range v {
+			collectSolcNodesForTest(child, nodes)
+		}
+	}
+}
+
+func TestToSolcJSON(t *testing.T) {
+	astBuilder := buildAstFromSourceForTest(t, "Vault", `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.4;
+
+contract Vault {
+    event Deposited(address indexed account, uint256 amount);
+
+    mapping(address => uint256) public balances;
+    address[] public accounts;
+
+    function deposit() external payable {
+        require(msg.value > 0 && msg.sender != address(0), "empty");
+        balances[msg.sender] += msg.value;
+        accounts.push(msg.sender);
+        emit Deposited(msg.sender, msg.value);
+    }
+
+    function total() public view returns (uint256 sum) {
+        for (uint256 i = 0; i < accounts.length; i++) {
+            sum += balances[accounts[i]];
+        }
+    }
+
+    function refund(address account) external {
+        payable(account).transfer(balances[account]);
+        assembly {
+            let size := extcodesize(account)
+        }
+    }
+}
+`)
+
+	data, err := astBuilder.GetRoot().ToSolcJSON()
+	require.NoError(t, err)
+
+	var output map[string]any
+	require.NoError(t, json.Unmarshal(data, &output))
+
+	sources, ok := output["sources"].(map[string]any)
+	require.True(t, ok)
+	require.Len(t, sources, 1)
+
+	nodes := make([]map[string]any, 0)
+	collectSolcNodesForTest(sources, &nodes)
+
+	srcRegex := regexp.MustCompile(`^\d+:\d+:0$`)
+	ids := make(map[float64]bool)
+	nodeTypes := make(map[string]bool)
+	for _, node := range nodes {
+		nodeType := node["nodeType"].(string)
+		nodeTypes[nodeType] = true
+		assert.Regexp(t, srcRegex, node["src"], nodeType)
+
+		// Yul nodes have no ids in solc's output.
+		if nodeType[:3] == "Yul" {
+			assert.NotContains(t, node, "id")
+			continue
+		}
+
+		id, ok := node["id"].(float64)
+		require.True(t, ok, "%s has no id", nodeType)
+		assert.False(t, ids[id], "duplicate id %v on %s", id, nodeType)
+		ids[id] = true
+	}
+
+	for _, nodeType := range []string{
+		"SourceUnit", "PragmaDirective", "ContractDefinition", "EventDefinition", "FunctionDefinition",
+		"VariableDeclaration", "Mapping", "ArrayTypeName", "ExpressionStatement", "BinaryOperation",
+		"Assignment", "EmitStatement", "ForStatement", "VariableDeclarationStatement", "UnaryOperation",
+		"IndexAccess", "MemberAccess", "FunctionCall", "ElementaryTypeNameExpression", "InlineAssembly",
+		"YulBlock", "YulVariableDeclaration", "YulFunctionCall",
+	} {
+		assert.True(t, nodeTypes[nodeType], "missing %s", nodeType)
+	}
+
+	root, err := FromSolcJSON(data)
+	require.NoError(t, err)
+	require.Len(t, root.GetSourceUnits(), 1)
+
+	contract, ok := root.GetSourceUnits()[0].GetContract().(*Contract)
+	require.True(t, ok)
+	assert.Equal(t, "Vault", contract.GetName())
+
+	original := astBuilder.GetRoot().GetSourceUnits()[0].GetContract().(*Contract)
+	assert.Equal(t, original.GetId(), contract.GetId())
+	require.Len(t, contract.GetNodes(), len(original.GetNodes()))
+
+	deposit, ok := contract.GetNodes()[3].(*Function)
+	require.True(t, ok)
+	assert.Equal(t, "deposit", deposit.GetName())
+	assert.Equal(t, ast_pb.Mutability_PAYABLE, deposit.GetStateMutability())
+	assert.Len(t, deposit.GetBody().GetStatements(), 4)
+
+	refund, ok := contract.GetNodes()[5].(*Function)
+	require.True(t, ok)
+	require.Len(t, refund.GetBody().GetStatements(), 2)
+	transfer, ok := refund.GetBody().GetStatements()[0].(*FunctionCall)
+	require.True(t, ok)
+	access, ok := transfer.GetExpression().(*MemberAccessExpression)
+	require.True(t, ok)
+	assert.IsType(t, &PayableConversion{}, access.GetExpression())
+}
+
+func TestToSolcJSONRoundTrip(t *testing.T) {
+	root, err := FromSolcJSON([]byte(solcCounterAST))
+	require.NoError(t, err)
+
+	data, err := root.ToSolcJSON()
+	require.NoError(t, err)
+
+	exported, err := FromSolcJSON(data)
+	require.NoError(t, err)
+	require.Len(t, exported.GetSourceUnits(), 1)
+
+	unit := exported.GetSourceUnits()[0]
+	assert.Equal(t, "Counter.sol", unit.GetAbsolutePath())
+	assert.Equal(t, "MIT", unit.GetLicense())
+	require.Len(t, unit.GetExportedSymbols(), 1)
+	assert.Equal(t, int64(31), unit.GetExportedSymbols()[0].GetId())
+
+	contract, ok := unit.GetContract().(*Contract)
+	require.True(t, ok)
+	assert.Equal(t, int64(31), contract.GetId())
+	require.Len(t, contract.GetNodes(), 3)
+
+	stateVariable, ok := contract.GetNodes()[0].(*StateVariableDeclaration)
+	require.True(t, ok)
+	assert.Equal(t, int64(3), stateVariable.GetId())
+	assert.Equal(t, SrcNode{Start: 80, End: 99, Length: 20, ParentIndex: 31}, stateVariable.GetSrc())
+
+	fn, ok := contract.GetNodes()[2].(*Function)
+	require.True(t, ok)
+	assert.Equal(t, int64(29), fn.GetId())
+	assert.Equal(t, "increment", fn.GetName())
+
+	original := root.GetSourceUnits()[0].GetContract().(*Contract).GetNodes()[2].(*Function)
+	assert.Equal(t, len(original.GetBody().GetStatements()), len(fn.GetBody().GetStatements()))
+	assert.Equal(t, original.GetSignature(), fn.GetSignature())
+
+	assignment, ok := fn.GetBody().GetStatements()[0].(*Assignment)
+	require.True(t, ok)
+	identifier, ok := assignment.GetLeftExpression().(*PrimaryExpression)
+	require.True(t, ok)
+	assert.Equal(t, int64(14), identifier.GetId())
+	assert.Equal(t, int64(3), identifier.GetReferencedDeclaration())
+
+	_, err = (&RootNode{}).ToSolcJSON()
+	assert.Error(t, err)
+}
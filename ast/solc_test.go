@@ -0,0 +1,360 @@
+package ast
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+)
+
+// solcCounterAST is the compact AST solc 0.8 outputs for the following source:
+//
+//	// SPDX-License-Identifier: MIT
+//	pragma solidity ^0.8.0;
+//
+//	contract Counter {
+//	    uint256 public count;
+//	    event Incremented(uint256 value);
+//
+//	    /// @notice Increments the counter.
+//	    function increment(uint256 by) external {
+//	        count += by;
+//	        emit Incremented(count);
+//	        assembly { let x := sload(0) }
+//	    }
+//	}
+const solcCounterAST = `{
+  "absolutePath": "contracts/Counter.sol",
+  "exportedSymbols": {"Counter": [31]},
+  "id": 32,
+  "license": "MIT",
+  "nodeType": "SourceUnit",
+  "nodes": [
+    {"id": 1, "literals": ["solidity", "^", "0.8", ".0"], "nodeType": "PragmaDirective", "src": "32:23:0"},
+    {
+      "abstract": false,
+      "baseContracts": [],
+      "canonicalName": "Counter",
+      "contractDependencies": [],
+      "contractKind": "contract",
+      "fullyImplemented": true,
+      "id": 31,
+      "linearizedBaseContracts": [31],
+      "name": "Counter",
+      "nameLocation": "66:7:0",
+      "nodeType": "ContractDefinition",
+      "nodes": [
+        {
+          "constant": false,
+          "functionSelector": "06661abd",
+          "id": 3,
+          "mutability": "mutable",
+          "name": "count",
+          "nameLocation": "95:5:0",
+          "nodeType": "VariableDeclaration",
+          "scope": 31,
+          "src": "80:20:0",
+          "stateVariable": true,
+          "storageLocation": "default",
+          "typeDescriptions": {"typeIdentifier": "t_uint256", "typeString": "uint256"},
+          "typeName": {"id": 2, "name": "uint256", "nodeType": "ElementaryTypeName", "src": "80:7:0", "typeDescriptions": {"typeIdentifier": "t_uint256", "typeString": "uint256"}},
+          "visibility": "public"
+        },
+        {
+          "anonymous": false,
+          "eventSelector": "20d8a6f5a693f9d1d627a598e8820f7a55ee74c183aa8f1a30e8d4e8dd9a8d84",
+          "id": 7,
+          "name": "Incremented",
+          "nameLocation": "112:11:0",
+          "nodeType": "EventDefinition",
+          "parameters": {
+            "id": 6,
+            "nodeType": "ParameterList",
+            "parameters": [
+              {
+                "constant": false,
+                "id": 5,
+                "indexed": false,
+                "mutability": "mutable",
+                "name": "value",
+                "nameLocation": "132:5:0",
+                "nodeType": "VariableDeclaration",
+                "scope": 7,
+                "src": "124:13:0",
+                "stateVariable": false,
+                "storageLocation": "default",
+                "typeDescriptions": {"typeIdentifier": "t_uint256", "typeString": "uint256"},
+                "typeName": {"id": 4, "name": "uint256", "nodeType": "ElementaryTypeName", "src": "124:7:0", "typeDescriptions": {"typeIdentifier": "t_uint256", "typeString": "uint256"}},
+                "visibility": "internal"
+              }
+            ],
+            "src": "123:15:0"
+          },
+          "src": "106:33:0"
+        },
+        {
+          "body": {
+            "id": 28,
+            "nodeType": "Block",
+            "src": "231:98:0",
+            "statements": [
+              {
+                "expression": {
+                  "id": 16,
+                  "isConstant": false,
+                  "isLValue": false,
+                  "isPure": false,
+                  "lValueRequested": false,
+                  "leftHandSide": {"id": 14, "name": "count", "nodeType": "Identifier", "overloadedDeclarations": [], "referencedDeclaration": 3, "src": "241:5:0", "typeDescriptions": {"typeIdentifier": "t_uint256", "typeString": "uint256"}},
+                  "nodeType": "Assignment",
+                  "operator": "+=",
+                  "rightHandSide": {"id": 15, "name": "by", "nodeType": "Identifier", "overloadedDeclarations": [], "referencedDeclaration": 10, "src": "250:2:0", "typeDescriptions": {"typeIdentifier": "t_uint256", "typeString": "uint256"}},
+                  "src": "241:11:0",
+                  "typeDescriptions": {"typeIdentifier": "t_uint256", "typeString": "uint256"}
+                },
+                "id": 17,
+                "nodeType": "ExpressionStatement",
+                "src": "241:11:0"
+              },
+              {
+                "eventCall": {
+                  "arguments": [
+                    {"id": 19, "name": "count", "nodeType": "Identifier", "overloadedDeclarations": [], "referencedDeclaration": 3, "src": "279:5:0", "typeDescriptions": {"typeIdentifier": "t_uint256", "typeString": "uint256"}}
+                  ],
+                  "expression": {"argumentTypes": [{"typeIdentifier": "t_uint256", "typeString": "uint256"}], "id": 18, "name": "Incremented", "nodeType": "Identifier", "overloadedDeclarations": [], "referencedDeclaration": 7, "src": "267:11:0", "typeDescriptions": {"typeIdentifier": "t_function_event_nonpayable$_t_uint256_$returns$__$", "typeString": "function (uint256)"}},
+                  "id": 20,
+                  "isConstant": false,
+                  "isLValue": false,
+                  "isPure": false,
+                  "kind": "functionCall",
+                  "lValueRequested": false,
+                  "nameLocations": [],
+                  "names": [],
+                  "nodeType": "FunctionCall",
+                  "src": "267:18:0",
+                  "tryCall": false,
+                  "typeDescriptions": {"typeIdentifier": "t_tuple$__$", "typeString": "tuple()"}
+                },
+                "id": 21,
+                "nodeType": "EmitStatement",
+                "src": "262:24:0"
+              },
+              {
+                "AST": {
+                  "nodeType": "YulBlock",
+                  "src": "305:18:0",
+                  "statements": [
+                    {
+                      "nodeType": "YulVariableDeclaration",
+                      "src": "307:14:0",
+                      "value": {
+                        "arguments": [{"kind": "number", "nodeType": "YulLiteral", "src": "319:1:0", "type": "", "value": "0"}],
+                        "functionName": {"name": "sload", "nodeType": "YulIdentifier", "src": "313:5:0"},
+                        "nodeType": "YulFunctionCall",
+                        "src": "313:8:0"
+                      },
+                      "variables": [{"name": "x", "nodeType": "YulTypedName", "src": "311:1:0", "type": ""}]
+                    }
+                  ]
+                },
+                "evmVersion": "paris",
+                "externalReferences": [],
+                "id": 22,
+                "nodeType": "InlineAssembly",
+                "src": "296:27:0"
+              }
+            ]
+          },
+          "documentation": {"id": 8, "nodeType": "StructuredDocumentation", "src": "145:39:0", "text": " @notice Increments the counter."},
+          "functionSelector": "7cf5dab0",
+          "id": 29,
+          "implemented": true,
+          "kind": "function",
+          "modifiers": [],
+          "name": "increment",
+          "nameLocation": "198:9:0",
+          "nodeType": "FunctionDefinition",
+          "parameters": {
+            "id": 11,
+            "nodeType": "ParameterList",
+            "parameters": [
+              {
+                "constant": false,
+                "id": 10,
+                "mutability": "mutable",
+                "name": "by",
+                "nameLocation": "216:2:0",
+                "nodeType": "VariableDeclaration",
+                "scope": 29,
+                "src": "208:10:0",
+                "stateVariable": false,
+                "storageLocation": "default",
+                "typeDescriptions": {"typeIdentifier": "t_uint256", "typeString": "uint256"},
+                "typeName": {"id": 9, "name": "uint256", "nodeType": "ElementaryTypeName", "src": "208:7:0", "typeDescriptions": {"typeIdentifier": "t_uint256", "typeString": "uint256"}},
+                "visibility": "internal"
+              }
+            ],
+            "src": "207:12:0"
+          },
+          "returnParameters": {"id": 12, "nodeType": "ParameterList", "parameters": [], "src": "231:0:0"},
+          "scope": 31,
+          "src": "189:140:0",
+          "stateMutability": "nonpayable",
+          "virtual": false,
+          "visibility": "external"
+        }
+      ],
+      "scope": 32,
+      "src": "57:274:0",
+      "usedErrors": [],
+      "usedEvents": [7]
+    }
+  ],
+  "src": "32:299:0"
+}`
+
+func TestFromSolcJSON(t *testing.T) {
+	root, err := FromSolcJSON([]byte("\n======= contracts/Counter.sol =======\n" + solcCounterAST))
+	require.NoError(t, err)
+	require.Len(t, root.GetSourceUnits(), 1)
+	assert.Empty(t, root.Globals)
+
+	unit := root.GetSourceUnits()[0]
+	assert.Equal(t, "Counter", unit.GetName())
+	assert.Equal(t, "MIT", unit.GetLicense())
+	assert.Equal(t, "Counter.sol", unit.GetAbsolutePath())
+	assert.Equal(t, unit.GetId(), root.GetEntrySourceUnit())
+	assert.Greater(t, unit.GetId(), int64(32), "generated ids must not collide with solc ids")
+	require.Len(t, unit.GetExportedSymbols(), 1)
+	assert.Equal(t, int64(31), unit.GetExportedSymbols()[0].GetId())
+
+	require.Len(t, unit.GetNodes(), 2)
+	pragma, ok := unit.GetNodes()[0].(*Pragma)
+	require.True(t, ok)
+	assert.Equal(t, "pragmasolidity^0.8.0;", pragma.GetText())
+
+	contract, ok := unit.GetContract().(*Contract)
+	require.True(t, ok)
+	assert.Equal(t, int64(31), contract.GetId())
+	assert.Equal(t, "Counter", contract.GetName())
+	assert.Equal(t, ast_pb.NodeType_KIND_CONTRACT, contract.GetKind())
+	require.Len(t, contract.GetNodes(), 3)
+
+	stateVariable, ok := contract.GetNodes()[0].(*StateVariableDeclaration)
+	require.True(t, ok)
+	assert.Equal(t, ast_pb.Visibility_PUBLIC, stateVariable.GetVisibility())
+	assert.Equal(t, "t_uint256", stateVariable.GetTypeDescription().GetIdentifier())
+	assert.Equal(t, SrcNode{Start: 80, End: 99, Length: 20, ParentIndex: 31}, stateVariable.GetSrc())
+
+	event, ok := contract.GetNodes()[1].(*EventDefinition)
+	require.True(t, ok)
+	require.Len(t, event.GetParameters().GetParameters(), 1)
+	assert.Equal(t, "value", event.GetParameters().GetParameters()[0].GetName())
+
+	fn, ok := contract.GetNodes()[2].(*Function)
+	require.True(t, ok)
+	assert.Equal(t, "increment", fn.GetName())
+	assert.Equal(t, ast_pb.Visibility_EXTERNAL, fn.GetVisibility())
+	assert.Equal(t, "increment(uint256)", fn.GetSignatureRaw())
+	require.Len(t, fn.GetBody().GetStatements(), 3)
+
+	assignment, ok := fn.GetBody().GetStatements()[0].(*Assignment)
+	require.True(t, ok)
+	assert.Equal(t, ast_pb.Operator_PLUS_EQUAL, assignment.GetOperator())
+	assert.Equal(t, int64(3), assignment.GetLeftExpression().(*PrimaryExpression).GetReferencedDeclaration())
+	assert.Equal(t, int64(10), assignment.GetRightExpression().(*PrimaryExpression).GetReferencedDeclaration())
+
+	emit, ok := fn.GetBody().GetStatements()[1].(*Emit)
+	require.True(t, ok)
+	assert.Equal(t, int64(7), emit.GetExpression().(*PrimaryExpression).GetReferencedDeclaration())
+	require.Len(t, emit.GetArguments(), 1)
+
+	assembly, ok := fn.GetBody().GetStatements()[2].(*Yul)
+	require.True(t, ok)
+	require.Len(t, assembly.GetBody().GetStatements(), 1)
+	variable, ok := assembly.GetBody().GetStatements()[0].(*YulStatement).GetStatements()[0].(*YulVariable)
+	require.True(t, ok)
+	assert.Equal(t, "x", variable.GetVariables()[0].GetName())
+	assert.Equal(t, "sload", variable.GetValue().(*YulFunctionCallStatement).GetFunctionName().GetName())
+
+	require.Len(t, root.GetComments(), 1)
+	assert.Equal(t, "/// @notice Increments the counter.", root.GetComments()[0].GetText())
+
+	// The imported tree can be walked and queried like a parsed one.
+	identifiers := 0
+	visitor := &NodeVisitor{}
+	visitor.RegisterTypeVisit(ast_pb.NodeType_IDENTIFIER, func(node Node[NodeType]) (bool, error) {
+		identifiers++
+		return true, nil
+	})
+	require.NoError(t, NewTree(nil).WalkNode(fn, visitor))
+	assert.Equal(t, 4, identifiers)
+
+	builder := NewAstBuilder(nil, nil)
+	_, err = builder.ImportFromSolcJSON(context.Background(), []byte(solcCounterAST))
+	require.NoError(t, err)
+	table := builder.GetSymbolTable()
+	require.NotNil(t, table)
+	resolved := table.ResolveIdentifier(16, "count")
+	require.NotNil(t, resolved)
+	assert.Equal(t, int64(3), resolved.GetId())
+
+	proto := builder.ToProto()
+	assert.NotNil(t, proto)
+}
+
+func TestFromSolcJSONFormats(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         string
+		sourceUnits   int
+		expectsErrors bool
+	}{
+		{
+			name:        "Single Source Unit",
+			input:       solcCounterAST,
+			sourceUnits: 1,
+		},
+		{
+			name:        "Source Unit Array",
+			input:       "[" + solcCounterAST + "]",
+			sourceUnits: 1,
+		},
+		{
+			name:        "Standard JSON Output",
+			input:       fmt.Sprintf(`{"contracts": {}, "sources": {"contracts/Counter.sol": {"id": 0, "ast": %s}}}`, solcCounterAST),
+			sourceUnits: 1,
+		},
+		{
+			name:          "Empty Input",
+			input:         "  ",
+			expectsErrors: true,
+		},
+		{
+			name:          "Standard JSON Without Sources",
+			input:         `{"errors": []}`,
+			expectsErrors: true,
+		},
+		{
+			name:          "Unexpected Node",
+			input:         `{"id": 1, "nodeType": "ContractDefinition"}`,
+			expectsErrors: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			root, err := FromSolcJSON([]byte(testCase.input))
+			if testCase.expectsErrors {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, root.GetSourceUnits(), testCase.sourceUnits)
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/antlr4-go/antlr/v4"
+)
+
+// SourceFidelity describes how a node is written in the source, beyond what the AST keeps of it, so that
+// unchanged regions can be reproduced byte for byte.
+type SourceFidelity struct {
+	Text        string `json:"text"`        // Text of the node exactly as written, whitespace and comments included.
+	Leading     string `json:"leading"`     // Whitespace and comments between the preceding token and the node.
+	Trailing    string `json:"trailing"`    // Whitespace and comments between the node and the following token.
+	Parentheses int    `json:"parentheses"` // Number of parentheses wrapping the node, such as 2 for a + b in ((a + b)).
+}
+
+// sourceSnapshot is the input the AST was parsed from, kept when source fidelity is enabled.
+type sourceSnapshot struct {
+	input  []rune       // Parsed input. Offsets of SrcNode index its characters.
+	tokens [][2]int64   // Start and stop offsets of the tokens on the default channel, in order.
+	files  []sourceFile // Files the input is made of, in order.
+}
+
+// sourceFile is the content of a file within the parsed input.
+type sourceFile struct {
+	name  string
+	path  string
+	start int64
+	end   int64 // Offset following the content.
+}
+
+// sourceReplacement is text standing in for the source of a node.
+type sourceReplacement struct {
+	start int64
+	end   int64
+	text  string
+}
+
+// SetSourceFidelity enables or disables keeping the parsed input, so that GetSourceFidelity and ToSource
+// can reproduce the original source of nodes byte for byte. It is disabled by default, as the input has to
+// outlive the parse. Enable it before the parser is released.
+func (b *ASTBuilder) SetSourceFidelity(enabled bool) {
+	b.sourceFidelity = enabled
+	if !enabled {
+		b.snapshot = nil
+		b.replacements = nil
+	}
+}
+
+// HasSourceFidelity reports whether source fidelity is enabled.
+func (b *ASTBuilder) HasSourceFidelity() bool {
+	return b.sourceFidelity
+}
+
+// GetSourceFidelity returns how the node is written in the source, or nil if source fidelity is not
+// enabled or the node is not part of the parsed input.
+func (b *ASTBuilder) GetSourceFidelity(node Node[NodeType]) *SourceFidelity {
+	snapshot := b.getSourceSnapshot()
+	if snapshot == nil || isNilNode(node) {
+		return nil
+	}
+
+	src := node.GetSrc()
+	if !snapshot.contains(src) {
+		return nil
+	}
+
+	file := snapshot.fileAt(src.Start)
+	toReturn := &SourceFidelity{
+		Text: string(snapshot.input[src.Start : src.End+1]),
+	}
+
+	// Trivia is whatever lies between the neighbouring tokens, within the file of the node.
+	next := int64(sort.Search(len(snapshot.tokens), func(i int) bool {
+		return snapshot.tokens[i][0] >= src.Start
+	}))
+	leadingStart := file.start
+	if next > 0 && snapshot.tokens[next-1][1]+1 > leadingStart {
+		leadingStart = snapshot.tokens[next-1][1] + 1
+	}
+	toReturn.Leading = string(snapshot.input[leadingStart:src.Start])
+
+	next = int64(sort.Search(len(snapshot.tokens), func(i int) bool {
+		return snapshot.tokens[i][0] > src.End
+	}))
+	trailingEnd := file.end
+	if next < int64(len(snapshot.tokens)) && snapshot.tokens[next][0] < trailingEnd {
+		trailingEnd = snapshot.tokens[next][0]
+	}
+	toReturn.Trailing = string(snapshot.input[src.End+1 : trailingEnd])
+
+	// Parenthesized expressions are tuples of a single component.
+	for parent := b.GetParent(node); parent != nil; parent = b.GetParent(parent) {
+		tuple, ok := parent.(*TupleExpression)
+		if !ok || len(tuple.GetComponents()) != 1 || !snapshot.contains(tuple.GetSrc()) || snapshot.input[tuple.GetSrc().Start] != '(' {
+			break
+		}
+		toReturn.Parentheses++
+	}
+
+	return toReturn
+}
+
+// ReplaceSource sets the text written in place of the node by ToSource, as automated fixes do. The rest of
+// the source, including the whitespace around the node, is reproduced unchanged. Replacements of nodes
+// within the node are dropped, while ones partially overlapping it are refused.
+func (b *ASTBuilder) ReplaceSource(node Node[NodeType], text string) error {
+	if isNilNode(node) {
+		return errors.New("node is not part of the parsed source")
+	}
+	return b.ReplaceSourceRange(node.GetSrc(), text)
+}
+
+// ReplaceSourceRange sets the text written in place of a range of the source, such as the name location
+// of a function, which no node covers on its own. See ReplaceSource.
+func (b *ASTBuilder) ReplaceSourceRange(src SrcNode, text string) error {
+	snapshot := b.getSourceSnapshot()
+	if snapshot == nil {
+		return errors.New("source fidelity is not enabled, call SetSourceFidelity first")
+	}
+	if !snapshot.contains(src) {
+		return errors.New("range is not part of the parsed source")
+	}
+
+	replacements := make([]*sourceReplacement, 0, len(b.replacements)+1)
+	for _, replacement := range b.replacements {
+		switch {
+		case replacement.start >= src.Start && replacement.end <= src.End:
+			continue
+		case replacement.start <= src.Start && replacement.end >= src.End:
+			return fmt.Errorf("range %d:%d is within replaced range %d:%d", src.Start, src.End, replacement.start, replacement.end)
+		case replacement.start <= src.End && replacement.end >= src.Start:
+			return fmt.Errorf("range %d:%d overlaps replaced range %d:%d", src.Start, src.End, replacement.start, replacement.end)
+		}
+		replacements = append(replacements, replacement)
+	}
+
+	b.replacements = append(replacements, &sourceReplacement{start: src.Start, end: src.End, text: text})
+	return nil
+}
+
+// ToSource returns the source of the node as written, with the replacements set by ReplaceSource applied.
+// Unchanged regions are reproduced byte for byte.
+func (b *ASTBuilder) ToSource(node Node[NodeType]) (string, error) {
+	snapshot := b.getSourceSnapshot()
+	if snapshot == nil {
+		return "", errors.New("source fidelity is not enabled, call SetSourceFidelity first")
+	}
+	if isNilNode(node) || !snapshot.contains(node.GetSrc()) {
+		return "", errors.New("node is not part of the parsed source")
+	}
+	return b.spliceSource(node.GetSrc().Start, node.GetSrc().End+1), nil
+}
+
+// ToSourceFile returns the content of the file with the given name, as in solgo.SourceUnit, with the
+// replacements set by ReplaceSource applied. Unchanged files equal their original content.
+func (b *ASTBuilder) ToSourceFile(name string) (string, error) {
+	snapshot := b.getSourceSnapshot()
+	if snapshot == nil {
+		return "", errors.New("source fidelity is not enabled, call SetSourceFidelity first")
+	}
+	for _, file := range snapshot.files {
+		if file.name == name {
+			return b.spliceSource(file.start, file.end), nil
+		}
+	}
+	return "", fmt.Errorf("file %s is not part of the parsed source", name)
+}
+
+// spliceSource returns the input between the offsets, end excluded, with the replacements within it.
+func (b *ASTBuilder) spliceSource(start int64, end int64) string {
+	replacements := make([]*sourceReplacement, 0)
+	for _, replacement := range b.replacements {
+		if replacement.start >= start && replacement.end < end {
+			replacements = append(replacements, replacement)
+		}
+	}
+	sort.Slice(replacements, func(i, j int) bool {
+		return replacements[i].start < replacements[j].start
+	})
+
+	var toReturn strings.Builder
+	offset := start
+	for _, replacement := range replacements {
+		toReturn.WriteString(string(b.snapshot.input[offset:replacement.start]))
+		toReturn.WriteString(replacement.text)
+		offset = replacement.end + 1
+	}
+	toReturn.WriteString(string(b.snapshot.input[offset:end]))
+	return toReturn.String()
+}
+
+// getSourceSnapshot returns the parsed input, taking it from the parser the first time it is requested.
+func (b *ASTBuilder) getSourceSnapshot() *sourceSnapshot {
+	if !b.sourceFidelity {
+		return nil
+	}
+	if b.snapshot != nil || b.parser == nil {
+		return b.snapshot
+	}
+
+	stream, ok := b.parser.GetTokenStream().(*antlr.CommonTokenStream)
+	if !ok || stream.GetTokenSource() == nil || stream.GetTokenSource().GetInputStream() == nil {
+		return nil
+	}
+
+	inputStream := stream.GetTokenSource().GetInputStream()
+	snapshot := &sourceSnapshot{
+		input:  []rune(inputStream.GetText(0, inputStream.Size()-1)),
+		tokens: make([][2]int64, 0),
+		files:  make([]sourceFile, 0),
+	}
+
+	for _, token := range stream.GetAllTokens() {
+		if token.GetChannel() == antlr.TokenDefaultChannel && token.GetTokenType() != antlr.TokenEOF {
+			snapshot.tokens = append(snapshot.tokens, [2]int64{int64(token.GetStart()), int64(token.GetStop())})
+		}
+	}
+
+	// The input of several files is their content separated by two new lines.
+	if b.sources != nil && b.sources.GetParserInput() == string(snapshot.input) {
+		offset := int64(0)
+		for i, unit := range b.sources.SourceUnits {
+			if i > 0 {
+				offset += 2
+			}
+			length := int64(utf8.RuneCountInString(unit.Content))
+			snapshot.files = append(snapshot.files, sourceFile{name: unit.Name, path: unit.Path, start: offset, end: offset + length})
+			offset += length
+		}
+	}
+
+	b.snapshot = snapshot
+	return b.snapshot
+}
+
+// contains reports whether the source range lies within the parsed input.
+func (s *sourceSnapshot) contains(src SrcNode) bool {
+	return src.Start >= 0 && src.End >= src.Start && src.End < int64(len(s.input))
+}
+
+// fileAt returns the file holding the offset, or the whole input if files are not known.
+func (s *sourceSnapshot) fileAt(offset int64) sourceFile {
+	for _, file := range s.files {
+		if offset >= file.start && offset < file.end {
+			return file
+		}
+	}
+	return sourceFile{start: 0, end: int64(len(s.input))}
+}
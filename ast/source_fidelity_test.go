@@ -0,0 +1,92 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+)
+
+func TestSourceFidelity(t *testing.T) {
+	content := `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.4;
+
+/// @notice Keeps balances.
+contract Ledger {
+    mapping(address => uint256)   public balances;
+
+    function credit(address to, uint256 amount) external {
+        // Credits the account.
+        balances[to] += ((amount  *  2));
+    }
+}
+
+`
+	astBuilder := buildAstFromSourceForTest(t, "Ledger", content)
+	unit := astBuilder.GetRoot().GetSourceUnits()[0]
+
+	_, err := astBuilder.ToSourceFile("Ledger")
+	assert.Error(t, err)
+	assert.Nil(t, astBuilder.GetSourceFidelity(unit))
+
+	astBuilder.SetSourceFidelity(true)
+	assert.True(t, astBuilder.HasSourceFidelity())
+
+	// Unchanged files are reproduced byte for byte.
+	source, err := astBuilder.ToSourceFile("Ledger")
+	require.NoError(t, err)
+	assert.Equal(t, content, source)
+
+	fidelity := astBuilder.GetSourceFidelity(unit)
+	require.NotNil(t, fidelity)
+	assert.True(t, strings.HasPrefix(fidelity.Text, "contract Ledger {"))
+	assert.Equal(t, "\n\n/// @notice Keeps balances.\n", fidelity.Leading)
+	assert.Equal(t, "\n\n", fidelity.Trailing)
+	_, err = astBuilder.ToSourceFile("Missing")
+	assert.Error(t, err)
+
+	var assignment *Assignment
+	for _, node := range collectNodesForTest(astBuilder, ast_pb.NodeType_ASSIGNMENT) {
+		assignment = node.(*Assignment)
+	}
+	require.NotNil(t, assignment)
+
+	fidelity = astBuilder.GetSourceFidelity(assignment)
+	require.NotNil(t, fidelity)
+	assert.Equal(t, "balances[to] += ((amount  *  2))", fidelity.Text)
+	assert.Equal(t, "\n        // Credits the account.\n        ", fidelity.Leading)
+	assert.Equal(t, "", fidelity.Trailing)
+	assert.Equal(t, 0, fidelity.Parentheses)
+
+	product := assignment.GetRightExpression()
+	for {
+		tuple, ok := product.(*TupleExpression)
+		if !ok {
+			break
+		}
+		product = tuple.GetComponents()[0]
+	}
+	fidelity = astBuilder.GetSourceFidelity(product)
+	require.NotNil(t, fidelity)
+	assert.Equal(t, "amount  *  2", fidelity.Text)
+	assert.Equal(t, 2, fidelity.Parentheses)
+
+	// Replacements leave the rest of the source untouched.
+	require.NoError(t, astBuilder.ReplaceSource(product, "amount << 1"))
+	source, err = astBuilder.ToSourceFile("Ledger")
+	require.NoError(t, err)
+	assert.Equal(t, strings.Replace(content, "amount  *  2", "amount << 1", 1), source)
+
+	source, err = astBuilder.ToSource(assignment)
+	require.NoError(t, err)
+	assert.Equal(t, "balances[to] += ((amount << 1))", source)
+
+	// Replacing an enclosing node supersedes the replacements within it.
+	require.NoError(t, astBuilder.ReplaceSource(assignment, "balances[to] += amount"))
+	assert.Error(t, astBuilder.ReplaceSource(product, "amount"))
+	source, err = astBuilder.ToSourceFile("Ledger")
+	require.NoError(t, err)
+	assert.Equal(t, strings.Replace(content, "balances[to] += ((amount  *  2))", "balances[to] += amount", 1), source)
+}
@@ -0,0 +1,228 @@
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// patchContextLines is the number of unchanged lines shown around changes in patches, as in diff -u.
+const patchContextLines = 3
+
+// sourceChange is a run of lines of a file replaced by other lines.
+type sourceChange struct {
+	start    int      // Index of the first line replaced.
+	oldLines []string // Lines replaced, with their line endings.
+	newLines []string // Lines written in their place, with their line endings.
+}
+
+// ToPatch returns the replacements set by ReplaceSource as a unified diff of the files they are in, in
+// the order of the files. Hunks only cover the lines changed and their context, so passes rewriting
+// nodes produce patches as small as their changes. No replacements produce an empty patch.
+func (b *ASTBuilder) ToPatch() (string, error) {
+	snapshot := b.getSourceSnapshot()
+	if snapshot == nil {
+		return "", errors.New("source fidelity is not enabled, call SetSourceFidelity first")
+	}
+
+	var toReturn strings.Builder
+	for _, file := range snapshot.files {
+		toReturn.WriteString(b.filePatch(file))
+	}
+	return toReturn.String(), nil
+}
+
+// ToFilePatch returns the replacements set by ReplaceSource within the file with the given name, as in
+// solgo.SourceUnit, as a unified diff. See ToPatch.
+func (b *ASTBuilder) ToFilePatch(name string) (string, error) {
+	snapshot := b.getSourceSnapshot()
+	if snapshot == nil {
+		return "", errors.New("source fidelity is not enabled, call SetSourceFidelity first")
+	}
+	for _, file := range snapshot.files {
+		if file.name == name {
+			return b.filePatch(file), nil
+		}
+	}
+	return "", fmt.Errorf("file %s is not part of the parsed source", name)
+}
+
+// filePatch returns the unified diff of the replacements within the file.
+func (b *ASTBuilder) filePatch(file sourceFile) string {
+	replacements := make([]*sourceReplacement, 0)
+	for _, replacement := range b.replacements {
+		if replacement.start >= file.start && replacement.end < file.end {
+			replacements = append(replacements, replacement)
+		}
+	}
+	if len(replacements) == 0 {
+		return ""
+	}
+	sort.Slice(replacements, func(i, j int) bool {
+		return replacements[i].start < replacements[j].start
+	})
+
+	// Lines are located by the offset they start at, relative to the start of the file.
+	content := b.snapshot.input[file.start:file.end]
+	lines := make([]string, 0)
+	lineStarts := make([]int64, 0)
+	for start, i := 0, 0; i < len(content); i++ {
+		if content[i] == '\n' || i == len(content)-1 {
+			lineStarts = append(lineStarts, int64(start))
+			lines = append(lines, string(content[start:i+1]))
+			start = i + 1
+		}
+	}
+	lineOf := func(offset int64) int {
+		return sort.Search(len(lineStarts), func(i int) bool {
+			return lineStarts[i] > offset
+		}) - 1
+	}
+	lineEnd := func(line int) int64 {
+		if line+1 < len(lineStarts) {
+			return lineStarts[line+1]
+		}
+		return int64(len(content))
+	}
+
+	// Replacements touching the same lines make a single change.
+	changes := make([]*sourceChange, 0)
+	for i := 0; i < len(replacements); {
+		first := lineOf(replacements[i].start - file.start)
+		last := lineOf(replacements[i].end - file.start)
+		j := i + 1
+		for j < len(replacements) && lineOf(replacements[j].start-file.start) <= last {
+			if end := lineOf(replacements[j].end - file.start); end > last {
+				last = end
+			}
+			j++
+		}
+
+		var written strings.Builder
+		offset := lineStarts[first]
+		for _, replacement := range replacements[i:j] {
+			written.WriteString(string(content[offset : replacement.start-file.start]))
+			written.WriteString(replacement.text)
+			offset = replacement.end - file.start + 1
+		}
+		written.WriteString(string(content[offset:lineEnd(last)]))
+
+		// Lines left as they were are context rather than part of the change.
+		change := &sourceChange{
+			start:    first,
+			oldLines: lines[first : last+1],
+			newLines: splitLines(written.String()),
+		}
+		for len(change.oldLines) > 0 && len(change.newLines) > 0 && change.oldLines[0] == change.newLines[0] {
+			change.start++
+			change.oldLines, change.newLines = change.oldLines[1:], change.newLines[1:]
+		}
+		for len(change.oldLines) > 0 && len(change.newLines) > 0 && change.oldLines[len(change.oldLines)-1] == change.newLines[len(change.newLines)-1] {
+			change.oldLines, change.newLines = change.oldLines[:len(change.oldLines)-1], change.newLines[:len(change.newLines)-1]
+		}
+		if len(change.oldLines) > 0 || len(change.newLines) > 0 {
+			changes = append(changes, change)
+		}
+		i = j
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var toReturn strings.Builder
+	path := file.path
+	if path == "" {
+		path = file.name
+	}
+	fmt.Fprintf(&toReturn, "--- a/%s\n+++ b/%s\n", path, path)
+
+	// Changes whose context overlaps share a hunk.
+	delta := 0
+	for i := 0; i < len(changes); {
+		j := i + 1
+		for j < len(changes) && changes[j].start-(changes[j-1].start+len(changes[j-1].oldLines)) <= 2*patchContextLines {
+			j++
+		}
+
+		last := changes[j-1]
+		from := changes[i].start - patchContextLines
+		if from < 0 {
+			from = 0
+		}
+		to := last.start + len(last.oldLines) + patchContextLines
+		if to > len(lines) {
+			to = len(lines)
+		}
+
+		var hunk strings.Builder
+		oldCount, newCount := 0, 0
+		line := from
+		for _, change := range changes[i:j] {
+			for ; line < change.start; line++ {
+				writePatchLine(&hunk, ' ', lines[line])
+				oldCount++
+				newCount++
+			}
+			for _, removed := range change.oldLines {
+				writePatchLine(&hunk, '-', removed)
+				oldCount++
+			}
+			for _, added := range change.newLines {
+				writePatchLine(&hunk, '+', added)
+				newCount++
+			}
+			line = change.start + len(change.oldLines)
+		}
+		for ; line < to; line++ {
+			writePatchLine(&hunk, ' ', lines[line])
+			oldCount++
+			newCount++
+		}
+
+		fmt.Fprintf(&toReturn, "@@ -%s +%s @@\n", patchRange(from, oldCount), patchRange(from+delta, newCount))
+		toReturn.WriteString(hunk.String())
+
+		for _, change := range changes[i:j] {
+			delta += len(change.newLines) - len(change.oldLines)
+		}
+		i = j
+	}
+
+	return toReturn.String()
+}
+
+// splitLines splits the text into lines, keeping their line endings.
+func splitLines(text string) []string {
+	toReturn := make([]string, 0)
+	for len(text) > 0 {
+		end := strings.IndexByte(text, '\n') + 1
+		if end == 0 {
+			end = len(text)
+		}
+		toReturn = append(toReturn, text[:end])
+		text = text[end:]
+	}
+	return toReturn
+}
+
+// writePatchLine writes a line of a hunk, marking lines without a line ending as diff does.
+func writePatchLine(hunk *strings.Builder, prefix byte, line string) {
+	hunk.WriteByte(prefix)
+	hunk.WriteString(line)
+	if !strings.HasSuffix(line, "\n") {
+		hunk.WriteString("\n\\ No newline at end of file\n")
+	}
+}
+
+// patchRange returns the range of a hunk header for the lines starting at the given index. Empty ranges
+// refer to the line before them, as diff does.
+func patchRange(start int, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
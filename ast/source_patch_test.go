@@ -0,0 +1,85 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+)
+
+func TestToPatch(t *testing.T) {
+	content := `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.4;
+
+contract Ledger {
+    mapping(address => uint256) public balances;
+    uint256 public total;
+
+    function credit(address to, uint256 amount) external {
+        balances[to] += amount * 2;
+        total += amount;
+    }
+
+    function debit(address from, uint256 amount) external {
+        balances[from] -= amount;
+        total -= amount;
+    }
+}`
+	astBuilder := buildAstFromSourceForTest(t, "Ledger", content)
+
+	_, err := astBuilder.ToPatch()
+	assert.Error(t, err)
+
+	astBuilder.SetSourceFidelity(true)
+	patch, err := astBuilder.ToPatch()
+	require.NoError(t, err)
+	assert.Empty(t, patch)
+
+	// Statements wrap their assignments in another one, without a left expression.
+	assignments := make([]*Assignment, 0)
+	for _, node := range collectNodesForTest(astBuilder, ast_pb.NodeType_ASSIGNMENT) {
+		if assignment := node.(*Assignment); assignment.GetLeftExpression() != nil {
+			assignments = append(assignments, assignment)
+		}
+	}
+	require.Len(t, assignments, 4)
+
+	// Replacements with the original text change nothing.
+	require.NoError(t, astBuilder.ReplaceSource(assignments[1], "total += amount"))
+	patch, err = astBuilder.ToPatch()
+	require.NoError(t, err)
+	assert.Empty(t, patch)
+
+	product := assignments[0].GetRightExpression()
+	require.NoError(t, astBuilder.ReplaceSource(product, "amount << 1"))
+	require.NoError(t, astBuilder.ReplaceSource(assignments[3], "total -= amount;\n        emit Debited(from, amount)"))
+
+	patch, err = astBuilder.ToPatch()
+	require.NoError(t, err)
+	assert.Equal(t, "--- a/Ledger.sol\n"+
+		"+++ b/Ledger.sol\n"+
+		"@@ -6,12 +6,13 @@\n"+
+		"     uint256 public total;\n"+
+		" \n"+
+		"     function credit(address to, uint256 amount) external {\n"+
+		"-        balances[to] += amount * 2;\n"+
+		"+        balances[to] += amount << 1;\n"+
+		"         total += amount;\n"+
+		"     }\n"+
+		" \n"+
+		"     function debit(address from, uint256 amount) external {\n"+
+		"         balances[from] -= amount;\n"+
+		"         total -= amount;\n"+
+		"+        emit Debited(from, amount);\n"+
+		"     }\n"+
+		" }\n"+
+		"\\ No newline at end of file\n", patch)
+
+	filePatch, err := astBuilder.ToFilePatch("Ledger")
+	require.NoError(t, err)
+	assert.Equal(t, patch, filePatch)
+
+	_, err = astBuilder.ToFilePatch("Missing")
+	assert.Error(t, err)
+}
@@ -145,6 +145,27 @@ func (v *StateVariableDeclaration) GetInitialValue() Node[NodeType] {
 	return v.InitialValue
 }
 
+// GetGetterSignature returns the signature of the getter solc generates for the state variable when it is
+// public: one parameter per mapping key and a uint256 index per array dimension.
+func (v *StateVariableDeclaration) GetGetterSignature() string {
+	params := make([]string, 0)
+	typeName := v.GetTypeName()
+	for typeName != nil {
+		if typeName.GetKeyType() != nil && typeName.GetValueType() != nil {
+			params = append(params, typeName.GetKeyType().GetName())
+			typeName = typeName.GetValueType()
+			continue
+		}
+
+		for i := 0; i < strings.Count(typeName.GetName(), "["); i++ {
+			params = append(params, "uint256")
+		}
+		break
+	}
+
+	return v.GetName() + "(" + strings.Join(params, ",") + ")"
+}
+
 // ToProto returns the protobuf representation of the state variable declaration.
 func (v *StateVariableDeclaration) ToProto() NodeType {
 	proto := ast_pb.StateVariable{
@@ -0,0 +1,75 @@
+package ast
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/unpackdev/solgo"
+)
+
+// StreamedChunk is the AST of a single chunk of a source, built on its own.
+type StreamedChunk struct {
+	Chunk   *solgo.SourceChunk // Chunk the AST was built from.
+	Builder *ASTBuilder        // Builder holding the resolved AST of the chunk.
+	Errors  []error            // Syntax errors of the chunk and references that could not be resolved within it.
+}
+
+// StreamChunks splits the source into chunks and calls fn with the AST of every chunk in source order.
+// Only the AST of the current chunk is referenced, so sources too large to build in one piece, such as
+// machine generated aggregated routers, are processed with memory bound by their largest definition.
+// Combined with ChunkOptions.LazyBodies, function bodies are left out of the ASTs and can be parsed on
+// demand through the chunk.
+//
+// Node positions are relative to the start of the chunk, and references to definitions of other
+// chunks, such as library calls, are reported as errors of the chunk rather than stopping the stream.
+func StreamChunks(ctx context.Context, content string, opts solgo.ChunkOptions, fn func(streamed *StreamedChunk) error) error {
+	return solgo.ForEachChunk(ctx, content, opts, func(chunk *solgo.SourceChunk) error {
+		streamed, err := BuildChunk(ctx, chunk)
+		if err != nil {
+			return err
+		}
+		return fn(streamed)
+	})
+}
+
+// BuildChunk parses the chunk and builds its AST.
+func BuildChunk(ctx context.Context, chunk *solgo.SourceChunk) (*StreamedChunk, error) {
+	name := chunk.Name
+	if name == "" {
+		name = fmt.Sprintf("Chunk%d", chunk.Index)
+	}
+
+	parser, err := chunk.NewParser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create parser for chunk %s: %w", name, err)
+	}
+
+	sources := &solgo.Sources{
+		SourceUnits: []*solgo.SourceUnit{
+			{
+				Name:    name,
+				Path:    name + ".sol",
+				Content: chunk.GetContent(),
+			},
+		},
+		EntrySourceUnitName: name,
+	}
+
+	builder := NewAstBuilder(parser.GetParser(), sources)
+	if err := parser.RegisterListener(solgo.ListenerAst, builder); err != nil {
+		return nil, fmt.Errorf("failed to register ast listener for chunk %s: %w", name, err)
+	}
+
+	toReturn := &StreamedChunk{
+		Chunk:   chunk,
+		Builder: builder,
+		Errors:  make([]error, 0),
+	}
+
+	for _, syntaxErr := range parser.Parse() {
+		toReturn.Errors = append(toReturn.Errors, syntaxErr.Error())
+	}
+
+	toReturn.Errors = append(toReturn.Errors, builder.ResolveReferences()...)
+	return toReturn, nil
+}
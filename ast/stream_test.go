@@ -0,0 +1,86 @@
+package ast
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+	"github.com/unpackdev/solgo"
+)
+
+const streamedSourceForTest = `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+library Math {
+    function max(uint256 a, uint256 b) internal pure returns (uint256) {
+        return a > b ? a : b;
+    }
+}
+
+contract Base {
+    uint256 public counter;
+}
+
+contract Router is Base {
+    function route(uint256 id) external returns (uint256) {
+        if (id > 1) {
+            return Math.max(id, counter);
+        }
+        return id + 1;
+    }
+
+    function quote(uint256 id) external pure returns (uint256) {
+        return id * 2;
+    }
+}
+`
+
+func TestStreamChunks(t *testing.T) {
+	names := make([]string, 0)
+	err := StreamChunks(context.TODO(), streamedSourceForTest, solgo.ChunkOptions{}, func(streamed *StreamedChunk) error {
+		// Source units are only built for contracts, libraries and interfaces.
+		for _, unit := range streamed.Builder.GetRoot().GetSourceUnits() {
+			names = append(names, unit.GetName())
+		}
+
+		switch streamed.Chunk.Name {
+		case "Router":
+			// Math lives in another chunk.
+			assert.NotEmpty(t, streamed.Errors)
+			functions := collectNodesForTest(streamed.Builder, ast_pb.NodeType_FUNCTION_DEFINITION)
+			require.Len(t, functions, 2)
+			assert.NotEmpty(t, functions[0].(*Function).GetBody().GetStatements())
+		default:
+			assert.Empty(t, streamed.Errors, streamed.Chunk.Name)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Math", "Base", "Router"}, names)
+}
+
+func TestStreamChunksLazyBodies(t *testing.T) {
+	visited := 0
+	err := StreamChunks(context.TODO(), streamedSourceForTest, solgo.ChunkOptions{LazyBodies: true}, func(streamed *StreamedChunk) error {
+		if streamed.Chunk.Name != "Router" {
+			return nil
+		}
+		visited++
+
+		functions := collectNodesForTest(streamed.Builder, ast_pb.NodeType_FUNCTION_DEFINITION)
+		require.Len(t, functions, 2)
+		for _, function := range functions {
+			assert.Empty(t, function.(*Function).GetBody().GetStatements())
+		}
+
+		require.Len(t, streamed.Chunk.Bodies, 2)
+		tree, errs := streamed.Chunk.ParseBody(streamed.Chunk.Bodies[0])
+		assert.Empty(t, errs)
+		assert.Len(t, tree.AllStatement(), 2)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, visited)
+}
@@ -110,6 +110,13 @@ func (t *Tree) byRecursiveReferenceUpdate(child Node[NodeType], nodeId int64, no
 		}
 	}
 
+	// Arguments given to base constructors in inheritance specifiers are not among the contract nodes.
+	for _, argument := range baseContractArguments(child) {
+		if n := t.byRecursiveReferenceUpdate(argument, nodeId, nodeRefId, typeRef); n {
+			return n
+		}
+	}
+
 	return false
 }
 
@@ -140,5 +147,11 @@ func (t *Tree) byRecursiveId(node Node[NodeType], id int64) Node[NodeType] {
 		}
 	}
 
+	for _, argument := range baseContractArguments(node) {
+		if n := t.byRecursiveId(argument, id); n != nil {
+			return n
+		}
+	}
+
 	return nil
 }
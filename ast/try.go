@@ -80,10 +80,14 @@ func (t *TryStatement) GetTypeDescription() *TypeDescription {
 // GetNodes returns the child nodes of the TryStatement node.
 func (t *TryStatement) GetNodes() []Node[NodeType] {
 	toReturn := make([]Node[NodeType], 0)
-	toReturn = append(toReturn, t.Body)
+	if t.Body != nil {
+		toReturn = append(toReturn, t.Body)
+	}
 	toReturn = append(toReturn, t.Expression)
 	toReturn = append(toReturn, t.Clauses...)
-	toReturn = append(toReturn, t.ReturnParameters.GetNodes()...)
+	if t.ReturnParameters != nil {
+		toReturn = append(toReturn, t.ReturnParameters.GetNodes()...)
+	}
 	return toReturn
 }
 
@@ -97,6 +101,48 @@ func (t *TryStatement) GetClauses() []Node[NodeType] {
 	return t.Clauses
 }
 
+// GetCatchClauses returns the catch clauses of the try statement as CatchStatement nodes.
+func (t *TryStatement) GetCatchClauses() []*CatchStatement {
+	toReturn := make([]*CatchStatement, 0, len(t.Clauses))
+	for _, clause := range t.Clauses {
+		if catchClause, ok := clause.(*CatchStatement); ok {
+			toReturn = append(toReturn, catchClause)
+		}
+	}
+	return toReturn
+}
+
+// GetErrorClause returns the 'catch Error(string memory reason)' clause or nil if there is none.
+func (t *TryStatement) GetErrorClause() *CatchStatement {
+	for _, clause := range t.GetCatchClauses() {
+		if clause.IsErrorClause() {
+			return clause
+		}
+	}
+	return nil
+}
+
+// GetPanicClause returns the 'catch Panic(uint errorCode)' clause or nil if there is none.
+func (t *TryStatement) GetPanicClause() *CatchStatement {
+	for _, clause := range t.GetCatchClauses() {
+		if clause.IsPanicClause() {
+			return clause
+		}
+	}
+	return nil
+}
+
+// GetFallbackClause returns the low-level 'catch (bytes memory lowLevelData)' or the bare
+// 'catch { ... }' clause, which handles every error not matched by other clauses, or nil if there is none.
+func (t *TryStatement) GetFallbackClause() *CatchStatement {
+	for _, clause := range t.GetCatchClauses() {
+		if clause.IsLowLevelClause() || clause.IsCatchAll() {
+			return clause
+		}
+	}
+	return nil
+}
+
 // GetReturns returns true if the try statement returns.
 func (t *TryStatement) GetReturns() bool {
 	return t.Returns
@@ -251,6 +297,7 @@ func (t *TryStatement) Parse(
 ) Node[NodeType] {
 	t.Src = SrcNode{
 		Line:        int64(ctx.GetStart().GetLine()),
+		Column:      int64(ctx.GetStart().GetColumn()),
 		Start:       int64(ctx.GetStart().GetStart()),
 		End:         int64(ctx.GetStop().GetStop()),
 		Length:      int64(ctx.GetStop().GetStop() - ctx.GetStart().GetStart() + 1),
@@ -265,14 +312,6 @@ func (t *TryStatement) Parse(
 		bodyNode.ParseBlock(unit, contractNode, t, ctx.Block())
 		t.Body = bodyNode
 
-		if ctx.Block().AllUncheckedBlock() != nil {
-			for _, uncheckedCtx := range ctx.Block().AllUncheckedBlock() {
-				bodyNode := NewBodyNode(t.ASTBuilder, false)
-				bodyNode.ParseUncheckedBlock(unit, contractNode, t, uncheckedCtx)
-				t.Body.Statements = append(t.Body.Statements, bodyNode)
-			}
-		}
-
 		// Very naive implementation check but it works for now until someone starts to complain.
 		if len(bodyNode.GetNodes()) > 0 {
 			t.Implemented = true
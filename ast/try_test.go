@@ -0,0 +1,79 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+)
+
+func TestTryCatchStatement(t *testing.T) {
+	astBuilder := buildAstFromSourceForTest(t, "TryCatch", `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+interface IFeed {
+    function latest() external view returns (uint256);
+}
+
+contract TryCatch {
+    IFeed public feed;
+    string public lastReason;
+    uint256 public lastCode;
+
+    function read() public returns (uint256) {
+        try feed.latest() returns (uint256 value) {
+            return value;
+        } catch Error(string memory reason) {
+            lastReason = reason;
+        } catch Panic(uint256 code) {
+            lastCode = code;
+        } catch (bytes memory) {
+            lastCode = 1;
+        }
+        return 0;
+    }
+
+    function readOrZero() public view returns (uint256) {
+        try feed.latest() returns (uint256 value) {
+            return value;
+        } catch {}
+        return 0;
+    }
+}
+`)
+
+	tries := collectNodesForTest(astBuilder, ast_pb.NodeType_TRY_STATEMENT)
+	require.Len(t, tries, 2)
+
+	tryNode, ok := tries[0].(*TryStatement)
+	require.True(t, ok)
+	assert.True(t, tryNode.GetReturns())
+	assert.Len(t, tryNode.GetCatchClauses(), 3)
+
+	errorClause := tryNode.GetErrorClause()
+	require.NotNil(t, errorClause)
+	assert.Equal(t, "Error(string)", errorClause.GetSignature())
+	assert.NotZero(t, errorClause.GetId())
+	assert.Len(t, errorClause.GetParameters().GetParameters(), 1)
+
+	panicClause := tryNode.GetPanicClause()
+	require.NotNil(t, panicClause)
+	assert.Equal(t, "Panic(uint256)", panicClause.GetSignature())
+	assert.NotEqual(t, errorClause.GetId(), panicClause.GetId())
+
+	fallbackClause := tryNode.GetFallbackClause()
+	require.NotNil(t, fallbackClause)
+	assert.True(t, fallbackClause.IsLowLevelClause())
+	assert.Empty(t, fallbackClause.GetSignature())
+
+	catchAllTry, ok := tries[1].(*TryStatement)
+	require.True(t, ok)
+	require.Len(t, catchAllTry.GetCatchClauses(), 1)
+	assert.True(t, catchAllTry.GetCatchClauses()[0].IsCatchAll())
+	assert.Nil(t, catchAllTry.GetErrorClause())
+
+	assert.NotPanics(t, func() {
+		assert.NotNil(t, astBuilder.ToProto())
+	})
+}
@@ -503,8 +503,14 @@ func (t *TypeName) parseIdentifierPath(unit *SourceUnit[Node[ast_pb.SourceUnit]]
 			NodeType: ast_pb.NodeType_IDENTIFIER_PATH,
 		}
 
+		// Qualified paths such as Library.Struct name the type by their last identifier.
+		name := identifierCtx.GetText()
+		if identifiers := ctx.AllIdentifier(); len(identifiers) > 1 {
+			name = identifiers[len(identifiers)-1].GetText()
+		}
+
 		normalizedTypeName, normalizedTypeIdentifier, found := normalizeTypeDescriptionWithStatus(
-			identifierCtx.GetText(),
+			name,
 		)
 
 		switch normalizedTypeIdentifier {
@@ -520,7 +526,7 @@ func (t *TypeName) parseIdentifierPath(unit *SourceUnit[Node[ast_pb.SourceUnit]]
 				TypeString:     normalizedTypeName,
 			}
 		} else {
-			if refId, refTypeDescription := t.GetResolver().ResolveByNode(t, identifierCtx.GetText()); refTypeDescription != nil {
+			if refId, refTypeDescription := t.GetResolver().ResolveByNode(t, name); refTypeDescription != nil {
 				t.PathNode.ReferencedDeclaration = refId
 				t.ReferencedDeclaration = refId
 				t.TypeDescription = refTypeDescription
@@ -0,0 +1,179 @@
+package ast
+
+import (
+	"github.com/goccy/go-json"
+
+	v3 "github.com/cncf/xds/go/xds/type/v3"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+	"github.com/unpackdev/solgo/parser"
+)
+
+// UncheckedBlock represents an 'unchecked { ... }' block in Solidity.
+// Arithmetic within the block is not checked for overflow and underflow.
+type UncheckedBlock struct {
+	*ASTBuilder
+
+	Id         int64            `json:"id"`         // Unique identifier for the unchecked block.
+	NodeType   ast_pb.NodeType  `json:"node_type"`  // Type of the node, which is 'UNCHECKED_BLOCK'.
+	Src        SrcNode          `json:"src"`        // Source information about the node.
+	Statements []Node[NodeType] `json:"statements"` // Statements within the unchecked block.
+}
+
+// NewUncheckedBlock creates a new UncheckedBlock instance.
+func NewUncheckedBlock(b *ASTBuilder) *UncheckedBlock {
+	return &UncheckedBlock{
+		ASTBuilder: b,
+		Id:         b.GetNextID(),
+		NodeType:   ast_pb.NodeType_UNCHECKED_BLOCK,
+		Statements: make([]Node[NodeType], 0),
+	}
+}
+
+// SetReferenceDescriptor sets the reference descriptions of the UncheckedBlock node.
+func (u *UncheckedBlock) SetReferenceDescriptor(refId int64, refDesc *TypeDescription) bool {
+	return false
+}
+
+// GetId returns the unique identifier of the unchecked block.
+func (u *UncheckedBlock) GetId() int64 {
+	return u.Id
+}
+
+// GetType returns the type of the node, which is 'UNCHECKED_BLOCK'.
+func (u *UncheckedBlock) GetType() ast_pb.NodeType {
+	return u.NodeType
+}
+
+// GetSrc returns the source information about the node.
+func (u *UncheckedBlock) GetSrc() SrcNode {
+	return u.Src
+}
+
+// GetStatements returns the statements within the unchecked block.
+func (u *UncheckedBlock) GetStatements() []Node[NodeType] {
+	return u.Statements
+}
+
+// GetTypeDescription returns the type description of the unchecked block.
+func (u *UncheckedBlock) GetTypeDescription() *TypeDescription {
+	return &TypeDescription{
+		TypeString:     "unchecked",
+		TypeIdentifier: "$_t_unchecked_block",
+	}
+}
+
+// GetNodes returns the statements within the unchecked block.
+func (u *UncheckedBlock) GetNodes() []Node[NodeType] {
+	return u.Statements
+}
+
+// UnmarshalJSON unmarshals a given JSON byte array into an UncheckedBlock node.
+func (u *UncheckedBlock) UnmarshalJSON(data []byte) error {
+	var tempMap map[string]json.RawMessage
+	if err := json.Unmarshal(data, &tempMap); err != nil {
+		return err
+	}
+
+	if id, ok := tempMap["id"]; ok {
+		if err := json.Unmarshal(id, &u.Id); err != nil {
+			return err
+		}
+	}
+
+	if nodeType, ok := tempMap["node_type"]; ok {
+		if err := json.Unmarshal(nodeType, &u.NodeType); err != nil {
+			return err
+		}
+	}
+
+	if src, ok := tempMap["src"]; ok {
+		if err := json.Unmarshal(src, &u.Src); err != nil {
+			return err
+		}
+	}
+
+	u.Statements = make([]Node[NodeType], 0)
+	if statements, ok := tempMap["statements"]; ok {
+		var nodes []json.RawMessage
+		if err := json.Unmarshal(statements, &nodes); err != nil {
+			return err
+		}
+
+		for _, tempNode := range nodes {
+			var tempNodeMap map[string]json.RawMessage
+			if err := json.Unmarshal(tempNode, &tempNodeMap); err != nil {
+				return err
+			}
+
+			var tempNodeType ast_pb.NodeType
+			if err := json.Unmarshal(tempNodeMap["node_type"], &tempNodeType); err != nil {
+				return err
+			}
+
+			node, err := unmarshalNode(tempNode, tempNodeType)
+			if err != nil {
+				return err
+			}
+			u.Statements = append(u.Statements, node)
+		}
+	}
+
+	return nil
+}
+
+// ToProto returns the protobuf representation of the unchecked block.
+func (u *UncheckedBlock) ToProto() NodeType {
+	proto := ast_pb.Body{
+		Id:          u.GetId(),
+		NodeType:    u.GetType(),
+		Kind:        u.GetType(),
+		Implemented: true,
+		Src:         u.GetSrc().ToProto(),
+		Statements:  make([]*v3.TypedStruct, 0),
+	}
+
+	for _, statement := range u.GetStatements() {
+		proto.Statements = append(proto.Statements, statement.ToProto().(*v3.TypedStruct))
+	}
+
+	return NewTypedStruct(&proto, "UncheckedBlock")
+}
+
+// Parse populates the UncheckedBlock fields using the provided parser context.
+// Statements are parsed with the unchecked block as their parent.
+func (u *UncheckedBlock) Parse(
+	unit *SourceUnit[Node[ast_pb.SourceUnit]],
+	contractNode Node[NodeType],
+	fnNode Node[NodeType],
+	bodyNode *BodyNode,
+	ctx parser.IUncheckedBlockContext,
+) Node[NodeType] {
+	u.Src = SrcNode{
+		Line:        int64(ctx.GetStart().GetLine()),
+		Column:      int64(ctx.GetStart().GetColumn()),
+		Start:       int64(ctx.GetStart().GetStart()),
+		End:         int64(ctx.GetStop().GetStop()),
+		Length:      int64(ctx.GetStop().GetStop() - ctx.GetStart().GetStart() + 1),
+		ParentIndex: bodyNode.GetId(),
+	}
+
+	if ctx.Block() == nil {
+		return u
+	}
+
+	// Statements expect an enclosing body, so they are parsed into one sharing the id and
+	// source location of the unchecked block.
+	body := &BodyNode{
+		ASTBuilder:  u.ASTBuilder,
+		Id:          u.GetId(),
+		NodeType:    u.GetType(),
+		Kind:        u.GetType(),
+		Src:         u.Src,
+		Implemented: true,
+		Statements:  make([]Node[NodeType], 0),
+	}
+	body.parseBlockChildren(unit, contractNode, fnNode, ctx.Block())
+	u.Statements = body.Statements
+
+	return u
+}
@@ -0,0 +1,110 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+
+	v3 "github.com/cncf/xds/go/xds/type/v3"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestUncheckedBlock(t *testing.T) {
+	astBuilder := buildAstFromSourceForTest(t, "Counter", `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+contract Counter {
+    uint256 public count;
+
+    function sum(uint256[] memory values) public returns (uint256 total) {
+        total = 1;
+        unchecked {
+            total += values.length;
+        }
+        for (uint256 i = 0; i < values.length;) {
+            total += values[i];
+            unchecked {
+                ++i;
+            }
+        }
+        count = total;
+    }
+}
+`)
+
+	blocks := collectNodesForTest(astBuilder, ast_pb.NodeType_UNCHECKED_BLOCK)
+	require.Len(t, blocks, 2)
+
+	for _, node := range blocks {
+		block, ok := node.(*UncheckedBlock)
+		require.True(t, ok)
+		require.Len(t, block.GetStatements(), 1)
+		assert.Equal(t, block.GetStatements(), block.GetNodes())
+		assert.NotZero(t, block.GetSrc().GetParentIndex())
+	}
+
+	functions := collectNodesForTest(astBuilder, ast_pb.NodeType_FUNCTION_DEFINITION)
+	require.Len(t, functions, 1)
+
+	// The unchecked block keeps its position between the surrounding statements.
+	statements := functions[0].(*Function).GetBody().GetStatements()
+	require.Len(t, statements, 4)
+	assert.Equal(t, ast_pb.NodeType_UNCHECKED_BLOCK, statements[1].GetType())
+	assert.Equal(t, ast_pb.NodeType_FOR_STATEMENT, statements[2].GetType())
+
+	loop := statements[2].(*ForStatement)
+	require.Len(t, loop.GetBody().GetStatements(), 2)
+	assert.Equal(t, blocks[1].GetId(), loop.GetBody().GetStatements()[1].GetId())
+
+	encoded, err := json.Marshal(blocks[0])
+	require.NoError(t, err)
+
+	decoded, err := unmarshalNode(encoded, ast_pb.NodeType_UNCHECKED_BLOCK)
+	require.NoError(t, err)
+	require.IsType(t, &UncheckedBlock{}, decoded)
+	assert.Equal(t, blocks[0].GetId(), decoded.GetId())
+	assert.Len(t, decoded.(*UncheckedBlock).GetStatements(), 1)
+}
+
+func TestUncheckedBlockFromProto(t *testing.T) {
+	astBuilder := buildAstFromSourceForTest(t, "Counter", `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+contract Counter {
+    uint256 public count;
+
+    function increment() public {
+        unchecked {
+            count += 1;
+        }
+    }
+}
+`)
+
+	blocks := collectNodesForTest(astBuilder, ast_pb.NodeType_UNCHECKED_BLOCK)
+	require.Len(t, blocks, 1)
+
+	expected := blocks[0].ToProto().(*v3.TypedStruct)
+	assert.True(t, strings.HasSuffix(expected.GetTypeUrl(), ".UncheckedBlock"))
+
+	node, err := NodeFromProto(expected)
+	require.NoError(t, err)
+
+	block, ok := node.(*UncheckedBlock)
+	require.True(t, ok)
+	assert.Equal(t, blocks[0].GetId(), block.GetId())
+	assert.Len(t, block.GetStatements(), 1)
+	assert.True(t, proto.Equal(expected, block.ToProto().(*v3.TypedStruct)))
+
+	// The body enclosing the unchecked block rebuilds it too.
+	functions := collectNodesForTest(astBuilder, ast_pb.NodeType_FUNCTION_DEFINITION)
+	require.Len(t, functions, 1)
+
+	node, err = NodeFromProto(functions[0].ToProto().(*v3.TypedStruct))
+	require.NoError(t, err)
+	require.Len(t, node.(*Function).GetBody().GetStatements(), 1)
+	assert.IsType(t, &UncheckedBlock{}, node.(*Function).GetBody().GetStatements()[0])
+}
@@ -318,7 +318,7 @@ func unmarshalNode(data []byte, nodeType ast_pb.NodeType) (Node[NodeType], error
 		}
 		return toReturn, nil
 	case ast_pb.NodeType_UNCHECKED_BLOCK:
-		var toReturn *BodyNode
+		var toReturn *UncheckedBlock
 		if err := json.Unmarshal(data, &toReturn); err != nil {
 			return nil, err
 		}
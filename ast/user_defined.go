@@ -1,6 +1,8 @@
 package ast
 
 import (
+	"fmt"
+
 	ast_pb "github.com/unpackdev/protos/dist/go/ast"
 	"github.com/unpackdev/solgo/parser"
 )
@@ -20,6 +22,7 @@ type UserDefinedValueTypeDefinition struct {
 	TypeName              *TypeName        `json:"type_name"`                        // AST node representing the type's name.
 	ReferencedDeclaration int64            `json:"referenced_declaration,omitempty"` // Referenced declaration (if any).
 	TypeDescription       *TypeDescription `json:"type_description"`                 // Description of the type.
+	UnderlyingType        *TypeDescription `json:"underlying_type,omitempty"`        // Description of the underlying elementary type.
 }
 
 // NewUserDefinedValueTypeDefinition creates a new UserDefinedValueTypeDefinition instance.
@@ -65,6 +68,11 @@ func (b *UserDefinedValueTypeDefinition) GetTypeDescription() *TypeDescription {
 	return b.TypeDescription
 }
 
+// GetUnderlyingType returns the type description of the elementary type the user-defined value type wraps.
+func (b *UserDefinedValueTypeDefinition) GetUnderlyingType() *TypeDescription {
+	return b.UnderlyingType
+}
+
 // GetNodes returns the child nodes of the UserDefinedValueTypeDefinition node.
 func (b *UserDefinedValueTypeDefinition) GetNodes() []Node[NodeType] {
 	return []Node[NodeType]{b.TypeName}
@@ -178,9 +186,11 @@ func (b *UserDefinedValueTypeDefinition) Parse(
 		typeName.WithParentNode(contractNode)
 		typeName.ParseElementaryType(unit, nil, b.GetId(), ctx.ElementaryTypeName())
 		b.TypeName = typeName
-		b.TypeDescription = typeName.GetTypeDescription()
+		b.UnderlyingType = typeName.GetTypeDescription()
 	}
 
+	b.TypeDescription = b.buildTypeDescription(contractNode)
+
 	b.currentUserDefinedVariables = append(b.currentUserDefinedVariables, b)
 
 	return b
@@ -237,15 +247,39 @@ func (b *UserDefinedValueTypeDefinition) ParseGlobal(
 		typeName := NewTypeName(b.ASTBuilder)
 		typeName.ParseElementaryType(nil, nil, b.GetId(), ctx.ElementaryTypeName())
 		b.TypeName = typeName
-		b.TypeDescription = typeName.GetTypeDescription()
+		b.UnderlyingType = typeName.GetTypeDescription()
 	}
 
+	b.TypeDescription = b.buildTypeDescription(nil)
+
 	b.currentUserDefinedVariables = append(b.currentUserDefinedVariables, b)
+	b.globalDefinitions = append(b.globalDefinitions, b)
 
 	return b
 }
 
+// buildTypeDescription builds the type description of the user-defined value type the same way solc does.
+// The type string is the canonical name, prefixed with the contract name for contract-level definitions,
+// while the underlying elementary type is kept separately in UnderlyingType.
+func (b *UserDefinedValueTypeDefinition) buildTypeDescription(contractNode Node[NodeType]) *TypeDescription {
+	typeString := b.GetName()
+	if named, ok := contractNode.(interface{ GetName() string }); ok && named.GetName() != "" {
+		typeString = named.GetName() + "." + typeString
+	}
+
+	return &TypeDescription{
+		TypeIdentifier: fmt.Sprintf("t_userDefinedValueType$_%s_$%d", b.GetName(), b.GetId()),
+		TypeString:     typeString,
+	}
+}
+
+// EnterUserDefinedValueTypeDefinition is called when the ASTBuilder enters a file-level user-defined value type
+// definition. Contract-level definitions are handled while parsing the contract body.
 func (b *ASTBuilder) EnterUserDefinedValueTypeDefinition(ctx *parser.UserDefinedValueTypeDefinitionContext) {
+	if _, ok := ctx.GetParent().(*parser.SourceUnitContext); !ok {
+		return
+	}
+
 	child := NewUserDefinedValueTypeDefinition(b)
 	child.ParseGlobal(ctx)
 }
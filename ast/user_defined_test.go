@@ -0,0 +1,63 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+)
+
+func TestUserDefinedValueTypeDefinition(t *testing.T) {
+	astBuilder := buildAstFromSourceForTest(t, "Prices", `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.8;
+
+type Price is uint256;
+
+contract Prices {
+    type Amount is uint128;
+
+    Price public last;
+
+    function set(Price price, Amount amount) public returns (Amount) {
+        last = price;
+        return amount;
+    }
+}
+`)
+
+	var global *UserDefinedValueTypeDefinition
+	for _, node := range astBuilder.GetRoot().GetGlobalNodes() {
+		if udvt, ok := node.(*UserDefinedValueTypeDefinition); ok {
+			require.Nil(t, global, "file-level type must be registered once")
+			global = udvt
+		}
+	}
+	require.NotNil(t, global)
+	assert.Equal(t, "Price", global.GetName())
+	assert.Equal(t, "Price", global.GetTypeDescription().GetString())
+	assert.Equal(t, "t_uint256", global.GetUnderlyingType().GetIdentifier())
+
+	locals := collectNodesForTest(astBuilder, ast_pb.NodeType_USER_DEFINED_VALUE_TYPE)
+	require.Len(t, locals, 1)
+	amount, ok := locals[0].(*UserDefinedValueTypeDefinition)
+	require.True(t, ok)
+	assert.Equal(t, "Prices.Amount", amount.GetTypeDescription().GetString())
+	assert.Contains(t, amount.GetTypeDescription().GetIdentifier(), "t_userDefinedValueType$_Amount_$")
+	assert.Equal(t, "uint128", amount.GetUnderlyingType().GetString())
+
+	contract := astBuilder.GetRoot().GetSourceUnits()[0].GetContract().(*Contract)
+	require.Len(t, contract.GetStateVariables(), 1)
+	assert.Equal(t, global.GetTypeDescription(), contract.GetStateVariables()[0].GetTypeDescription())
+
+	fn := contract.GetFunctions()[0]
+	params := fn.GetParameters().GetParameters()
+	require.Len(t, params, 2)
+	assert.Equal(t, global.GetTypeDescription(), params[0].GetTypeDescription())
+	assert.Equal(t, amount.GetTypeDescription(), params[1].GetTypeDescription())
+	assert.Equal(t, amount.GetTypeDescription(), fn.GetReturnParameters().GetParameters()[0].GetTypeDescription())
+
+	assert.NotPanics(t, func() {
+		assert.NotNil(t, astBuilder.ToProto())
+	})
+}
@@ -56,7 +56,6 @@ func (a *Yul) GetTypeDescription() *TypeDescription {
 }
 
 // ToProto converts the assembly statement into its protobuf representation.
-// Note: Complete implementation is yet to be provided.
 func (a *Yul) ToProto() NodeType {
 	proto := ast_pb.AssemblyStatement{
 		Id:       a.GetId(),
@@ -203,5 +203,22 @@ func ParseYulExpression(
 		)
 	}
 
+	if ctx.YulPath() != nil {
+		path := ctx.YulPath()
+		return &YulIdentifier{
+			Id:       b.GetNextID(),
+			NodeType: ast_pb.NodeType_YUL_IDENTIFIER,
+			Name:     path.GetText(),
+			Src: SrcNode{
+				Line:        int64(path.GetStart().GetLine()),
+				Column:      int64(path.GetStart().GetColumn()),
+				Start:       int64(path.GetStart().GetStart()),
+				End:         int64(path.GetStop().GetStop()),
+				Length:      int64(path.GetStop().GetStop() - path.GetStart().GetStart() + 1),
+				ParentIndex: parentNode.GetId(),
+			},
+		}
+	}
+
 	return nil
 }
@@ -1,6 +1,7 @@
 package ast
 
 import (
+	v3 "github.com/cncf/xds/go/xds/type/v3"
 	"github.com/goccy/go-json"
 
 	ast_pb "github.com/unpackdev/protos/dist/go/ast"
@@ -11,10 +12,12 @@ import (
 type YulSwitchStatement struct {
 	*ASTBuilder // Embedded ASTBuilder for utility functions.
 
-	Id       int64            `json:"id"`        // Id is the unique identifier for the switch statement.
-	NodeType ast_pb.NodeType  `json:"node_type"` // NodeType specifies the type of the node.
-	Src      SrcNode          `json:"src"`       // Src provides source location details of the switch statement.
-	Cases    []Node[NodeType] `json:"cases"`     // Cases holds the different cases of the switch statement.
+	Id         int64            `json:"id"`                // Id is the unique identifier for the switch statement.
+	NodeType   ast_pb.NodeType  `json:"node_type"`         // NodeType specifies the type of the node.
+	Src        SrcNode          `json:"src"`               // Src provides source location details of the switch statement.
+	Expression Node[NodeType]   `json:"expression"`        // Expression is the value the switch statement branches on.
+	Cases      []Node[NodeType] `json:"cases"`             // Cases holds the different cases of the switch statement.
+	Default    Node[NodeType]   `json:"default,omitempty"` // Default holds the default case of the switch statement, if present.
 }
 
 // NewYulSwitchStatement creates and initializes a new YulSwitchStatement.
@@ -51,7 +54,13 @@ func (y *YulSwitchStatement) GetSrc() SrcNode {
 // GetNodes returns a list of nodes associated with the YulSwitchStatement.
 func (y *YulSwitchStatement) GetNodes() []Node[NodeType] {
 	toReturn := make([]Node[NodeType], 0)
+	if y.Expression != nil {
+		toReturn = append(toReturn, y.Expression)
+	}
 	toReturn = append(toReturn, y.Cases...)
+	if y.Default != nil {
+		toReturn = append(toReturn, y.Default)
+	}
 	return toReturn
 }
 
@@ -61,12 +70,24 @@ func (y *YulSwitchStatement) GetTypeDescription() *TypeDescription {
 	return &TypeDescription{}
 }
 
+// GetExpression returns the expression the switch statement branches on.
+func (y *YulSwitchStatement) GetExpression() Node[NodeType] {
+	return y.Expression
+}
+
+// GetCases returns the cases of the switch statement, excluding the default case.
 func (y *YulSwitchStatement) GetCases() []Node[NodeType] {
 	return y.Cases
 }
 
+// GetDefault returns the default case of the switch statement or nil if there is none.
+func (y *YulSwitchStatement) GetDefault() Node[NodeType] {
+	return y.Default
+}
+
 // ToProto converts the YulSwitchStatement into its protobuf representation.
-// Note: This method currently returns an empty Statement.
+// The protos message has neither the switch expression nor the default case, so both are set as
+// the expression and default fields of the returned TypedStruct, as in the JSON representation.
 func (y *YulSwitchStatement) ToProto() NodeType {
 	toReturn := ast_pb.YulSwitchStatement{
 		Id:       y.GetId(),
@@ -79,11 +100,20 @@ func (y *YulSwitchStatement) ToProto() NodeType {
 		toReturn.Cases = append(toReturn.Cases, ycase.ToProto().(*ast_pb.YulSwitchCaseStatement))
 	}
 
-	return NewTypedStruct(&toReturn, "YulSwitchStatement")
+	typed := NewTypedStruct(&toReturn, "YulSwitchStatement")
+
+	if y.GetExpression() != nil {
+		SetTypedStructField(typed, "expression", y.GetExpression().ToProto().(*v3.TypedStruct))
+	}
+
+	if y.GetDefault() != nil {
+		SetTypedStructField(typed, "default", y.GetDefault().ToProto().(*ast_pb.YulSwitchCaseStatement))
+	}
+
+	return typed
 }
 
 // UnmarshalJSON unmarshals a given JSON byte array into a YulSwitchStatement.
-// Currently, this is a placeholder and does nothing.
 func (f *YulSwitchStatement) UnmarshalJSON(data []byte) error {
 	var tempMap map[string]json.RawMessage
 	if err := json.Unmarshal(data, &tempMap); err != nil {
@@ -133,6 +163,46 @@ func (f *YulSwitchStatement) UnmarshalJSON(data []byte) error {
 		}
 	}
 
+	if expression, ok := tempMap["expression"]; ok {
+		if err := json.Unmarshal(expression, &f.Expression); err != nil {
+			var tempNodeMap map[string]json.RawMessage
+			if err := json.Unmarshal(expression, &tempNodeMap); err != nil {
+				return err
+			}
+
+			var tempNodeType ast_pb.NodeType
+			if err := json.Unmarshal(tempNodeMap["node_type"], &tempNodeType); err != nil {
+				return err
+			}
+
+			node, err := unmarshalNode(expression, tempNodeType)
+			if err != nil {
+				return err
+			}
+			f.Expression = node
+		}
+	}
+
+	if defaultCase, ok := tempMap["default"]; ok {
+		if err := json.Unmarshal(defaultCase, &f.Default); err != nil {
+			var tempNodeMap map[string]json.RawMessage
+			if err := json.Unmarshal(defaultCase, &tempNodeMap); err != nil {
+				return err
+			}
+
+			var tempNodeType ast_pb.NodeType
+			if err := json.Unmarshal(tempNodeMap["node_type"], &tempNodeType); err != nil {
+				return err
+			}
+
+			node, err := unmarshalNode(defaultCase, tempNodeType)
+			if err != nil {
+				return err
+			}
+			f.Default = node
+		}
+	}
+
 	return nil
 }
 
@@ -167,5 +237,33 @@ func (y *YulSwitchStatement) Parse(
 		}
 	}
 
+	// Parse the expression the switch statement branches on.
+	if ctx.YulExpression() != nil {
+		y.Expression = ParseYulExpression(
+			y.ASTBuilder, unit, contractNode, fnNode, bodyNode, assemblyNode, statementNode, nil, nil, y,
+			ctx.YulExpression(),
+		)
+	}
+
+	// Parse the default case if present. It is represented as a case without a value.
+	if ctx.YulDefault() != nil && ctx.YulBlock() != nil {
+		defaultCase := NewYulSwitchCaseStatement(y.ASTBuilder)
+		defaultCase.Src = SrcNode{
+			Line:        int64(ctx.YulDefault().GetSymbol().GetLine()),
+			Column:      int64(ctx.YulDefault().GetSymbol().GetColumn()),
+			Start:       int64(ctx.YulDefault().GetSymbol().GetStart()),
+			End:         int64(ctx.YulBlock().GetStop().GetStop()),
+			Length:      int64(ctx.YulBlock().GetStop().GetStop() - ctx.YulDefault().GetSymbol().GetStart() + 1),
+			ParentIndex: y.GetId(),
+		}
+
+		block := NewYulBlockStatement(y.ASTBuilder)
+		defaultCase.Body = block.Parse(
+			unit, contractNode, fnNode, bodyNode, assemblyNode, statementNode, nil, defaultCase,
+			ctx.YulBlock().(*parser.YulBlockContext),
+		)
+		y.Default = defaultCase
+	}
+
 	return y
 }
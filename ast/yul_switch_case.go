@@ -74,7 +74,6 @@ func (y *YulSwitchCaseStatement) GetBody() Node[NodeType] {
 }
 
 // ToProto converts the YulSwitchCaseStatement into its protobuf representation.
-// Note: This method currently returns an empty Statement.
 func (y *YulSwitchCaseStatement) ToProto() NodeType {
 	toReturn := &ast_pb.YulSwitchCaseStatement{
 		Id:       y.GetId(),
@@ -0,0 +1,65 @@
+package ast
+
+import (
+	"testing"
+
+	v3 "github.com/cncf/xds/go/xds/type/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+)
+
+func TestYulSwitchStatement(t *testing.T) {
+	astBuilder := buildAstFromSourceForTest(t, "YulSwitch", `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+contract YulSwitch {
+    function pick(uint256 selector) public pure returns (uint256 result) {
+        assembly {
+            let tmp
+            switch selector
+            case 0 { result := 10 }
+            case 1 { result := 20 }
+            default { result := add(selector, 1) }
+        }
+    }
+}
+`)
+
+	switches := collectNodesForTest(astBuilder, ast_pb.NodeType_YUL_SWITCH)
+	require.Len(t, switches, 1)
+
+	switchNode, ok := switches[0].(*YulSwitchStatement)
+	require.True(t, ok)
+
+	assert.NotNil(t, switchNode.GetExpression())
+	assert.Equal(t, ast_pb.NodeType_YUL_IDENTIFIER, switchNode.GetExpression().GetType())
+	assert.Len(t, switchNode.GetCases(), 2)
+
+	defaultCase, ok := switchNode.GetDefault().(*YulSwitchCaseStatement)
+	require.True(t, ok)
+	assert.Nil(t, defaultCase.GetCase())
+	assert.NotNil(t, defaultCase.GetBody())
+	assert.Equal(t, switchNode.GetId(), defaultCase.GetSrc().GetParentIndex())
+	assert.Len(t, switchNode.GetNodes(), 4)
+
+	proto, ok := switchNode.ToProto().(*v3.TypedStruct)
+	require.True(t, ok)
+	fields := proto.GetValue().GetFields()
+	assert.Len(t, fields["cases"].GetListValue().GetValues(), 2)
+	assert.Equal(t, "selector", fields["expression"].GetStructValue().GetFields()["value"].GetStructValue().GetFields()["name"].GetStringValue())
+	assert.NotNil(t, fields["default"].GetStructValue().GetFields()["body"])
+	assert.Nil(t, fields["default"].GetStructValue().GetFields()["case"])
+
+	restored, err := NodeFromProto(proto)
+	require.NoError(t, err)
+	restoredSwitch, ok := restored.(*YulSwitchStatement)
+	require.True(t, ok)
+	assert.Equal(t, switchNode.GetExpression().GetId(), restoredSwitch.GetExpression().GetId())
+	assert.Equal(t, defaultCase.GetId(), restoredSwitch.GetDefault().GetId())
+	assert.Len(t, restoredSwitch.GetCases(), 2)
+
+	assert.NotPanics(t, func() {
+		assert.NotNil(t, astBuilder.ToProto())
+	})
+}
@@ -85,14 +85,16 @@ func (y *YulVariable) GetVariables() []*YulIdentifier {
 }
 
 // ToProto converts the YulVariable into its protobuf representation.
-// Note: This method currently returns an empty Statement.
 func (y *YulVariable) ToProto() NodeType {
 	toReturn := ast_pb.YulVariableStatement{
 		Id:       y.GetId(),
 		NodeType: y.GetType(),
 		Src:      y.GetSrc().ToProto(),
 		Let:      y.IsLet(),
-		Value:    y.GetValue().ToProto().(*v3.TypedStruct),
+	}
+
+	if y.GetValue() != nil {
+		toReturn.Value = y.GetValue().ToProto().(*v3.TypedStruct)
 	}
 
 	for _, variable := range y.GetVariables() {
@@ -0,0 +1,251 @@
+package audit
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/0x19/solc-switch"
+	"github.com/unpackdev/solgo"
+	"github.com/unpackdev/solgo/syntaxerrors"
+)
+
+// MaxAnnotationsPerRequest is the maximum number of annotations GitHub accepts in a
+// single check run create or update request.
+const MaxAnnotationsPerRequest = 50
+
+// AnnotationLevel represents the severity of a GitHub check run annotation.
+type AnnotationLevel string
+
+// String returns the string representation of the AnnotationLevel.
+func (a AnnotationLevel) String() string {
+	return string(a)
+}
+
+// Annotation levels supported by the GitHub Checks API.
+const (
+	AnnotationNotice  AnnotationLevel = "notice"  // Represents informational annotations.
+	AnnotationWarning AnnotationLevel = "warning" // Represents warning annotations.
+	AnnotationFailure AnnotationLevel = "failure" // Represents annotations that should fail the check.
+)
+
+// Annotation represents a single GitHub check run annotation as expected by the
+// `output.annotations` field of the Checks API.
+type Annotation struct {
+	Path            string          `json:"path"`                   // Path of the file relative to the repository root.
+	StartLine       int             `json:"start_line"`             // Line where the annotation starts.
+	EndLine         int             `json:"end_line"`               // Line where the annotation ends.
+	StartColumn     int             `json:"start_column,omitempty"` // Column where the annotation starts. Only allowed on single line annotations.
+	EndColumn       int             `json:"end_column,omitempty"`   // Column where the annotation ends. Only allowed on single line annotations.
+	AnnotationLevel AnnotationLevel `json:"annotation_level"`       // Severity of the annotation.
+	Message         string          `json:"message"`                // Short description of the finding.
+	Title           string          `json:"title,omitempty"`        // Title of the annotation.
+	RawDetails      string          `json:"raw_details,omitempty"`  // Additional details about the finding.
+}
+
+// ToAnnotations converts the detected issues of the report into GitHub check run annotations.
+// The location of each annotation is taken from the first element of the detector that has
+// source mapping information. Detectors without any source mapping are skipped, as GitHub
+// requires every annotation to point at a file.
+func (r *Report) ToAnnotations() []Annotation {
+	annotations := make([]Annotation, 0)
+	if r == nil || r.Results == nil {
+		return annotations
+	}
+
+	for _, detector := range r.Results.Detectors {
+		element := detector.firstMappedElement()
+		if element == nil {
+			continue
+		}
+
+		mapping := element.SourceMapping
+		annotation := Annotation{
+			Path:            mapping.FilenameRelative,
+			StartLine:       int(mapping.Lines[0]),
+			EndLine:         int(mapping.Lines[len(mapping.Lines)-1]),
+			AnnotationLevel: AnnotationLevelFromImpact(ImpactLevel(detector.Impact)),
+			Message:         strings.TrimSpace(detector.Description),
+			Title:           fmt.Sprintf("%s (%s impact, %s confidence)", detector.Check, detector.Impact, detector.Confidence),
+			RawDetails:      detector.Markdown,
+		}
+
+		if annotation.StartLine == annotation.EndLine {
+			annotation.StartColumn = mapping.StartingColumn
+			annotation.EndColumn = mapping.EndingColumn
+		}
+
+		annotations = append(annotations, annotation)
+	}
+
+	return annotations
+}
+
+// firstMappedElement returns the first element of the detector that carries line information.
+func (d *Detector) firstMappedElement() *Element {
+	for i := range d.Elements {
+		if len(d.Elements[i].SourceMapping.Lines) > 0 {
+			return &d.Elements[i]
+		}
+	}
+	return nil
+}
+
+// AnnotationLevelFromImpact maps an audit impact level onto a GitHub annotation level.
+func AnnotationLevelFromImpact(impact ImpactLevel) AnnotationLevel {
+	switch impact {
+	case ImpactHigh:
+		return AnnotationFailure
+	case ImpactMedium:
+		return AnnotationWarning
+	default:
+		return AnnotationNotice
+	}
+}
+
+// NewAnnotationsFromCompilerErrors converts solc compilation errors and warnings into GitHub
+// check run annotations. Solc reports locations as byte offsets, so the provided sources are
+// used to translate them into lines and columns. Errors referencing a file that cannot be found
+// within the sources, including errors reported against the combined `<stdin>` source, are
+// attributed to the source unit whose part of the combined source holds their offsets. Errors
+// whose offsets fall outside of every source unit are attributed to the first line of the entry
+// source unit, as GitHub rejects the whole batch when an annotation has no valid location.
+func NewAnnotationsFromCompilerErrors(sources *solgo.Sources, errs []solc.CompilationError) []Annotation {
+	annotations := make([]Annotation, 0, len(errs))
+
+	for _, compilationErr := range errs {
+		location := compilationErr.SourceLocation
+		path, content, start, end, found := resolveAnnotationSource(sources, location.File, location.Start, location.End)
+
+		startLine, startColumn := offsetToLineColumn(content, start)
+		endLine, endColumn := offsetToLineColumn(content, end)
+		if !found {
+			startLine, endLine = 1, 1
+		}
+
+		annotation := Annotation{
+			Path:            path,
+			StartLine:       startLine,
+			EndLine:         endLine,
+			AnnotationLevel: annotationLevelFromSeverity(compilationErr.Severity),
+			Message:         strings.TrimSpace(compilationErr.Message),
+			Title:           strings.TrimSpace(fmt.Sprintf("%s %s", compilationErr.Component, compilationErr.Type)),
+			RawDetails:      compilationErr.Formatted,
+		}
+
+		if found && startLine == endLine {
+			annotation.StartColumn = startColumn
+			annotation.EndColumn = endColumn
+		}
+
+		annotations = append(annotations, annotation)
+	}
+
+	return annotations
+}
+
+// NewAnnotationsFromSyntaxErrors converts syntax errors reported by the parser for the file
+// located at path into GitHub check run annotations.
+func NewAnnotationsFromSyntaxErrors(path string, errs []syntaxerrors.SyntaxError) []Annotation {
+	annotations := make([]Annotation, 0, len(errs))
+
+	for _, syntaxErr := range errs {
+		level := AnnotationNotice
+		switch syntaxErr.Severity {
+		case syntaxerrors.SeverityError:
+			level = AnnotationFailure
+		case syntaxerrors.SeverityWarning:
+			level = AnnotationWarning
+		}
+
+		annotations = append(annotations, Annotation{
+			Path:            path,
+			StartLine:       syntaxErr.Line,
+			EndLine:         syntaxErr.Line,
+			StartColumn:     syntaxErr.Column + 1,
+			EndColumn:       syntaxErr.Column + 1,
+			AnnotationLevel: level,
+			Message:         syntaxErr.Message,
+			Title:           fmt.Sprintf("Syntax error in %s", syntaxErr.Context),
+		})
+	}
+
+	return annotations
+}
+
+// ChunkAnnotations splits annotations into batches that respect the GitHub limit of
+// MaxAnnotationsPerRequest annotations per check run request.
+func ChunkAnnotations(annotations []Annotation) [][]Annotation {
+	chunks := make([][]Annotation, 0, (len(annotations)+MaxAnnotationsPerRequest-1)/MaxAnnotationsPerRequest)
+	for start := 0; start < len(annotations); start += MaxAnnotationsPerRequest {
+		end := start + MaxAnnotationsPerRequest
+		if end > len(annotations) {
+			end = len(annotations)
+		}
+		chunks = append(chunks, annotations[start:end])
+	}
+	return chunks
+}
+
+// annotationLevelFromSeverity maps a solc severity onto a GitHub annotation level.
+func annotationLevelFromSeverity(severity string) AnnotationLevel {
+	switch strings.ToLower(severity) {
+	case "error":
+		return AnnotationFailure
+	case "warning":
+		return AnnotationWarning
+	default:
+		return AnnotationNotice
+	}
+}
+
+// resolveAnnotationSource returns the path and content of the source unit referenced by file, along
+// with the start and end offsets within that content. Offsets into the combined source, used when
+// file is not one of the source units, are mapped to the source unit owning them. It reports false
+// when no source unit owns the offsets, in which case the entry source unit is returned.
+func resolveAnnotationSource(sources *solgo.Sources, file string, start int, end int) (string, string, int, int, bool) {
+	if sources == nil {
+		return file, "", start, end, true
+	}
+
+	if unit := sources.GetSourceUnitByPath(file); unit != nil {
+		return unit.Path, unit.Content, start, end, true
+	}
+
+	if unit := sources.GetSourceUnitByName(strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))); unit != nil && file != "" {
+		return unit.Path, unit.Content, start, end, true
+	}
+
+	// The combined source joins the source units with blank lines, see Sources.GetCombinedSource.
+	offset := 0
+	for _, unit := range sources.SourceUnits {
+		if start >= offset && start <= offset+len(unit.Content) {
+			return unit.Path, unit.Content, start - offset, min(max(end-offset, start-offset), len(unit.Content)), true
+		}
+		offset += len(unit.Content) + len("\n\n")
+	}
+
+	if unit := sources.GetSourceUnitByName(sources.EntrySourceUnitName); unit != nil {
+		return unit.Path, unit.Content, 0, 0, false
+	}
+
+	if len(sources.SourceUnits) > 0 {
+		return sources.SourceUnits[0].Path, sources.SourceUnits[0].Content, 0, 0, false
+	}
+
+	return file, "", 0, 0, false
+}
+
+// offsetToLineColumn translates a byte offset within content into a 1-based line and column.
+func offsetToLineColumn(content string, offset int) (int, int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(content) {
+		offset = len(content)
+	}
+
+	line := strings.Count(content[:offset], "\n") + 1
+	column := offset - strings.LastIndex(content[:offset], "\n")
+	return line, column
+}
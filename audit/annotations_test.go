@@ -0,0 +1,185 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/0x19/solc-switch"
+	"github.com/stretchr/testify/assert"
+	"github.com/unpackdev/solgo"
+	"github.com/unpackdev/solgo/syntaxerrors"
+)
+
+func TestReportToAnnotations(t *testing.T) {
+	report := &Report{
+		Success: true,
+		Results: &Results{
+			Detectors: []Detector{
+				{
+					Check:       "reentrancy-eth",
+					Impact:      ImpactHigh.String(),
+					Confidence:  "Medium",
+					Description: "Reentrancy in VulnerableBank.withdraw()\n",
+					Elements: []Element{
+						{
+							Type: "function",
+							Name: "withdraw",
+							SourceMapping: SourceMapping{
+								FilenameRelative: "contracts/VulnerableBank.sol",
+								Lines:            []int32{10, 11, 12},
+								StartingColumn:   5,
+								EndingColumn:     6,
+							},
+						},
+					},
+				},
+				{
+					Check:       "solc-version",
+					Impact:      ImpactInfo.String(),
+					Confidence:  "High",
+					Description: "Pragma version allows old versions",
+					Elements: []Element{
+						{
+							Type: "pragma",
+							SourceMapping: SourceMapping{
+								FilenameRelative: "contracts/VulnerableBank.sol",
+								Lines:            []int32{2},
+								StartingColumn:   1,
+								EndingColumn:     24,
+							},
+						},
+					},
+				},
+				{
+					Check:    "no-location",
+					Impact:   ImpactLow.String(),
+					Elements: []Element{{Type: "contract"}},
+				},
+			},
+		},
+	}
+
+	annotations := report.ToAnnotations()
+	assert.Len(t, annotations, 2)
+
+	assert.Equal(t, "contracts/VulnerableBank.sol", annotations[0].Path)
+	assert.Equal(t, 10, annotations[0].StartLine)
+	assert.Equal(t, 12, annotations[0].EndLine)
+	assert.Equal(t, 0, annotations[0].StartColumn)
+	assert.Equal(t, AnnotationFailure, annotations[0].AnnotationLevel)
+	assert.Equal(t, "Reentrancy in VulnerableBank.withdraw()", annotations[0].Message)
+
+	assert.Equal(t, 2, annotations[1].StartLine)
+	assert.Equal(t, 1, annotations[1].StartColumn)
+	assert.Equal(t, 24, annotations[1].EndColumn)
+	assert.Equal(t, AnnotationNotice, annotations[1].AnnotationLevel)
+
+	var nilReport *Report
+	assert.Empty(t, nilReport.ToAnnotations())
+}
+
+func TestCompilerErrorsToAnnotations(t *testing.T) {
+	sources := &solgo.Sources{
+		SourceUnits: []*solgo.SourceUnit{
+			{
+				Name:    "Token",
+				Path:    "contracts/Token.sol",
+				Content: "pragma solidity ^0.8.0;\ncontract Token {\n    uint x\n}\n",
+			},
+			{
+				Name:    "Math",
+				Path:    "contracts/Math.sol",
+				Content: "pragma solidity ^0.8.0;\nlibrary Math {}\n",
+			},
+		},
+		EntrySourceUnitName: "Token",
+	}
+
+	annotations := NewAnnotationsFromCompilerErrors(sources, []solc.CompilationError{
+		{
+			Component: "general",
+			Severity:  "error",
+			Type:      "ParserError",
+			Message:   "Expected ';' but got '}'",
+			SourceLocation: solc.CompilationErrorSourceLocation{
+				File:  "contracts/Token.sol",
+				Start: 46,
+				End:   53,
+			},
+		},
+		{
+			Severity: "warning",
+			Message:  "Unused local variable.",
+			SourceLocation: solc.CompilationErrorSourceLocation{
+				File:  "<stdin>",
+				Start: 0,
+				End:   6,
+			},
+		},
+		{
+			Severity: "warning",
+			Message:  "Unused library.",
+			SourceLocation: solc.CompilationErrorSourceLocation{
+				File:  "<stdin>",
+				Start: 80,
+				End:   87,
+			},
+		},
+		{
+			Severity: "warning",
+			Message:  "Out of range.",
+			SourceLocation: solc.CompilationErrorSourceLocation{
+				File:  "<stdin>",
+				Start: 500,
+				End:   510,
+			},
+		},
+	})
+
+	assert.Len(t, annotations, 4)
+	assert.Equal(t, "contracts/Token.sol", annotations[0].Path)
+	assert.Equal(t, 3, annotations[0].StartLine)
+	assert.Equal(t, 4, annotations[0].EndLine)
+	assert.Equal(t, AnnotationFailure, annotations[0].AnnotationLevel)
+	assert.Equal(t, "general ParserError", annotations[0].Title)
+
+	assert.Equal(t, "contracts/Token.sol", annotations[1].Path)
+	assert.Equal(t, 1, annotations[1].StartLine)
+	assert.Equal(t, 1, annotations[1].StartColumn)
+	assert.Equal(t, 7, annotations[1].EndColumn)
+	assert.Equal(t, AnnotationWarning, annotations[1].AnnotationLevel)
+
+	// Offsets into the combined source are attributed to the source unit owning them.
+	assert.Equal(t, "contracts/Math.sol", annotations[2].Path)
+	assert.Equal(t, 2, annotations[2].StartLine)
+	assert.Equal(t, 1, annotations[2].StartColumn)
+	assert.Equal(t, 8, annotations[2].EndColumn)
+
+	// Offsets outside of every source unit fall back to the first line of the entry source unit.
+	assert.Equal(t, "contracts/Token.sol", annotations[3].Path)
+	assert.Equal(t, 1, annotations[3].StartLine)
+	assert.Equal(t, 1, annotations[3].EndLine)
+	assert.Zero(t, annotations[3].StartColumn)
+	assert.Zero(t, annotations[3].EndColumn)
+}
+
+func TestSyntaxErrorsToAnnotations(t *testing.T) {
+	annotations := NewAnnotationsFromSyntaxErrors("contracts/Token.sol", []syntaxerrors.SyntaxError{
+		{Line: 3, Column: 10, Message: "missing ';'", Severity: syntaxerrors.SeverityError, Context: "ContractDefinition"},
+	})
+
+	assert.Len(t, annotations, 1)
+	assert.Equal(t, 3, annotations[0].StartLine)
+	assert.Equal(t, 11, annotations[0].StartColumn)
+	assert.Equal(t, AnnotationFailure, annotations[0].AnnotationLevel)
+	assert.Equal(t, "Syntax error in ContractDefinition", annotations[0].Title)
+}
+
+func TestChunkAnnotations(t *testing.T) {
+	annotations := make([]Annotation, MaxAnnotationsPerRequest*2+1)
+
+	chunks := ChunkAnnotations(annotations)
+	assert.Len(t, chunks, 3)
+	assert.Len(t, chunks[0], MaxAnnotationsPerRequest)
+	assert.Len(t, chunks[2], 1)
+	assert.Empty(t, ChunkAnnotations(nil))
+}
@@ -63,7 +63,8 @@ func (a *Auditor) GetSlither() *Slither {
 	return a.slither
 }
 
-// Analyze performs an analysis of the smart contracts using the Slither tool.
+// Analyze performs an analysis of the smart contracts using the Slither tool, along with the
+// standard detectors of solgo, such as the unicode detector, whose issues are added to the results.
 // It returns the analysis response or an error if the analysis fails.
 func (a *Auditor) Analyze() (*Report, error) {
 	response, _, err := a.slither.Analyze(a.sources)
@@ -71,5 +72,10 @@ func (a *Auditor) Analyze() (*Report, error) {
 		return nil, err
 	}
 
+	if response.Results == nil {
+		response.Results = &Results{}
+	}
+	response.Results.Detectors = append(response.Results.Detectors, DetectUnicode(a.sources)...)
+
 	return response, nil
 }
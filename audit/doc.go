@@ -1,4 +1,6 @@
 // Package audit provides a comprehensive suite of tools for auditing
 // smart contracts. It integrates with the Slither static analysis tool
 // to facilitate in-depth contract analysis and ensures best practices in contract development.
+// Standard detectors of its own, such as the detector of bidirectional controls, invisible
+// characters and homoglyphs in identifiers and string literals, report along with Slither.
 package audit
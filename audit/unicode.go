@@ -0,0 +1,280 @@
+package audit
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/unpackdev/solgo"
+)
+
+// CheckUnicode is the check of findings of the unicode detector.
+const CheckUnicode = "suspicious-unicode"
+
+// UnicodeKind classifies a suspicious unicode character.
+type UnicodeKind string
+
+const (
+	UnicodeBidi      UnicodeKind = "bidi"      // Bidirectional control, which reorders how code is displayed.
+	UnicodeInvisible UnicodeKind = "invisible" // Zero width or other format character, which is not displayed.
+	UnicodeHomoglyph UnicodeKind = "homoglyph" // Character looking like an ASCII one.
+	UnicodeNonASCII  UnicodeKind = "non-ascii" // Any other character outside of ASCII, which identifiers never have.
+)
+
+// UnicodeContext is where in the source a suspicious unicode character is.
+type UnicodeContext string
+
+const (
+	UnicodeInIdentifier UnicodeContext = "identifier"
+	UnicodeInString     UnicodeContext = "string"
+)
+
+// UnicodeFinding is a suspicious unicode character in an identifier or string literal, such as those
+// used to make code read differently from how it compiles.
+type UnicodeFinding struct {
+	Path      string         `json:"path"`
+	Offset    int            `json:"offset"` // Byte offset in the source unit, as solc reports them.
+	Length    int            `json:"length"` // Length of the character in bytes.
+	Line      int            `json:"line"`   // Line of the character, 1-based.
+	Column    int            `json:"column"` // Byte column of the character, 1-based.
+	CodePoint string         `json:"code_point"`
+	Name      string         `json:"name,omitempty"`
+	LooksLike string         `json:"looks_like,omitempty"` // ASCII character a homoglyph looks like.
+	Kind      UnicodeKind    `json:"kind"`
+	Context   UnicodeContext `json:"context"`
+}
+
+// unicodeNames are the names of the invisible characters most used to disguise code.
+var unicodeNames = map[rune]string{
+	'\u00ad': "SOFT HYPHEN",
+	'\u061c': "ARABIC LETTER MARK",
+	'\u200b': "ZERO WIDTH SPACE",
+	'\u200c': "ZERO WIDTH NON-JOINER",
+	'\u200d': "ZERO WIDTH JOINER",
+	'\u200e': "LEFT-TO-RIGHT MARK",
+	'\u200f': "RIGHT-TO-LEFT MARK",
+	'\u202a': "LEFT-TO-RIGHT EMBEDDING",
+	'\u202b': "RIGHT-TO-LEFT EMBEDDING",
+	'\u202c': "POP DIRECTIONAL FORMATTING",
+	'\u202d': "LEFT-TO-RIGHT OVERRIDE",
+	'\u202e': "RIGHT-TO-LEFT OVERRIDE",
+	'\u2060': "WORD JOINER",
+	'\u2066': "LEFT-TO-RIGHT ISOLATE",
+	'\u2067': "RIGHT-TO-LEFT ISOLATE",
+	'\u2068': "FIRST STRONG ISOLATE",
+	'\u2069': "POP DIRECTIONAL ISOLATE",
+	'\ufeff': "ZERO WIDTH NO-BREAK SPACE",
+}
+
+// homoglyphs are Cyrillic and Greek letters looking like ASCII ones. Fullwidth forms are handled apart.
+var homoglyphs = map[rune]rune{
+	'\u0430': 'a', '\u0435': 'e', '\u043e': 'o', '\u0440': 'p', '\u0441': 'c', '\u0443': 'y', '\u0445': 'x',
+	'\u0456': 'i', '\u0458': 'j', '\u0455': 's', '\u0501': 'd', '\u04bb': 'h',
+	'\u0410': 'A', '\u0412': 'B', '\u0415': 'E', '\u041a': 'K', '\u041c': 'M', '\u041d': 'H', '\u041e': 'O',
+	'\u0420': 'P', '\u0421': 'C', '\u0422': 'T', '\u0425': 'X', '\u0406': 'I', '\u0408': 'J', '\u0405': 'S',
+	'\u03b1': 'a', '\u03bf': 'o', '\u03bd': 'v', '\u03b9': 'i', '\u03ba': 'k', '\u03c1': 'p', '\u03c5': 'u',
+	'\u0391': 'A', '\u0392': 'B', '\u0395': 'E', '\u0396': 'Z', '\u0397': 'H', '\u0399': 'I', '\u039a': 'K',
+	'\u039c': 'M', '\u039d': 'N', '\u039f': 'O', '\u03a1': 'P', '\u03a4': 'T', '\u03a5': 'Y', '\u03a7': 'X',
+	'\u0131': 'i',
+}
+
+// ScanUnicode returns the bidirectional controls, invisible characters and homoglyphs in identifiers
+// and string literals of the source units, in order of path and offset. Every character outside of
+// ASCII is reported in identifiers, which solc only allows ASCII in, while string literals only report
+// those hiding or disguising text: homoglyphs count only in literals that also have ASCII letters. A
+// byte order mark starting a file and comments are left out.
+func ScanUnicode(sources *solgo.Sources) []*UnicodeFinding {
+	toReturn := make([]*UnicodeFinding, 0)
+	if sources == nil {
+		return toReturn
+	}
+
+	for _, unit := range sources.SourceUnits {
+		path := unit.Path
+		if path == "" {
+			path = unit.Name + ".sol"
+		}
+		toReturn = append(toReturn, scanUnicode(path, unit.Content)...)
+	}
+
+	sort.SliceStable(toReturn, func(i, j int) bool {
+		if toReturn[i].Path != toReturn[j].Path {
+			return toReturn[i].Path < toReturn[j].Path
+		}
+		return toReturn[i].Offset < toReturn[j].Offset
+	})
+
+	return toReturn
+}
+
+// scanUnicode scans the content of a source unit.
+func scanUnicode(path string, content string) []*UnicodeFinding {
+	toReturn := make([]*UnicodeFinding, 0)
+
+	report := func(offset int, r rune, kind UnicodeKind, context UnicodeContext) {
+		line, column := offsetToLineColumn(content, offset)
+		finding := &UnicodeFinding{
+			Path:      path,
+			Offset:    offset,
+			Length:    utf8.RuneLen(r),
+			Line:      line,
+			Column:    column,
+			CodePoint: fmt.Sprintf("U+%04X", r),
+			Name:      unicodeNames[r],
+			Kind:      kind,
+			Context:   context,
+		}
+		if kind == UnicodeHomoglyph {
+			finding.LooksLike = string(homoglyphOf(r))
+		}
+		toReturn = append(toReturn, finding)
+	}
+
+	offset := 0
+	if strings.HasPrefix(content, "\ufeff") {
+		offset = len("\ufeff")
+	}
+
+	for offset < len(content) {
+		switch {
+		case strings.HasPrefix(content[offset:], "//"):
+			end := strings.IndexByte(content[offset:], '\n')
+			if end < 0 {
+				return toReturn
+			}
+			offset += end + 1
+		case strings.HasPrefix(content[offset:], "/*"):
+			end := strings.Index(content[offset+2:], "*/")
+			if end < 0 {
+				return toReturn
+			}
+			offset += end + 4
+		case content[offset] == '"' || content[offset] == '\'':
+			end := stringLiteralEnd(content, offset)
+			literal := content[offset:end]
+			hasLetters := strings.IndexFunc(literal, func(r rune) bool {
+				return r < utf8.RuneSelf && unicode.IsLetter(r)
+			}) >= 0
+			for index, r := range literal {
+				kind, ok := unicodeKindOf(r)
+				if !ok || kind == UnicodeNonASCII || (kind == UnicodeHomoglyph && !hasLetters) {
+					continue
+				}
+				report(offset+index, r, kind, UnicodeInString)
+			}
+			offset = end
+		default:
+			r, size := utf8.DecodeRuneInString(content[offset:])
+			if kind, ok := unicodeKindOf(r); ok {
+				report(offset, r, kind, UnicodeInIdentifier)
+			}
+			offset += size
+		}
+	}
+
+	return toReturn
+}
+
+// stringLiteralEnd returns the offset following the string literal starting at the offset, or the
+// end of the line if the literal is not terminated on it.
+func stringLiteralEnd(content string, offset int) int {
+	quote := content[offset]
+	for i := offset + 1; i < len(content); i++ {
+		switch content[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		case '\n':
+			return i
+		}
+	}
+	return len(content)
+}
+
+// unicodeKindOf returns the kind of the character, or false if it is ASCII.
+func unicodeKindOf(r rune) (UnicodeKind, bool) {
+	switch {
+	case r < utf8.RuneSelf:
+		return "", false
+	case unicode.Is(unicode.Bidi_Control, r):
+		return UnicodeBidi, true
+	case unicode.Is(unicode.Cf, r) || unicode.Is(unicode.Zs, r) || r == utf8.RuneError:
+		return UnicodeInvisible, true
+	case homoglyphOf(r) != 0:
+		return UnicodeHomoglyph, true
+	}
+	return UnicodeNonASCII, true
+}
+
+// homoglyphOf returns the ASCII character the character looks like, or zero if there is none.
+func homoglyphOf(r rune) rune {
+	// Fullwidth forms of ASCII characters are offset from them by a constant.
+	if r >= '\uff01' && r <= '\uff5e' {
+		return r - 0xfee0
+	}
+	return homoglyphs[r]
+}
+
+// impact returns the impact of the finding. Characters hiding or reordering code are high, while
+// homoglyphs in string literals may as well be legitimate text.
+func (f *UnicodeFinding) impact() ImpactLevel {
+	if f.Context == UnicodeInString && f.Kind == UnicodeHomoglyph {
+		return ImpactMedium
+	}
+	if f.Kind == UnicodeNonASCII {
+		return ImpactMedium
+	}
+	return ImpactHigh
+}
+
+// ToDetector converts the finding into a detected issue, as reported along with those of Slither.
+func (f *UnicodeFinding) ToDetector() Detector {
+	description := fmt.Sprintf("%s character %s", f.Kind, f.CodePoint)
+	if f.Name != "" {
+		description += " (" + f.Name + ")"
+	}
+	if f.LooksLike != "" {
+		description += fmt.Sprintf(" looking like %q", f.LooksLike)
+	}
+	description += fmt.Sprintf(" in %s at %s:%d:%d", f.Context, f.Path, f.Line, f.Column)
+
+	return Detector{
+		Elements: []Element{
+			{
+				Type: string(f.Context),
+				Name: f.CodePoint,
+				SourceMapping: SourceMapping{
+					Start:            f.Offset,
+					Length:           f.Length,
+					FilenameRelative: f.Path,
+					FilenameShort:    filepath.Base(f.Path),
+					Lines:            []int32{int32(f.Line)},
+					StartingColumn:   f.Column,
+					EndingColumn:     f.Column + f.Length,
+				},
+			},
+		},
+		Description:          description + "\n",
+		Markdown:             description + "\n",
+		FirstMarkdownElement: fmt.Sprintf("%s#L%d", f.Path, f.Line),
+		ID:                   fmt.Sprintf("%s-%s-%d", CheckUnicode, f.Path, f.Offset),
+		Check:                CheckUnicode,
+		Impact:               f.impact().String(),
+		Confidence:           "High",
+	}
+}
+
+// DetectUnicode runs the unicode detector over the source units and returns its findings as detected
+// issues.
+func DetectUnicode(sources *solgo.Sources) []Detector {
+	findings := ScanUnicode(sources)
+	toReturn := make([]Detector, 0, len(findings))
+	for _, finding := range findings {
+		toReturn = append(toReturn, finding.ToDetector())
+	}
+	return toReturn
+}
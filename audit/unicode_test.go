@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo"
+)
+
+func TestScanUnicode(t *testing.T) {
+	content := "\ufeff// SPDX-License-Identifier: MIT\n" +
+		"pragma solidity ^0.8.0;\n\n" +
+		"// Zähler \u202e comments are left out\n" +
+		"contract Token {\n" +
+		"    address public \u043ewner;\n" +
+		"    string public name = \"T\u043eken\u200b\";\n" +
+		"    string public greeting = \"\u041f\u0440\u0438\u0432\u0435\u0442\";\n" +
+		"    string public note = \"a\\\"\u202e\";\n" +
+		"}\n"
+	sources := &solgo.Sources{
+		SourceUnits: []*solgo.SourceUnit{
+			{Name: "Token", Path: "contracts/Token.sol", Content: content},
+			{Name: "Clean", Content: "contract Clean {}"},
+		},
+	}
+
+	findings := ScanUnicode(sources)
+	require.Len(t, findings, 4)
+
+	owner := findings[0]
+	assert.Equal(t, &UnicodeFinding{
+		Path:      "contracts/Token.sol",
+		Offset:    len("\ufeff// SPDX-License-Identifier: MIT\npragma solidity ^0.8.0;\n\n// Zähler \u202e comments are left out\ncontract Token {\n    address public "),
+		Length:    2,
+		Line:      6,
+		Column:    20,
+		CodePoint: "U+043E",
+		LooksLike: "o",
+		Kind:      UnicodeHomoglyph,
+		Context:   UnicodeInIdentifier,
+	}, owner)
+	assert.Equal(t, "\u043e", content[owner.Offset:owner.Offset+owner.Length])
+
+	assert.Equal(t, UnicodeHomoglyph, findings[1].Kind)
+	assert.Equal(t, UnicodeInString, findings[1].Context)
+	assert.Equal(t, 7, findings[1].Line)
+
+	assert.Equal(t, "U+200B", findings[2].CodePoint)
+	assert.Equal(t, "ZERO WIDTH SPACE", findings[2].Name)
+	assert.Equal(t, UnicodeInvisible, findings[2].Kind)
+
+	// Escaped quotes do not end string literals and text in other scripts is not reported.
+	assert.Equal(t, "U+202E", findings[3].CodePoint)
+	assert.Equal(t, UnicodeBidi, findings[3].Kind)
+	assert.Equal(t, UnicodeInString, findings[3].Context)
+	assert.Equal(t, 9, findings[3].Line)
+
+	detectors := DetectUnicode(sources)
+	require.Len(t, detectors, 4)
+	assert.Equal(t, CheckUnicode, detectors[0].Check)
+	assert.Equal(t, ImpactHigh.String(), detectors[0].Impact)
+	assert.Equal(t, ImpactMedium.String(), detectors[1].Impact)
+	assert.Contains(t, detectors[3].Description, "U+202E (RIGHT-TO-LEFT OVERRIDE) in string at contracts/Token.sol:9")
+
+	annotations := (&Report{Results: &Results{Detectors: detectors}}).ToAnnotations()
+	require.Len(t, annotations, 4)
+	assert.Equal(t, "contracts/Token.sol", annotations[0].Path)
+	assert.Equal(t, 6, annotations[0].StartLine)
+	assert.Equal(t, AnnotationFailure, annotations[0].AnnotationLevel)
+
+	assert.Empty(t, ScanUnicode(nil))
+}
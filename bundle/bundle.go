@@ -0,0 +1,424 @@
+package bundle
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/unpackdev/solgo"
+	"github.com/unpackdev/solgo/compiler"
+	"github.com/unpackdev/solgo/utils"
+)
+
+// Version is the version of the bundle format.
+const Version = 1
+
+// Extension is the file extension of bundles.
+const Extension = ".solgo"
+
+// Media types of artifacts bundles commonly carry.
+const (
+	MediaTypeCompilerOutput = "application/vnd.solc.output+json" // Standard JSON output of solc.
+	MediaTypeArtifact       = "application/vnd.solgo.artifact"   // Encoded artifacts of the artifacts package.
+	MediaTypeJSON           = "application/json"
+)
+
+// CompilerOutputArtifact is the name the compiler output is stored under.
+const CompilerOutputArtifact = "compiler-output"
+
+var (
+	// ErrMalformed is returned when reading data that is not a valid bundle.
+	ErrMalformed = errors.New("malformed bundle")
+
+	// ErrArtifactNotFound is returned when reading an artifact the bundle does not have.
+	ErrArtifactNotFound = errors.New("artifact not found")
+)
+
+// Source is a source unit of the bundle, its content stored as a blob.
+type Source struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Hash string `json:"hash"` // Hash of the content blob.
+}
+
+// Artifact is an artifact of the bundle, its data stored as a blob.
+type Artifact struct {
+	Name      string `json:"name"`
+	MediaType string `json:"media_type"`
+	Hash      string `json:"hash"` // Hash of the data blob.
+}
+
+// Manifest describes the project a bundle holds, referencing its contents by hash.
+type Manifest struct {
+	Version             int              `json:"version"`
+	EntrySourceUnitName string           `json:"entry_source_unit_name"`
+	Sources             []*Source        `json:"sources"`
+	Remappings          []string         `json:"remappings,omitempty"`       // Import remappings, such as @openzeppelin/=lib/openzeppelin/.
+	CompilerVersion     string           `json:"compiler_version,omitempty"` // Version of solc the project compiles with, if known.
+	Settings            *compiler.Config `json:"settings,omitempty"`         // Compilation settings, if known.
+	Artifacts           []*Artifact      `json:"artifacts,omitempty"`        // Sorted by name.
+}
+
+// Bundle is a project packed with its contents, stored as blobs by hash.
+type Bundle struct {
+	Manifest *Manifest         `json:"manifest"`
+	Blobs    map[string][]byte `json:"blobs"`
+}
+
+// HashBlob returns the hex encoded keccak256 hash blobs are addressed by.
+func HashBlob(data []byte) string {
+	return hex.EncodeToString(utils.Keccak256(data))
+}
+
+// NewBundle creates a bundle of the source units.
+func NewBundle(sources *solgo.Sources) (*Bundle, error) {
+	if sources == nil || len(sources.GetUnits()) == 0 {
+		return nil, errors.New("sources must be set")
+	}
+
+	toReturn := &Bundle{
+		Manifest: &Manifest{
+			Version:             Version,
+			EntrySourceUnitName: sources.EntrySourceUnitName,
+			Sources:             make([]*Source, 0, len(sources.GetUnits())),
+			Artifacts:           make([]*Artifact, 0),
+		},
+		Blobs: make(map[string][]byte),
+	}
+
+	for _, unit := range sources.GetUnits() {
+		toReturn.Manifest.Sources = append(toReturn.Manifest.Sources, &Source{
+			Name: unit.GetName(),
+			Path: unit.GetPath(),
+			Hash: toReturn.putBlob([]byte(unit.GetContent())),
+		})
+	}
+
+	return toReturn, nil
+}
+
+// GetManifest returns the manifest of the bundle.
+func (b *Bundle) GetManifest() *Manifest {
+	return b.Manifest
+}
+
+// SetRemappings sets the import remappings of the project, each in the prefix=target form.
+func (b *Bundle) SetRemappings(remappings []string) error {
+	for _, remapping := range remappings {
+		if index := strings.Index(remapping, "="); index <= 0 {
+			return fmt.Errorf("invalid remapping: %s", remapping)
+		}
+	}
+	b.Manifest.Remappings = remappings
+	return nil
+}
+
+// GetRemappings returns the import remappings of the project.
+func (b *Bundle) GetRemappings() []string {
+	return b.Manifest.Remappings
+}
+
+// SetCompilerVersion sets the version of solc the project compiles with.
+func (b *Bundle) SetCompilerVersion(version string) {
+	b.Manifest.CompilerVersion = strings.TrimPrefix(version, "v")
+}
+
+// GetCompilerVersion returns the version of solc the project compiles with, if known.
+func (b *Bundle) GetCompilerVersion() string {
+	return b.Manifest.CompilerVersion
+}
+
+// SetSettings sets the compilation settings of the project.
+func (b *Bundle) SetSettings(settings *compiler.Config) error {
+	if settings != nil {
+		if err := settings.Validate(); err != nil {
+			return err
+		}
+	}
+	b.Manifest.Settings = settings
+	return nil
+}
+
+// GetSettings returns the compilation settings of the project, if known.
+func (b *Bundle) GetSettings() *compiler.Config {
+	return b.Manifest.Settings
+}
+
+// AddArtifact adds the data as an artifact of the media type, replacing any artifact of the name.
+func (b *Bundle) AddArtifact(name string, mediaType string, data []byte) error {
+	if name == "" {
+		return errors.New("artifact name must be set")
+	}
+
+	artifacts := make([]*Artifact, 0, len(b.Manifest.Artifacts)+1)
+	for _, artifact := range b.Manifest.Artifacts {
+		if artifact.Name != name {
+			artifacts = append(artifacts, artifact)
+		}
+	}
+	artifacts = append(artifacts, &Artifact{Name: name, MediaType: mediaType, Hash: b.putBlob(data)})
+	sort.Slice(artifacts, func(i, j int) bool {
+		return artifacts[i].Name < artifacts[j].Name
+	})
+
+	b.Manifest.Artifacts = artifacts
+	b.prune()
+	return nil
+}
+
+// GetArtifacts returns the artifacts of the bundle, sorted by name.
+func (b *Bundle) GetArtifacts() []*Artifact {
+	return b.Manifest.Artifacts
+}
+
+// GetArtifact returns the data of the artifact with the name.
+func (b *Bundle) GetArtifact(name string) ([]byte, error) {
+	for _, artifact := range b.Manifest.Artifacts {
+		if artifact.Name == name {
+			return b.getBlob(artifact.Hash)
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrArtifactNotFound, name)
+}
+
+// SetCompilerOutput adds the standard JSON output of solc as the CompilerOutputArtifact, and sets the
+// compiler version from it if it is not set.
+func (b *Bundle) SetCompilerOutput(output *compiler.Output) error {
+	if output == nil {
+		return errors.New("compiler output must be set")
+	}
+
+	encoded, err := json.Marshal(output)
+	if err != nil {
+		return err
+	}
+
+	if b.Manifest.CompilerVersion == "" {
+		b.SetCompilerVersion(output.Version)
+	}
+	return b.AddArtifact(CompilerOutputArtifact, MediaTypeCompilerOutput, encoded)
+}
+
+// GetCompilerOutput returns the standard JSON output of solc stored as the CompilerOutputArtifact.
+func (b *Bundle) GetCompilerOutput() (*compiler.Output, error) {
+	encoded, err := b.GetArtifact(CompilerOutputArtifact)
+	if err != nil {
+		return nil, err
+	}
+
+	toReturn := &compiler.Output{}
+	if err := json.Unmarshal(encoded, toReturn); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMalformed, err)
+	}
+	return toReturn, nil
+}
+
+// GetSources returns the source units of the bundle, not prepared.
+func (b *Bundle) GetSources() (*solgo.Sources, error) {
+	toReturn := &solgo.Sources{
+		SourceUnits:         make([]*solgo.SourceUnit, 0, len(b.Manifest.Sources)),
+		EntrySourceUnitName: b.Manifest.EntrySourceUnitName,
+	}
+
+	for _, source := range b.Manifest.Sources {
+		content, err := b.getBlob(source.Hash)
+		if err != nil {
+			return nil, err
+		}
+		toReturn.SourceUnits = append(toReturn.SourceUnits, &solgo.SourceUnit{
+			Name:    source.Name,
+			Path:    source.Path,
+			Content: string(content),
+		})
+	}
+
+	return toReturn, nil
+}
+
+// ToInput returns the standard JSON input compiling the project with its remappings and settings,
+// selecting the DefaultOutputs of every contract.
+func (b *Bundle) ToInput() (*compiler.Input, error) {
+	sources, err := b.GetSources()
+	if err != nil {
+		return nil, err
+	}
+
+	toReturn, err := compiler.NewInputFromSources(sources)
+	if err != nil {
+		return nil, err
+	}
+	toReturn.Settings.Remappings = b.Manifest.Remappings
+
+	if b.Manifest.Settings != nil {
+		if err := b.Manifest.Settings.Apply(toReturn); err != nil {
+			return nil, err
+		}
+	}
+
+	return toReturn, nil
+}
+
+// GetID returns the hash of the manifest, which references every blob by hash, so bundles of the same
+// project with the same contents share their identifier.
+func (b *Bundle) GetID() (string, error) {
+	encoded, err := json.Marshal(b.Manifest)
+	if err != nil {
+		return "", err
+	}
+	return HashBlob(encoded), nil
+}
+
+// Validate checks the version of the bundle and that every blob the manifest references is present
+// and matches its hash.
+func (b *Bundle) Validate() error {
+	if b.Manifest == nil {
+		return fmt.Errorf("%w: missing manifest", ErrMalformed)
+	}
+
+	if b.Manifest.Version != Version {
+		return fmt.Errorf("%w: unsupported version %d", ErrMalformed, b.Manifest.Version)
+	}
+
+	if len(b.Manifest.Sources) == 0 {
+		return fmt.Errorf("%w: no sources", ErrMalformed)
+	}
+
+	for _, hash := range b.references() {
+		if _, err := b.getBlob(hash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Encode writes the bundle, gzip compressed JSON, to the writer. Blobs no longer referenced are left
+// out, and equal bundles encode to equal bytes.
+func (b *Bundle) Encode(w io.Writer) error {
+	if err := b.Validate(); err != nil {
+		return err
+	}
+	b.prune()
+
+	encoded, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+
+	// The header is left without name and modification time, so the encoding only depends on contents.
+	writer := gzip.NewWriter(w)
+	if _, err := writer.Write(encoded); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// Bytes returns the encoded bundle.
+func (b *Bundle) Bytes() ([]byte, error) {
+	var toReturn bytes.Buffer
+	if err := b.Encode(&toReturn); err != nil {
+		return nil, err
+	}
+	return toReturn.Bytes(), nil
+}
+
+// WriteFile writes the encoded bundle to the file, which conventionally has the Extension.
+func (b *Bundle) WriteFile(path string) error {
+	encoded, err := b.Bytes()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, encoded, 0600)
+}
+
+// Decode reads a bundle from the reader, verifying every blob against its hash.
+func Decode(r io.Reader) (*Bundle, error) {
+	reader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMalformed, err)
+	}
+	defer reader.Close()
+
+	encoded, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMalformed, err)
+	}
+
+	toReturn := &Bundle{}
+	if err := json.Unmarshal(encoded, toReturn); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMalformed, err)
+	}
+
+	if toReturn.Blobs == nil {
+		toReturn.Blobs = make(map[string][]byte)
+	}
+
+	if err := toReturn.Validate(); err != nil {
+		return nil, err
+	}
+
+	return toReturn, nil
+}
+
+// ReadFile reads the bundle of the file, verifying every blob against its hash.
+func ReadFile(path string) (*Bundle, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Decode(file)
+}
+
+// putBlob stores the data under its hash and returns the hash.
+func (b *Bundle) putBlob(data []byte) string {
+	hash := HashBlob(data)
+	if _, ok := b.Blobs[hash]; !ok {
+		b.Blobs[hash] = append([]byte{}, data...)
+	}
+	return hash
+}
+
+// getBlob returns the data stored under the hash, verifying it matches the hash.
+func (b *Bundle) getBlob(hash string) ([]byte, error) {
+	data, ok := b.Blobs[hash]
+	if !ok {
+		return nil, fmt.Errorf("%w: missing blob %s", ErrMalformed, hash)
+	}
+	if HashBlob(data) != hash {
+		return nil, fmt.Errorf("%w: blob %s does not match its hash", ErrMalformed, hash)
+	}
+	return data, nil
+}
+
+// references returns the hashes of the blobs the manifest references.
+func (b *Bundle) references() []string {
+	toReturn := make([]string, 0, len(b.Manifest.Sources)+len(b.Manifest.Artifacts))
+	for _, source := range b.Manifest.Sources {
+		toReturn = append(toReturn, source.Hash)
+	}
+	for _, artifact := range b.Manifest.Artifacts {
+		toReturn = append(toReturn, artifact.Hash)
+	}
+	return toReturn
+}
+
+// prune removes the blobs the manifest no longer references, such as those of replaced artifacts.
+func (b *Bundle) prune() {
+	referenced := make(map[string]bool)
+	for _, hash := range b.references() {
+		referenced[hash] = true
+	}
+	for hash := range b.Blobs {
+		if !referenced[hash] {
+			delete(b.Blobs, hash)
+		}
+	}
+}
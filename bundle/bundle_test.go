@@ -0,0 +1,143 @@
+package bundle
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo"
+	"github.com/unpackdev/solgo/compiler"
+)
+
+func sourcesForTest() *solgo.Sources {
+	return &solgo.Sources{
+		SourceUnits: []*solgo.SourceUnit{
+			{
+				Name: "Vault",
+				Path: "contracts/Vault.sol",
+				Content: `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.19;
+
+import "@openzeppelin/contracts/access/Ownable.sol";
+
+contract Vault is Ownable {
+    uint256 public total;
+}
+`,
+			},
+			{
+				Name:    "Ownable",
+				Path:    "lib/openzeppelin/contracts/access/Ownable.sol",
+				Content: "// SPDX-License-Identifier: MIT\npragma solidity ^0.8.19;\n\nabstract contract Ownable {}\n",
+			},
+			{
+				Name:    "Copy",
+				Path:    "contracts/Copy.sol",
+				Content: "// SPDX-License-Identifier: MIT\npragma solidity ^0.8.19;\n\nabstract contract Ownable {}\n",
+			},
+		},
+		EntrySourceUnitName: "Vault",
+	}
+}
+
+func TestBundle(t *testing.T) {
+	_, err := NewBundle(&solgo.Sources{})
+	assert.Error(t, err)
+
+	bundle, err := NewBundle(sourcesForTest())
+	require.NoError(t, err)
+	assert.Len(t, bundle.Blobs, 2, "equal contents are stored once")
+	assert.Equal(t, bundle.GetManifest().Sources[1].Hash, bundle.GetManifest().Sources[2].Hash)
+
+	assert.Error(t, bundle.SetRemappings([]string{"=lib/"}))
+	require.NoError(t, bundle.SetRemappings([]string{"@openzeppelin/=lib/openzeppelin/"}))
+	assert.Error(t, bundle.SetSettings(&compiler.Config{OptimizerRuns: -1}))
+	require.NoError(t, bundle.SetSettings(&compiler.Config{Optimized: true, OptimizerRuns: 1000, EVMVersion: "paris"}))
+
+	output := &compiler.Output{
+		Version:   "0.8.19",
+		Contracts: map[string]map[string]*compiler.Contract{"contracts/Vault.sol": {"Vault": {ABI: []byte(`[]`)}}},
+	}
+	require.NoError(t, bundle.SetCompilerOutput(output))
+	assert.Equal(t, "0.8.19", bundle.GetCompilerVersion())
+	require.NoError(t, bundle.AddArtifact("notes", MediaTypeJSON, []byte(`{"ticket":42}`)))
+	require.NoError(t, bundle.AddArtifact("notes", MediaTypeJSON, []byte(`{"ticket":43}`)))
+	require.Len(t, bundle.GetArtifacts(), 2)
+	assert.Equal(t, CompilerOutputArtifact, bundle.GetArtifacts()[0].Name)
+	assert.Len(t, bundle.Blobs, 4, "replaced artifacts are pruned")
+	assert.Error(t, bundle.AddArtifact("", MediaTypeJSON, nil))
+
+	id, err := bundle.GetID()
+	require.NoError(t, err)
+	assert.Len(t, id, 64)
+
+	path := filepath.Join(t.TempDir(), "vault"+Extension)
+	require.NoError(t, bundle.WriteFile(path))
+	read, err := ReadFile(path)
+	require.NoError(t, err)
+
+	readID, err := read.GetID()
+	require.NoError(t, err)
+	assert.Equal(t, id, readID)
+
+	encoded, err := bundle.Bytes()
+	require.NoError(t, err)
+	reencoded, err := read.Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, encoded, reencoded, "equal bundles encode to equal bytes")
+
+	sources, err := read.GetSources()
+	require.NoError(t, err)
+	assert.Equal(t, sourcesForTest(), sources)
+
+	notes, err := read.GetArtifact("notes")
+	require.NoError(t, err)
+	assert.Equal(t, `{"ticket":43}`, string(notes))
+	_, err = read.GetArtifact("missing")
+	assert.ErrorIs(t, err, ErrArtifactNotFound)
+
+	restored, err := read.GetCompilerOutput()
+	require.NoError(t, err)
+	assert.Equal(t, "[]", restored.GetContract("contracts/Vault.sol", "Vault").GetABI())
+
+	input, err := read.ToInput()
+	require.NoError(t, err)
+	assert.Len(t, input.Sources, 3)
+	assert.Equal(t, []string{"@openzeppelin/=lib/openzeppelin/"}, input.Settings.Remappings)
+	assert.Equal(t, compiler.Optimizer{Enabled: true, Runs: 1000}, input.Settings.Optimizer)
+	assert.Equal(t, "paris", input.Settings.EVMVersion)
+
+	// The same sources without the settings and artifacts are another bundle.
+	other, err := NewBundle(sourcesForTest())
+	require.NoError(t, err)
+	otherID, err := other.GetID()
+	require.NoError(t, err)
+	assert.NotEqual(t, id, otherID)
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	_, err := Decode(bytes.NewReader([]byte("not a bundle")))
+	assert.ErrorIs(t, err, ErrMalformed)
+
+	bundle, err := NewBundle(sourcesForTest())
+	require.NoError(t, err)
+
+	// Blobs not matching their hash are rejected.
+	hash := bundle.GetManifest().Sources[0].Hash
+	bundle.Blobs[hash] = []byte("contract Tampered {}")
+	_, err = bundle.Bytes()
+	assert.ErrorIs(t, err, ErrMalformed)
+	_, err = bundle.GetSources()
+	assert.ErrorIs(t, err, ErrMalformed)
+
+	delete(bundle.Blobs, hash)
+	assert.ErrorIs(t, bundle.Validate(), ErrMalformed)
+
+	bundle, err = NewBundle(sourcesForTest())
+	require.NoError(t, err)
+	bundle.Manifest.Version = Version + 1
+	_, err = bundle.Bytes()
+	assert.ErrorIs(t, err, ErrMalformed)
+}
@@ -0,0 +1,10 @@
+/*
+Package bundle packs complete analyzable projects into single .solgo files: the source units, the
+import remappings, the compilation settings and compiler version, and optional artifacts such as
+compiler outputs or protocol buffer exports. Bundles are content addressed. Every source and artifact
+is stored once as a blob keyed by its keccak256 hash, the manifest references blobs by hash, and the
+bundle is identified by the hash of its manifest, so identical projects share an identifier and
+reading a bundle verifies every blob against its hash. Bundles move projects between ticketing systems,
+pipelines and archives as one object, and restore into sources or a standard JSON input.
+*/
+package bundle
@@ -0,0 +1,134 @@
+package bytecode
+
+import (
+	"bytes"
+	"sort"
+
+	abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AmbiguityReason describes why several methods of an ABI share the selector of the decoded data.
+type AmbiguityReason string
+
+const (
+	// AmbiguityGetterOverride is reported when a public state variable getter and a declared function have the
+	// same signature, as when a state variable overrides an interface function. Solc exposes the getter.
+	AmbiguityGetterOverride AmbiguityReason = "getter_override"
+
+	// AmbiguityDuplicate is reported when the ABI lists the same function more than once, as merged ABIs of
+	// a contract and its interfaces do.
+	AmbiguityDuplicate AmbiguityReason = "duplicate"
+
+	// AmbiguityCollision is reported when functions with different signatures hash to the same selector.
+	// Solc rejects such contracts, so the functions come from different contracts of the ABI.
+	AmbiguityCollision AmbiguityReason = "collision"
+)
+
+// SelectorAmbiguity reports the methods of an ABI that share the selector of decoded calldata, and the one
+// the data was decoded with.
+type SelectorAmbiguity struct {
+	Selector   string          `json:"selector"`   // Hex encoded selector, with the 0x prefix.
+	Reason     AmbiguityReason `json:"reason"`     // Why the methods share the selector.
+	Candidates []string        `json:"candidates"` // Signatures of the methods sharing the selector.
+	Resolved   string          `json:"resolved"`   // Signature of the method the data was decoded with.
+}
+
+// ResolveMethod returns the method of the ABI the selector dispatches to, along with the ambiguity found
+// when several methods share it. Identical signatures resolve to the getter, as solc lets public state
+// variables override external functions but not the other way around, and then to the method listed
+// first. Colliding signatures resolve to the method whose parameters the arguments are encoded for.
+func ResolveMethod(contractABI abi.ABI, selector []byte, arguments []byte) (*abi.Method, *SelectorAmbiguity, error) {
+	candidates := make([]abi.Method, 0, 1)
+	for _, method := range contractABI.Methods {
+		if bytes.Equal(method.ID, selector) {
+			candidates = append(candidates, method)
+		}
+	}
+
+	if len(candidates) == 0 {
+		// Keeps the error of abi.MethodById callers match against.
+		_, err := contractABI.MethodById(selector)
+		return nil, nil, err
+	}
+	if len(candidates) == 1 {
+		return &candidates[0], nil, nil
+	}
+
+	// Methods are ordered by name, with overloads, named name, name0, name1..., in the order the ABI lists them.
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].RawName != candidates[j].RawName {
+			return candidates[i].RawName < candidates[j].RawName
+		}
+		if len(candidates[i].Name) != len(candidates[j].Name) {
+			return len(candidates[i].Name) < len(candidates[j].Name)
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+
+	ambiguity := &SelectorAmbiguity{
+		Selector:   "0x" + common.Bytes2Hex(selector),
+		Reason:     AmbiguityDuplicate,
+		Candidates: make([]string, 0, len(candidates)),
+	}
+	for _, candidate := range candidates {
+		ambiguity.Candidates = append(ambiguity.Candidates, candidate.Sig)
+		if candidate.Sig != candidates[0].Sig {
+			ambiguity.Reason = AmbiguityCollision
+		}
+	}
+
+	resolved := &candidates[0]
+	switch ambiguity.Reason {
+	case AmbiguityCollision:
+		// Arguments that encode back to the data exactly are preferred over ones that merely decode,
+		// as shorter parameter lists decode from the head of longer calldata too.
+		decoded := -1
+		for i, candidate := range candidates {
+			values, err := candidate.Inputs.Unpack(arguments)
+			if err != nil {
+				continue
+			}
+			if packed, err := candidate.Inputs.Pack(values...); err == nil && bytes.Equal(packed, arguments) {
+				decoded = i
+				break
+			}
+			if decoded == -1 {
+				decoded = i
+			}
+		}
+		if decoded != -1 {
+			resolved = &candidates[decoded]
+		}
+	default:
+		getters := 0
+		for i, candidate := range candidates {
+			if isGetterLike(candidate) {
+				if getters == 0 {
+					resolved = &candidates[i]
+				}
+				getters++
+			}
+		}
+		if getters > 0 && getters < len(candidates) {
+			ambiguity.Reason = AmbiguityGetterOverride
+		}
+	}
+
+	ambiguity.Resolved = resolved.Sig
+	return resolved, ambiguity, nil
+}
+
+// isGetterLike reports whether the method has the shape of a public state variable getter: a view
+// function whose parameters, the keys and indices of the variable, are all unnamed.
+func isGetterLike(method abi.Method) bool {
+	if method.StateMutability != "view" || len(method.Outputs) == 0 {
+		return false
+	}
+	for _, input := range method.Inputs {
+		if input.Name != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -1,5 +1,6 @@
 /*
 Package bytecode provides tools for decoding and analyzing Ethereum contract, transaction, events, and log bytecode.
-The package is designed to extract and interpret metadata from Ethereum contract creation bytecode. It provides a Metadata struct that represents the metadata contained in the bytecode, as defined by the Solidity compiler. This includes information such as the IPFS hash of the metadata, the Swarm hash of the metadata, experimental metadata, and the version of the Solidity compiler used.
+The package is designed to extract and interpret metadata from Ethereum contract creation bytecode. It provides a Metadata struct that represents the metadata contained in the bytecode, as defined by the Solidity compiler. This includes information such as the IPFS hash of the metadata, the Swarm hash of the metadata, experimental metadata, and the version of the Solidity compiler used. ExtractMetadata locates the metadata trailing runtime bytecode, checking it is a map of the keys solc encodes, and StripMetadata leaves it out for comparing or analysing the code alone.
+It also decodes and validates EOF (EVM Object Format) containers, as defined by EIP-3540 and EIP-3670, exposing their code and data sections.
 */
 package bytecode
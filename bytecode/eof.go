@@ -0,0 +1,457 @@
+package bytecode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// EOF container constants, as defined by EIP-3540.
+// More information can be found at https://eips.ethereum.org/EIPS/eip-3540
+const (
+	EOFVersion1 byte = 0x01 // The only EOF version defined so far.
+
+	eofKindTypes      byte = 0x01
+	eofKindCode       byte = 0x02
+	eofKindContainer  byte = 0x03
+	eofKindData       byte = 0x04
+	eofKindDataLegacy byte = 0xff // Data section kind used by earlier revisions of the specification.
+	eofTerminator     byte = 0x00
+
+	eofMaxCodeSections      = 1024
+	eofMaxContainerSections = 256
+	eofMaxStackHeight       = 1023
+	eofMaxInputsOutputs     = 127
+	eofNonReturning         = 0x80
+	eofTypeSize             = 4
+)
+
+var (
+	// EOFMagic is the prefix of every EOF container. The 0xef byte is reserved by EIP-3541, so legacy
+	// code cannot start with it.
+	EOFMagic = []byte{0xef, 0x00}
+
+	ErrNotEOF     = errors.New("bytecode is not an EOF container")
+	ErrInvalidEOF = errors.New("invalid EOF container")
+)
+
+// EOFTypeSection describes the signature of a code section of an EOF container.
+type EOFTypeSection struct {
+	Inputs         uint8  `json:"inputs"`           // Number of stack items the section takes.
+	Outputs        uint8  `json:"outputs"`          // Number of stack items the section returns, 0x80 if it never returns.
+	MaxStackHeight uint16 `json:"max_stack_height"` // Maximum stack height reached by the section.
+}
+
+// IsNonReturning returns whether the code section never returns to its caller.
+func (t EOFTypeSection) IsNonReturning() bool {
+	return t.Outputs == eofNonReturning
+}
+
+// EOFContainer is a decoded EOF (EVM Object Format) container: a header followed by the type, code,
+// subcontainer and data sections it declares.
+type EOFContainer struct {
+	Version      byte             `json:"version"`       // Version of the container format.
+	Types        []EOFTypeSection `json:"types"`         // Signature of each code section.
+	CodeSections [][]byte         `json:"code_sections"` // Code of each section.
+	CodeOffsets  []int            `json:"code_offsets"`  // Offset of each code section in the container.
+	Containers   []*EOFContainer  `json:"containers"`    // Subcontainers, used as initcode or deployed code.
+	Data         []byte           `json:"data"`          // Data section as present in the container.
+	DataSize     int              `json:"data_size"`     // Data section size declared in the header.
+	Size         int              `json:"size"`          // Size of the container in bytes.
+}
+
+// IsEOF returns whether the bytecode is an EOF container, as opposed to legacy bytecode.
+func IsEOF(bytecode []byte) bool {
+	return bytes.HasPrefix(bytecode, EOFMagic)
+}
+
+// DecodeEOFContainer decodes the EOF container at the beginning of the bytecode, including its
+// subcontainers. It checks that the header is well formed and that the sections it declares are
+// present, but not the code itself; use Validate for that.
+//
+// The data section may be shorter than declared, as allowed for containers deployed by EOFCREATE,
+// which append the rest of the data on deployment.
+func DecodeEOFContainer(bytecode []byte) (*EOFContainer, error) {
+	if !IsEOF(bytecode) {
+		return nil, ErrNotEOF
+	}
+
+	reader := &eofReader{bytecode: bytecode, offset: len(EOFMagic)}
+	toReturn := &EOFContainer{}
+
+	var err error
+	if toReturn.Version, err = reader.byte("version"); err != nil {
+		return nil, err
+	}
+	if toReturn.Version != EOFVersion1 {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidEOF, toReturn.Version)
+	}
+
+	typesSize, err := reader.section(eofKindTypes, "types")
+	if err != nil {
+		return nil, err
+	}
+
+	codeSizes, err := reader.sections(eofKindCode, "code", eofMaxCodeSections)
+	if err != nil {
+		return nil, err
+	}
+
+	var containerSizes []int
+	if kind, err := reader.peek("data section kind"); err != nil {
+		return nil, err
+	} else if kind == eofKindContainer {
+		if containerSizes, err = reader.sections(eofKindContainer, "container", eofMaxContainerSections); err != nil {
+			return nil, err
+		}
+	}
+
+	if kind, err := reader.peek("data section kind"); err != nil {
+		return nil, err
+	} else if kind == eofKindDataLegacy {
+		toReturn.DataSize, err = reader.section(eofKindDataLegacy, "data")
+		if err != nil {
+			return nil, err
+		}
+	} else if toReturn.DataSize, err = reader.section(eofKindData, "data"); err != nil {
+		return nil, err
+	}
+
+	if terminator, err := reader.byte("terminator"); err != nil {
+		return nil, err
+	} else if terminator != eofTerminator {
+		return nil, fmt.Errorf("%w: missing header terminator at offset %d", ErrInvalidEOF, reader.offset-1)
+	}
+
+	if typesSize != len(codeSizes)*eofTypeSize {
+		return nil, fmt.Errorf(
+			"%w: types section of %d bytes for %d code sections", ErrInvalidEOF, typesSize, len(codeSizes),
+		)
+	}
+
+	types, err := reader.body(typesSize, "types section")
+	if err != nil {
+		return nil, err
+	}
+	for index := 0; index < len(types); index += eofTypeSize {
+		toReturn.Types = append(toReturn.Types, EOFTypeSection{
+			Inputs:         types[index],
+			Outputs:        types[index+1],
+			MaxStackHeight: binary.BigEndian.Uint16(types[index+2:]),
+		})
+	}
+
+	for _, size := range codeSizes {
+		toReturn.CodeOffsets = append(toReturn.CodeOffsets, reader.offset)
+		code, err := reader.body(size, "code section")
+		if err != nil {
+			return nil, err
+		}
+		toReturn.CodeSections = append(toReturn.CodeSections, code)
+	}
+
+	for _, size := range containerSizes {
+		body, err := reader.body(size, "container section")
+		if err != nil {
+			return nil, err
+		}
+		container, err := DecodeEOFContainer(body)
+		if err != nil {
+			return nil, fmt.Errorf("container section %d: %w", len(toReturn.Containers), err)
+		}
+		toReturn.Containers = append(toReturn.Containers, container)
+	}
+
+	remaining := len(bytecode) - reader.offset
+	if remaining > toReturn.DataSize {
+		return nil, fmt.Errorf(
+			"%w: %d bytes after the declared end of the container", ErrInvalidEOF, remaining-toReturn.DataSize,
+		)
+	}
+	toReturn.Data = bytecode[reader.offset:]
+	toReturn.Size = len(bytecode)
+
+	return toReturn, nil
+}
+
+// GetTypes returns the signatures of the code sections.
+func (c *EOFContainer) GetTypes() []EOFTypeSection {
+	return c.Types
+}
+
+// GetCodeSections returns the code of each section.
+func (c *EOFContainer) GetCodeSections() [][]byte {
+	return c.CodeSections
+}
+
+// GetCodeOffsets returns the offset of each code section in the container.
+func (c *EOFContainer) GetCodeOffsets() []int {
+	return c.CodeOffsets
+}
+
+// GetContainers returns the subcontainers.
+func (c *EOFContainer) GetContainers() []*EOFContainer {
+	return c.Containers
+}
+
+// GetData returns the data section as present in the container.
+func (c *EOFContainer) GetData() []byte {
+	return c.Data
+}
+
+// IsDataTruncated returns whether the data section is shorter than declared in the header.
+func (c *EOFContainer) IsDataTruncated() bool {
+	return len(c.Data) < c.DataSize
+}
+
+// Validate checks the container against the rules of EIP-3540, EIP-3670 and EIP-4200: section
+// signatures are in range, the first section is the non-returning entry point, code sections only
+// hold instructions valid in EOF with complete immediates, and relative jumps land on instructions
+// of the same section. Subcontainers are validated as well, where a truncated data section is allowed.
+func (c *EOFContainer) Validate() error {
+	if c.IsDataTruncated() {
+		return fmt.Errorf("%w: data section of %d bytes, declared %d", ErrInvalidEOF, len(c.Data), c.DataSize)
+	}
+	return c.validate()
+}
+
+func (c *EOFContainer) validate() error {
+	if len(c.Types) == 0 || c.Types[0].Inputs != 0 || !c.Types[0].IsNonReturning() {
+		return fmt.Errorf("%w: first code section must take no inputs and be non-returning", ErrInvalidEOF)
+	}
+
+	for index, section := range c.Types {
+		if section.Inputs > eofMaxInputsOutputs || (section.Outputs > eofMaxInputsOutputs && !section.IsNonReturning()) {
+			return fmt.Errorf("%w: code section %d has too many inputs or outputs", ErrInvalidEOF, index)
+		}
+		if section.MaxStackHeight > eofMaxStackHeight {
+			return fmt.Errorf("%w: code section %d exceeds the maximum stack height", ErrInvalidEOF, index)
+		}
+	}
+
+	for index, code := range c.CodeSections {
+		if err := validateEOFCode(code, len(c.CodeSections), len(c.Containers)); err != nil {
+			return fmt.Errorf("code section %d: %w", index, err)
+		}
+	}
+
+	for index, container := range c.Containers {
+		if err := container.validate(); err != nil {
+			return fmt.Errorf("container section %d: %w", index, err)
+		}
+	}
+
+	return nil
+}
+
+// eofImmediateSizes holds the size of the immediate arguments of EOF instructions that take a fixed
+// number of them. RJUMPV takes a variable number, see EOFImmediateSize.
+var eofImmediateSizes = map[byte]int{
+	0xd1: 2, // DATALOADN
+	0xe0: 2, // RJUMP
+	0xe1: 2, // RJUMPI
+	0xe3: 2, // CALLF
+	0xe5: 2, // JUMPF
+	0xe6: 1, // DUPN
+	0xe7: 1, // SWAPN
+	0xe8: 1, // EXCHANGE
+	0xec: 1, // EOFCREATE
+	0xee: 1, // RETURNCONTRACT
+}
+
+// eofDisallowedOpcodes are opcodes of legacy code that are undefined in EOF code: code and gas
+// introspection, dynamic jumps, and calls and creations replaced by their EOF counterparts.
+var eofDisallowedOpcodes = map[byte]bool{
+	0x38: true, // CODESIZE
+	0x39: true, // CODECOPY
+	0x3b: true, // EXTCODESIZE
+	0x3c: true, // EXTCODECOPY
+	0x3f: true, // EXTCODEHASH
+	0x56: true, // JUMP
+	0x57: true, // JUMPI
+	0x58: true, // PC
+	0x5a: true, // GAS
+	0xf0: true, // CREATE
+	0xf1: true, // CALL
+	0xf2: true, // CALLCODE
+	0xf4: true, // DELEGATECALL
+	0xf5: true, // CREATE2
+	0xfa: true, // STATICCALL
+	0xff: true, // SELFDESTRUCT
+}
+
+// IsValidEOFOpcode returns whether the opcode is defined in EOF code.
+func IsValidEOFOpcode(op byte) bool {
+	if eofDisallowedOpcodes[op] {
+		return false
+	}
+
+	switch {
+	case op <= 0x0b, op >= 0x10 && op <= 0x1d, op == 0x20:
+		return true
+	case op >= 0x30 && op <= 0x4a, op >= 0x50 && op <= 0xa4:
+		return true
+	case op >= 0xd0 && op <= 0xd3, op >= 0xe0 && op <= 0xe8:
+		return true
+	}
+
+	switch op {
+	case 0xec, 0xee, 0xf3, 0xf7, 0xf8, 0xf9, 0xfb, 0xfd, 0xfe:
+		return true
+	}
+
+	return false
+}
+
+// EOFImmediateSize returns the number of immediate bytes following the instruction at the offset of
+// EOF code, or -1 if the immediates are cut off by the end of the code.
+func EOFImmediateSize(code []byte, offset int) int {
+	op := code[offset]
+
+	size := 0
+	switch {
+	case op >= 0x60 && op <= 0x7f: // PUSH1 to PUSH32
+		size = int(op) - 0x60 + 1
+	case op == 0xe2: // RJUMPV, a jump table of max_index + 1 relative offsets
+		if offset+1 >= len(code) {
+			return -1
+		}
+		size = 1 + (int(code[offset+1])+1)*2
+	default:
+		size = eofImmediateSizes[op]
+	}
+
+	if offset+size >= len(code) && size > 0 {
+		return -1
+	}
+	return size
+}
+
+// validateEOFCode checks the instructions of a single code section.
+func validateEOFCode(code []byte, codeSections int, containerSections int) error {
+	if len(code) == 0 {
+		return fmt.Errorf("%w: empty code section", ErrInvalidEOF)
+	}
+
+	starts := make(map[int]bool)
+	targets := make([]int, 0)
+
+	for offset := 0; offset < len(code); {
+		op := code[offset]
+		if !IsValidEOFOpcode(op) {
+			return fmt.Errorf("%w: undefined instruction 0x%02x at offset %d", ErrInvalidEOF, op, offset)
+		}
+
+		size := EOFImmediateSize(code, offset)
+		if size < 0 {
+			return fmt.Errorf("%w: truncated immediate of instruction 0x%02x at offset %d", ErrInvalidEOF, op, offset)
+		}
+		starts[offset] = true
+		next := offset + 1 + size
+
+		switch op {
+		case 0xe0, 0xe1: // RJUMP, RJUMPI
+			targets = append(targets, next+int(int16(binary.BigEndian.Uint16(code[offset+1:]))))
+		case 0xe2: // RJUMPV
+			for entry := offset + 2; entry < next; entry += 2 {
+				targets = append(targets, next+int(int16(binary.BigEndian.Uint16(code[entry:]))))
+			}
+		case 0xe3, 0xe5: // CALLF, JUMPF
+			if section := int(binary.BigEndian.Uint16(code[offset+1:])); section >= codeSections {
+				return fmt.Errorf("%w: call to missing code section %d at offset %d", ErrInvalidEOF, section, offset)
+			}
+		case 0xec, 0xee: // EOFCREATE, RETURNCONTRACT
+			if container := int(code[offset+1]); container >= containerSections {
+				return fmt.Errorf("%w: reference to missing container %d at offset %d", ErrInvalidEOF, container, offset)
+			}
+		}
+
+		offset = next
+	}
+
+	for _, target := range targets {
+		if !starts[target] {
+			return fmt.Errorf("%w: relative jump to offset %d is not an instruction", ErrInvalidEOF, target)
+		}
+	}
+
+	return nil
+}
+
+// eofReader reads the header and sections of an EOF container, tracking the current offset.
+type eofReader struct {
+	bytecode []byte
+	offset   int
+}
+
+// peek returns the next byte without consuming it.
+func (r *eofReader) peek(what string) (byte, error) {
+	if r.offset >= len(r.bytecode) {
+		return 0, fmt.Errorf("%w: missing %s at offset %d", ErrInvalidEOF, what, r.offset)
+	}
+	return r.bytecode[r.offset], nil
+}
+
+// byte consumes the next byte.
+func (r *eofReader) byte(what string) (byte, error) {
+	value, err := r.peek(what)
+	if err == nil {
+		r.offset++
+	}
+	return value, err
+}
+
+// uint16 consumes the next two bytes as a big endian number.
+func (r *eofReader) uint16(what string) (int, error) {
+	if r.offset+2 > len(r.bytecode) {
+		return 0, fmt.Errorf("%w: missing %s at offset %d", ErrInvalidEOF, what, r.offset)
+	}
+	value := binary.BigEndian.Uint16(r.bytecode[r.offset:])
+	r.offset += 2
+	return int(value), nil
+}
+
+// section consumes a header entry of a single section of the given kind and returns its size.
+func (r *eofReader) section(kind byte, what string) (int, error) {
+	if actual, err := r.byte(what + " section kind"); err != nil {
+		return 0, err
+	} else if actual != kind {
+		return 0, fmt.Errorf("%w: expected %s section kind 0x%02x, got 0x%02x", ErrInvalidEOF, what, kind, actual)
+	}
+	return r.uint16(what + " section size")
+}
+
+// sections consumes a header entry of a list of sections of the given kind and returns their sizes.
+func (r *eofReader) sections(kind byte, what string, limit int) ([]int, error) {
+	count, err := r.section(kind, what)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 || count > limit {
+		return nil, fmt.Errorf("%w: %d %s sections, expected 1 to %d", ErrInvalidEOF, count, what, limit)
+	}
+
+	toReturn := make([]int, 0, count)
+	for i := 0; i < count; i++ {
+		size, err := r.uint16(what + " section size")
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return nil, fmt.Errorf("%w: empty %s section %d", ErrInvalidEOF, what, i)
+		}
+		toReturn = append(toReturn, size)
+	}
+	return toReturn, nil
+}
+
+// body consumes the given number of bytes of the container body.
+func (r *eofReader) body(size int, what string) ([]byte, error) {
+	if r.offset+size > len(r.bytecode) {
+		return nil, fmt.Errorf("%w: %s of %d bytes at offset %d is cut off", ErrInvalidEOF, what, size, r.offset)
+	}
+	toReturn := r.bytecode[r.offset : r.offset+size]
+	r.offset += size
+	return toReturn, nil
+}
@@ -0,0 +1,97 @@
+package bytecode
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Minimal container with a single code section holding STOP.
+const minimalEOFForTest = "ef000101000402000100010400000000800000" + "00"
+
+// Container with two code sections, a subcontainer and two bytes of data. The first section jumps
+// over a STOP with RJUMPI and calls the second section, which returns with RETF.
+const eofForTest = "ef0001" + "010008" + "020002000a0003" + "0300010014" + "040002" + "00" +
+	"00800001" + "00000001" +
+	"6001e1000100e3000100" + "5f50e4" +
+	minimalEOFForTest +
+	"aabb"
+
+func TestDecodeEOFContainer(t *testing.T) {
+	code, err := hex.DecodeString(eofForTest)
+	require.NoError(t, err)
+	require.True(t, IsEOF(code))
+
+	container, err := DecodeEOFContainer(code)
+	require.NoError(t, err)
+	assert.Equal(t, EOFVersion1, container.Version)
+	assert.Equal(t, []EOFTypeSection{
+		{Inputs: 0, Outputs: 0x80, MaxStackHeight: 1},
+		{Inputs: 0, Outputs: 0, MaxStackHeight: 1},
+	}, container.GetTypes())
+	assert.True(t, container.GetTypes()[0].IsNonReturning())
+	assert.Equal(t, []int{30, 40}, container.GetCodeOffsets())
+	require.Len(t, container.GetCodeSections(), 2)
+	assert.Equal(t, "6001e1000100e3000100", hex.EncodeToString(container.GetCodeSections()[0]))
+	assert.Equal(t, "5f50e4", hex.EncodeToString(container.GetCodeSections()[1]))
+	require.Len(t, container.GetContainers(), 1)
+	assert.Equal(t, 20, container.GetContainers()[0].Size)
+	assert.Equal(t, []byte{0xaa, 0xbb}, container.GetData())
+	assert.False(t, container.IsDataTruncated())
+	assert.NoError(t, container.Validate())
+
+	// Containers deployed by EOFCREATE may declare more data than they hold.
+	truncated, err := DecodeEOFContainer(code[:len(code)-1])
+	require.NoError(t, err)
+	assert.True(t, truncated.IsDataTruncated())
+	assert.ErrorIs(t, truncated.Validate(), ErrInvalidEOF)
+
+	_, err = DecodeEOFContainer(append(code, 0x00))
+	assert.ErrorIs(t, err, ErrInvalidEOF)
+
+	_, err = DecodeEOFContainer([]byte{0x60, 0x80})
+	assert.ErrorIs(t, err, ErrNotEOF)
+	assert.False(t, IsEOF([]byte{0x60, 0x80}))
+}
+
+func TestValidateEOFContainer(t *testing.T) {
+	testCases := []struct {
+		name    string
+		code    string
+		wantErr bool
+	}{
+		{"minimal", minimalEOFForTest, false},
+		{"unsupported version", "ef000201000402000100010400000000800000" + "00", true},
+		{"missing terminator", "ef000101000402000100010400000100800000" + "00", true},
+		{"types size mismatch", "ef000101000802000100010400000000800000" + "00", true},
+		{"no code sections", "ef0001010004020000040000000080000000", true},
+		{"cut off code section", "ef000101000402000100020400000000800000" + "00", true},
+		{"returning entry point", "ef000101000402000100010400000000000000" + "00", true},
+		{"legacy JUMP", "ef000101000402000100010400000000800000" + "56", true},
+		{"legacy CALL", "ef000101000402000100010400000000800000" + "f1", true},
+		{"truncated push", "ef000101000402000100020400000000800000" + "6100", true},
+		{"jump into immediate", "ef000101000402000100040400000000800000" + "e0ffff00", true},
+		{"call to missing section", "ef000101000402000100040400000000800000" + "e3000100", true},
+		{"data section with legacy kind", "ef00010100040200010001ff0000000080000000", false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			code, err := hex.DecodeString(testCase.code)
+			require.NoError(t, err)
+
+			container, err := DecodeEOFContainer(code)
+			if err == nil {
+				err = container.Validate()
+			}
+
+			if testCase.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidEOF)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package bytecode
 
 import (
 	"bytes"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"strconv"
@@ -43,7 +44,12 @@ func (m *Metadata) ToProto() *metadata_pb.BytecodeMetadata {
 }
 
 // GetCompilerVersion returns the version of the Solidity compiler used to compile the contract.
+// Releases encode it as three bytes, while other builds encode the full version string.
 func (m *Metadata) GetCompilerVersion() string {
+	if len(m.Solc) != 3 {
+		return string(m.Solc)
+	}
+
 	s := make([]string, 0, len(m.Solc))
 	for _, i := range m.Solc {
 		s = append(s, strconv.Itoa(int(i)))
@@ -83,6 +89,20 @@ func (m *Metadata) GetBzzr1() string {
 	return fmt.Sprintf("bzz://%s", base58.Encode(m.Bzzr1))
 }
 
+// GetHashURL returns the IPFS or Swarm URL of the metadata file, whichever the compiler embedded, or an
+// empty string if it embedded neither.
+func (m *Metadata) GetHashURL() string {
+	switch {
+	case len(m.Ipfs) > 0:
+		return m.GetIPFS()
+	case len(m.Bzzr1) > 0:
+		return m.GetBzzr1()
+	case len(m.Bzzr0) > 0:
+		return m.GetBzzr0()
+	}
+	return ""
+}
+
 // GetExecutionBytecode returns the execution bytecode of the contract.
 func (m *Metadata) GetExecutionBytecode() []byte {
 	return m.executionBytecode
@@ -153,3 +173,90 @@ func DecodeContractMetadata(bytecode []byte) (*Metadata, error) {
 
 	return &toReturn, nil
 }
+
+// ExtractMetadata locates the CBOR encoded metadata solc appends to bytecode, whose length is given by
+// the last two bytes, and decodes it. Unlike DecodeContractMetadata, it only accepts a CBOR map of the
+// keys solc encodes, ipfs, bzzr0, bzzr1, solc and experimental, so bytecode without metadata, such as
+// that compiled with the metadata left out, is not mistaken for having some.
+func ExtractMetadata(code []byte) (*Metadata, error) {
+	if len(code) < 2 {
+		return nil, errors.New("provided bytecode slice does not contain cbor metadata")
+	}
+
+	cborLength := int(code[len(code)-2])<<8 | int(code[len(code)-1])
+	if cborLength == 0 || cborLength > len(code)-2 {
+		return nil, errors.New("provided bytecode slice does not contain cbor metadata")
+	}
+
+	auxbytes := code[len(code)-2-cborLength : len(code)-2]
+	// Metadata is a map of at most the five keys solc encodes.
+	if auxbytes[0] < 0xa1 || auxbytes[0] > 0xa5 {
+		return nil, errors.New("cbor metadata is not a map")
+	}
+
+	var fields map[string]cbor.RawMessage
+	if err := cbor.Unmarshal(auxbytes, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode cbor metadata: %w", err)
+	}
+
+	toReturn := &Metadata{
+		executionBytecode: code[:len(code)-2-cborLength],
+		cborLength:        int16(cborLength),
+		auxbytes:          auxbytes,
+	}
+	for key, value := range fields {
+		var err error
+		switch key {
+		case "ipfs":
+			err = cbor.Unmarshal(value, &toReturn.Ipfs)
+		case "bzzr0":
+			err = cbor.Unmarshal(value, &toReturn.Bzzr0)
+		case "bzzr1":
+			err = cbor.Unmarshal(value, &toReturn.Bzzr1)
+		case "solc":
+			// Releases are encoded as bytes and other builds as text.
+			var version string
+			if err = cbor.Unmarshal(value, &toReturn.Solc); err != nil {
+				if err = cbor.Unmarshal(value, &version); err == nil {
+					toReturn.Solc = []byte(version)
+				}
+			}
+		case "experimental":
+			var experimental bool
+			err = cbor.Unmarshal(value, &experimental)
+			toReturn.Experimental = experimental
+		default:
+			return nil, fmt.Errorf("cbor metadata has unknown key %s", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s of cbor metadata: %w", key, err)
+		}
+	}
+
+	return toReturn, nil
+}
+
+// ExtractMetadataFromHex is ExtractMetadata for hex encoded bytecode, with or without the 0x prefix.
+func ExtractMetadataFromHex(code string) (*Metadata, error) {
+	decoded, err := hex.DecodeString(strings.TrimPrefix(code, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode bytecode: %w", err)
+	}
+	return ExtractMetadata(decoded)
+}
+
+// HasMetadata reports whether the bytecode ends in CBOR encoded metadata.
+func HasMetadata(code []byte) bool {
+	_, err := ExtractMetadata(code)
+	return err == nil
+}
+
+// StripMetadata returns the bytecode without the CBOR encoded metadata it ends in, or as it is if it
+// has none.
+func StripMetadata(code []byte) []byte {
+	metadata, err := ExtractMetadata(code)
+	if err != nil {
+		return code
+	}
+	return metadata.GetExecutionBytecode()
+}
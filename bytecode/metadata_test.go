@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"testing"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -71,3 +72,52 @@ func TestDecodeContractCreationMetadata(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractMetadata(t *testing.T) {
+	const execution = "6080604052600080fd"
+	const ipfs = "1220b47452e094a03da36336b5ba3efebf10e52f7ea54c6ed62e28448b1e8d1f0f0a"
+	code, err := hex.DecodeString(execution + "a2646970667358221220" + ipfs[4:] + "64736f6c634300081300" + "33")
+	assert.NoError(t, err)
+
+	metadata, err := ExtractMetadata(code)
+	assert.NoError(t, err)
+	assert.Equal(t, execution, hex.EncodeToString(metadata.GetExecutionBytecode()))
+	assert.Equal(t, ipfs, hex.EncodeToString(metadata.Ipfs))
+	assert.Equal(t, "0.8.19", metadata.GetCompilerVersion())
+	assert.Equal(t, int16(51), metadata.GetCborLength())
+	assert.False(t, metadata.GetExperimental())
+	assert.Equal(t, metadata.GetIPFS(), metadata.GetHashURL())
+	assert.True(t, HasMetadata(code))
+	assert.Equal(t, execution, hex.EncodeToString(StripMetadata(code)))
+
+	fromHex, err := ExtractMetadataFromHex("0x" + hex.EncodeToString(code))
+	assert.NoError(t, err)
+	assert.Equal(t, metadata, fromHex)
+
+	// Builds other than releases encode the version as text.
+	aux, err := cbor.Marshal(map[string]any{"bzzr1": []byte{0x01, 0x02}, "solc": "0.8.21-nightly.2023.7.1", "experimental": true})
+	assert.NoError(t, err)
+	metadata, err = ExtractMetadata(append(append([]byte{0x00}, aux...), 0x00, byte(len(aux))))
+	assert.NoError(t, err)
+	assert.Equal(t, "0.8.21-nightly.2023.7.1", metadata.GetCompilerVersion())
+	assert.True(t, metadata.GetExperimental())
+	assert.Equal(t, metadata.GetBzzr1(), metadata.GetHashURL())
+	assert.Equal(t, []byte{0x00}, metadata.GetExecutionBytecode())
+
+	// Bytecode without metadata is left as it is.
+	plain, err := hex.DecodeString(execution)
+	assert.NoError(t, err)
+	assert.False(t, HasMetadata(plain))
+	assert.Equal(t, plain, StripMetadata(plain))
+
+	unknown, err := cbor.Marshal(map[string]any{"hash": 1})
+	assert.NoError(t, err)
+	_, err = ExtractMetadata(append(unknown, 0x00, byte(len(unknown))))
+	assert.Error(t, err)
+	_, err = ExtractMetadata([]byte{0x01, 0x02, 0x00, 0x02})
+	assert.Error(t, err)
+	_, err = ExtractMetadata(nil)
+	assert.Error(t, err)
+	_, err = ExtractMetadataFromHex("0xzz")
+	assert.Error(t, err)
+}
@@ -13,13 +13,14 @@ import (
 // about the transaction's method, its arguments, and the associated ABI.
 // This structured format makes it easier to work with Ethereum transactions programmatically.
 type Transaction struct {
-	Abi            string                      `json:"abi"`             // ABI string of the transaction's method.
-	SignatureBytes []byte                      `json:"signature_bytes"` // Raw signature bytes of the transaction.
-	Signature      string                      `json:"signature"`       // Human-readable signature of the transaction's method.
-	Type           utils.TransactionMethodType `json:"type"`            // Type of the transaction, classified by its method name.
-	Name           string                      `json:"name"`            // Name of the transaction's method.
-	Method         *abi.Method                 `json:"-"`               // ABI method information, not serialized to JSON.
-	Inputs         map[string]interface{}      `json:"inputs"`          // Decoded arguments passed to the transaction's method.
+	Abi            string                      `json:"abi"`                 // ABI string of the transaction's method.
+	SignatureBytes []byte                      `json:"signature_bytes"`     // Raw signature bytes of the transaction.
+	Signature      string                      `json:"signature"`           // Human-readable signature of the transaction's method.
+	Type           utils.TransactionMethodType `json:"type"`                // Type of the transaction, classified by its method name.
+	Name           string                      `json:"name"`                // Name of the transaction's method.
+	Method         *abi.Method                 `json:"-"`                   // ABI method information, not serialized to JSON.
+	Inputs         map[string]interface{}      `json:"inputs"`              // Decoded arguments passed to the transaction's method.
+	Ambiguity      *SelectorAmbiguity          `json:"ambiguity,omitempty"` // Methods of the ABI sharing the selector, if there are several.
 }
 
 // DecodeTransactionFromAbi decodes an Ethereum transaction using the provided ABI.
@@ -30,9 +31,17 @@ type Transaction struct {
 // (`abiData`) in JSON format. It returns a pointer to a Transaction object, populated with
 // the decoded method information and its arguments, or an error if decoding fails.
 //
+// When several methods of the ABI share the selector, such as a public state variable getter and
+// the interface function it overrides, the method is chosen following the solc override rules and
+// the ambiguity is reported on the returned Transaction.
+//
 // This function simplifies the process of interacting with raw Ethereum transactions, making
 // it easier to analyze and use the transaction data programmatically.
 func DecodeTransactionFromAbi(data []byte, abiData []byte) (*Transaction, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("invalid transaction data length: %d", len(data))
+	}
+
 	// The first 4 bytes of the data represent the ID of the method in the ABI.
 	methodSigData := data[:4]
 
@@ -41,7 +50,7 @@ func DecodeTransactionFromAbi(data []byte, abiData []byte) (*Transaction, error)
 		return nil, fmt.Errorf("failed to parse abi: %s", err)
 	}
 
-	method, err := contractABI.MethodById(methodSigData)
+	method, ambiguity, err := ResolveMethod(contractABI, methodSigData, data[4:])
 	if err != nil {
 		return nil, fmt.Errorf("failed to get method by id: %s", err)
 	}
@@ -65,5 +74,6 @@ func DecodeTransactionFromAbi(data []byte, abiData []byte) (*Transaction, error)
 		Method:         method,
 		Type:           utils.TransactionMethodType(strings.ToLower(method.Name)),
 		Inputs:         inputsMap,
+		Ambiguity:      ambiguity,
 	}, nil
 }
@@ -0,0 +1,97 @@
+package bytecode
+
+import (
+	"testing"
+
+	abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeTransactionFromAbiAmbiguity(t *testing.T) {
+	address, _ := abi.NewType("address", "", nil)
+	uint256, _ := abi.NewType("uint256", "", nil)
+	holder := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	balances := abi.NewMethod("balances", "balances", abi.Function, "view", false, false, abi.Arguments{{Type: address}}, nil)
+	balancesData, err := balances.Inputs.Pack(holder)
+	require.NoError(t, err)
+
+	transferFrom := abi.NewMethod("transferFrom", "transferFrom", abi.Function, "nonpayable", false, false, abi.Arguments{{Type: address}, {Type: address}, {Type: uint256}}, nil)
+	transferFromData, err := transferFrom.Inputs.Pack(holder, holder, common.Big1)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		abi        string
+		data       []byte
+		want       string
+		ambiguity  *SelectorAmbiguity
+		outputName string
+	}{
+		{
+			name: "Single method",
+			abi:  `[{"inputs":[{"internalType":"address","name":"","type":"address"}],"name":"balances","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`,
+			data: append(common.CopyBytes(balances.ID), balancesData...),
+			want: "balances",
+		},
+		{
+			name: "Getter overriding an interface function",
+			abi: `[{"inputs":[{"internalType":"address","name":"account","type":"address"}],"name":"balances","outputs":[{"internalType":"uint256","name":"balance","type":"uint256"}],"stateMutability":"view","type":"function"},` +
+				`{"inputs":[{"internalType":"address","name":"","type":"address"}],"name":"balances","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`,
+			data: append(common.CopyBytes(balances.ID), balancesData...),
+			want: "balances",
+			ambiguity: &SelectorAmbiguity{
+				Selector:   "0x27e235e3",
+				Reason:     AmbiguityGetterOverride,
+				Candidates: []string{"balances(address)", "balances(address)"},
+				Resolved:   "balances(address)",
+			},
+			outputName: "",
+		},
+		{
+			name: "Duplicate functions of merged ABIs",
+			abi: `[{"inputs":[{"internalType":"address","name":"account","type":"address"}],"name":"balances","outputs":[{"internalType":"uint256","name":"balance","type":"uint256"}],"stateMutability":"view","type":"function"},` +
+				`{"inputs":[{"internalType":"address","name":"owner","type":"address"}],"name":"balances","outputs":[{"internalType":"uint256","name":"amount","type":"uint256"}],"stateMutability":"view","type":"function"}]`,
+			data: append(common.CopyBytes(balances.ID), balancesData...),
+			want: "balances",
+			ambiguity: &SelectorAmbiguity{
+				Selector:   "0x27e235e3",
+				Reason:     AmbiguityDuplicate,
+				Candidates: []string{"balances(address)", "balances(address)"},
+				Resolved:   "balances(address)",
+			},
+			outputName: "balance",
+		},
+		{
+			name: "Colliding signatures",
+			abi: `[{"inputs":[{"internalType":"address","name":"from","type":"address"},{"internalType":"address","name":"to","type":"address"},{"internalType":"uint256","name":"amount","type":"uint256"}],"name":"transferFrom","outputs":[],"stateMutability":"nonpayable","type":"function"},` +
+				`{"inputs":[{"internalType":"int128","name":"value","type":"int128"}],"name":"gasprice_bit_ether","outputs":[],"stateMutability":"nonpayable","type":"function"}]`,
+			data: append(common.CopyBytes(transferFrom.ID), transferFromData...),
+			want: "transferFrom",
+			ambiguity: &SelectorAmbiguity{
+				Selector:   "0x23b872dd",
+				Reason:     AmbiguityCollision,
+				Candidates: []string{"gasprice_bit_ether(int128)", "transferFrom(address,address,uint256)"},
+				Resolved:   "transferFrom(address,address,uint256)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transaction, err := DecodeTransactionFromAbi(tt.data, []byte(tt.abi))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, transaction.Method.RawName)
+			assert.Equal(t, tt.ambiguity, transaction.Ambiguity)
+			if tt.ambiguity != nil && tt.ambiguity.Reason != AmbiguityCollision {
+				require.Len(t, transaction.Method.Outputs, 1)
+				assert.Equal(t, tt.outputName, transaction.Method.Outputs[0].Name)
+			}
+		})
+	}
+
+	_, err = DecodeTransactionFromAbi([]byte{0x01}, []byte(`[]`))
+	assert.Error(t, err)
+}
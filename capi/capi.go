@@ -0,0 +1,163 @@
+package capi
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/0x19/solc-switch"
+	"github.com/goccy/go-json"
+	"github.com/unpackdev/solgo"
+	"github.com/unpackdev/solgo/compiler"
+	"github.com/unpackdev/solgo/ir"
+	"github.com/unpackdev/solgo/validation"
+	"github.com/unpackdev/solgo/wasm"
+)
+
+// Version is the version of the C ABI of the library. It changes only when functions are removed or
+// their JSON requests and responses change incompatibly.
+const Version = 1
+
+// VerifyRequest is the request of Verify.
+type VerifyRequest struct {
+	Sources         *solgo.Sources    `json:"sources"`
+	Bytecode        string            `json:"bytecode"`         // Hex encoded deployed bytecode.
+	CompilerVersion string            `json:"compiler_version"` // Such as 0.8.19.
+	Settings        *compiler.Config  `json:"settings,omitempty"`
+	Libraries       map[string]string `json:"libraries,omitempty"`     // Addresses by fully qualified name or name.
+	ReleasesPath    string            `json:"releases_path,omitempty"` // Where solc-switch keeps the compilers.
+}
+
+// Call runs the function on the JSON encoded data and returns its JSON encoded response. Panics of the
+// function are recovered and reported in the errors of the response, as they would otherwise cross the
+// cgo boundary and abort the process embedding the library.
+func Call(ctx context.Context, function func(ctx context.Context, data string) string, data string) (toReturn string) {
+	defer func() {
+		if r := recover(); r != nil {
+			toReturn = respond(nil, []error{fmt.Errorf("recovered from panic: %v", r)})
+		}
+	}()
+
+	return function(ctx, data)
+}
+
+// Parse parses the JSON encoded sources and returns the JSON encoded syntax errors found, as the
+// WebAssembly bindings do.
+func Parse(ctx context.Context, data string) string {
+	return wasm.Call(ctx, "parse", data)
+}
+
+// ABI builds the ABI of the contracts of the JSON encoded sources and returns it JSON encoded, as the
+// WebAssembly bindings do.
+func ABI(ctx context.Context, data string) string {
+	return wasm.Call(ctx, "abi", data)
+}
+
+// Standards detects the EIP standards the contracts of the JSON encoded sources implement and returns
+// them JSON encoded.
+func Standards(ctx context.Context, data string) string {
+	sources, err := wasm.DecodeSources(data)
+	if err != nil {
+		return respond(nil, []error{err})
+	}
+
+	builder, err := ir.NewBuilderFromSources(ctx, sources)
+	if err != nil {
+		return respond(nil, []error{err})
+	}
+
+	errs := builder.Parse()
+	if err := builder.Build(); err != nil {
+		return respond(nil, append(errs, err))
+	}
+
+	toReturn := make([]*ir.Standard, 0)
+	if root := builder.GetRoot(); root != nil {
+		toReturn = append(toReturn, root.GetStandards()...)
+	}
+	return respond(toReturn, errs)
+}
+
+// Verify compiles the sources of the JSON encoded VerifyRequest and returns the JSON encoded result of
+// verifying the bytecode against them. Bytecode not matching is reported along with the result, which
+// has its diff.
+func Verify(ctx context.Context, data string) string {
+	var request *VerifyRequest
+	if err := json.Unmarshal([]byte(data), &request); err != nil {
+		return respond(nil, []error{fmt.Errorf("failed to decode verify request: %w", err)})
+	}
+
+	result, err := verify(ctx, request)
+	switch {
+	case err != nil && result == nil:
+		return respond(nil, []error{err})
+	case err != nil:
+		return respond(result, []error{err})
+	}
+	return respond(result, nil)
+}
+
+// verify verifies the bytecode of the request against its sources.
+func verify(ctx context.Context, request *VerifyRequest) (*validation.VerifyResult, error) {
+	if request == nil || request.Sources == nil || !request.Sources.HasUnits() {
+		return nil, errors.New("verify request has no sources")
+	}
+
+	if request.CompilerVersion == "" {
+		return nil, errors.New("verify request has no compiler version")
+	}
+
+	bytecode, err := hex.DecodeString(strings.TrimPrefix(request.Bytecode, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("verify request has invalid bytecode: %w", err)
+	}
+	if len(bytecode) == 0 {
+		return nil, errors.New("verify request has no bytecode")
+	}
+
+	solcConfig, err := solc.NewDefaultConfig()
+	if err != nil {
+		return nil, err
+	}
+	if request.ReleasesPath != "" {
+		if err := solcConfig.SetReleasesPath(request.ReleasesPath); err != nil {
+			return nil, err
+		}
+	}
+
+	switcher, err := solc.New(ctx, solcConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	verifier, err := validation.NewVerifier(ctx, switcher, request.Sources)
+	if err != nil {
+		return nil, err
+	}
+	verifier.SetConfig(request.Settings)
+	verifier.SetLibraries(request.Libraries)
+
+	compilerConfig, err := solc.NewDefaultCompilerConfig(strings.TrimPrefix(request.CompilerVersion, "v"))
+	if err != nil {
+		return nil, err
+	}
+	compilerConfig.SetEntrySourceName(request.Sources.EntrySourceUnitName)
+
+	return verifier.Verify(ctx, bytecode, compilerConfig)
+}
+
+// respond returns the JSON encoded response of the result and errors.
+func respond(result any, errs []error) string {
+	response := &wasm.Response{Result: result}
+	for _, err := range errs {
+		response.Errors = append(response.Errors, err.Error())
+	}
+
+	encoded, err := json.Marshal(response)
+	if err != nil {
+		encoded, _ = json.Marshal(&wasm.Response{Errors: []string{fmt.Sprintf("failed to encode response: %s", err)}})
+	}
+	return string(encoded)
+}
@@ -0,0 +1,121 @@
+package capi
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const tokenForTest = `{"source_units": [{"name": "Token", "path": "Token.sol", "content": "// SPDX-License-Identifier: MIT\npragma solidity ^0.8.0;\n\ncontract Token {\n    mapping(address => uint256) balances;\n\n    event Transfer(address indexed from, address indexed to, uint256 value);\n    event Approval(address indexed owner, address indexed spender, uint256 value);\n\n    function totalSupply() external view returns (uint256) { return 0; }\n    function balanceOf(address owner) external view returns (uint256) { return balances[owner]; }\n    function transfer(address to, uint256 value) external returns (bool) { return true; }\n    function allowance(address owner, address spender) external view returns (uint256) { return 0; }\n    function approve(address spender, uint256 value) external returns (bool) { return true; }\n    function transferFrom(address from, address to, uint256 value) external returns (bool) { return true; }\n}\n"}], "base_source_unit": "Token"}`
+
+func TestStandards(t *testing.T) {
+	var response struct {
+		Result []struct {
+			ContractName string `json:"contract_name"`
+			Standard     struct {
+				Type string `json:"type"`
+			} `json:"standards"`
+		} `json:"result"`
+		Errors []string `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(Standards(context.TODO(), tokenForTest)), &response))
+	assert.Empty(t, response.Errors)
+
+	found := make([]string, 0)
+	for _, standard := range response.Result {
+		assert.Equal(t, "Token", standard.ContractName)
+		found = append(found, standard.Standard.Type)
+	}
+	assert.Contains(t, found, "ERC20")
+
+	assert.Contains(t, Standards(context.TODO(), `{"source_units": []}`), "sources have no source units")
+	assert.JSONEq(t, `{"result": []}`, Parse(context.TODO(), tokenForTest))
+	assert.Contains(t, ABI(context.TODO(), tokenForTest), `"entry_contract_name":"Token"`)
+}
+
+func TestCallRecoversPanic(t *testing.T) {
+	panics := func(ctx context.Context, data string) string {
+		var sources []string
+		return sources[len(data)]
+	}
+
+	var response map[string]any
+	require.NoError(t, json.Unmarshal([]byte(Call(context.TODO(), panics, tokenForTest)), &response))
+	assert.NotContains(t, response, "result")
+	require.Len(t, response["errors"], 1)
+	assert.Contains(t, response["errors"].([]any)[0], "recovered from panic: runtime error: index out of range")
+
+	assert.Equal(t, Standards(context.TODO(), `{"source_units": []}`), Call(context.TODO(), Standards, `{"source_units": []}`))
+}
+
+func TestVerify(t *testing.T) {
+	for name, testCase := range map[string]struct {
+		request string
+		error   string
+	}{
+		"invalid json":     {request: `{"sources":`, error: "failed to decode verify request"},
+		"no sources":       {request: `{"bytecode": "0x6080"}`, error: "verify request has no sources"},
+		"no version":       {request: `{"sources": ` + tokenForTest + `, "bytecode": "0x6080"}`, error: "verify request has no compiler version"},
+		"invalid bytecode": {request: `{"sources": ` + tokenForTest + `, "bytecode": "0xzz", "compiler_version": "0.8.19"}`, error: "verify request has invalid bytecode"},
+		"empty bytecode":   {request: `{"sources": ` + tokenForTest + `, "bytecode": "0x", "compiler_version": "0.8.19"}`, error: "verify request has no bytecode"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var response map[string]any
+			require.NoError(t, json.Unmarshal([]byte(Verify(context.TODO(), testCase.request)), &response))
+			assert.NotContains(t, response, "result")
+			require.Len(t, response["errors"], 1)
+			assert.Contains(t, response["errors"].([]any)[0], testCase.error)
+		})
+	}
+}
+
+func TestVerifyViaIR(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake compiler is a shell script")
+	}
+
+	// The fake solc records its standard JSON input and outputs the bytecode of the token.
+	releasesPath := t.TempDir()
+	inputPath := filepath.Join(releasesPath, "input.json")
+	outputPath := filepath.Join(releasesPath, "output.json")
+	output := `{"contracts": {"<stdin>": {"Token": {"evm": {"bytecode": {"object": "6080604052600080fd"}, "deployedBytecode": {"object": "6080604052600080fd"}}}}}}`
+	require.NoError(t, os.WriteFile(outputPath, []byte(output), 0600))
+	script := "#!/bin/sh\n[ \"$1\" = \"--standard-json\" ] || exit 2\ncat > " + inputPath + "\ncat " + outputPath + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(releasesPath, "releases.json"), []byte(`[{"tag_name":"v0.8.19"}]`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(releasesPath, "solc-0.8.19"), []byte(script), 0700))
+
+	request, err := json.Marshal(map[string]any{
+		"sources":          json.RawMessage(tokenForTest),
+		"bytecode":         "0x6080604052600080fd",
+		"compiler_version": "0.8.19",
+		"settings":         map[string]any{"optimized": true, "optimizerRuns": 200, "viaIR": true},
+		"releases_path":    releasesPath,
+	})
+	require.NoError(t, err)
+
+	var response struct {
+		Result struct {
+			Verified bool `json:"verified"`
+		} `json:"result"`
+		Errors []string `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(Verify(context.TODO(), string(request))), &response))
+	assert.Empty(t, response.Errors)
+	assert.True(t, response.Result.Verified)
+
+	sent, err := os.ReadFile(inputPath)
+	require.NoError(t, err)
+	var input struct {
+		Settings struct {
+			ViaIR bool `json:"viaIR"`
+		} `json:"settings"`
+	}
+	require.NoError(t, json.Unmarshal(sent, &input))
+	assert.True(t, input.Settings.ViaIR)
+}
@@ -0,0 +1,60 @@
+//go:build cgo
+
+// Command libsolgo is the C shared library of solgo, built with -buildmode=c-shared. Functions take
+// NUL terminated JSON and return NUL terminated JSON, { "result": ..., "errors": [...] }, allocated by
+// the library, which callers release with solgo_free.
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"context"
+	"unsafe"
+
+	"github.com/unpackdev/solgo/capi"
+)
+
+// call runs the function on the input and returns its response allocated in C memory. Panics are
+// reported in the errors of the response rather than unwinding into the caller.
+func call(function func(ctx context.Context, data string) string, input *C.char) *C.char {
+	if input == nil {
+		return C.CString(capi.Call(context.Background(), function, ""))
+	}
+	return C.CString(capi.Call(context.Background(), function, C.GoString(input)))
+}
+
+//export solgo_version
+func solgo_version() C.int {
+	return C.int(capi.Version)
+}
+
+//export solgo_parse
+func solgo_parse(sources *C.char) *C.char {
+	return call(capi.Parse, sources)
+}
+
+//export solgo_abi
+func solgo_abi(sources *C.char) *C.char {
+	return call(capi.ABI, sources)
+}
+
+//export solgo_standards
+func solgo_standards(sources *C.char) *C.char {
+	return call(capi.Standards, sources)
+}
+
+//export solgo_verify
+func solgo_verify(request *C.char) *C.char {
+	return call(capi.Verify, request)
+}
+
+//export solgo_free
+func solgo_free(response *C.char) {
+	C.free(unsafe.Pointer(response))
+}
+
+// main is required by -buildmode=c-shared and never runs.
+func main() {}
@@ -0,0 +1,9 @@
+// Package capi is the facade of the C shared library of solgo, which lets Python, Rust and other
+// pipelines embed the parser, ABI builder, EIP detection and bytecode verification without spawning
+// processes. Functions take and return JSON, encoded as the WebAssembly bindings of the wasm package
+// encode it, so the C ABI of cmd/libsolgo stays stable as solgo grows:
+//
+//	make c-shared
+//
+// builds build/libsolgo.so along with its header, libsolgo.h.
+package capi
@@ -67,11 +67,12 @@ func (b *Builder) Build() error {
 					allRelatedContracts = append(allRelatedContracts, importedContract)
 				}
 			}
+			// Bases missing from the IR, such as ones of unresolved imports, are kept as inheritance
+			// of the contract, but have no node of their own.
 			for _, baseContract := range contract.GetBaseContracts() {
+				b.graph.AddInheritance(contract.GetName(), baseContract)
 				baseContractId := baseContract.GetBaseName().GetReferencedDeclaration()
-				baseContractObj := root.GetContractBySourceUnitId(baseContractId)
-				if baseContractObj != nil {
-					b.graph.AddInheritance(contract.GetName(), baseContract)
+				if baseContractObj := root.GetContractBySourceUnitId(baseContractId); baseContractObj != nil {
 					allRelatedContracts = append(allRelatedContracts, baseContractObj)
 				}
 			}
@@ -0,0 +1,350 @@
+package cfg
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/unpackdev/solgo/ast"
+	ir_cfg "github.com/unpackdev/solgo/ir/cfg"
+)
+
+// D3LinkKind describes the relationship a D3Link represents.
+type D3LinkKind string
+
+const (
+	// D3LinkImport marks a link created from an import statement.
+	D3LinkImport D3LinkKind = "import"
+
+	// D3LinkInheritance marks a link created from a base contract declaration.
+	D3LinkInheritance D3LinkKind = "inheritance"
+)
+
+// D3Node represents a single vertex of a D3Graph. Besides the identifier and label used
+// by the renderer, it carries the source location of the underlying declaration so UIs
+// can link the diagram back to the code.
+type D3Node struct {
+	Id           string         `json:"id"`
+	Label        string         `json:"label"`
+	Group        string         `json:"group"`
+	Entry        bool           `json:"entry"`
+	AbsolutePath string         `json:"absolute_path,omitempty"`
+	Src          *ast.SrcNode   `json:"src,omitempty"`
+	Metadata     map[string]any `json:"metadata,omitempty"`
+}
+
+// D3Link represents a directed edge between two D3Graph nodes. Links of contract graphs are
+// of the D3LinkImport or D3LinkInheritance kind, while links of call graphs and control flow
+// graphs carry the kind of the underlying edge, such as ir.CallGraphEdgeSuper or cfg.EdgeTrue
+// of the ir/cfg package.
+type D3Link struct {
+	Source string     `json:"source"`
+	Target string     `json:"target"`
+	Kind   D3LinkKind `json:"kind"`
+	Label  string     `json:"label,omitempty"`
+}
+
+// D3Graph is a node-link representation of a graph in the format expected by D3 force
+// layouts and most other JavaScript graph libraries.
+type D3Graph struct {
+	Nodes []*D3Node `json:"nodes"`
+	Links []*D3Link `json:"links"`
+
+	index map[string]int // Positions of the nodes in Nodes by their identifiers.
+}
+
+// NewD3Graph creates and returns an empty D3Graph.
+func NewD3Graph() *D3Graph {
+	return &D3Graph{
+		Nodes: make([]*D3Node, 0),
+		Links: make([]*D3Link, 0),
+		index: make(map[string]int),
+	}
+}
+
+// AddNode appends a node to the graph unless a node with the same identifier already exists.
+func (g *D3Graph) AddNode(node *D3Node) {
+	index := g.nodeIndex()
+	if _, ok := index[node.Id]; !ok {
+		index[node.Id] = len(g.Nodes)
+		g.Nodes = append(g.Nodes, node)
+	}
+}
+
+// AddLink appends a directed link to the graph.
+func (g *D3Graph) AddLink(source, target string, kind D3LinkKind, label string) {
+	g.Links = append(g.Links, &D3Link{
+		Source: source,
+		Target: target,
+		Kind:   kind,
+		Label:  label,
+	})
+}
+
+// GetNode retrieves a node by identifier. It returns nil if the node does not exist.
+func (g *D3Graph) GetNode(id string) *D3Node {
+	if i, ok := g.nodeIndex()[id]; ok {
+		return g.Nodes[i]
+	}
+	return nil
+}
+
+// nodeIndex returns the positions of the nodes by their identifiers, rebuilding them when the
+// nodes were set without AddNode, such as by decoding JSON.
+func (g *D3Graph) nodeIndex() map[string]int {
+	if g.index == nil || len(g.index) != len(g.Nodes) {
+		g.index = make(map[string]int, len(g.Nodes))
+		for i, node := range g.Nodes {
+			if _, ok := g.index[node.Id]; !ok {
+				g.index[node.Id] = i
+			}
+		}
+	}
+	return g.index
+}
+
+// ToJSON returns the JSON encoding of the graph.
+func (g *D3Graph) ToJSON() ([]byte, error) {
+	return json.Marshal(g)
+}
+
+// ToD3 converts the contract graph into a D3Graph. Every contract becomes a node carrying
+// its kind, license, source location and declaration counts, while imports and inheritance
+// relationships become typed links. Imports and base contracts that cannot be resolved to a
+// contract within the graph are represented by nodes grouped as "external".
+//
+// Nodes are emitted in name order so the output is deterministic across runs.
+func (b *Builder) ToD3() *D3Graph {
+	toReturn := NewD3Graph()
+	if b.graph == nil {
+		return toReturn
+	}
+
+	names := make([]string, 0, len(b.graph.Nodes))
+	for name := range b.graph.Nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		node := b.graph.Nodes[name]
+		d3Node := &D3Node{
+			Id:    node.Name,
+			Label: node.Name,
+			Group: "contract",
+			Entry: node.EntryContract,
+		}
+
+		if contract := node.GetContract(); contract != nil {
+			src := contract.GetSrc()
+			d3Node.Group = contract.GetKind().String()
+			d3Node.AbsolutePath = contract.GetAbsolutePath()
+			d3Node.Src = &src
+			d3Node.Metadata = map[string]any{
+				"id":              contract.GetId(),
+				"license":         contract.GetLicense(),
+				"state_variables": len(contract.GetStateVariables()),
+				"functions":       len(contract.GetFunctions()),
+				"events":          len(contract.GetEvents()),
+				"errors":          len(contract.GetErrors()),
+			}
+		}
+
+		toReturn.AddNode(d3Node)
+	}
+
+	for _, name := range names {
+		node := b.graph.Nodes[name]
+
+		for _, imp := range node.GetImports() {
+			target := imp.GetAbsolutePath()
+			if imported := b.builder.GetRoot().GetContractById(imp.GetContractId()); imported != nil && b.graph.NodeExists(imported.GetName()) {
+				target = imported.GetName()
+			} else {
+				toReturn.AddNode(&D3Node{
+					Id:           target,
+					Label:        target,
+					Group:        "external",
+					AbsolutePath: target,
+				})
+			}
+			toReturn.AddLink(node.Name, target, D3LinkImport, imp.GetFile())
+		}
+
+		for _, inherit := range node.GetInherits() {
+			target := inherit.BaseName.GetName()
+			if !b.graph.NodeExists(target) {
+				toReturn.AddNode(&D3Node{
+					Id:    target,
+					Label: target,
+					Group: "external",
+				})
+			}
+			toReturn.AddLink(node.Name, target, D3LinkInheritance, "")
+		}
+	}
+
+	return toReturn
+}
+
+// ToD3JSON converts the contract graph into a D3Graph and returns its JSON encoding.
+func (b *Builder) ToD3JSON() ([]byte, error) {
+	return b.ToD3().ToJSON()
+}
+
+// CallGraphToD3 converts the call graph of the IR into a D3Graph. Every function, modifier,
+// constructor, fallback and receive function becomes a node identified by its AST id and
+// labelled with its contract, and every call graph edge becomes a link carrying the kind of
+// the edge. Nodes declared by the entry contract are marked as entry nodes.
+//
+// It returns an empty graph if the IR has no call graph.
+func (b *Builder) CallGraphToD3() *D3Graph {
+	toReturn := NewD3Graph()
+	root := b.builder.GetRoot()
+	if root == nil || root.GetCallGraph() == nil {
+		return toReturn
+	}
+
+	for _, node := range root.GetCallGraph().GetNodes() {
+		d3Node := &D3Node{
+			Id:    strconv.FormatInt(node.GetId(), 10),
+			Label: node.GetContractName() + "." + node.GetName(),
+			Group: strings.ToLower(node.NodeType.String()),
+			Entry: node.GetContractId() == root.GetEntryId(),
+			Metadata: map[string]any{
+				"id":          node.GetId(),
+				"contract":    node.GetContractName(),
+				"contract_id": node.GetContractId(),
+				"visibility":  strings.ToLower(node.Visibility.String()),
+				"implemented": node.IsImplemented(),
+				"overrides":   node.GetOverrides(),
+			},
+		}
+
+		if contract := root.GetContractById(node.GetContractId()); contract != nil {
+			d3Node.AbsolutePath = contract.GetAbsolutePath()
+		}
+
+		if unit := node.GetAST(); unit != nil {
+			src := unit.GetSrc()
+			d3Node.Src = &src
+		}
+
+		toReturn.AddNode(d3Node)
+	}
+
+	for _, edge := range root.GetCallGraph().GetEdges() {
+		toReturn.AddLink(
+			strconv.FormatInt(edge.GetCallerId(), 10),
+			strconv.FormatInt(edge.GetCalleeId(), 10),
+			D3LinkKind(edge.GetKind()),
+			strconv.FormatInt(edge.GetCallId(), 10),
+		)
+	}
+
+	return toReturn
+}
+
+// CallGraphToD3JSON converts the call graph of the IR into a D3Graph and returns its JSON encoding.
+func (b *Builder) CallGraphToD3JSON() ([]byte, error) {
+	return b.CallGraphToD3().ToJSON()
+}
+
+// ControlFlowToD3 builds the control flow graph of the function, constructor, fallback or
+// receive function declared by the given AST node and converts it into a D3Graph. Overloaded
+// functions are told apart by their declarations.
+func (b *Builder) ControlFlowToD3(declaration ast.Node[ast.NodeType]) (*D3Graph, error) {
+	if declaration == nil {
+		return nil, fmt.Errorf("declaration is nil")
+	}
+
+	for _, contract := range b.builder.GetRoot().GetContracts() {
+		var graph *ir_cfg.Graph
+		var err error
+		switch {
+		case contract.GetConstructor() != nil && contract.GetConstructor().GetAST().GetId() == declaration.GetId():
+			graph, err = contract.GetConstructor().GetControlFlowGraph()
+		case contract.GetFallback() != nil && contract.GetFallback().GetAST().GetId() == declaration.GetId():
+			graph, err = contract.GetFallback().GetControlFlowGraph()
+		case contract.GetReceive() != nil && contract.GetReceive().GetAST().GetId() == declaration.GetId():
+			graph, err = contract.GetReceive().GetControlFlowGraph()
+		default:
+			for _, function := range contract.GetFunctions() {
+				if function.GetAST().GetId() == declaration.GetId() {
+					graph, err = function.GetControlFlowGraph()
+					break
+				}
+			}
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if graph != nil {
+			return ControlFlowGraphToD3(graph), nil
+		}
+	}
+
+	return nil, fmt.Errorf("declaration %d is not a function of the IR", declaration.GetId())
+}
+
+// ControlFlowGraphToD3 converts a control flow graph into a D3Graph. Every block becomes a node
+// grouped by its kind, carrying the identifiers of its statements and the source range they
+// span, and every edge becomes a link carrying the kind of the edge. The entry block is marked
+// as the entry node.
+func ControlFlowGraphToD3(graph *ir_cfg.Graph) *D3Graph {
+	toReturn := NewD3Graph()
+	if graph == nil {
+		return toReturn
+	}
+
+	for _, block := range graph.GetBlocks() {
+		d3Node := &D3Node{
+			Id:    strconv.Itoa(block.GetId()),
+			Label: string(block.GetKind()),
+			Group: string(block.GetKind()),
+			Entry: block.GetId() == ir_cfg.EntryBlockId,
+			Metadata: map[string]any{
+				"function":      graph.GetName(),
+				"function_id":   graph.GetId(),
+				"statement_ids": block.StatementIds,
+				"reachable":     graph.IsReachable(block.GetId()),
+			},
+		}
+
+		if block.GetKind() == ir_cfg.BlockBasic {
+			d3Node.Label = "block " + d3Node.Id
+		}
+
+		if block.ConditionId != 0 {
+			d3Node.Metadata["condition_id"] = block.ConditionId
+		}
+
+		nodes := block.GetStatements()
+		if condition := block.GetCondition(); condition != nil {
+			nodes = append(append([]ast.Node[ast.NodeType]{}, nodes...), condition)
+		}
+
+		if len(nodes) > 0 {
+			src := nodes[0].GetSrc()
+			for _, node := range nodes[1:] {
+				if end := node.GetSrc().GetEnd(); end > src.End {
+					src.End = end
+				}
+			}
+			src.Length = src.End - src.Start
+			d3Node.Src = &src
+		}
+
+		toReturn.AddNode(d3Node)
+	}
+
+	for _, edge := range graph.GetEdges() {
+		toReturn.AddLink(strconv.Itoa(edge.From), strconv.Itoa(edge.To), D3LinkKind(edge.Kind), "")
+	}
+
+	return toReturn
+}
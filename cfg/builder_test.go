@@ -2,12 +2,15 @@ package cfg
 
 import (
 	"context"
+	"encoding/json"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/unpackdev/solgo"
 	"github.com/unpackdev/solgo/ast"
 	"github.com/unpackdev/solgo/ir"
+	ir_cfg "github.com/unpackdev/solgo/ir/cfg"
 	"github.com/unpackdev/solgo/tests"
 	"github.com/unpackdev/solgo/utils"
 	"go.uber.org/zap"
@@ -25,10 +28,11 @@ func TestCfgBuilder(t *testing.T) {
 
 	// Define multiple test cases
 	testCases := []struct {
-		name       string
-		outputPath string
-		sources    *solgo.Sources
-		wantErr    bool
+		name         string
+		outputPath   string
+		sources      *solgo.Sources
+		wantErr      bool
+		wantBuildErr bool
 	}{
 		{
 			name:       "Empty Contract Test",
@@ -45,6 +49,7 @@ func TestCfgBuilder(t *testing.T) {
 				MaskLocalSourcesPath: false,
 				LocalSourcesPath:     utils.GetLocalSourcesPath(),
 			},
+			wantBuildErr: true,
 		},
 		{
 			name:       "Simple Storage Contract Test",
@@ -100,6 +105,184 @@ func TestCfgBuilder(t *testing.T) {
 			assert.NotNil(t, builder)
 			assert.IsType(t, &Builder{}, builder)
 
+			if testCase.wantBuildErr {
+				assert.Error(t, builder.Build())
+				return
+			}
+
+			assert.NoError(t, builder.Build())
+
+			d3 := builder.ToD3()
+			assert.NotNil(t, d3)
+			assert.GreaterOrEqual(t, len(d3.Nodes), builder.GetGraph().CountNodes())
+			for _, link := range d3.Links {
+				assert.NotNil(t, d3.GetNode(link.Source))
+				assert.NotNil(t, d3.GetNode(link.Target))
+			}
+
+			d3Json, err := builder.ToD3JSON()
+			assert.NoError(t, err)
+			assert.NotEmpty(t, d3Json)
+
+			callGraph := builder.CallGraphToD3()
+			assert.NotEmpty(t, callGraph.Nodes)
+			for _, link := range callGraph.Links {
+				assert.NotNil(t, callGraph.GetNode(link.Source))
+				assert.NotNil(t, callGraph.GetNode(link.Target))
+				assert.NotEmpty(t, link.Kind)
+			}
+
+			callGraphJson, err := builder.CallGraphToD3JSON()
+			assert.NoError(t, err)
+			assert.NotEmpty(t, callGraphJson)
+
+			entry := parser.GetRoot().GetEntryContract()
+			for _, function := range entry.GetFunctions() {
+				controlFlow, err := builder.ControlFlowToD3(function.GetAST())
+				assert.NoError(t, err)
+				assert.True(t, controlFlow.GetNode("0").Entry)
+				for _, link := range controlFlow.Links {
+					assert.NotNil(t, controlFlow.GetNode(link.Source))
+					assert.NotNil(t, controlFlow.GetNode(link.Target))
+				}
+			}
+
+			_, err = builder.ControlFlowToD3(entry.GetAST())
+			assert.Error(t, err)
+			_, err = builder.ControlFlowToD3(nil)
+			assert.Error(t, err)
 		})
 	}
 }
+
+func TestD3CallGraphAndControlFlow(t *testing.T) {
+	sources := &solgo.Sources{
+		SourceUnits: []*solgo.SourceUnit{
+			{
+				Name: "Vault",
+				Path: "Vault.sol",
+				Content: `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+contract Base {
+    uint256 internal total;
+
+    function _record(uint256 amount) internal virtual {
+        total += amount;
+    }
+}
+
+contract Vault is Base {
+    function deposit(uint256 amount) external {
+        if (amount == 0) {
+            revert("empty");
+        }
+        _record(amount);
+    }
+}
+`,
+			},
+		},
+		EntrySourceUnitName: "Vault",
+		LocalSourcesPath:    utils.GetLocalSourcesPath(),
+	}
+
+	parser, err := ir.NewBuilderFromSources(context.TODO(), sources)
+	assert.NoError(t, err)
+	assert.Empty(t, parser.Parse())
+	assert.NoError(t, parser.Build())
+
+	builder, err := NewBuilder(context.Background(), parser)
+	assert.NoError(t, err)
+	assert.NoError(t, builder.Build())
+
+	// Call graph nodes are linked back to their declarations.
+	callGraph := builder.CallGraphToD3()
+	deposit := parser.GetRoot().GetCallGraph().GetNodeByName("Vault", "deposit")
+	record := parser.GetRoot().GetCallGraph().GetNodeByName("Base", "_record")
+	assert.NotNil(t, deposit)
+	assert.NotNil(t, record)
+
+	node := callGraph.GetNode(strconv.FormatInt(deposit.GetId(), 10))
+	assert.NotNil(t, node)
+	assert.Equal(t, "Vault.deposit", node.Label)
+	assert.True(t, node.Entry)
+	assert.NotNil(t, node.Src)
+	assert.Equal(t, deposit.GetAST().GetSrc(), *node.Src)
+	assert.False(t, callGraph.GetNode(strconv.FormatInt(record.GetId(), 10)).Entry)
+
+	assert.Len(t, callGraph.Links, 1)
+	assert.Equal(t, node.Id, callGraph.Links[0].Source)
+	assert.Equal(t, strconv.FormatInt(record.GetId(), 10), callGraph.Links[0].Target)
+	assert.Equal(t, D3LinkKind(ir.CallGraphEdgeInherited), callGraph.Links[0].Kind)
+
+	// Control flow blocks carry their kind, statements and source range.
+	controlFlow, err := builder.ControlFlowToD3(parser.GetRoot().GetContractByName("Vault").GetFunctions()[0].GetAST())
+	assert.NoError(t, err)
+	assert.True(t, controlFlow.GetNode("0").Entry)
+	assert.Equal(t, "exit", controlFlow.GetNode("1").Group)
+	assert.Equal(t, "revert", controlFlow.GetNode("2").Group)
+
+	kinds := make(map[D3LinkKind]bool)
+	for _, link := range controlFlow.Links {
+		assert.NotNil(t, controlFlow.GetNode(link.Source))
+		assert.NotNil(t, controlFlow.GetNode(link.Target))
+		kinds[link.Kind] = true
+	}
+	assert.True(t, kinds[D3LinkKind(ir_cfg.EdgeTrue)])
+	assert.True(t, kinds[D3LinkKind(ir_cfg.EdgeFalse)])
+	assert.True(t, kinds[D3LinkKind(ir_cfg.EdgeRevert)])
+
+	for _, node := range controlFlow.Nodes {
+		if node.Group == string(ir_cfg.BlockBasic) {
+			assert.NotNil(t, node.Src)
+			assert.Equal(t, node.Src.End-node.Src.Start, node.Src.Length)
+		}
+	}
+}
+
+func TestD3UnresolvedBase(t *testing.T) {
+	sources := &solgo.Sources{
+		SourceUnits: []*solgo.SourceUnit{
+			{
+				Name: "Vault",
+				Path: "Vault.sol",
+				Content: `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+import "./IVault.sol";
+
+contract Vault is IVault {
+    function deposit(uint256 amount) external {}
+}
+`,
+			},
+		},
+		EntrySourceUnitName: "Vault",
+		LocalSourcesPath:    utils.GetLocalSourcesPath(),
+	}
+
+	parser, err := ir.NewBuilderFromSources(context.TODO(), sources)
+	assert.NoError(t, err)
+	assert.Empty(t, parser.Parse())
+	assert.NoError(t, parser.Build())
+
+	builder, err := NewBuilder(context.Background(), parser)
+	assert.NoError(t, err)
+	assert.NoError(t, builder.Build())
+
+	// The base missing from the sources is linked to as an external node.
+	d3 := builder.ToD3()
+	assert.Len(t, d3.Links, 1)
+	assert.Equal(t, D3Link{Source: "Vault", Target: "IVault", Kind: D3LinkInheritance}, *d3.Links[0])
+	assert.Equal(t, "external", d3.GetNode("IVault").Group)
+
+	// Decoded graphs look their nodes up the same way.
+	encoded, err := d3.ToJSON()
+	assert.NoError(t, err)
+	decoded := &D3Graph{}
+	assert.NoError(t, json.Unmarshal(encoded, decoded))
+	assert.Equal(t, "Vault", decoded.GetNode("Vault").Label)
+	decoded.AddNode(&D3Node{Id: "IVault"})
+	assert.Len(t, decoded.Nodes, 2)
+}
@@ -0,0 +1,355 @@
+package solgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/antlr4-go/antlr/v4"
+	"github.com/unpackdev/solgo/parser"
+	"github.com/unpackdev/solgo/syntaxerrors"
+)
+
+// ErrChunkTooLarge is returned when a single definition of a source exceeds the maximum chunk size.
+var ErrChunkTooLarge = errors.New("chunk exceeds the maximum size")
+
+// chunkCancelCheckInterval is the number of tokens scanned between checks of the context.
+const chunkCancelCheckInterval = 4096
+
+// ChunkKind is the kind of top level definition a chunk holds.
+type ChunkKind string
+
+const (
+	ChunkContract  ChunkKind = "contract"
+	ChunkAbstract  ChunkKind = "abstract"
+	ChunkInterface ChunkKind = "interface"
+	ChunkLibrary   ChunkKind = "library"
+	// ChunkGlobal holds pragmas, imports and free definitions found between contracts.
+	ChunkGlobal ChunkKind = "global"
+)
+
+// ChunkOptions controls how a source is split into chunks.
+type ChunkOptions struct {
+	// MaxChunkSize is the largest number of characters a chunk may hand to the parser. Bodies left out
+	// through LazyBodies do not count towards it. Zero disables the limit.
+	MaxChunkSize int
+	// LazyBodies leaves the bodies of functions, modifiers, constructors, fallback and receive functions
+	// out of chunk contents so that they are parsed only when requested through SourceChunk.ParseBody.
+	LazyBodies bool
+}
+
+// BodyRange locates a function-like body within the source.
+type BodyRange struct {
+	Owner  string `json:"owner"`  // Name of the function, or the constructor, fallback, receive or modifier name.
+	Start  int    `json:"start"`  // Offset of the opening brace, in characters.
+	End    int    `json:"end"`    // Offset of the closing brace, in characters.
+	Line   int    `json:"line"`   // Line of the opening brace.
+	Column int    `json:"column"` // Column of the opening brace.
+}
+
+// SourceChunk is a top level definition of a source, or a run of global definitions between them,
+// that can be parsed on its own.
+type SourceChunk struct {
+	Index  int          `json:"index"`
+	Kind   ChunkKind    `json:"kind"`
+	Name   string       `json:"name"`
+	Start  int          `json:"start"` // Offset of the first character, in characters.
+	End    int          `json:"end"`   // Offset of the last character, in characters.
+	Line   int          `json:"line"`  // Line of the first character.
+	Bodies []*BodyRange `json:"bodies"`
+	lazy   bool
+	source []rune // source is shared by every chunk of the same source.
+}
+
+// GetSize returns the number of characters of the chunk.
+func (c *SourceChunk) GetSize() int {
+	return c.End - c.Start + 1
+}
+
+// GetParsedSize returns the number of characters of the chunk handed to the parser, leaving out lazy bodies.
+func (c *SourceChunk) GetParsedSize() int {
+	toReturn := c.GetSize()
+	if c.lazy {
+		for _, body := range c.Bodies {
+			toReturn -= body.End - body.Start - 1
+		}
+	}
+	return toReturn
+}
+
+// GetContent returns the source of the chunk. When bodies are lazy, everything between their braces is
+// blanked out, keeping line breaks, so that positions within the chunk match the original source.
+func (c *SourceChunk) GetContent() string {
+	if !c.lazy || len(c.Bodies) == 0 {
+		return string(c.source[c.Start : c.End+1])
+	}
+
+	content := make([]rune, c.GetSize())
+	copy(content, c.source[c.Start:c.End+1])
+	for _, body := range c.Bodies {
+		for i := body.Start + 1; i < body.End; i++ {
+			if content[i-c.Start] != '\n' {
+				content[i-c.Start] = ' '
+			}
+		}
+	}
+	return string(content)
+}
+
+// GetBody returns the source of the body, braces included.
+func (c *SourceChunk) GetBody(body *BodyRange) string {
+	return string(c.source[body.Start : body.End+1])
+}
+
+// NewParser creates a parser over the content of the chunk.
+func (c *SourceChunk) NewParser(ctx context.Context) (*Parser, error) {
+	return NewParser(ctx, strings.NewReader(c.GetContent()))
+}
+
+// ParseBody parses the body on its own and returns its parse tree together with any syntax errors.
+// Positions within the tree are relative to the opening brace.
+func (c *SourceChunk) ParseBody(body *BodyRange) (parser.IBlockContext, []syntaxerrors.SyntaxError) {
+	return parseBlock(antlr.NewInputStream(c.GetBody(body)), 1, 0)
+}
+
+// parseBlock parses a block from the current position of the input, with the first token placed at
+// the given line and column.
+func parseBlock(input antlr.CharStream, line int, column int) (parser.IBlockContext, []syntaxerrors.SyntaxError) {
+	errListener := syntaxerrors.NewSyntaxErrorListener()
+
+	lexer := parser.NewSolidityLexer(input)
+	lexer.RemoveErrorListeners()
+	lexer.AddErrorListener(errListener)
+	if simulator, ok := lexer.Interpreter.(*antlr.LexerATNSimulator); ok {
+		simulator.Line = line
+		simulator.CharPositionInLine = column
+	}
+
+	stream := antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel)
+	contextualParser := syntaxerrors.NewContextualParser(stream, errListener)
+
+	tree := contextualParser.Block()
+	if len(errListener.Errors) > 0 {
+		return tree, errListener.Errors
+	}
+	return tree, nil
+}
+
+// SplitSource splits the source into chunks. See ForEachChunk.
+func SplitSource(ctx context.Context, content string, opts ChunkOptions) ([]*SourceChunk, error) {
+	toReturn := make([]*SourceChunk, 0)
+	err := ForEachChunk(ctx, content, opts, func(chunk *SourceChunk) error {
+		toReturn = append(toReturn, chunk)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toReturn, nil
+}
+
+// ForEachChunk tokenizes the source once, without building a parse tree, and calls fn with every
+// contract, abstract contract, interface and library definition as soon as its closing brace is seen.
+// Pragmas, imports and free definitions between them are handed over as global chunks. Machine generated
+// sources of many megabytes can then be parsed one definition at a time, so that only the parse tree of
+// the current chunk has to be held in memory.
+//
+// Chunks are independent of each other, so references between them, such as inherited contracts, are
+// left for the caller to resolve. Scanning stops at the first error returned by fn, when the context is
+// done or when a chunk exceeds the maximum size.
+func ForEachChunk(ctx context.Context, content string, opts ChunkOptions, fn func(chunk *SourceChunk) error) error {
+	s := &chunkScanner{
+		opts:   opts,
+		source: []rune(content),
+		fn:     fn,
+	}
+
+	lexer := parser.NewSolidityLexer(antlr.NewInputStream(content))
+	lexer.RemoveErrorListeners()
+
+	for count := 0; ; count++ {
+		if count%chunkCancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+
+		token := lexer.NextToken()
+		if token.GetTokenType() == antlr.TokenEOF {
+			break
+		}
+		if token.GetChannel() != antlr.TokenDefaultChannel {
+			continue
+		}
+		if err := s.scan(token); err != nil {
+			return err
+		}
+	}
+
+	return s.flush(len(s.source) - 1)
+}
+
+// chunkScanner groups the tokens of a source into chunks.
+type chunkScanner struct {
+	opts   ChunkOptions
+	source []rune
+	fn     func(chunk *SourceChunk) error
+	index  int
+	depth  int
+
+	current   *SourceChunk // current is the chunk being scanned, if any.
+	expecting bool         // expecting is set when the next token names the current chunk.
+	lastStop  int          // lastStop is the offset of the last character of the previous token.
+
+	owner     string     // owner names the function-like definition whose body comes next.
+	ownerNext bool       // ownerNext is set when the next token names the owner.
+	body      *BodyRange // body is the body being scanned, if any.
+	bodyDepth int
+}
+
+// scan processes a single token of the default channel.
+func (s *chunkScanner) scan(token antlr.Token) error {
+	defer func() { s.lastStop = token.GetStop() }()
+
+	if s.expecting {
+		s.current.Name = token.GetText()
+		s.expecting = false
+	}
+
+	if s.depth == 0 {
+		if kind, ok := chunkKinds[token.GetTokenType()]; ok {
+			if s.current != nil && s.current.Kind == ChunkAbstract && s.current.Name == "" {
+				// The contract keyword following abstract belongs to the same chunk.
+				s.expecting = true
+				return nil
+			}
+			if err := s.flush(s.lastStop); err != nil {
+				return err
+			}
+			s.open(kind, token)
+			s.expecting = kind != ChunkAbstract
+			return nil
+		}
+		if s.current == nil {
+			s.open(ChunkGlobal, token)
+		}
+	}
+
+	s.scanMember(token)
+
+	switch token.GetTokenType() {
+	case parser.SolidityLexerLBrace, parser.SolidityLexerAssemblyLBrace, parser.SolidityLexerYulLBrace:
+		s.depth++
+	case parser.SolidityLexerRBrace, parser.SolidityLexerYulRBrace:
+		if s.depth > 0 {
+			s.depth--
+		}
+		if s.body != nil && s.depth == s.bodyDepth {
+			s.body.End = token.GetStop()
+			s.current.Bodies = append(s.current.Bodies, s.body)
+			s.body = nil
+		}
+		if s.depth == 0 && s.current != nil && s.current.Kind != ChunkGlobal {
+			return s.flush(token.GetStop())
+		}
+	}
+
+	return nil
+}
+
+// scanMember tracks function-like definitions at the member level of the current chunk to locate
+// their bodies.
+func (s *chunkScanner) scanMember(token antlr.Token) {
+	if s.current == nil || s.body != nil {
+		return
+	}
+
+	memberDepth := 1
+	if s.current.Kind == ChunkGlobal {
+		memberDepth = 0
+	}
+	if s.depth != memberDepth {
+		return
+	}
+
+	if s.ownerNext {
+		if token.GetTokenType() != parser.SolidityLexerLParen {
+			s.owner = token.GetText()
+		}
+		s.ownerNext = false
+	}
+
+	switch token.GetTokenType() {
+	case parser.SolidityLexerFunction, parser.SolidityLexerModifier:
+		if s.owner == "" {
+			s.ownerNext = true
+			s.owner = token.GetText()
+		}
+	case parser.SolidityLexerConstructor, parser.SolidityLexerFallback, parser.SolidityLexerReceive:
+		if s.owner == "" {
+			s.owner = token.GetText()
+		}
+	case parser.SolidityLexerSemicolon:
+		s.owner = ""
+		s.ownerNext = false
+	case parser.SolidityLexerLBrace:
+		if s.owner != "" {
+			s.body = &BodyRange{
+				Owner:  s.owner,
+				Start:  token.GetStart(),
+				Line:   token.GetLine(),
+				Column: token.GetColumn(),
+			}
+			s.bodyDepth = memberDepth
+			s.owner = ""
+		}
+	}
+}
+
+// open starts a new chunk at the token.
+func (s *chunkScanner) open(kind ChunkKind, token antlr.Token) {
+	s.current = &SourceChunk{
+		Index:  s.index,
+		Kind:   kind,
+		Start:  token.GetStart(),
+		Line:   token.GetLine(),
+		Bodies: make([]*BodyRange, 0),
+		lazy:   s.opts.LazyBodies,
+		source: s.source,
+	}
+	s.index++
+	s.owner, s.ownerNext, s.body = "", false, nil
+}
+
+// flush hands the current chunk, ending at the given offset, over to the callback.
+func (s *chunkScanner) flush(end int) error {
+	if s.current == nil {
+		return nil
+	}
+
+	chunk := s.current
+	s.current = nil
+	chunk.End = end
+	if s.body != nil {
+		// Unbalanced braces; the parser reports the error once the chunk is parsed.
+		s.body = nil
+	}
+
+	if s.opts.MaxChunkSize > 0 && chunk.GetParsedSize() > s.opts.MaxChunkSize {
+		return fmt.Errorf(
+			"%w: %s %s at line %d has %d characters, limit is %d",
+			ErrChunkTooLarge, chunk.Kind, chunk.Name, chunk.Line, chunk.GetParsedSize(), s.opts.MaxChunkSize,
+		)
+	}
+
+	return s.fn(chunk)
+}
+
+// chunkKinds maps the keywords opening a top level definition to the kind of chunk they start.
+var chunkKinds = map[int]ChunkKind{
+	parser.SolidityLexerContract:  ChunkContract,
+	parser.SolidityLexerAbstract:  ChunkAbstract,
+	parser.SolidityLexerInterface: ChunkInterface,
+	parser.SolidityLexerLibrary:   ChunkLibrary,
+}
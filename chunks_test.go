@@ -0,0 +1,174 @@
+package solgo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const chunkedSourceForTest = `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+import "./Other.sol";
+
+function freeAdd(uint256 a, uint256 b) pure returns (uint256) {
+    return a + b;
+}
+
+interface IRouter {
+    function route(uint256 id) external returns (uint256);
+}
+
+abstract contract Base {
+    function hook() internal virtual;
+}
+
+library Math {
+    function max(uint256 a, uint256 b) internal pure returns (uint256) {
+        return a > b ? a : b;
+    }
+}
+
+contract Router is Base, IRouter {
+    uint256 public counter;
+    function(uint256) external returns (uint256) public handler;
+
+    modifier counted() {
+        counter++;
+        _;
+    }
+
+    constructor() {
+        counter = 1;
+    }
+
+    function route(uint256 id) external counted returns (uint256) {
+        if (id > 1) {
+            return Math.max(id, counter);
+        }
+        assembly {
+            let x := add(id, 1)
+            if gt(x, 2) { x := 2 }
+        }
+        return freeAdd(id, 1);
+    }
+
+    function hook() internal override {}
+
+    receive() external payable {}
+}
+`
+
+func TestSplitSource(t *testing.T) {
+	ctx := context.Background()
+
+	chunks, err := SplitSource(ctx, chunkedSourceForTest, ChunkOptions{})
+	require.NoError(t, err)
+	require.Len(t, chunks, 5)
+
+	expected := []struct {
+		kind ChunkKind
+		name string
+		line int
+	}{
+		{ChunkGlobal, "", 2},
+		{ChunkInterface, "IRouter", 10},
+		{ChunkAbstract, "Base", 14},
+		{ChunkLibrary, "Math", 18},
+		{ChunkContract, "Router", 24},
+	}
+	for i, chunk := range chunks {
+		assert.Equal(t, i, chunk.Index)
+		assert.Equal(t, expected[i].kind, chunk.Kind)
+		assert.Equal(t, expected[i].name, chunk.Name)
+		assert.Equal(t, expected[i].line, chunk.Line)
+
+		parser, err := chunk.NewParser(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, parser.Parse(), chunk.Name)
+	}
+
+	assert.True(t, strings.HasPrefix(chunks[0].GetContent(), "pragma solidity"))
+	assert.True(t, strings.HasSuffix(chunks[0].GetContent(), "}"))
+	assert.True(t, strings.HasPrefix(chunks[2].GetContent(), "abstract contract Base {"))
+	assert.True(t, strings.HasSuffix(chunks[4].GetContent(), "receive() external payable {}\n}"))
+
+	owners := make([]string, 0)
+	for _, body := range chunks[4].Bodies {
+		owners = append(owners, body.Owner)
+	}
+	assert.Equal(t, []string{"counted", "constructor", "route", "hook", "receive"}, owners)
+	assert.Equal(t, "freeAdd", chunks[0].Bodies[0].Owner)
+	assert.Empty(t, chunks[1].Bodies)
+	assert.Empty(t, chunks[2].Bodies)
+}
+
+func TestSplitSourceLazyBodies(t *testing.T) {
+	ctx := context.Background()
+
+	chunks, err := SplitSource(ctx, chunkedSourceForTest, ChunkOptions{LazyBodies: true})
+	require.NoError(t, err)
+	require.Len(t, chunks, 5)
+
+	router := chunks[4]
+	content := router.GetContent()
+	assert.Equal(t, router.GetSize(), len([]rune(content)))
+	assert.NotContains(t, content, "Math.max")
+	assert.NotContains(t, content, "assembly")
+	assert.Equal(t, strings.Count(string(router.source[router.Start:router.End+1]), "\n"), strings.Count(content, "\n"))
+	assert.Less(t, router.GetParsedSize(), router.GetSize())
+
+	parser, err := router.NewParser(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, parser.Parse())
+
+	route := router.Bodies[2]
+	assert.Equal(t, "route", route.Owner)
+	assert.Equal(t, 37, route.Line)
+	assert.Contains(t, router.GetBody(route), "Math.max(id, counter)")
+
+	tree, errs := router.ParseBody(route)
+	assert.Empty(t, errs)
+	require.NotNil(t, tree)
+	assert.Len(t, tree.AllStatement(), 3)
+}
+
+func TestSplitSourceSafeguards(t *testing.T) {
+	var source strings.Builder
+	source.WriteString("pragma solidity ^0.8.0;\n")
+	for i := 0; i < 100; i++ {
+		fmt.Fprintf(&source, "contract Generated%d {\n    function f() external pure returns (uint256) { return %d; }\n}\n", i, i)
+	}
+
+	chunks, err := SplitSource(context.Background(), source.String(), ChunkOptions{MaxChunkSize: 200})
+	require.NoError(t, err)
+	assert.Len(t, chunks, 101)
+	assert.Equal(t, "Generated99", chunks[100].Name)
+
+	_, err = SplitSource(context.Background(), source.String(), ChunkOptions{MaxChunkSize: 80})
+	assert.ErrorIs(t, err, ErrChunkTooLarge)
+
+	_, err = SplitSource(context.Background(), source.String(), ChunkOptions{MaxChunkSize: 80, LazyBodies: true})
+	assert.NoError(t, err)
+
+	visited := 0
+	stop := fmt.Errorf("stop")
+	err = ForEachChunk(context.Background(), source.String(), ChunkOptions{}, func(chunk *SourceChunk) error {
+		visited++
+		if chunk.Name == "Generated9" {
+			return stop
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 11, visited)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = SplitSource(ctx, source.String(), ChunkOptions{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
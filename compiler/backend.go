@@ -0,0 +1,41 @@
+package compiler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/0x19/solc-switch"
+)
+
+// Backend runs compiler versions in standard JSON mode. NativeBackend runs the binaries managed by
+// solc-switch and DockerBackend the official solc images.
+type Backend interface {
+	// Compile compiles the input with the compiler version, such as 0.8.19. The output is returned along
+	// with an error when the compilation fails, so its structured errors can be inspected.
+	Compile(ctx context.Context, version string, input *Input) (*Output, error)
+}
+
+// NativeBackend runs the solc binaries managed by solc-switch, downloading them on first use.
+type NativeBackend struct {
+	solc *solc.Solc // Resolves the binaries of compiler versions.
+}
+
+// NewNativeBackend creates a backend running the binaries managed by the solc-switch instance.
+func NewNativeBackend(compiler *solc.Solc) *NativeBackend {
+	return &NativeBackend{solc: compiler}
+}
+
+// Compile compiles the input with the binary of the compiler version, downloading it first if it is not
+// cached yet.
+func (b *NativeBackend) Compile(ctx context.Context, version string, input *Input) (*Output, error) {
+	if b.solc == nil {
+		return nil, errors.New("native backend needs solc-switch to resolve binaries")
+	}
+
+	binary, err := EnsureBinary(b.solc, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return compileWithBinary(ctx, binary, input)
+}
@@ -0,0 +1,278 @@
+package compiler
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/unpackdev/solgo/utils"
+)
+
+// cacheKeyFormat versions the derivation of cache keys, so outputs cached by older derivations are
+// never mistaken for current ones.
+const cacheKeyFormat = "solgo-compile-v1"
+
+// CacheStore stores compiler outputs by content addressed key, as NewCacheKey derives them. Stores are
+// shared by concurrent compilations, so implementations have to be safe for concurrent use.
+type CacheStore interface {
+	// Get returns the value of the key, and false if there is none.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores the value of the key.
+	Set(ctx context.Context, key string, value []byte) error
+}
+
+// NewCacheKey returns the content addressed key of the compilation of the parts, such as the standard
+// JSON input and the arguments, with the compiler version. Identical compilations yield identical keys
+// and any difference in the parts yields another key.
+func NewCacheKey(version string, parts ...string) string {
+	data := []byte(cacheKeyFormat)
+	for _, part := range append([]string{strings.TrimPrefix(version, "v")}, parts...) {
+		// Parts are length prefixed, so that moving bytes from one part to the next changes the key.
+		data = binary.BigEndian.AppendUint64(data, uint64(len(part)))
+		data = append(data, part...)
+	}
+	return hex.EncodeToString(utils.Keccak256(data))
+}
+
+// InputCacheKey returns the content addressed key of the compilation of the input, its prepared sources
+// and settings, with the compiler version.
+func InputCacheKey(version string, input *Input) (string, error) {
+	if input == nil {
+		return "", errors.New("input must be set")
+	}
+
+	encoded, err := input.ToJSON()
+	if err != nil {
+		return "", err
+	}
+	return NewCacheKey(version, string(encoded)), nil
+}
+
+// CachedBackend is a backend returning the outputs of compilations it already ran from its store, and
+// running the others with the backend it wraps. Only successful compilations are cached.
+type CachedBackend struct {
+	backend Backend
+	store   CacheStore
+	hits    atomic.Int64
+	misses  atomic.Int64
+}
+
+// NewCachedBackend creates a backend caching the outputs of the backend in the store.
+func NewCachedBackend(backend Backend, store CacheStore) *CachedBackend {
+	return &CachedBackend{
+		backend: backend,
+		store:   store,
+	}
+}
+
+// GetBackend returns the backend running the compilations not cached.
+func (b *CachedBackend) GetBackend() Backend {
+	return b.backend
+}
+
+// GetStore returns the store outputs are cached in.
+func (b *CachedBackend) GetStore() CacheStore {
+	return b.store
+}
+
+// GetHits returns the number of compilations whose output came from the store.
+func (b *CachedBackend) GetHits() int64 {
+	return b.hits.Load()
+}
+
+// GetMisses returns the number of compilations the wrapped backend ran.
+func (b *CachedBackend) GetMisses() int64 {
+	return b.misses.Load()
+}
+
+// Compile returns the cached output of the compilation of the input with the compiler version, or runs
+// the compilation and caches its output. Failing to read or write the store only costs the compilation.
+func (b *CachedBackend) Compile(ctx context.Context, version string, input *Input) (*Output, error) {
+	key, err := InputCacheKey(version, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached, found, err := b.store.Get(ctx, key); err == nil && found {
+		var output Output
+		if err := json.Unmarshal(cached, &output); err == nil {
+			b.hits.Add(1)
+			return &output, nil
+		}
+	}
+
+	b.misses.Add(1)
+	output, err := b.backend.Compile(ctx, version, input)
+	if err != nil {
+		return output, err
+	}
+
+	if encoded, err := json.Marshal(output); err == nil {
+		_ = b.store.Set(ctx, key, encoded)
+	}
+	return output, nil
+}
+
+// CountingCacheStore is a store counting the hits and misses of the store it wraps. Compilers and verifiers
+// given a store wrap it in backends of their own, so the store is where the hits of every one of them can
+// be counted.
+type CountingCacheStore struct {
+	store  CacheStore
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// NewCountingCacheStore creates a store counting the hits and misses of the store.
+func NewCountingCacheStore(store CacheStore) *CountingCacheStore {
+	return &CountingCacheStore{store: store}
+}
+
+// GetStore returns the store the values are kept in.
+func (s *CountingCacheStore) GetStore() CacheStore {
+	return s.store
+}
+
+// GetHits returns the number of reads of keys the store had a value for.
+func (s *CountingCacheStore) GetHits() int64 {
+	return s.hits.Load()
+}
+
+// GetMisses returns the number of reads of keys the store had no value for, or failed to read.
+func (s *CountingCacheStore) GetMisses() int64 {
+	return s.misses.Load()
+}
+
+// Get returns the value of the key from the wrapped store, counting the read as a hit or a miss.
+func (s *CountingCacheStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, found, err := s.store.Get(ctx, key)
+	if err == nil && found {
+		s.hits.Add(1)
+	} else {
+		s.misses.Add(1)
+	}
+	return value, found, err
+}
+
+// Set stores the value of the key in the wrapped store.
+func (s *CountingCacheStore) Set(ctx context.Context, key string, value []byte) error {
+	return s.store.Set(ctx, key, value)
+}
+
+// FileCacheStore stores values as files of a directory, in subdirectories named by the first two
+// characters of their keys.
+type FileCacheStore struct {
+	dir string
+}
+
+// NewFileCacheStore creates a store in the directory, creating it if it does not exist yet.
+func NewFileCacheStore(dir string) (*FileCacheStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory %s: %w", dir, err)
+	}
+	return &FileCacheStore{dir: dir}, nil
+}
+
+// GetDir returns the directory of the store.
+func (s *FileCacheStore) GetDir() string {
+	return s.dir
+}
+
+// path returns the path of the file of the key.
+func (s *FileCacheStore) path(key string) (string, error) {
+	if len(key) < 3 || strings.ContainsAny(key, `/\.`) {
+		return "", fmt.Errorf("invalid cache key %s", key)
+	}
+	return filepath.Join(s.dir, key[:2], key+".json"), nil
+}
+
+// Get returns the value of the key, and false if there is none.
+func (s *FileCacheStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	value, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set stores the value of the key. The file is written aside and renamed into place, so concurrent
+// readers never see it partially written.
+func (s *FileCacheStore) Set(ctx context.Context, key string, value []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(value); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+// RedisCacheStore stores values in Redis, under keys with a prefix, expiring after a time to live.
+type RedisCacheStore struct {
+	client *redis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+// NewRedisCacheStore creates a store in Redis. Keys are prefixed with the prefix, such as solgo:compile:,
+// and values expire after the time to live, or never if it is zero.
+func NewRedisCacheStore(client *redis.Client, prefix string, ttl time.Duration) (*RedisCacheStore, error) {
+	if client == nil {
+		return nil, errors.New("redis client must be set")
+	}
+
+	return &RedisCacheStore{
+		client: client,
+		prefix: prefix,
+		ttl:    ttl,
+	}, nil
+}
+
+// Get returns the value of the key, and false if there is none.
+func (s *RedisCacheStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := s.client.Get(ctx, s.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set stores the value of the key.
+func (s *RedisCacheStore) Set(ctx context.Context, key string, value []byte) error {
+	return s.client.Set(ctx, s.prefix+key, value, s.ttl).Err()
+}
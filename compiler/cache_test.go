@@ -0,0 +1,161 @@
+package compiler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingBackend returns the output, or the error, and counts its compilations.
+type countingBackend struct {
+	output       *Output
+	err          error
+	compilations int
+}
+
+func (b *countingBackend) Compile(ctx context.Context, version string, input *Input) (*Output, error) {
+	b.compilations++
+	return b.output, b.err
+}
+
+func TestCacheKey(t *testing.T) {
+	key := NewCacheKey("0.8.19", "source", "--optimize")
+	assert.Len(t, key, 64)
+	assert.Equal(t, key, NewCacheKey("v0.8.19", "source", "--optimize"))
+	assert.NotEqual(t, key, NewCacheKey("0.8.20", "source", "--optimize"))
+	assert.NotEqual(t, key, NewCacheKey("0.8.19", "sourc", "e--optimize"), "parts are delimited")
+
+	input := NewInput(map[string]string{"Counter.sol": "contract Counter {}"}).Select("*", "*", DefaultOutputs...)
+	inputKey, err := InputCacheKey("0.8.19", input)
+	require.NoError(t, err)
+	sameKey, err := InputCacheKey("0.8.19", NewInput(map[string]string{"Counter.sol": "contract Counter {}"}).Select("*", "*", DefaultOutputs...))
+	require.NoError(t, err)
+	assert.Equal(t, inputKey, sameKey)
+
+	require.NoError(t, (&Config{Optimized: true, OptimizerRuns: 200}).Apply(input))
+	optimizedKey, err := InputCacheKey("0.8.19", input)
+	require.NoError(t, err)
+	assert.NotEqual(t, inputKey, optimizedKey, "settings are part of the key")
+
+	_, err = InputCacheKey("0.8.19", nil)
+	assert.Error(t, err)
+}
+
+func TestFileCacheStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+	store, err := NewFileCacheStore(dir)
+	require.NoError(t, err)
+	assert.Equal(t, dir, store.GetDir())
+
+	key := NewCacheKey("0.8.19", "source")
+	_, found, err := store.Get(context.TODO(), key)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	require.NoError(t, store.Set(context.TODO(), key, []byte(`{"sources":{}}`)))
+	value, found, err := store.Get(context.TODO(), key)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, `{"sources":{}}`, string(value))
+	assert.FileExists(t, filepath.Join(dir, key[:2], key+".json"))
+
+	entries, err := os.ReadDir(filepath.Join(dir, key[:2]))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary files are renamed into place")
+
+	_, _, err = store.Get(context.TODO(), "../escape")
+	assert.Error(t, err)
+	assert.Error(t, store.Set(context.TODO(), "a/b", nil))
+}
+
+func TestCachedBackend(t *testing.T) {
+	store, err := NewFileCacheStore(t.TempDir())
+	require.NoError(t, err)
+
+	backend := &countingBackend{output: &Output{Contracts: map[string]map[string]*Contract{"Counter.sol": {"Counter": {}}}}}
+	compiler := &Compiler{ctx: context.TODO(), backend: backend}
+	compiler.SetCache(store)
+	cached, ok := compiler.GetBackend().(*CachedBackend)
+	require.True(t, ok)
+	assert.Same(t, backend, cached.GetBackend())
+	assert.Same(t, store, cached.GetStore())
+
+	input := NewInput(map[string]string{"Counter.sol": "contract Counter {}"}).Select("*", "*", DefaultOutputs...)
+	for i := 0; i < 3; i++ {
+		output, err := compiler.Compile(context.TODO(), "0.8.19", input)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Counter.sol"}, output.GetFiles())
+		assert.Equal(t, "0.8.19", output.Version)
+	}
+	assert.Equal(t, 1, backend.compilations)
+	assert.Equal(t, int64(2), cached.GetHits())
+	assert.Equal(t, int64(1), cached.GetMisses())
+
+	_, err = compiler.Compile(context.TODO(), "0.8.20", input)
+	require.NoError(t, err)
+	assert.Equal(t, 2, backend.compilations, "other versions are other compilations")
+
+	// Failed compilations are not cached.
+	failing := &countingBackend{output: &Output{}, err: errors.New("compilation failed")}
+	failingCached := NewCachedBackend(failing, store)
+	other := NewInput(map[string]string{"Other.sol": "contract Other {"})
+	for i := 0; i < 2; i++ {
+		_, err := failingCached.Compile(context.TODO(), "0.8.19", other)
+		assert.Error(t, err)
+	}
+	assert.Equal(t, 2, failing.compilations)
+}
+
+func TestCountingCacheStore(t *testing.T) {
+	files, err := NewFileCacheStore(t.TempDir())
+	require.NoError(t, err)
+	store := NewCountingCacheStore(files)
+	assert.Same(t, files, store.GetStore())
+
+	_, found, err := store.Get(context.TODO(), "key")
+	require.NoError(t, err)
+	assert.False(t, found)
+	require.NoError(t, store.Set(context.TODO(), "key", []byte("value")))
+	value, found, err := store.Get(context.TODO(), "key")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []byte("value"), value)
+
+	// Compilers wrapping the store in a cached backend of their own are counted too.
+	backend := &countingBackend{output: &Output{}}
+	cached := NewCachedBackend(backend, store)
+	input := NewInput(map[string]string{"A.sol": "contract A {}"})
+	for i := 0; i < 2; i++ {
+		_, err := cached.Compile(context.TODO(), "0.8.19", input)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int64(2), store.GetHits())
+	assert.Equal(t, int64(2), store.GetMisses())
+}
+
+func TestRedisCacheStore(t *testing.T) {
+	_, err := NewRedisCacheStore(nil, "solgo:compile:", time.Hour)
+	assert.Error(t, err)
+
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 100 * time.Millisecond, MaxRetries: -1})
+	defer client.Close()
+	store, err := NewRedisCacheStore(client, "solgo:compile:", time.Hour)
+	require.NoError(t, err)
+
+	// Unreachable stores fail the lookup, which the cached backend treats as a miss.
+	_, _, err = store.Get(context.TODO(), "key")
+	assert.Error(t, err)
+
+	backend := &countingBackend{output: &Output{}}
+	output, err := NewCachedBackend(backend, store).Compile(context.TODO(), "0.8.19", NewInput(map[string]string{"A.sol": "contract A {}"}))
+	require.NoError(t, err)
+	assert.NotNil(t, output)
+	assert.Equal(t, 1, backend.compilations)
+}
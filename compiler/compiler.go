@@ -0,0 +1,153 @@
+package compiler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/0x19/solc-switch"
+	"github.com/unpackdev/solgo"
+)
+
+// Compiler compiles inputs through the standard JSON interface of solc, running the binaries managed by
+// solc-switch unless another backend is set.
+type Compiler struct {
+	ctx     context.Context // The context for the compiler operations.
+	solc    *solc.Solc      // Resolves the binaries and releases of compiler versions.
+	backend Backend         // Runs the compiler versions.
+	config  *Config         // Settings applied to every input, if set.
+}
+
+// NewCompiler creates a compiler using the binaries managed by the solc-switch instance.
+func NewCompiler(ctx context.Context, compiler *solc.Solc) (*Compiler, error) {
+	if compiler == nil {
+		return nil, errors.New("compiler must be set")
+	}
+
+	return &Compiler{
+		ctx:     ctx,
+		solc:    compiler,
+		backend: NewNativeBackend(compiler),
+	}, nil
+}
+
+// GetContext returns the context associated with the compiler.
+func (c *Compiler) GetContext() context.Context {
+	return c.ctx
+}
+
+// SetBackend sets the backend running the compiler versions, such as a DockerBackend on hosts where
+// installing native binaries is impractical or to sandbox untrusted sources. Versions are still resolved
+// from the releases known to solc-switch.
+func (c *Compiler) SetBackend(backend Backend) {
+	c.backend = backend
+}
+
+// GetBackend returns the backend running the compiler versions.
+func (c *Compiler) GetBackend() Backend {
+	return c.backend
+}
+
+// SetCache caches the outputs of the compilations of the backend in the store, so compilations of the
+// same prepared sources, compiler version and settings run once.
+func (c *Compiler) SetCache(store CacheStore) {
+	c.backend = NewCachedBackend(c.backend, store)
+}
+
+// SetConfig sets the settings applied to every input compiled, such as those of a deployment being
+// verified. Without a config, the settings of inputs are left as they are.
+func (c *Compiler) SetConfig(config *Config) {
+	c.config = config
+}
+
+// GetConfig returns the settings applied to every input compiled, if set.
+func (c *Compiler) GetConfig() *Config {
+	return c.config
+}
+
+// Compile compiles the input with the compiler version, such as 0.8.19. The output is returned along
+// with an error when the compilation fails, so its structured errors can be inspected. The backend runs
+// the version, the native one downloading its binary first if it is not cached yet, and the config, if
+// set, is applied to the input.
+func (c *Compiler) Compile(ctx context.Context, version string, input *Input) (*Output, error) {
+	if c.config != nil && input != nil {
+		if err := c.config.Apply(input); err != nil {
+			return nil, err
+		}
+	}
+
+	output, err := c.backend.Compile(ctx, version, input)
+	if output != nil {
+		output.Version = strings.TrimPrefix(version, "v")
+	}
+	return output, err
+}
+
+// ResolveVersion returns the latest compiler release satisfying every solidity pragma of the sources.
+func (c *Compiler) ResolveVersion(sources *solgo.Sources) (string, error) {
+	return ResolveVersion(c.solc, sources)
+}
+
+// CompileSources compiles the sources, selecting the DefaultOutputs of every contract, with the latest
+// compiler release satisfying their solidity pragmas.
+func (c *Compiler) CompileSources(ctx context.Context, sources *solgo.Sources) (*Output, error) {
+	version, err := c.ResolveVersion(sources)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := NewInputFromSources(sources)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Compile(ctx, version, input)
+}
+
+// compileWithBinary runs the solc binary in standard JSON mode with the input on its standard input.
+func compileWithBinary(ctx context.Context, binary string, input *Input) (*Output, error) {
+	return runStandardJSON(ctx, binary, []string{"--standard-json"}, input)
+}
+
+// runStandardJSON runs the command, which runs solc in standard JSON mode, with the input on its standard
+// input and decodes the output it writes to its standard output.
+func runStandardJSON(ctx context.Context, name string, args []string, input *Input) (*Output, error) {
+	if input == nil {
+		return nil, errors.New("input must be set")
+	}
+
+	encoded, err := input.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	// #nosec G204
+	// The command is resolved by solc-switch or set by the backend, and the input is passed on the
+	// standard input rather than as arguments.
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = bytes.NewReader(encoded)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	// Compilation errors are reported in the output, so failing to run at all is the only failure here.
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to run %s: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var output Output
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return nil, fmt.Errorf("failed to decode standard json output: %w", err)
+	}
+
+	if compileErrors := output.GetErrors(); len(compileErrors) > 0 {
+		return &output, fmt.Errorf("compilation failed with %d errors: %w", len(compileErrors), compileErrors[0])
+	}
+
+	return &output, nil
+}
@@ -0,0 +1,202 @@
+package compiler
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/0x19/solc-switch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo"
+)
+
+// outputForTest is standard JSON output solc 0.8.19 generated for Counter.sol, trimmed down.
+const outputForTest = `{
+  "contracts": {
+    "Counter.sol": {
+      "Counter": {
+        "abi": [{"inputs":[],"name":"count","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}],
+        "evm": {
+          "bytecode": {"object": "6080604052", "opcodes": "PUSH1 0x80 PUSH1 0x40 MSTORE", "sourceMap": "57:60:0:-:0;;;", "linkReferences": {}},
+          "deployedBytecode": {"object": "60806040", "opcodes": "PUSH1 0x80 PUSH1 0x40", "sourceMap": "57:60:0:-:0;;", "linkReferences": {}, "immutableReferences": {}},
+          "methodIdentifiers": {"count()": "06661abd"}
+        },
+        "metadata": "{\"compiler\":{\"version\":\"0.8.19+commit.7dd6d404\"}}",
+        "storageLayout": {
+          "storage": [{"astId": 3, "contract": "Counter.sol:Counter", "label": "count", "offset": 0, "slot": "0", "type": "t_uint256"}],
+          "types": {"t_uint256": {"encoding": "inplace", "label": "uint256", "numberOfBytes": "32"}}
+        }
+      }
+    }
+  },
+  "errors": [
+    {"component": "general", "errorCode": "1878", "formattedMessage": "Warning: SPDX license identifier not provided in source file.\n", "message": "SPDX license identifier not provided in source file.", "severity": "warning", "sourceLocation": {"end": -1, "file": "Counter.sol", "start": -1}, "type": "Warning"}
+  ],
+  "sources": {"Counter.sol": {"id": 0}}
+}`
+
+// fakeSolcForTest writes an executable standing in for solc, which saves its standard input next to it
+// and prints the output.
+func fakeSolcForTest(t *testing.T, output string) (string, string) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake compiler is a shell script")
+	}
+
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "output.json")
+	require.NoError(t, os.WriteFile(outputPath, []byte(output), 0600))
+
+	inputPath := filepath.Join(dir, "input.json")
+	binary := filepath.Join(dir, "solc")
+	script := "#!/bin/sh\n[ \"$1\" = \"--standard-json\" ] || exit 2\ncat > " + inputPath + "\ncat " + outputPath + "\n"
+	require.NoError(t, os.WriteFile(binary, []byte(script), 0700))
+	return binary, inputPath
+}
+
+func TestInput(t *testing.T) {
+	input, err := NewInputFromSources(&solgo.Sources{
+		SourceUnits: []*solgo.SourceUnit{
+			{Name: "Counter", Path: "contracts/Counter.sol", Content: "contract Counter {}"},
+			{Name: "Math", Content: "library Math {}"},
+		},
+	})
+	require.NoError(t, err)
+
+	input.Select("*", "", OutputAST).
+		Select("contracts/Counter.sol", "Counter", OutputIR, OutputABI).
+		Link("Math.sol", "Math", "0x0000000000000000000000000000000000000001")
+	input.Settings.Optimizer = Optimizer{Enabled: true, Runs: 200}
+
+	encoded, err := input.ToJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"language": "Solidity",
+		"sources": {
+			"contracts/Counter.sol": {"content": "contract Counter {}"},
+			"Math.sol": {"content": "library Math {}"}
+		},
+		"settings": {
+			"optimizer": {"enabled": true, "runs": 200},
+			"libraries": {"Math.sol": {"Math": "0x0000000000000000000000000000000000000001"}},
+			"outputSelection": {
+				"*": {
+					"*": ["abi", "evm.bytecode", "evm.deployedBytecode", "metadata", "storageLayout"],
+					"": ["ast"]
+				},
+				"contracts/Counter.sol": {"Counter": ["abi", "ir"]}
+			}
+		}
+	}`, string(encoded))
+
+	_, err = NewInputFromSources(&solgo.Sources{})
+	assert.Error(t, err)
+
+	input, err = NewInputFromJsonConfig(&solc.CompilerJsonConfig{
+		Language: "Solidity",
+		Sources:  map[string]solc.Source{"Counter.sol": {Content: "contract Counter {}"}},
+		Settings: solc.Settings{
+			Optimizer:       solc.Optimizer{Enabled: true, Runs: 1000},
+			EVMVersion:      "paris",
+			Remappings:      []string{"@openzeppelin/=lib/openzeppelin/"},
+			OutputSelection: map[string]map[string][]string{"*": {"*": {"abi"}}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "contract Counter {}", input.Sources["Counter.sol"].Content)
+	assert.Equal(t, Optimizer{Enabled: true, Runs: 1000}, input.Settings.Optimizer)
+	assert.Equal(t, "paris", input.Settings.EVMVersion)
+	assert.Equal(t, []string{"@openzeppelin/=lib/openzeppelin/"}, input.Settings.Remappings)
+	assert.Empty(t, input.Settings.OutputSelection, "outputs are selected by the caller")
+
+	_, err = NewInputFromJsonConfig(&solc.CompilerJsonConfig{})
+	assert.Error(t, err)
+}
+
+func TestCompileWithBinary(t *testing.T) {
+	binary, inputPath := fakeSolcForTest(t, outputForTest)
+
+	input := NewInput(map[string]string{"Counter.sol": "contract Counter { uint256 public count; }"}).
+		Select("*", "*", DefaultOutputs...)
+	output, err := compileWithBinary(context.TODO(), binary, input)
+	require.NoError(t, err)
+
+	sent, err := os.ReadFile(inputPath)
+	require.NoError(t, err)
+	encoded, err := input.ToJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, string(encoded), string(sent))
+
+	assert.False(t, output.HasErrors())
+	require.Len(t, output.GetWarnings(), 1)
+	assert.Equal(t, "Warning: SPDX license identifier not provided in source file.", output.GetWarnings()[0].Error())
+	assert.Equal(t, []string{"Counter.sol"}, output.GetFiles())
+
+	counter := output.GetContractByName("Counter")
+	require.NotNil(t, counter)
+	assert.Same(t, counter, output.GetContract("Counter.sol", "Counter"))
+	assert.Equal(t, "6080604052", counter.GetBytecode())
+	assert.Equal(t, "60806040", counter.GetDeployedBytecode())
+	assert.True(t, counter.EVM.DeployedBytecode.IsLinked())
+	assert.Empty(t, counter.GetImmutableReferences())
+	assert.Equal(t, "06661abd", counter.EVM.MethodIdentifiers["count()"])
+	require.NotNil(t, counter.StorageLayout)
+	assert.Equal(t, "count", counter.StorageLayout.Storage[0].Label)
+	assert.Equal(t, "32", counter.StorageLayout.Types["t_uint256"].NumberOfBytes)
+
+	var abi []map[string]interface{}
+	require.NoError(t, json.Unmarshal(counter.ABI, &abi))
+	assert.Equal(t, "count", abi[0]["name"])
+
+	// Compilation errors fail the compilation while the output is still returned.
+	binary, _ = fakeSolcForTest(t, `{"errors": [{"component": "general", "errorCode": "7576", "message": "Undeclared identifier.", "severity": "error", "type": "DeclarationError", "sourceLocation": {"file": "Counter.sol", "start": 10, "end": 15}}]}`)
+	output, err = compileWithBinary(context.TODO(), binary, input)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "DeclarationError: Undeclared identifier.")
+	require.NotNil(t, output)
+	assert.True(t, output.HasErrors())
+	assert.Equal(t, 10, output.GetErrors()[0].SourceLocation.Start)
+
+	_, err = compileWithBinary(context.TODO(), filepath.Join(t.TempDir(), "missing"), input)
+	assert.Error(t, err)
+}
+
+func TestOutputContracts(t *testing.T) {
+	var output Output
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"contracts": {
+			"contracts/Factory.sol": {
+				"Factory": {"abi": [], "metadata": "{}", "evm": {"bytecode": {"object": "6001"}, "deployedBytecode": {"object": "6002"}}},
+				"Pair": {"abi": [], "evm": {"bytecode": {"object": "6003"}, "deployedBytecode": {"object": "6004"}}}
+			},
+			"contracts/libraries/Math.sol": {
+				"Math": {"abi": [], "evm": {"bytecode": {"object": "6005"}, "deployedBytecode": {"object": "6006"}}}
+			}
+		},
+		"errors": [{"component": "general", "message": "Unused local variable.", "severity": "warning", "type": "Warning", "sourceLocation": {"file": "contracts/Factory.sol", "start": 1, "end": 2}}]
+	}`), &output))
+	output.Version = "0.8.19"
+
+	assert.Equal(t, []string{"contracts/Factory.sol:Factory", "contracts/Factory.sol:Pair", "contracts/libraries/Math.sol:Math"}, output.GetFullyQualifiedNames())
+	assert.Len(t, output.GetContracts(), 3)
+	assert.Equal(t, "6004", output.GetContractByFullyQualifiedName("contracts/Factory.sol:Pair").GetDeployedBytecode())
+	assert.Same(t, output.GetContracts()["contracts/libraries/Math.sol:Math"], output.GetContractByName("Math"))
+	assert.Nil(t, output.GetContractByFullyQualifiedName("Pair"))
+
+	results := output.ToCompilerResults("Pair")
+	require.Len(t, results.GetResults(), 3)
+	entry := results.GetEntryContract()
+	require.NotNil(t, entry)
+	assert.Equal(t, "contracts/Factory.sol:Pair", entry.GetContractName())
+	assert.Equal(t, "6003", entry.GetBytecode())
+	assert.Equal(t, "6004", entry.GetDeployedBytecode())
+	assert.Equal(t, "[]", entry.GetABI())
+	assert.Equal(t, "0.8.19", entry.GetCompilerVersion())
+	assert.Equal(t, "{}", results.GetResults()[0].GetMetadata())
+	assert.Equal(t, "contracts/Factory.sol", entry.GetErrors()[0].SourceLocation.File)
+
+	assert.Nil(t, output.ToCompilerResults("").GetEntryContract())
+}
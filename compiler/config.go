@@ -0,0 +1,216 @@
+package compiler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/0x19/solc-switch"
+)
+
+// Hashes of the metadata solc appends to the bytecode.
+const (
+	BytecodeHashIPFS  = "ipfs"
+	BytecodeHashBzzr1 = "bzzr1"
+	BytecodeHashNone  = "none"
+)
+
+// DefaultOptimizerRuns is the number of runs solc optimizes for unless told otherwise.
+const DefaultOptimizerRuns = 200
+
+// Config holds the compilation settings changing the bytecode solc generates. Verifying the bytecode
+// of a deployment requires compiling it with the same settings.
+type Config struct {
+	Optimized     bool   `json:"optimized"`
+	OptimizerRuns int    `json:"optimizerRuns"`
+	ViaIR         bool   `json:"viaIR"`
+	EVMVersion    string `json:"evmVersion,omitempty"`   // Empty for the default of the compiler version.
+	BytecodeHash  string `json:"bytecodeHash,omitempty"` // Empty for the default of the compiler version, ipfs since 0.6.0.
+}
+
+// NewDefaultConfig creates a config with the defaults of solc: optimizer disabled, 200 runs, legacy
+// code generation and the default EVM version and metadata hash of the compiler version.
+func NewDefaultConfig() *Config {
+	return &Config{OptimizerRuns: DefaultOptimizerRuns}
+}
+
+// NewConfigFromMetadata creates a config from the settings recorded in the metadata JSON of a contract,
+// which solc embeds the hash of in its bytecode and which verification services publish.
+func NewConfigFromMetadata(metadata []byte) (*Config, error) {
+	var decoded struct {
+		Settings struct {
+			Optimizer struct {
+				Enabled bool `json:"enabled"`
+				Runs    int  `json:"runs"`
+			} `json:"optimizer"`
+			EVMVersion string `json:"evmVersion"`
+			ViaIR      bool   `json:"viaIR"`
+			Metadata   struct {
+				BytecodeHash string `json:"bytecodeHash"`
+			} `json:"metadata"`
+		} `json:"settings"`
+	}
+	if err := json.Unmarshal(metadata, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode metadata: %w", err)
+	}
+
+	toReturn := NewDefaultConfig()
+	toReturn.Optimized = decoded.Settings.Optimizer.Enabled
+	if decoded.Settings.Optimizer.Runs > 0 {
+		toReturn.OptimizerRuns = decoded.Settings.Optimizer.Runs
+	}
+	toReturn.ViaIR = decoded.Settings.ViaIR
+	toReturn.EVMVersion = decoded.Settings.EVMVersion
+	toReturn.BytecodeHash = decoded.Settings.Metadata.BytecodeHash
+	return toReturn, nil
+}
+
+// NewConfigFromArguments creates a config from the command line arguments of a solc-switch compiler
+// config, so sources compiled through solc-switch can be compiled through the standard JSON interface
+// with the same settings, including --via-ir, which solc-switch itself cannot compile with. Arguments other
+// than those of the settings are ignored.
+func NewConfigFromArguments(arguments []string) (*Config, error) {
+	toReturn := NewDefaultConfig()
+	for i := 0; i < len(arguments); i++ {
+		name := arguments[i]
+		takesValue, ok := settingArguments[name]
+		if !ok {
+			continue
+		}
+
+		value := ""
+		if takesValue {
+			if i+1 >= len(arguments) {
+				return nil, fmt.Errorf("missing value of argument %s", name)
+			}
+			i++
+			value = arguments[i]
+		}
+
+		switch name {
+		case "--optimize":
+			toReturn.Optimized = true
+		case "--via-ir":
+			toReturn.ViaIR = true
+		case "--optimize-runs":
+			runs, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid optimizer runs %q: %w", value, err)
+			}
+			toReturn.OptimizerRuns = runs
+		case "--evm-version":
+			toReturn.EVMVersion = value
+		case "--metadata-hash":
+			toReturn.BytecodeHash = value
+		}
+	}
+
+	if err := toReturn.Validate(); err != nil {
+		return nil, err
+	}
+	return toReturn, nil
+}
+
+// Validate checks the optimizer runs and the metadata hash.
+func (c *Config) Validate() error {
+	if c.OptimizerRuns < 0 {
+		return fmt.Errorf("invalid optimizer runs: %d", c.OptimizerRuns)
+	}
+
+	switch c.BytecodeHash {
+	case "", BytecodeHashIPFS, BytecodeHashBzzr1, BytecodeHashNone:
+	default:
+		return fmt.Errorf("invalid bytecode hash: %s", c.BytecodeHash)
+	}
+
+	return nil
+}
+
+// Apply sets the settings of the input to those of the config.
+func (c *Config) Apply(input *Input) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	input.Settings.Optimizer = Optimizer{Enabled: c.Optimized, Runs: c.OptimizerRuns}
+	input.Settings.ViaIR = c.ViaIR
+	input.Settings.EVMVersion = c.EVMVersion
+
+	if c.BytecodeHash != "" {
+		if input.Settings.Metadata == nil {
+			input.Settings.Metadata = &MetadataSettings{}
+		}
+		input.Settings.Metadata.BytecodeHash = c.BytecodeHash
+	} else if input.Settings.Metadata != nil {
+		input.Settings.Metadata.BytecodeHash = ""
+	}
+
+	return nil
+}
+
+// ApplyTo sets the settings of the solc-switch compiler config to those of the config, in its JSON
+// config if it has one or as command line arguments otherwise. solc-switch cannot compile via IR, nor
+// change the metadata hash of JSON configs, so those settings fail to apply.
+func (c *Config) ApplyTo(config *solc.CompilerConfig) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	if config == nil {
+		return errors.New("compiler config must be set")
+	}
+
+	if c.ViaIR {
+		return errors.New("solc-switch cannot compile via IR, use the standard json compiler instead")
+	}
+
+	if jsonConfig := config.GetJsonConfig(); jsonConfig != nil {
+		if c.BytecodeHash != "" {
+			return errors.New("solc-switch json configs cannot set the bytecode hash, use the standard json compiler instead")
+		}
+		jsonConfig.Settings.Optimizer = solc.Optimizer{Enabled: c.Optimized, Runs: c.OptimizerRuns}
+		jsonConfig.Settings.EVMVersion = c.EVMVersion
+		return nil
+	}
+
+	config.SetArguments(withoutSettingArguments(config.GetArguments()))
+	if c.Optimized {
+		config.AppendArguments("--optimize", "--optimize-runs", strconv.Itoa(c.OptimizerRuns))
+	}
+	if c.EVMVersion != "" {
+		config.AppendArguments("--evm-version", c.EVMVersion)
+	}
+	if c.BytecodeHash != "" {
+		config.AppendArguments("--metadata-hash", c.BytecodeHash)
+	}
+
+	return nil
+}
+
+// settingArguments are the command line arguments of the settings of a config, by whether they take a
+// value.
+var settingArguments = map[string]bool{
+	"--optimize":      false,
+	"--optimize-runs": true,
+	"--via-ir":        false,
+	"--evm-version":   true,
+	"--metadata-hash": true,
+}
+
+// withoutSettingArguments returns the arguments without those of the settings of a config and their
+// values.
+func withoutSettingArguments(arguments []string) []string {
+	toReturn := make([]string, 0, len(arguments))
+	for i := 0; i < len(arguments); i++ {
+		takesValue, ok := settingArguments[arguments[i]]
+		if !ok {
+			toReturn = append(toReturn, arguments[i])
+			continue
+		}
+		if takesValue {
+			i++
+		}
+	}
+	return toReturn
+}
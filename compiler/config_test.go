@@ -0,0 +1,115 @@
+package compiler
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0x19/solc-switch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig(t *testing.T) {
+	config, err := NewConfigFromMetadata([]byte(`{
+		"compiler": {"version": "0.8.21+commit.d9974bed"},
+		"settings": {
+			"optimizer": {"enabled": true, "runs": 10000},
+			"evmVersion": "paris",
+			"viaIR": true,
+			"metadata": {"bytecodeHash": "none"}
+		}
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, &Config{Optimized: true, OptimizerRuns: 10000, ViaIR: true, EVMVersion: "paris", BytecodeHash: BytecodeHashNone}, config)
+
+	input := NewInput(map[string]string{"Counter.sol": "contract Counter {}"})
+	require.NoError(t, config.Apply(input))
+	encoded, err := input.ToJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"language": "Solidity",
+		"sources": {"Counter.sol": {"content": "contract Counter {}"}},
+		"settings": {
+			"optimizer": {"enabled": true, "runs": 10000},
+			"evmVersion": "paris",
+			"viaIR": true,
+			"metadata": {"bytecodeHash": "none"},
+			"outputSelection": {}
+		}
+	}`, string(encoded))
+
+	// solc-switch compiles neither via IR nor with the metadata hash of JSON configs.
+	compilerConfig := &solc.CompilerConfig{Arguments: []string{"--overwrite", "--optimize", "--optimize-runs", "1", "--combined-json", "bin"}}
+	assert.Error(t, config.ApplyTo(compilerConfig))
+
+	config.ViaIR = false
+	require.NoError(t, config.ApplyTo(compilerConfig))
+	assert.Equal(t, []string{
+		"--overwrite", "--combined-json", "bin",
+		"--optimize", "--optimize-runs", "10000", "--evm-version", "paris", "--metadata-hash", "none",
+	}, compilerConfig.GetArguments())
+
+	compilerConfig.SetJsonConfig(&solc.CompilerJsonConfig{Language: "Solidity"})
+	assert.Error(t, config.ApplyTo(compilerConfig))
+
+	config.BytecodeHash = ""
+	require.NoError(t, config.ApplyTo(compilerConfig))
+	assert.Equal(t, solc.Optimizer{Enabled: true, Runs: 10000}, compilerConfig.GetJsonConfig().Settings.Optimizer)
+	assert.Equal(t, "paris", compilerConfig.GetJsonConfig().Settings.EVMVersion)
+
+	assert.Error(t, (&Config{BytecodeHash: "swarm"}).Validate())
+	assert.Error(t, (&Config{OptimizerRuns: -1}).Validate())
+	_, err = NewConfigFromMetadata([]byte("not json"))
+	assert.Error(t, err)
+
+	config, err = NewConfigFromArguments([]string{"--overwrite", "--combined-json", "bin,abi", "-", "--optimize", "--optimize-runs", "10000", "--evm-version", "paris", "--metadata-hash", "none"})
+	require.NoError(t, err)
+	assert.Equal(t, &Config{Optimized: true, OptimizerRuns: 10000, EVMVersion: "paris", BytecodeHash: BytecodeHashNone}, config)
+
+	config, err = NewConfigFromArguments([]string{"--via-ir", "--optimize", "-"})
+	require.NoError(t, err)
+	assert.Equal(t, &Config{Optimized: true, OptimizerRuns: DefaultOptimizerRuns, ViaIR: true}, config)
+	input = NewInput(map[string]string{"Token.sol": "contract Token {}"})
+	require.NoError(t, config.Apply(input))
+	assert.True(t, input.Settings.ViaIR)
+
+	_, err = NewConfigFromArguments([]string{"--optimize-runs", "many"})
+	assert.Error(t, err)
+	_, err = NewConfigFromArguments([]string{"--evm-version"})
+	assert.Error(t, err)
+}
+
+func TestCompileWithConfig(t *testing.T) {
+	binary, inputPath := fakeSolcForTest(t, outputForTest)
+	script, err := os.ReadFile(binary)
+	require.NoError(t, err)
+
+	releasesPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(releasesPath, "releases.json"), []byte(`[{"tag_name":"v0.8.19"}]`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(releasesPath, "solc-0.8.19"), script, 0700))
+
+	switchConfig, err := solc.NewDefaultConfig()
+	require.NoError(t, err)
+	require.NoError(t, switchConfig.SetReleasesPath(releasesPath))
+	switcher, err := solc.New(context.TODO(), switchConfig)
+	require.NoError(t, err)
+
+	compiler, err := NewCompiler(context.TODO(), switcher)
+	require.NoError(t, err)
+	compiler.SetConfig(&Config{Optimized: true, OptimizerRuns: 1, ViaIR: true})
+	assert.True(t, compiler.GetConfig().ViaIR)
+
+	input := NewInput(map[string]string{"Counter.sol": "contract Counter {}"}).Select("*", "*", OutputABI)
+	_, err = compiler.Compile(context.TODO(), "0.8.19", input)
+	require.NoError(t, err)
+
+	sent, err := os.ReadFile(inputPath)
+	require.NoError(t, err)
+	var decoded Input
+	require.NoError(t, json.Unmarshal(sent, &decoded))
+	assert.Equal(t, Optimizer{Enabled: true, Runs: 1}, decoded.Settings.Optimizer)
+	assert.True(t, decoded.Settings.ViaIR)
+}
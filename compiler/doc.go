@@ -0,0 +1,13 @@
+/*
+Package compiler compiles Solidity sources through the standard JSON interface of solc
+(`solc --standard-json`) rather than its command line flags. Inputs select the outputs to generate per
+file and contract, such as the ABI, creation and deployed bytecode, storage layouts and metadata, and
+outputs are decoded into typed structures along with structured errors and warnings, so compilations
+are deterministic and complete. Source maps decode into the source range of every instruction, which
+locates the solgo AST node a program counter was generated from, and library placeholders left in
+bytecode are linked with the addresses of the libraries. Compiler binaries are resolved through
+solc-switch, or compilations run in containers of the official solc images with the Docker backend,
+which sandboxes untrusted sources. Outputs can be cached by a hash of the prepared sources, compiler
+version and settings, on the file system or in Redis, so identical compilations run once.
+*/
+package compiler
@@ -0,0 +1,88 @@
+package compiler
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// DefaultDockerImage is the repository of the official solc images, tagged by compiler version.
+const DefaultDockerImage = "ethereum/solc"
+
+// DockerBackend runs compiler versions in containers of the solc images, such as ethereum/solc:0.8.19,
+// which Docker pulls on first use. It suits hosts where installing dozens of native compiler versions is
+// impractical and sandboxes untrusted sources: inputs are passed on the standard input, and containers run
+// without network access, on a read-only file system and within the resource limits set.
+type DockerBackend struct {
+	command string // Docker command, docker unless set.
+	image   string // Repository of the images, tagged by compiler version.
+	network string // Network of the containers, none unless set.
+	memory  string // Memory limit of the containers, such as 512m, if set.
+	cpus    string // CPU limit of the containers, such as 1.5, if set.
+}
+
+// NewDockerBackend creates a backend running the DefaultDockerImage images without network access.
+func NewDockerBackend() *DockerBackend {
+	return &DockerBackend{
+		command: "docker",
+		image:   DefaultDockerImage,
+		network: "none",
+	}
+}
+
+// SetCommand sets the command running containers, such as the path of docker or a compatible one such
+// as podman.
+func (b *DockerBackend) SetCommand(command string) {
+	b.command = command
+}
+
+// SetImage sets the repository of the images, tagged by compiler version, such as a private mirror of
+// DefaultDockerImage.
+func (b *DockerBackend) SetImage(image string) {
+	b.image = image
+}
+
+// SetNetwork sets the network containers run in, none by default.
+func (b *DockerBackend) SetNetwork(network string) {
+	b.network = network
+}
+
+// SetLimits sets the memory limit, such as 512m, and the CPU limit, such as 1.5, of the containers.
+// Empty limits are left unset.
+func (b *DockerBackend) SetLimits(memory string, cpus string) {
+	b.memory = memory
+	b.cpus = cpus
+}
+
+// GetImage returns the image of the compiler version, such as ethereum/solc:0.8.19.
+func (b *DockerBackend) GetImage(version string) string {
+	version = strings.TrimPrefix(version, "v")
+	// Images are tagged by version alone, without the commit of the build.
+	if index := strings.Index(version, "+"); index >= 0 {
+		version = version[:index]
+	}
+	return b.image + ":" + version
+}
+
+// Arguments returns the arguments of the command running the compiler version in standard JSON mode.
+func (b *DockerBackend) Arguments(version string) []string {
+	toReturn := []string{"run", "--rm", "--interactive", "--read-only"}
+	if b.network != "" {
+		toReturn = append(toReturn, "--network", b.network)
+	}
+	if b.memory != "" {
+		toReturn = append(toReturn, "--memory", b.memory)
+	}
+	if b.cpus != "" {
+		toReturn = append(toReturn, "--cpus", b.cpus)
+	}
+	return append(toReturn, b.GetImage(version), "--standard-json")
+}
+
+// Compile compiles the input in a container of the image of the compiler version.
+func (b *DockerBackend) Compile(ctx context.Context, version string, input *Input) (*Output, error) {
+	if strings.TrimPrefix(version, "v") == "" {
+		return nil, errors.New("compiler version must be set")
+	}
+	return runStandardJSON(ctx, b.command, b.Arguments(version), input)
+}
@@ -0,0 +1,79 @@
+package compiler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDockerForTest writes a script standing in for docker, which records its arguments and input and
+// writes the output.
+func fakeDockerForTest(t *testing.T, output string) (string, string, string) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake docker is a shell script")
+	}
+
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "output.json")
+	require.NoError(t, os.WriteFile(outputPath, []byte(output), 0600))
+
+	argsPath := filepath.Join(dir, "args")
+	inputPath := filepath.Join(dir, "input.json")
+	command := filepath.Join(dir, "docker")
+	script := "#!/bin/sh\necho \"$@\" > " + argsPath + "\ncat > " + inputPath + "\ncat " + outputPath + "\n"
+	require.NoError(t, os.WriteFile(command, []byte(script), 0700))
+	return command, argsPath, inputPath
+}
+
+func TestDockerBackend(t *testing.T) {
+	backend := NewDockerBackend()
+	assert.Equal(t, "ethereum/solc:0.8.19", backend.GetImage("v0.8.19+commit.7dd6d404"))
+	assert.Equal(t, []string{"run", "--rm", "--interactive", "--read-only", "--network", "none", "ethereum/solc:0.8.19", "--standard-json"}, backend.Arguments("0.8.19"))
+
+	backend.SetImage("registry.local/solc")
+	backend.SetLimits("512m", "1.5")
+	backend.SetNetwork("")
+	assert.Equal(t, []string{"run", "--rm", "--interactive", "--read-only", "--memory", "512m", "--cpus", "1.5", "registry.local/solc:0.6.12", "--standard-json"}, backend.Arguments("0.6.12"))
+
+	command, argsPath, inputPath := fakeDockerForTest(t, outputForTest)
+	backend = NewDockerBackend()
+	backend.SetCommand(command)
+
+	input := NewInput(map[string]string{"Counter.sol": "contract Counter { uint256 public count; }"}).
+		Select("*", "*", DefaultOutputs...)
+	output, err := backend.Compile(context.TODO(), "0.8.19", input)
+	require.NoError(t, err)
+	require.NotNil(t, output.GetContractByName("Counter"))
+
+	args, err := os.ReadFile(argsPath)
+	require.NoError(t, err)
+	assert.Equal(t, "run --rm --interactive --read-only --network none ethereum/solc:0.8.19 --standard-json", strings.TrimSpace(string(args)))
+
+	sent, err := os.ReadFile(inputPath)
+	require.NoError(t, err)
+	encoded, err := input.ToJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, string(encoded), string(sent))
+
+	// The backend of a compiler runs its compilations.
+	compiler := &Compiler{ctx: context.TODO()}
+	compiler.SetBackend(backend)
+	assert.Same(t, backend, compiler.GetBackend())
+	output, err = compiler.Compile(context.TODO(), "v0.8.19", input)
+	require.NoError(t, err)
+	assert.Equal(t, "0.8.19", output.Version)
+
+	_, err = backend.Compile(context.TODO(), "", input)
+	assert.Error(t, err)
+	backend.SetCommand(filepath.Join(t.TempDir(), "missing"))
+	_, err = backend.Compile(context.TODO(), "0.8.19", input)
+	assert.Error(t, err)
+	_, err = NewNativeBackend(nil).Compile(context.TODO(), "0.8.19", input)
+	assert.Error(t, err)
+}
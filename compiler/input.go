@@ -0,0 +1,166 @@
+package compiler
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/0x19/solc-switch"
+	"github.com/unpackdev/solgo"
+	"github.com/unpackdev/solgo/utils"
+)
+
+// Outputs solc generates on request, as selected by Input.Select.
+const (
+	OutputABI               = "abi"
+	OutputMetadata          = "metadata"
+	OutputBytecode          = "evm.bytecode"
+	OutputDeployedBytecode  = "evm.deployedBytecode"
+	OutputMethodIdentifiers = "evm.methodIdentifiers"
+	OutputAssembly          = "evm.assembly"
+	OutputLegacyAssembly    = "evm.legacyAssembly"
+	OutputOpcodes           = "evm.bytecode.opcodes"           // Selected along with the rest of evm.bytecode.
+	OutputDeployedOpcodes   = "evm.deployedBytecode.opcodes"   // Selected along with the rest of evm.deployedBytecode.
+	OutputSourceMap         = "evm.bytecode.sourceMap"         // Selected along with the rest of evm.bytecode.
+	OutputDeployedSourceMap = "evm.deployedBytecode.sourceMap" // Selected along with the rest of evm.deployedBytecode.
+	OutputStorageLayout     = "storageLayout"
+	OutputIR                = "ir"
+	OutputIROptimized       = "irOptimized"
+	OutputAST               = "ast" // Generated per file rather than per contract.
+)
+
+// DefaultOutputs are the contract outputs selected by NewInputFromSources.
+var DefaultOutputs = []string{OutputABI, OutputMetadata, OutputBytecode, OutputDeployedBytecode, OutputStorageLayout}
+
+// Source is a source file of the input.
+type Source struct {
+	Content string `json:"content"`
+}
+
+// Optimizer configures the optimizer.
+type Optimizer struct {
+	Enabled bool `json:"enabled"`
+	Runs    int  `json:"runs,omitempty"`
+}
+
+// MetadataSettings configures the metadata appended to the bytecode.
+type MetadataSettings struct {
+	UseLiteralContent bool   `json:"useLiteralContent,omitempty"` // Embed sources rather than their hashes.
+	BytecodeHash      string `json:"bytecodeHash,omitempty"`      // ipfs, bzzr1 or none.
+}
+
+// Settings are the compilation settings of the input. Output selections and libraries are set per
+// file, while other settings apply to every file.
+type Settings struct {
+	Remappings      []string                       `json:"remappings,omitempty"`
+	Optimizer       Optimizer                      `json:"optimizer"`
+	EVMVersion      string                         `json:"evmVersion,omitempty"`
+	ViaIR           bool                           `json:"viaIR,omitempty"`
+	Metadata        *MetadataSettings              `json:"metadata,omitempty"`
+	Libraries       map[string]map[string]string   `json:"libraries,omitempty"` // Library addresses by file and library name.
+	OutputSelection map[string]map[string][]string `json:"outputSelection"`     // Outputs by file and contract.
+}
+
+// Input is the standard JSON input of solc.
+type Input struct {
+	Language string             `json:"language"`
+	Sources  map[string]*Source `json:"sources"`
+	Settings Settings           `json:"settings"`
+}
+
+// NewInput creates an input for Solidity sources, by file name, without outputs selected.
+func NewInput(sources map[string]string) *Input {
+	toReturn := &Input{
+		Language: "Solidity",
+		Sources:  make(map[string]*Source),
+		Settings: Settings{
+			OutputSelection: make(map[string]map[string][]string),
+		},
+	}
+
+	for name, content := range sources {
+		toReturn.Sources[name] = &Source{Content: content}
+	}
+
+	return toReturn
+}
+
+// NewInputFromSources creates an input for the source units, by path, selecting the DefaultOutputs of
+// every contract.
+func NewInputFromSources(sources *solgo.Sources) (*Input, error) {
+	if sources == nil || len(sources.GetUnits()) == 0 {
+		return nil, errors.New("sources must be set")
+	}
+
+	contents := make(map[string]string)
+	for _, unit := range sources.GetUnits() {
+		name := unit.Path
+		if name == "" {
+			name = unit.Name + ".sol"
+		}
+		contents[name] = unit.Content
+	}
+
+	return NewInput(contents).Select("*", "*", DefaultOutputs...), nil
+}
+
+// NewInputFromJsonConfig creates an input for the sources and settings of the solc-switch JSON config,
+// without outputs selected, so outputs solc-switch does not return can be compiled for the same config.
+func NewInputFromJsonConfig(config *solc.CompilerJsonConfig) (*Input, error) {
+	if config == nil || len(config.Sources) == 0 {
+		return nil, errors.New("json config with sources must be set")
+	}
+
+	contents := make(map[string]string, len(config.Sources))
+	for name, source := range config.Sources {
+		contents[name] = source.Content
+	}
+
+	toReturn := NewInput(contents)
+	if config.Language != "" {
+		toReturn.Language = config.Language
+	}
+	toReturn.Settings.Optimizer = Optimizer{Enabled: config.Settings.Optimizer.Enabled, Runs: config.Settings.Optimizer.Runs}
+	toReturn.Settings.EVMVersion = config.Settings.EVMVersion
+	toReturn.Settings.Remappings = config.Settings.Remappings
+	return toReturn, nil
+}
+
+// Select selects outputs of the contract of the file, where either may be * to select them for every
+// file or contract. File level outputs, such as the AST, are selected for the empty contract name.
+func (i *Input) Select(file string, contract string, outputs ...string) *Input {
+	if i.Settings.OutputSelection == nil {
+		i.Settings.OutputSelection = make(map[string]map[string][]string)
+	}
+	if i.Settings.OutputSelection[file] == nil {
+		i.Settings.OutputSelection[file] = make(map[string][]string)
+	}
+
+	selected := i.Settings.OutputSelection[file][contract]
+	for _, output := range outputs {
+		if !utils.StringInSlice(output, selected) {
+			selected = append(selected, output)
+		}
+	}
+	sort.Strings(selected)
+	i.Settings.OutputSelection[file][contract] = selected
+	return i
+}
+
+// Link sets the address of a library of the file, so bytecode referencing it is linked.
+func (i *Input) Link(file string, library string, address string) *Input {
+	if i.Settings.Libraries == nil {
+		i.Settings.Libraries = make(map[string]map[string]string)
+	}
+	if i.Settings.Libraries[file] == nil {
+		i.Settings.Libraries[file] = make(map[string]string)
+	}
+	i.Settings.Libraries[file][library] = address
+	return i
+}
+
+// ToJSON returns the JSON encoding of the input. Maps are encoded with sorted keys, so equal inputs
+// encode equally.
+func (i *Input) ToJSON() ([]byte, error) {
+	return json.Marshal(i)
+}
@@ -0,0 +1,147 @@
+package compiler
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/unpackdev/solgo/utils"
+)
+
+// placeholderLength is the length of library placeholders in hex encoded bytecode, that of an address.
+const placeholderLength = 40
+
+// Placeholder is a placeholder for the address of a library in hex encoded bytecode.
+type Placeholder struct {
+	Offset      int    `json:"offset"`      // Byte offset in the bytecode.
+	Placeholder string `json:"placeholder"` // Such as __$d2a3...$__, or __Lib.sol:Lib___ before solc 0.5.
+}
+
+// LibraryPlaceholder returns the placeholder solc leaves in bytecode for the address of the library
+// with the fully qualified name, such as contracts/Math.sol:Math.
+func LibraryPlaceholder(fullyQualifiedName string) string {
+	return "__$" + hex.EncodeToString(utils.Keccak256([]byte(fullyQualifiedName)))[:34] + "$__"
+}
+
+// legacyLibraryPlaceholder returns the placeholder solc used before 0.5, the fully qualified name cut
+// to 36 characters and padded with underscores.
+func legacyLibraryPlaceholder(fullyQualifiedName string) string {
+	if len(fullyQualifiedName) > placeholderLength-4 {
+		fullyQualifiedName = fullyQualifiedName[:placeholderLength-4]
+	}
+	return "__" + fullyQualifiedName + strings.Repeat("_", placeholderLength-2-len(fullyQualifiedName))
+}
+
+// FindPlaceholders returns the library placeholders in the hex encoded bytecode, in order. Hex never
+// has underscores, so any run of them starts a placeholder.
+func FindPlaceholders(bytecode string) []*Placeholder {
+	bytecode = strings.TrimPrefix(bytecode, "0x")
+	toReturn := make([]*Placeholder, 0)
+	for offset := 0; offset+placeholderLength <= len(bytecode); {
+		index := strings.Index(bytecode[offset:], "__")
+		if index < 0 || offset+index+placeholderLength > len(bytecode) {
+			break
+		}
+		offset += index
+		toReturn = append(toReturn, &Placeholder{Offset: offset / 2, Placeholder: bytecode[offset : offset+placeholderLength]})
+		offset += placeholderLength
+	}
+	return toReturn
+}
+
+// IsUnlinked reports whether the hex encoded bytecode has placeholders for library addresses.
+func IsUnlinked(bytecode string) bool {
+	return len(FindPlaceholders(bytecode)) > 0
+}
+
+// LinkBytecode replaces the placeholders of the hex encoded bytecode with the addresses of the libraries,
+// given by fully qualified name, such as contracts/Math.sol:Math. Returns the bytecode linked as far as
+// the libraries go, and an error naming the placeholders left if any.
+func LinkBytecode(bytecode string, libraries map[string]string) (string, error) {
+	replacements := make(map[string]string, len(libraries)*2)
+	for name, address := range libraries {
+		address, err := normalizeLibraryAddress(name, address)
+		if err != nil {
+			return bytecode, err
+		}
+		replacements[LibraryPlaceholder(name)] = address
+		replacements[legacyLibraryPlaceholder(name)] = address
+	}
+
+	unlinked := make([]string, 0)
+	for _, placeholder := range FindPlaceholders(bytecode) {
+		address, ok := replacements[placeholder.Placeholder]
+		if !ok {
+			unlinked = append(unlinked, placeholder.Placeholder)
+			continue
+		}
+		bytecode = strings.Replace(bytecode, placeholder.Placeholder, address, 1)
+	}
+
+	if len(unlinked) > 0 {
+		return bytecode, fmt.Errorf("no address for library placeholders %s", strings.Join(unlinked, ", "))
+	}
+	return bytecode, nil
+}
+
+// Link returns the bytecode with the library addresses written at its link references. Libraries are
+// given by fully qualified name, such as contracts/Math.sol:Math, or by name alone. Returns an error
+// naming the libraries without address, if any.
+func (b *Bytecode) Link(libraries map[string]string) (string, error) {
+	linked := []byte(b.Object)
+	unlinked := make([]string, 0)
+	for file, references := range b.LinkReferences {
+		for name, positions := range references {
+			fullyQualifiedName := FullyQualifiedName(file, name)
+			address, ok := libraries[fullyQualifiedName]
+			if !ok {
+				if address, ok = libraries[name]; !ok {
+					unlinked = append(unlinked, fullyQualifiedName)
+					continue
+				}
+			}
+
+			address, err := normalizeLibraryAddress(fullyQualifiedName, address)
+			if err != nil {
+				return b.Object, err
+			}
+			for _, position := range positions {
+				if position.Length*2 != placeholderLength || (position.Start+position.Length)*2 > len(linked) {
+					return b.Object, fmt.Errorf("invalid link reference of %s at %d", fullyQualifiedName, position.Start)
+				}
+				copy(linked[position.Start*2:], address)
+			}
+		}
+	}
+
+	if len(unlinked) > 0 {
+		sort.Strings(unlinked)
+		return string(linked), fmt.Errorf("no address for libraries %s", strings.Join(unlinked, ", "))
+	}
+	return string(linked), nil
+}
+
+// GetLibraries returns the fully qualified names of the libraries the bytecode links, in order.
+func (b *Bytecode) GetLibraries() []string {
+	toReturn := make([]string, 0)
+	for file, references := range b.LinkReferences {
+		for name := range references {
+			toReturn = append(toReturn, FullyQualifiedName(file, name))
+		}
+	}
+	sort.Strings(toReturn)
+	return toReturn
+}
+
+// normalizeLibraryAddress returns the address as lower case hex without the 0x prefix.
+func normalizeLibraryAddress(library string, address string) (string, error) {
+	address = strings.ToLower(strings.TrimPrefix(address, "0x"))
+	if len(address) != placeholderLength {
+		return "", fmt.Errorf("invalid address %s of library %s", address, library)
+	}
+	if _, err := hex.DecodeString(address); err != nil {
+		return "", fmt.Errorf("invalid address %s of library %s: %w", address, library, err)
+	}
+	return address, nil
+}
@@ -0,0 +1,70 @@
+package compiler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinkBytecode(t *testing.T) {
+	mathPlaceholder := LibraryPlaceholder("contracts/Math.sol:Math")
+	require.Len(t, mathPlaceholder, 40)
+	assert.True(t, strings.HasPrefix(mathPlaceholder, "__$"))
+	assert.True(t, strings.HasSuffix(mathPlaceholder, "$__"))
+	assert.NotEqual(t, mathPlaceholder, LibraryPlaceholder("contracts/Math.sol:Other"))
+
+	legacyPlaceholder := legacyLibraryPlaceholder("Math.sol:Math")
+	assert.Equal(t, "__Math.sol:Math"+strings.Repeat("_", 25), legacyPlaceholder)
+
+	mathAddress := strings.Repeat("ab", 20)
+	unlinked := "6080" + mathPlaceholder + "5b73" + mathPlaceholder + "00"
+
+	placeholders := FindPlaceholders("0x" + unlinked)
+	require.Len(t, placeholders, 2)
+	assert.Equal(t, &Placeholder{Offset: 2, Placeholder: mathPlaceholder}, placeholders[0])
+	assert.Equal(t, 24, placeholders[1].Offset)
+	assert.True(t, IsUnlinked(unlinked))
+	assert.False(t, IsUnlinked("60806040"))
+
+	linked, err := LinkBytecode(unlinked, map[string]string{"contracts/Math.sol:Math": "0x" + strings.ToUpper(mathAddress)})
+	require.NoError(t, err)
+	assert.Equal(t, "6080"+mathAddress+"5b73"+mathAddress+"00", linked)
+	assert.False(t, IsUnlinked(linked))
+
+	linked, err = LinkBytecode("60"+legacyPlaceholder, map[string]string{"Math.sol:Math": mathAddress})
+	require.NoError(t, err)
+	assert.Equal(t, "60"+mathAddress, linked)
+
+	partial, err := LinkBytecode(unlinked+LibraryPlaceholder("contracts/Strings.sol:Strings"), map[string]string{"contracts/Math.sol:Math": mathAddress})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), LibraryPlaceholder("contracts/Strings.sol:Strings"))
+	assert.True(t, strings.HasPrefix(partial, "6080"+mathAddress))
+
+	_, err = LinkBytecode(unlinked, map[string]string{"contracts/Math.sol:Math": "0x1234"})
+	assert.Error(t, err)
+	_, err = LinkBytecode(unlinked, map[string]string{"contracts/Math.sol:Math": strings.Repeat("zz", 20)})
+	assert.Error(t, err)
+
+	code := &Bytecode{
+		Object: unlinked,
+		LinkReferences: map[string]map[string][]LinkReference{
+			"contracts/Math.sol": {"Math": {{Start: 2, Length: 20}, {Start: 24, Length: 20}}},
+		},
+	}
+	assert.False(t, code.IsLinked())
+	assert.Equal(t, []string{"contracts/Math.sol:Math"}, code.GetLibraries())
+
+	linked, err = code.Link(map[string]string{"Math": mathAddress})
+	require.NoError(t, err)
+	assert.Equal(t, "6080"+mathAddress+"5b73"+mathAddress+"00", linked)
+	assert.Equal(t, unlinked, code.Object, "linking leaves the bytecode as compiled")
+
+	_, err = code.Link(map[string]string{})
+	assert.Error(t, err)
+
+	code.LinkReferences["contracts/Math.sol"]["Math"] = []LinkReference{{Start: 100, Length: 20}}
+	_, err = code.Link(map[string]string{"contracts/Math.sol:Math": mathAddress})
+	assert.Error(t, err)
+}
@@ -0,0 +1,92 @@
+package compiler
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/unpackdev/solgo/opcode"
+)
+
+// opcodeAliases are names solc prints for opcodes under other names, depending on its version.
+var opcodeAliases = map[string]opcode.OpCode{
+	"SHA3":       opcode.KECCAK256,
+	"PREVRANDAO": opcode.DIFFICULTY,
+	"SUICIDE":    opcode.SELFDESTRUCT,
+}
+
+// ParseOpcodes parses the opcodes solc outputs, such as `PUSH1 0x80 PUSH1 0x40 MSTORE`, into the
+// instructions of the bytecode with their offsets. Bytes that are not opcodes, such as those of the
+// metadata, are printed as hex by solc and parsed as the opcode of that byte.
+func ParseOpcodes(opcodes string) ([]opcode.Instruction, error) {
+	fields := strings.Fields(opcodes)
+	toReturn := make([]opcode.Instruction, 0, len(fields))
+
+	offset := 0
+	for i := 0; i < len(fields); i++ {
+		op, err := parseOpcode(fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse opcode at offset %d: %w", offset, err)
+		}
+
+		instruction := opcode.Instruction{
+			Offset:      offset,
+			OpCode:      op,
+			Args:        []byte{},
+			Description: op.GetDescription(),
+		}
+		offset++
+
+		if op >= opcode.PUSH1 && op <= opcode.PUSH32 {
+			size := int(op-opcode.PUSH1) + 1
+			// solc cuts the argument of a push running past the end of the bytecode short.
+			if i+1 < len(fields) && strings.HasPrefix(fields[i+1], "0x") {
+				i++
+				args, err := hex.DecodeString(padHex(strings.TrimPrefix(fields[i], "0x")))
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse argument of %s at offset %d: %w", op, instruction.Offset, err)
+				}
+				if len(args) > size {
+					return nil, fmt.Errorf("argument of %s at offset %d is %d bytes long", op, instruction.Offset, len(args))
+				}
+				// Leading zero bytes are left out of arguments.
+				instruction.Args = append(make([]byte, size-len(args)), args...)
+			}
+			offset += size
+		}
+
+		toReturn = append(toReturn, instruction)
+	}
+
+	return toReturn, nil
+}
+
+// parseOpcode returns the opcode with the name, or of the hex encoded byte.
+func parseOpcode(name string) (opcode.OpCode, error) {
+	if strings.HasPrefix(name, "0x") {
+		value, err := hex.DecodeString(padHex(strings.TrimPrefix(name, "0x")))
+		if err != nil || len(value) != 1 {
+			return 0, fmt.Errorf("invalid opcode %s", name)
+		}
+		return opcode.OpCode(value[0]), nil
+	}
+
+	if op, ok := opcodeAliases[name]; ok {
+		return op, nil
+	}
+
+	// Unknown names map to STOP, whose value is zero.
+	if op := opcode.StringToOp(name); op != opcode.STOP || name == "STOP" {
+		return op, nil
+	}
+
+	return 0, fmt.Errorf("unknown opcode %s", name)
+}
+
+// padHex left pads hex of odd length with a zero.
+func padHex(value string) string {
+	if len(value)%2 == 1 {
+		return "0" + value
+	}
+	return value
+}
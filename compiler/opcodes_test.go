@@ -0,0 +1,51 @@
+package compiler
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo/opcode"
+)
+
+func TestParseOpcodes(t *testing.T) {
+	// Opcodes solc 0.8.19 printed for the bytecode, which ends in the start of the metadata.
+	const bytecode = "6080604052348015600f57600080fd5b50fea2646970667358221220"
+	const opcodes = "PUSH1 0x80 PUSH1 0x40 MSTORE CALLVALUE DUP1 ISZERO PUSH1 0xF JUMPI PUSH1 0x0 DUP1 REVERT JUMPDEST POP INVALID LOG2 PUSH5 0x6970667358 0x22 SLT SHA3"
+
+	instructions, err := ParseOpcodes(opcodes)
+	require.NoError(t, err)
+
+	code, err := hex.DecodeString(bytecode)
+	require.NoError(t, err)
+	disassembler, err := opcode.NewDecompiler(context.TODO(), code)
+	require.NoError(t, err)
+	require.NoError(t, disassembler.Decompile())
+	assert.Equal(t, disassembler.GetInstructions(), instructions)
+
+	assert.Equal(t, opcode.PUSH1, instructions[8].OpCode)
+	assert.Equal(t, []byte{0x00}, instructions[8].Args)
+	assert.Equal(t, opcode.KECCAK256, instructions[len(instructions)-1].OpCode)
+
+	var contract Contract
+	require.NoError(t, json.Unmarshal([]byte(`{"evm": {
+		"assembly": "    /* \"Counter.sol\":57:117  contract Counter {... */\n  mstore(0x40, 0x80)\n",
+		"bytecode": {"object": "`+bytecode+`", "opcodes": "`+opcodes+`"},
+		"deployedBytecode": {"object": "6080", "opcodes": "PUSH1 0x80"}
+	}}`), &contract))
+	assert.Contains(t, contract.GetAssembly(), "mstore(0x40, 0x80)")
+	assert.Equal(t, opcodes, contract.GetOpcodes())
+
+	deployed, err := contract.GetDeployedInstructions()
+	require.NoError(t, err)
+	require.Len(t, deployed, 1)
+	assert.Equal(t, []byte{0x80}, deployed[0].Args)
+
+	_, err = ParseOpcodes("PUSH1 0x80 NOTANOPCODE")
+	assert.Error(t, err)
+	_, err = ParseOpcodes("PUSH1 0x8000")
+	assert.Error(t, err)
+}
@@ -0,0 +1,334 @@
+package compiler
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/0x19/solc-switch"
+	"github.com/unpackdev/solgo/opcode"
+)
+
+// Severities of compiler errors.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// SourceLocation locates an error in a source file by byte offsets.
+type SourceLocation struct {
+	File  string `json:"file"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// Error is an error, warning or info message of the compiler.
+type Error struct {
+	Component        string          `json:"component"`
+	Severity         string          `json:"severity"`  // One of error, warning or info.
+	Type             string          `json:"type"`      // Such as TypeError or ParserError.
+	ErrorCode        string          `json:"errorCode"` // Numeric code identifying the message.
+	Message          string          `json:"message"`
+	FormattedMessage string          `json:"formattedMessage"`
+	SourceLocation   *SourceLocation `json:"sourceLocation,omitempty"`
+}
+
+// IsError reports whether the message fails the compilation.
+func (e *Error) IsError() bool {
+	return e.Severity == SeverityError
+}
+
+// Error returns the formatted message, or the message if it is not formatted.
+func (e *Error) Error() string {
+	if e.FormattedMessage != "" {
+		return strings.TrimSpace(e.FormattedMessage)
+	}
+	return fmt.Sprintf("%s: %s", e.Type, e.Message)
+}
+
+// LinkReference locates a placeholder for a library address in bytecode.
+type LinkReference struct {
+	Start  int `json:"start"`
+	Length int `json:"length"`
+}
+
+// Bytecode is the creation or deployed bytecode of a contract.
+type Bytecode struct {
+	Object              string                                `json:"object"` // Hex encoded, with placeholders for unlinked libraries.
+	Opcodes             string                                `json:"opcodes"`
+	SourceMap           string                                `json:"sourceMap"`
+	LinkReferences      map[string]map[string][]LinkReference `json:"linkReferences,omitempty"`      // By file and library name.
+	ImmutableReferences map[string][]LinkReference            `json:"immutableReferences,omitempty"` // By AST id of the immutable.
+}
+
+// IsLinked reports whether the bytecode has no placeholders for library addresses.
+func (b *Bytecode) IsLinked() bool {
+	return len(b.LinkReferences) == 0
+}
+
+// EVM holds the EVM related outputs of a contract.
+type EVM struct {
+	Bytecode          *Bytecode         `json:"bytecode,omitempty"`
+	DeployedBytecode  *Bytecode         `json:"deployedBytecode,omitempty"`
+	MethodIdentifiers map[string]string `json:"methodIdentifiers,omitempty"` // Selectors by signature.
+	Assembly          string            `json:"assembly,omitempty"`          // Human-readable assembly of the creation and deployed code.
+	LegacyAssembly    json.RawMessage   `json:"legacyAssembly,omitempty"`    // Assembly as JSON, unset when compiling via IR.
+}
+
+// StorageSlot is a state variable, or a struct member, in a storage layout.
+type StorageSlot struct {
+	AstId    int64  `json:"astId"`
+	Contract string `json:"contract"`
+	Label    string `json:"label"`
+	Offset   int    `json:"offset"`
+	Slot     string `json:"slot"`
+	Type     string `json:"type"`
+}
+
+// StorageType describes a type of a storage layout.
+type StorageType struct {
+	Encoding      string         `json:"encoding"` // inplace, mapping, dynamic_array or bytes.
+	Label         string         `json:"label"`
+	NumberOfBytes string         `json:"numberOfBytes"`
+	Key           string         `json:"key,omitempty"`
+	Value         string         `json:"value,omitempty"`
+	Base          string         `json:"base,omitempty"`
+	Members       []*StorageSlot `json:"members,omitempty"`
+}
+
+// StorageLayout is the layout of the state variables of a contract in storage.
+type StorageLayout struct {
+	Storage []*StorageSlot          `json:"storage"`
+	Types   map[string]*StorageType `json:"types"`
+}
+
+// Contract holds the outputs generated for a contract. Outputs that were not selected are empty.
+type Contract struct {
+	ABI           json.RawMessage `json:"abi,omitempty"`
+	Metadata      string          `json:"metadata,omitempty"`
+	EVM           *EVM            `json:"evm,omitempty"`
+	StorageLayout *StorageLayout  `json:"storageLayout,omitempty"`
+	IR            string          `json:"ir,omitempty"`
+	IROptimized   string          `json:"irOptimized,omitempty"`
+}
+
+// GetBytecode returns the hex encoded creation bytecode, if selected.
+func (c *Contract) GetBytecode() string {
+	if c.EVM == nil || c.EVM.Bytecode == nil {
+		return ""
+	}
+	return c.EVM.Bytecode.Object
+}
+
+// GetDeployedBytecode returns the hex encoded deployed bytecode, if selected.
+func (c *Contract) GetDeployedBytecode() string {
+	if c.EVM == nil || c.EVM.DeployedBytecode == nil {
+		return ""
+	}
+	return c.EVM.DeployedBytecode.Object
+}
+
+// GetImmutableReferences returns the byte ranges of the deployed bytecode the constructor writes the
+// values of immutables into, by AST id of the immutable, if the deployed bytecode was selected.
+func (c *Contract) GetImmutableReferences() map[string][]LinkReference {
+	if c.EVM == nil || c.EVM.DeployedBytecode == nil {
+		return nil
+	}
+	return c.EVM.DeployedBytecode.ImmutableReferences
+}
+
+// GetAssembly returns the human-readable assembly, if selected.
+func (c *Contract) GetAssembly() string {
+	if c.EVM == nil {
+		return ""
+	}
+	return c.EVM.Assembly
+}
+
+// GetOpcodes returns the opcodes of the creation bytecode, if selected.
+func (c *Contract) GetOpcodes() string {
+	if c.EVM == nil || c.EVM.Bytecode == nil {
+		return ""
+	}
+	return c.EVM.Bytecode.Opcodes
+}
+
+// GetDeployedOpcodes returns the opcodes of the deployed bytecode, if selected.
+func (c *Contract) GetDeployedOpcodes() string {
+	if c.EVM == nil || c.EVM.DeployedBytecode == nil {
+		return ""
+	}
+	return c.EVM.DeployedBytecode.Opcodes
+}
+
+// GetInstructions returns the instructions of the creation bytecode parsed from its opcodes, so they
+// can be analysed without disassembling the bytecode.
+func (c *Contract) GetInstructions() ([]opcode.Instruction, error) {
+	return ParseOpcodes(c.GetOpcodes())
+}
+
+// GetDeployedInstructions returns the instructions of the deployed bytecode parsed from its opcodes.
+func (c *Contract) GetDeployedInstructions() ([]opcode.Instruction, error) {
+	return ParseOpcodes(c.GetDeployedOpcodes())
+}
+
+// GetABI returns the JSON encoded ABI, if selected.
+func (c *Contract) GetABI() string {
+	return string(c.ABI)
+}
+
+// GetMetadata returns the JSON encoded metadata, if selected.
+func (c *Contract) GetMetadata() string {
+	return c.Metadata
+}
+
+// SourceOutput holds the outputs generated for a source file.
+type SourceOutput struct {
+	Id  int             `json:"id"`
+	AST json.RawMessage `json:"ast,omitempty"`
+}
+
+// Output is the standard JSON output of solc.
+type Output struct {
+	Version   string                          `json:"version,omitempty"` // Version of the compiler, set by Compile.
+	Errors    []*Error                        `json:"errors,omitempty"`
+	Sources   map[string]*SourceOutput        `json:"sources,omitempty"`
+	Contracts map[string]map[string]*Contract `json:"contracts,omitempty"` // By file and contract name.
+}
+
+// HasErrors reports whether the compilation failed.
+func (o *Output) HasErrors() bool {
+	return len(o.GetErrors()) > 0
+}
+
+// GetErrors returns the messages failing the compilation.
+func (o *Output) GetErrors() []*Error {
+	return o.bySeverity(SeverityError)
+}
+
+// GetWarnings returns the warnings of the compilation.
+func (o *Output) GetWarnings() []*Error {
+	return o.bySeverity(SeverityWarning)
+}
+
+// GetContract returns the outputs of the contract of the file, or nil if there are none.
+func (o *Output) GetContract(file string, name string) *Contract {
+	return o.Contracts[file][name]
+}
+
+// GetContractByName returns the outputs of the contract with the name, in whichever file it is
+// declared, or nil if there are none. Files are searched in alphabetical order.
+func (o *Output) GetContractByName(name string) *Contract {
+	for _, file := range o.GetFiles() {
+		if contract, ok := o.Contracts[file][name]; ok {
+			return contract
+		}
+	}
+	return nil
+}
+
+// GetContracts returns the outputs of every contract, by fully qualified name such as
+// contracts/Token.sol:Token, so those of factories, the contracts they deploy and libraries all come
+// from a single compilation.
+func (o *Output) GetContracts() map[string]*Contract {
+	toReturn := make(map[string]*Contract)
+	for file, contracts := range o.Contracts {
+		for name, contract := range contracts {
+			toReturn[FullyQualifiedName(file, name)] = contract
+		}
+	}
+	return toReturn
+}
+
+// GetContractByFullyQualifiedName returns the outputs of the contract with the fully qualified name,
+// such as contracts/Token.sol:Token, or nil if there are none.
+func (o *Output) GetContractByFullyQualifiedName(name string) *Contract {
+	index := strings.LastIndex(name, ":")
+	if index < 0 {
+		return nil
+	}
+	return o.GetContract(name[:index], name[index+1:])
+}
+
+// GetFullyQualifiedNames returns the fully qualified names of the contracts, in alphabetical order.
+func (o *Output) GetFullyQualifiedNames() []string {
+	toReturn := make([]string, 0)
+	for _, file := range o.GetFiles() {
+		names := make([]string, 0, len(o.Contracts[file]))
+		for name := range o.Contracts[file] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			toReturn = append(toReturn, FullyQualifiedName(file, name))
+		}
+	}
+	return toReturn
+}
+
+// ToCompilerResults converts the output to solc-switch results, one per contract named by its fully
+// qualified name, so code consuming those, such as the verifier, works with every contract of the
+// output. The contract named entry, by name or fully qualified name, is marked as the entry contract.
+func (o *Output) ToCompilerResults(entry string) *solc.CompilerResults {
+	compilationErrors := make([]solc.CompilationError, 0, len(o.Errors))
+	for _, e := range o.Errors {
+		compilationError := solc.CompilationError{
+			Component: e.Component,
+			Formatted: e.FormattedMessage,
+			Message:   e.Message,
+			Severity:  e.Severity,
+			Type:      e.Type,
+		}
+		if e.SourceLocation != nil {
+			compilationError.SourceLocation = solc.CompilationErrorSourceLocation(*e.SourceLocation)
+		}
+		compilationErrors = append(compilationErrors, compilationError)
+	}
+
+	toReturn := &solc.CompilerResults{Results: make([]*solc.CompilerResult, 0)}
+	for _, name := range o.GetFullyQualifiedNames() {
+		contract := o.GetContractByFullyQualifiedName(name)
+		toReturn.Results = append(toReturn.Results, &solc.CompilerResult{
+			IsEntryContract:  entry != "" && (name == entry || strings.HasSuffix(name, ":"+entry)),
+			RequestedVersion: o.Version,
+			CompilerVersion:  o.Version,
+			ContractName:     name,
+			Bytecode:         contract.GetBytecode(),
+			DeployedBytecode: contract.GetDeployedBytecode(),
+			ABI:              contract.GetABI(),
+			Opcodes:          contract.GetOpcodes(),
+			Metadata:         contract.GetMetadata(),
+			Errors:           compilationErrors,
+		})
+	}
+	return toReturn
+}
+
+// FullyQualifiedName returns the name solc identifies the contract of the file with, such as
+// contracts/Token.sol:Token.
+func FullyQualifiedName(file string, name string) string {
+	return file + ":" + name
+}
+
+// GetFiles returns the files contracts were generated for, in alphabetical order.
+func (o *Output) GetFiles() []string {
+	toReturn := make([]string, 0, len(o.Contracts))
+	for file := range o.Contracts {
+		toReturn = append(toReturn, file)
+	}
+	sort.Strings(toReturn)
+	return toReturn
+}
+
+// bySeverity returns the messages with the severity.
+func (o *Output) bySeverity(severity string) []*Error {
+	toReturn := make([]*Error, 0)
+	for _, e := range o.Errors {
+		if e.Severity == severity {
+			toReturn = append(toReturn, e)
+		}
+	}
+	return toReturn
+}
@@ -0,0 +1,190 @@
+package compiler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/unpackdev/solgo"
+	"github.com/unpackdev/solgo/ast"
+	"github.com/unpackdev/solgo/opcode"
+)
+
+// Jumps of instructions in source maps.
+const (
+	JumpIntoFunction  = "i"
+	JumpOutOfFunction = "o"
+	JumpRegular       = "-"
+)
+
+// NoSourceFile is the file of instructions solc generated without a source, such as its utility code.
+const NoSourceFile = -1
+
+// SourceRange is the source range an instruction was generated from, an entry of a source map.
+type SourceRange struct {
+	Start         int    `json:"start"`  // Byte offset in the source file.
+	Length        int    `json:"length"` // Length in bytes.
+	File          int    `json:"file"`   // Id of the source file, as of SourceOutput, or NoSourceFile.
+	Jump          string `json:"jump"`   // One of JumpIntoFunction, JumpOutOfFunction or JumpRegular.
+	ModifierDepth int    `json:"modifierDepth"`
+}
+
+// HasSource reports whether the instruction was generated from a source file.
+func (r *SourceRange) HasSource() bool {
+	return r.File >= 0 && r.Start >= 0
+}
+
+// DecodeSourceMap expands the compressed source map solc outputs, entries of `s:l:f:j:m` separated
+// by semicolons where empty fields repeat those of the previous entry, into the source range of every
+// instruction of the bytecode, in order.
+func DecodeSourceMap(sourceMap string) ([]*SourceRange, error) {
+	toReturn := make([]*SourceRange, 0)
+	if sourceMap == "" {
+		return toReturn, nil
+	}
+
+	previous := &SourceRange{Start: -1, Length: -1, File: NoSourceFile, Jump: JumpRegular}
+	for index, entry := range strings.Split(sourceMap, ";") {
+		current := *previous
+
+		fields := strings.Split(entry, ":")
+		if len(fields) > 5 {
+			return nil, fmt.Errorf("source map entry %d has %d fields", index, len(fields))
+		}
+		for i, field := range fields {
+			if field == "" {
+				continue
+			}
+
+			if i == 3 {
+				if field != JumpIntoFunction && field != JumpOutOfFunction && field != JumpRegular {
+					return nil, fmt.Errorf("source map entry %d has invalid jump %s", index, field)
+				}
+				current.Jump = field
+				continue
+			}
+
+			value, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("source map entry %d has invalid field %s: %w", index, field, err)
+			}
+			switch i {
+			case 0:
+				current.Start = value
+			case 1:
+				current.Length = value
+			case 2:
+				current.File = value
+			case 4:
+				current.ModifierDepth = value
+			}
+		}
+
+		toReturn = append(toReturn, &current)
+		previous = &current
+	}
+
+	return toReturn, nil
+}
+
+// SourceRangeAt returns the source range of the instruction at the program counter, given the
+// instructions of the bytecode and its decoded source map.
+func SourceRangeAt(instructions []opcode.Instruction, sourceRanges []*SourceRange, pc int) (*SourceRange, error) {
+	for index, instruction := range instructions {
+		if instruction.Offset != pc {
+			continue
+		}
+		if index >= len(sourceRanges) {
+			return nil, fmt.Errorf("source map has no entry for instruction %d at program counter %d", index, pc)
+		}
+		return sourceRanges[index], nil
+	}
+
+	return nil, fmt.Errorf("no instruction at program counter %d", pc)
+}
+
+// GetSourceMap returns the source map of the creation bytecode, if selected.
+func (c *Contract) GetSourceMap() string {
+	if c.EVM == nil || c.EVM.Bytecode == nil {
+		return ""
+	}
+	return c.EVM.Bytecode.SourceMap
+}
+
+// GetDeployedSourceMap returns the source map of the deployed bytecode, if selected.
+func (c *Contract) GetDeployedSourceMap() string {
+	if c.EVM == nil || c.EVM.DeployedBytecode == nil {
+		return ""
+	}
+	return c.EVM.DeployedBytecode.SourceMap
+}
+
+// GetDeployedSourceRange returns the source range of the instruction of the deployed bytecode at the
+// program counter. Requires the opcodes and source map of the deployed bytecode to be selected.
+func (c *Contract) GetDeployedSourceRange(pc int) (*SourceRange, error) {
+	if c.GetDeployedSourceMap() == "" {
+		return nil, errors.New("deployed bytecode has no source map")
+	}
+
+	instructions, err := c.GetDeployedInstructions()
+	if err != nil {
+		return nil, err
+	}
+
+	sourceRanges, err := DecodeSourceMap(c.GetDeployedSourceMap())
+	if err != nil {
+		return nil, err
+	}
+
+	return SourceRangeAt(instructions, sourceRanges, pc)
+}
+
+// GetSourcePath returns the path of the source file with the id source maps refer to it by, or an
+// empty string if there is none.
+func (o *Output) GetSourcePath(id int) string {
+	for path, source := range o.Sources {
+		if source.Id == id {
+			return path
+		}
+	}
+	return ""
+}
+
+// NodeAtSourceRange returns the innermost node of the solgo AST, built from the sources compiled,
+// covering the source range, or nil if the range has no source or no node covers it.
+func (o *Output) NodeAtSourceRange(root *ast.RootNode, sources *solgo.Sources, sourceRange *SourceRange) (ast.Node[ast.NodeType], error) {
+	if sourceRange == nil || !sourceRange.HasSource() {
+		return nil, nil
+	}
+
+	path := o.GetSourcePath(sourceRange.File)
+	if path == "" {
+		return nil, fmt.Errorf("no source file with id %d", sourceRange.File)
+	}
+
+	start, err := ast.ParsedOffset(sources, path, int64(sourceRange.Start))
+	if err != nil {
+		return nil, err
+	}
+	end := start
+	if sourceRange.Length > 0 {
+		// The range ends at the character its last byte is in.
+		if end, err = ast.ParsedOffset(sources, path, int64(sourceRange.Start+sourceRange.Length-1)); err != nil {
+			return nil, err
+		}
+	}
+
+	return ast.NodeAtRange(root, start, end), nil
+}
+
+// NodeAtProgramCounter returns the innermost node of the solgo AST, built from the sources compiled,
+// that the instruction of the deployed bytecode of the contract at the program counter was generated
+// from. Returns nil for instructions solc generated without a source.
+func (o *Output) NodeAtProgramCounter(root *ast.RootNode, sources *solgo.Sources, contract *Contract, pc int) (ast.Node[ast.NodeType], error) {
+	sourceRange, err := contract.GetDeployedSourceRange(pc)
+	if err != nil {
+		return nil, err
+	}
+	return o.NodeAtSourceRange(root, sources, sourceRange)
+}
@@ -0,0 +1,99 @@
+package compiler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo"
+	"github.com/unpackdev/solgo/ast"
+)
+
+func TestDecodeSourceMap(t *testing.T) {
+	sourceRanges, err := DecodeSourceMap("57:60:0:-:0;;;73:10::i;::1;-1:-1:-1:o;")
+	require.NoError(t, err)
+	require.Len(t, sourceRanges, 7)
+
+	assert.Equal(t, &SourceRange{Start: 57, Length: 60, File: 0, Jump: JumpRegular}, sourceRanges[0])
+	assert.Equal(t, sourceRanges[0], sourceRanges[2], "empty entries repeat the previous one")
+	assert.Equal(t, &SourceRange{Start: 73, Length: 10, File: 0, Jump: JumpIntoFunction}, sourceRanges[3])
+	assert.Equal(t, &SourceRange{Start: 73, Length: 10, File: 1, Jump: JumpIntoFunction}, sourceRanges[4])
+	assert.Equal(t, &SourceRange{Start: -1, Length: -1, File: NoSourceFile, Jump: JumpOutOfFunction}, sourceRanges[5])
+	assert.False(t, sourceRanges[6].HasSource())
+	assert.True(t, sourceRanges[0].HasSource())
+
+	empty, err := DecodeSourceMap("")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	_, err = DecodeSourceMap("1:2:0:x")
+	assert.Error(t, err)
+	_, err = DecodeSourceMap("1:2:0:-:0:1")
+	assert.Error(t, err)
+	_, err = DecodeSourceMap("1:a")
+	assert.Error(t, err)
+}
+
+func TestNodeAtProgramCounter(t *testing.T) {
+	content := "// SPDX-License-Identifier: MIT\npragma solidity ^0.8.4;\n\n// Zähler\ncontract Counter {\n    uint256 public count;\n\n    function increment() external {\n        count += 1;\n    }\n}\n"
+	sources := &solgo.Sources{
+		SourceUnits:         []*solgo.SourceUnit{{Name: "Counter", Path: "contracts/Counter.sol", Content: content}},
+		EntrySourceUnitName: "Counter",
+		LocalSourcesPath:    "../sources/",
+	}
+	parser, err := solgo.NewParserFromSources(context.TODO(), sources)
+	require.NoError(t, err)
+	builder := ast.NewAstBuilder(parser.GetParser(), parser.GetSources())
+	require.NoError(t, parser.RegisterListener(solgo.ListenerAst, builder))
+	require.Empty(t, parser.Parse())
+	require.Empty(t, builder.ResolveReferences())
+
+	// Source maps locate by bytes, which the umlaut is two of.
+	contractStart := strings.Index(content, "contract Counter")
+	contractLength := len(content) - 1 - contractStart
+	assignmentStart := strings.Index(content, "count += 1")
+	sourceMap := fmt.Sprintf("%d:%d:0:-:0;;;%d:10::i;;;-1:-1:-1:o", contractStart, contractLength, assignmentStart)
+
+	contract := &Contract{EVM: &EVM{DeployedBytecode: &Bytecode{
+		Object:    "6080604052600160005500",
+		Opcodes:   "PUSH1 0x80 PUSH1 0x40 MSTORE PUSH1 0x1 PUSH1 0x0 SSTORE STOP",
+		SourceMap: sourceMap,
+	}}}
+	output := &Output{
+		Sources:   map[string]*SourceOutput{"contracts/Counter.sol": {Id: 0}},
+		Contracts: map[string]map[string]*Contract{"contracts/Counter.sol": {"Counter": contract}},
+	}
+	assert.Equal(t, sourceMap, contract.GetDeployedSourceMap())
+	assert.Equal(t, "contracts/Counter.sol", output.GetSourcePath(0))
+	assert.Empty(t, output.GetSourcePath(1))
+
+	sourceRange, err := contract.GetDeployedSourceRange(7)
+	require.NoError(t, err)
+	assert.Equal(t, assignmentStart, sourceRange.Start)
+
+	node, err := output.NodeAtProgramCounter(builder.GetRoot(), sources, contract, 7)
+	require.NoError(t, err)
+	require.NotNil(t, node)
+	assert.IsType(t, &ast.Assignment{}, node)
+
+	node, err = output.NodeAtProgramCounter(builder.GetRoot(), sources, contract, 0)
+	require.NoError(t, err)
+	require.NotNil(t, node)
+	counter, ok := node.(*ast.Contract)
+	require.True(t, ok, "got %T", node)
+	assert.Equal(t, "Counter", counter.GetName())
+
+	node, err = output.NodeAtProgramCounter(builder.GetRoot(), sources, contract, 10)
+	require.NoError(t, err)
+	assert.Nil(t, node, "solc generated the instruction without a source")
+
+	_, err = output.NodeAtProgramCounter(builder.GetRoot(), sources, contract, 1)
+	assert.Error(t, err, "program counter within push data")
+	_, err = output.NodeAtSourceRange(builder.GetRoot(), sources, &SourceRange{Start: 0, Length: 1, File: 3})
+	assert.Error(t, err)
+	_, err = (&Contract{}).GetDeployedSourceRange(0)
+	assert.Error(t, err)
+}
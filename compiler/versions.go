@@ -0,0 +1,140 @@
+package compiler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/0x19/solc-switch"
+	"github.com/unpackdev/solgo"
+	"github.com/unpackdev/solgo/utils"
+	"go.uber.org/zap"
+)
+
+// CompatibleVersions returns the versions of the releases, prereleases and drafts aside, satisfying
+// every constraint, such as the ones of solidity pragmas, in ascending order.
+func CompatibleVersions(releases []solc.Version, constraints []string) []string {
+	toReturn := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, release := range releases {
+		if release.Prerelease || release.Draft {
+			continue
+		}
+
+		version := strings.TrimPrefix(release.TagName, "v")
+		if seen[version] {
+			continue
+		}
+
+		compatible := true
+		for _, constraint := range constraints {
+			if !utils.SatisfiesVersionConstraint(version, constraint) {
+				compatible = false
+				break
+			}
+		}
+
+		if compatible {
+			seen[version] = true
+			toReturn = append(toReturn, version)
+		}
+	}
+
+	return SortVersions(toReturn)
+}
+
+// SelectVersion returns the latest version of the releases satisfying every constraint, which is the
+// one solc users pick and the one most likely to have compiled sources pinned to a range.
+func SelectVersion(releases []solc.Version, constraints []string) (string, error) {
+	versions := CompatibleVersions(releases, constraints)
+	if len(versions) == 0 {
+		return "", fmt.Errorf("no compiler release satisfies %s", strings.Join(constraints, " and "))
+	}
+	return versions[len(versions)-1], nil
+}
+
+// SortVersions returns the versions without their v prefix in ascending order.
+func SortVersions(versions []string) []string {
+	toReturn := make([]string, 0, len(versions))
+	for _, version := range versions {
+		toReturn = append(toReturn, strings.TrimPrefix(version, "v"))
+	}
+
+	sort.SliceStable(toReturn, func(i, j int) bool {
+		return utils.ParseSemanticVersion(toReturn[i]).Compare(utils.ParseSemanticVersion(toReturn[j])) < 0
+	})
+	return toReturn
+}
+
+// Releases returns the compiler releases known to solc-switch, synchronizing the list of releases
+// when none are cached yet.
+func Releases(compiler *solc.Solc) ([]solc.Version, error) {
+	if releases := compiler.GetCachedReleases(); len(releases) > 0 {
+		return releases, nil
+	}
+
+	releases, err := compiler.GetLocalReleases()
+	if err == nil && len(releases) > 0 {
+		return releases, nil
+	}
+
+	zap.L().Debug("no local solc releases found, synchronizing releases", zap.Error(err))
+	return compiler.SyncReleases()
+}
+
+// ResolveVersion returns the latest compiler release satisfying every solidity pragma of the sources.
+func ResolveVersion(compiler *solc.Solc, sources *solgo.Sources) (string, error) {
+	if sources == nil {
+		return "", errors.New("sources must be set")
+	}
+
+	pragmas := sources.GetSolidityPragmas()
+	if len(pragmas) == 0 {
+		return "", errors.New("sources have no solidity pragma to select a compiler version from")
+	}
+
+	releases, err := Releases(compiler)
+	if err != nil {
+		return "", err
+	}
+
+	return SelectVersion(releases, pragmas)
+}
+
+// EnsureBinary returns the path of the binary of the compiler version, downloading it into the
+// releases path of solc-switch first if it is not cached there yet.
+func EnsureBinary(compiler *solc.Solc, version string) (string, error) {
+	version = strings.TrimPrefix(version, "v")
+	if binary, err := compiler.GetBinary(version); err == nil {
+		return binary, nil
+	}
+
+	if _, err := Releases(compiler); err != nil {
+		return "", err
+	}
+
+	release, err := compiler.GetRelease(version)
+	if err != nil {
+		return "", fmt.Errorf("compiler release %s not found: %w", version, err)
+	}
+
+	zap.L().Debug("downloading solc binary", zap.String("version", version))
+	if err := compiler.SyncOne(release); err != nil {
+		return "", fmt.Errorf("failed to download compiler %s: %w", version, err)
+	}
+
+	binary, err := compiler.GetBinary(version)
+	if err != nil {
+		return "", err
+	}
+
+	if info, err := os.Stat(binary); err == nil && info.Mode()&0111 == 0 {
+		if err := os.Chmod(binary, info.Mode()|0700); err != nil {
+			return "", err
+		}
+	}
+
+	return binary, nil
+}
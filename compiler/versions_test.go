@@ -0,0 +1,88 @@
+package compiler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0x19/solc-switch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo"
+)
+
+func TestSelectVersion(t *testing.T) {
+	releases := []solc.Version{
+		{TagName: "v0.8.20"}, {TagName: "v0.8.21", Prerelease: true}, {TagName: "v0.8.19"},
+		{TagName: "v0.8.4"}, {TagName: "v0.7.6"}, {TagName: "v0.9.0"},
+	}
+
+	version, err := SelectVersion(releases, []string{">=0.6.0 <0.9.0", "^0.8.0"})
+	require.NoError(t, err)
+	assert.Equal(t, "0.8.20", version)
+
+	version, err = SelectVersion(releases, []string{"~0.8.4"})
+	require.NoError(t, err)
+	assert.Equal(t, "0.8.20", version)
+
+	version, err = SelectVersion(releases, []string{"0.7.6"})
+	require.NoError(t, err)
+	assert.Equal(t, "0.7.6", version)
+
+	_, err = SelectVersion(releases, []string{"^0.5.0"})
+	assert.Error(t, err)
+}
+
+func TestCompileSources(t *testing.T) {
+	fake, inputPath := fakeSolcForTest(t, outputForTest)
+	script, err := os.ReadFile(fake)
+	require.NoError(t, err)
+
+	// Only 0.8.20 is cached, so nothing is downloaded while resolving it.
+	releasesPath := t.TempDir()
+	require.NoError(t, os.WriteFile(
+		filepath.Join(releasesPath, "releases.json"),
+		[]byte(`[{"tag_name":"v0.8.21","prerelease":true},{"tag_name":"v0.8.20"},{"tag_name":"v0.8.19"}]`),
+		0600,
+	))
+	require.NoError(t, os.WriteFile(filepath.Join(releasesPath, "solc-0.8.20"), script, 0700))
+
+	config, err := solc.NewDefaultConfig()
+	require.NoError(t, err)
+	require.NoError(t, config.SetReleasesPath(releasesPath))
+	switcher, err := solc.New(context.TODO(), config)
+	require.NoError(t, err)
+
+	compiler, err := NewCompiler(context.TODO(), switcher)
+	require.NoError(t, err)
+
+	sources := &solgo.Sources{
+		SourceUnits: []*solgo.SourceUnit{
+			{Name: "Counter", Path: "Counter.sol", Content: "// SPDX-License-Identifier: MIT\npragma solidity ^0.8.0;\n\ncontract Counter { uint256 public count; }\n"},
+		},
+		EntrySourceUnitName: "Counter",
+	}
+
+	version, err := compiler.ResolveVersion(sources)
+	require.NoError(t, err)
+	assert.Equal(t, "0.8.20", version)
+
+	binary, err := EnsureBinary(switcher, "v0.8.20")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(releasesPath, "solc-0.8.20"), binary)
+
+	output, err := compiler.CompileSources(context.TODO(), sources)
+	require.NoError(t, err)
+	assert.Equal(t, "0.8.20", output.Version)
+	assert.NotNil(t, output.GetContractByName("Counter"))
+	assert.FileExists(t, inputPath)
+
+	_, err = compiler.ResolveVersion(&solgo.Sources{
+		SourceUnits: []*solgo.SourceUnit{{Name: "Counter", Content: "contract Counter {}"}},
+	})
+	assert.Error(t, err)
+
+	_, err = EnsureBinary(switcher, "0.9.99")
+	assert.Error(t, err)
+}
@@ -19,7 +19,7 @@ func (c *Contract) DiscoverMetadata(ctx context.Context) (*bytecode.Metadata, er
 	case <-ctx.Done():
 		return nil, nil
 	default:
-		bMetadata, err := bytecode.DecodeContractMetadata(c.GetDeployedBytecode())
+		bMetadata, err := bytecode.ExtractMetadata(c.GetDeployedBytecode())
 		if err != nil {
 			return nil, fmt.Errorf("failed to decode contract %s metadata: %s", c.GetAddress(), err)
 		}
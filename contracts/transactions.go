@@ -3,6 +3,7 @@ package contracts
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/unpackdev/solgo/bytecode"
@@ -31,7 +32,21 @@ func (c *Contract) DecodeTransaction(ctx context.Context, data []byte) (*bytecod
 			abiRoot := detector.GetABI().GetRoot()
 
 			if irRoot.GetEntryContract() != nil {
-				for _, contract := range abiRoot.GetContracts() {
+				// The deployed contract dispatches the selector, so its ABI is tried before the ABIs of the
+				// contracts it uses, which may declare functions with the same selector.
+				names := make([]string, 0, len(abiRoot.GetContracts()))
+				for name := range abiRoot.GetContracts() {
+					if name != abiRoot.GetEntryName() {
+						names = append(names, name)
+					}
+				}
+				sort.Strings(names)
+				if abiRoot.GetEntryContract() != nil {
+					names = append([]string{abiRoot.GetEntryName()}, names...)
+				}
+
+				for _, name := range names {
+					contract := abiRoot.GetContractByName(name)
 					jsonData, err := utils.ToJSON(contract)
 					if err != nil {
 						return nil, fmt.Errorf("failed to convert contract to json: %s", err)
@@ -0,0 +1,7 @@
+/*
+Package corpus aggregates statistics across many analyzed contracts, such as the distribution of pragma
+versions, the prevalence of EIP standards, the hit rates of audit detectors and the average number of
+functions per contract. Statistics are mergeable, so workers analyzing shards of a corpus can accumulate
+their own and combine them, or persist them as JSON and resume later, for research and monitoring.
+*/
+package corpus
@@ -0,0 +1,215 @@
+package corpus
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-json"
+	"github.com/unpackdev/solgo/audit"
+	"github.com/unpackdev/solgo/ir"
+	"github.com/unpackdev/solgo/standards"
+)
+
+// Stats accumulates statistics across analyzed contracts. It is safe for concurrent use.
+type Stats struct {
+	mu sync.Mutex
+
+	Projects          int64            `json:"projects"`           // IR roots added, one per analyzed set of sources.
+	Contracts         int64            `json:"contracts"`          // Contracts, interfaces and libraries of the roots.
+	ContractsByKind   map[string]int64 `json:"contracts_by_kind"`  // Contracts by kind, such as contract or interface.
+	Functions         int64            `json:"functions"`          // Functions declared by the contracts.
+	Pragmas           map[string]int64 `json:"pragmas"`            // Contracts by the solidity version they pragma, such as ^0.8.19.
+	Standards         map[string]int64 `json:"standards"`          // Roots by the standards detected in them, such as ERC20.
+	Audits            int64            `json:"audits"`             // Successful audit reports added.
+	Detectors         map[string]int64 `json:"detectors"`          // Audit reports by the detector checks they hit, such as reentrancy-eth.
+	DetectorsFindings map[string]int64 `json:"detectors_findings"` // Findings by detector check.
+}
+
+// NewStats creates empty statistics.
+func NewStats() *Stats {
+	return &Stats{
+		ContractsByKind:   make(map[string]int64),
+		Pragmas:           make(map[string]int64),
+		Standards:         make(map[string]int64),
+		Detectors:         make(map[string]int64),
+		DetectorsFindings: make(map[string]int64),
+	}
+}
+
+// NewStatsFromJSON restores statistics persisted with ToJSON.
+func NewStatsFromJSON(data []byte) (*Stats, error) {
+	toReturn := NewStats()
+	if err := json.Unmarshal(data, toReturn); err != nil {
+		return nil, err
+	}
+	toReturn.init()
+	return toReturn, nil
+}
+
+// AddRoot accumulates the contracts of an IR root, its pragmas, functions and the standards detected in
+// it. Standards count once per root, whichever number of its contracts implement them.
+func (s *Stats) AddRoot(root *ir.RootSourceUnit) {
+	if root == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.init()
+
+	s.Projects++
+	for _, contract := range root.GetContracts() {
+		s.Contracts++
+		s.ContractsByKind[strings.ToLower(strings.TrimPrefix(contract.GetKind().String(), "KIND_"))]++
+		s.Functions += int64(len(contract.GetFunctions()))
+
+		for _, pragma := range contract.GetPragmas() {
+			if strings.Contains(pragma.GetText(), "solidity") {
+				s.Pragmas[pragma.GetVersion()]++
+				break
+			}
+		}
+	}
+
+	detected := make(map[string]bool)
+	for _, standard := range root.GetStandards() {
+		if standard.Confidence.Confidence > standards.NoConfidence {
+			detected[standard.Standard.Type.String()] = true
+		}
+	}
+	for standard := range detected {
+		s.Standards[standard]++
+	}
+}
+
+// AddReport accumulates the findings of an audit report. Reports which failed are left out, as they say
+// nothing of the detectors.
+func (s *Stats) AddReport(report *audit.Report) {
+	if report == nil || !report.IsSuccess() {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.init()
+
+	s.Audits++
+	if report.GetResults() == nil {
+		return
+	}
+	hit := make(map[string]bool)
+	for _, detector := range report.GetResults().GetDetectors() {
+		hit[detector.Check] = true
+		s.DetectorsFindings[detector.Check]++
+	}
+	for check := range hit {
+		s.Detectors[check]++
+	}
+}
+
+// Merge adds the statistics accumulated by other to these, such as the ones of another worker.
+func (s *Stats) Merge(other *Stats) {
+	if other == nil || other == s {
+		return
+	}
+	snapshot := other.Snapshot()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.init()
+
+	s.Projects += snapshot.Projects
+	s.Contracts += snapshot.Contracts
+	s.Functions += snapshot.Functions
+	s.Audits += snapshot.Audits
+	mergeCounts(s.ContractsByKind, snapshot.ContractsByKind)
+	mergeCounts(s.Pragmas, snapshot.Pragmas)
+	mergeCounts(s.Standards, snapshot.Standards)
+	mergeCounts(s.Detectors, snapshot.Detectors)
+	mergeCounts(s.DetectorsFindings, snapshot.DetectorsFindings)
+}
+
+// Snapshot returns a copy of the statistics accumulated so far.
+func (s *Stats) Snapshot() *Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	toReturn := NewStats()
+	toReturn.Projects = s.Projects
+	toReturn.Contracts = s.Contracts
+	toReturn.Functions = s.Functions
+	toReturn.Audits = s.Audits
+	mergeCounts(toReturn.ContractsByKind, s.ContractsByKind)
+	mergeCounts(toReturn.Pragmas, s.Pragmas)
+	mergeCounts(toReturn.Standards, s.Standards)
+	mergeCounts(toReturn.Detectors, s.Detectors)
+	mergeCounts(toReturn.DetectorsFindings, s.DetectorsFindings)
+	return toReturn
+}
+
+// AverageFunctions returns the average number of functions per contract.
+func (s *Stats) AverageFunctions() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return ratio(s.Functions, s.Contracts)
+}
+
+// PragmaDistribution returns the share of contracts pragmaing each solidity version. Contracts without a
+// solidity pragma make the shares add up to less than one.
+func (s *Stats) PragmaDistribution() map[string]float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return shares(s.Pragmas, s.Contracts)
+}
+
+// StandardPrevalence returns the share of roots each standard was detected in.
+func (s *Stats) StandardPrevalence() map[string]float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return shares(s.Standards, s.Projects)
+}
+
+// DetectorHitRates returns the share of audit reports each detector check was hit in.
+func (s *Stats) DetectorHitRates() map[string]float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return shares(s.Detectors, s.Audits)
+}
+
+// ToJSON returns the JSON representation of the statistics, which NewStatsFromJSON restores.
+func (s *Stats) ToJSON() ([]byte, error) {
+	return json.Marshal(s.Snapshot())
+}
+
+// init allocates the maps left nil, such as by a zero Stats or JSON without them.
+func (s *Stats) init() {
+	for _, counts := range []*map[string]int64{&s.ContractsByKind, &s.Pragmas, &s.Standards, &s.Detectors, &s.DetectorsFindings} {
+		if *counts == nil {
+			*counts = make(map[string]int64)
+		}
+	}
+}
+
+// mergeCounts adds the counts of from to the ones of to.
+func mergeCounts(to map[string]int64, from map[string]int64) {
+	for key, count := range from {
+		to[key] += count
+	}
+}
+
+// shares returns the counts divided by the total.
+func shares(counts map[string]int64, total int64) map[string]float64 {
+	toReturn := make(map[string]float64, len(counts))
+	for key, count := range counts {
+		toReturn[key] = ratio(count, total)
+	}
+	return toReturn
+}
+
+// ratio returns count divided by total, or zero for no total.
+func ratio(count int64, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) / float64(total)
+}
@@ -0,0 +1,94 @@
+package corpus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo"
+	"github.com/unpackdev/solgo/audit"
+	"github.com/unpackdev/solgo/ir"
+)
+
+func buildRootForTest(t *testing.T, name string, content string) *ir.RootSourceUnit {
+	builder, err := ir.NewBuilderFromSources(context.TODO(), &solgo.Sources{
+		SourceUnits:         []*solgo.SourceUnit{{Name: name, Path: name + ".sol", Content: content}},
+		EntrySourceUnitName: name,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, builder.Parse())
+	require.NoError(t, builder.Build())
+	return builder.GetRoot()
+}
+
+func TestStats(t *testing.T) {
+	token := buildRootForTest(t, "Token", `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.19;
+
+interface IERC20 {
+    event Transfer(address indexed from, address indexed to, uint256 value);
+    event Approval(address indexed owner, address indexed spender, uint256 value);
+
+    function totalSupply() external view returns (uint256);
+    function balanceOf(address account) external view returns (uint256);
+    function transfer(address to, uint256 amount) external returns (bool);
+    function allowance(address owner, address spender) external view returns (uint256);
+    function approve(address spender, uint256 amount) external returns (bool);
+    function transferFrom(address from, address to, uint256 amount) external returns (bool);
+}
+
+library Math {
+    function max(uint256 a, uint256 b) internal pure returns (uint256) {
+        return a > b ? a : b;
+    }
+}
+`)
+	vault := buildRootForTest(t, "Vault", `// SPDX-License-Identifier: MIT
+pragma solidity 0.7.6;
+
+contract Vault {
+    function deposit() external payable {}
+}
+`)
+
+	first, second := NewStats(), NewStats()
+	first.AddRoot(token)
+	first.AddReport(&audit.Report{Success: true, Results: &audit.Results{Detectors: []audit.Detector{
+		{Check: "reentrancy-eth"}, {Check: "reentrancy-eth"}, {Check: "solc-version"},
+	}}})
+	second.AddRoot(vault)
+	second.AddReport(&audit.Report{Success: true, Results: &audit.Results{Detectors: []audit.Detector{
+		{Check: "solc-version"},
+	}}})
+	second.AddReport(&audit.Report{Success: false})
+	second.AddRoot(nil)
+
+	first.Merge(second)
+	assert.Equal(t, int64(2), first.Projects)
+	assert.Equal(t, int64(3), first.Contracts)
+	assert.Equal(t, map[string]int64{"interface": 1, "library": 1, "contract": 1}, first.ContractsByKind)
+	assert.Equal(t, int64(8), first.Functions)
+	assert.InDelta(t, 8.0/3.0, first.AverageFunctions(), 1e-9)
+	assert.Equal(t, map[string]int64{"^0.8.19": 2, "0.7.6": 1}, first.Pragmas)
+	assert.InDelta(t, 1.0/3.0, first.PragmaDistribution()["0.7.6"], 1e-9)
+	assert.Equal(t, 0.5, first.StandardPrevalence()["ERC20"])
+
+	assert.Equal(t, int64(2), first.Audits)
+	assert.Equal(t, map[string]float64{"reentrancy-eth": 0.5, "solc-version": 1}, first.DetectorHitRates())
+	assert.Equal(t, int64(2), first.DetectorsFindings["reentrancy-eth"])
+
+	// Merged statistics are left as they were.
+	assert.Equal(t, int64(1), second.Projects)
+
+	data, err := first.ToJSON()
+	require.NoError(t, err)
+	restored, err := NewStatsFromJSON(data)
+	require.NoError(t, err)
+	assert.Equal(t, first.Snapshot(), restored.Snapshot())
+
+	var empty Stats
+	empty.Merge(restored)
+	assert.Equal(t, int64(2), empty.Projects)
+	assert.Equal(t, float64(0), NewStats().AverageFunctions())
+}
@@ -0,0 +1,300 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+// ErrDraining is returned for jobs submitted once the daemon started draining.
+var ErrDraining = errors.New("daemon is draining")
+
+// Options defines the options for configuring a daemon.
+type Options struct {
+	Addr         string        `mapstructure:"addr" yaml:"addr" json:"addr"`                            // Address the HTTP server listens on, such as :8080.
+	DrainTimeout time.Duration `mapstructure:"drain_timeout" yaml:"drain_timeout" json:"drain_timeout"` // How long stopping waits for jobs in flight, 30 seconds if unset.
+	CheckTimeout time.Duration `mapstructure:"check_timeout" yaml:"check_timeout" json:"check_timeout"` // How long a health check may run, 5 seconds if unset.
+}
+
+// CheckFunc checks the health of a dependency of the daemon, such as a client or a cache store.
+type CheckFunc func(ctx context.Context) error
+
+// CheckResult is the outcome of a health check.
+type CheckResult struct {
+	Name  string `json:"name"`
+	Error string `json:"error,omitempty"` // Why the check failed, if it did.
+}
+
+// Status is the body of the health endpoints.
+type Status struct {
+	Status   string         `json:"status"` // ok, draining or unavailable.
+	InFlight int64          `json:"in_flight"`
+	Checks   []*CheckResult `json:"checks,omitempty"`
+}
+
+// Daemon serves health checks and metrics of solgo operated as a service, and tracks the jobs it runs
+// so that stopping drains them. Endpoints are /healthz for liveness, /readyz for readiness, which fails
+// while draining or when a check fails, and /metrics for Prometheus.
+type Daemon struct {
+	opts     *Options
+	metrics  *Metrics
+	server   *http.Server
+	draining atomic.Bool
+	inFlight atomic.Int64
+	jobs     sync.WaitGroup
+	mu       sync.RWMutex // Guards checks, and orders submitting jobs before draining.
+	checks   map[string]CheckFunc
+}
+
+// NewDaemon creates a daemon with the options, which has to be started with Run or served through
+// its Handler.
+func NewDaemon(opts *Options) (*Daemon, error) {
+	if opts == nil {
+		return nil, errors.New("options must be set")
+	}
+
+	if opts.DrainTimeout == 0 {
+		opts.DrainTimeout = 30 * time.Second
+	}
+
+	if opts.CheckTimeout == 0 {
+		opts.CheckTimeout = 5 * time.Second
+	}
+
+	toReturn := &Daemon{
+		opts:    opts,
+		metrics: NewMetrics(),
+		checks:  make(map[string]CheckFunc),
+	}
+	toReturn.server = &http.Server{
+		Addr:              opts.Addr,
+		Handler:           toReturn.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return toReturn, nil
+}
+
+// GetOptions returns the options of the daemon.
+func (d *Daemon) GetOptions() *Options {
+	return d.opts
+}
+
+// GetMetrics returns the metrics the daemon exposes.
+func (d *Daemon) GetMetrics() *Metrics {
+	return d.metrics
+}
+
+// IsDraining returns whether the daemon stopped accepting jobs.
+func (d *Daemon) IsDraining() bool {
+	return d.draining.Load()
+}
+
+// GetInFlight returns the number of jobs running.
+func (d *Daemon) GetInFlight() int64 {
+	return d.inFlight.Load()
+}
+
+// AddCheck adds a check readiness depends on under the name, replacing any check of the same name.
+func (d *Daemon) AddCheck(name string, check CheckFunc) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.checks[name] = check
+}
+
+// Go runs the job in its own goroutine, tracked as in flight until it returns. Once the daemon is
+// draining, jobs are rejected with ErrDraining.
+func (d *Daemon) Go(ctx context.Context, job func(ctx context.Context)) error {
+	done, err := d.Track()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer done()
+		job(ctx)
+	}()
+	return nil
+}
+
+// Track marks a job run by the caller as in flight, until the returned function is called. Once the
+// daemon is draining, jobs are rejected with ErrDraining.
+func (d *Daemon) Track() (func(), error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.draining.Load() {
+		return nil, ErrDraining
+	}
+
+	d.jobs.Add(1)
+	d.metrics.inFlight.Set(float64(d.inFlight.Add(1)))
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			d.metrics.inFlight.Set(float64(d.inFlight.Add(-1)))
+			d.jobs.Done()
+		})
+	}, nil
+}
+
+// Handler returns the handler serving the health and metrics endpoints.
+func (d *Daemon) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", d.handleLiveness)
+	mux.HandleFunc("/readyz", d.handleReadiness)
+	mux.Handle("/metrics", promhttp.HandlerFor(d.metrics.GetRegistry(), promhttp.HandlerOpts{}))
+	return mux
+}
+
+// Run serves the endpoints on the address of the options until the context is done, then drains the
+// jobs in flight and shuts the server down.
+func (d *Daemon) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", d.opts.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", d.opts.Addr, err)
+	}
+	return d.Serve(ctx, listener)
+}
+
+// Serve serves the endpoints on the listener until the context is done, then drains the jobs in flight
+// and shuts the server down.
+func (d *Daemon) Serve(ctx context.Context, listener net.Listener) error {
+	errCh := make(chan error, 1)
+	go func() {
+		zap.L().Info("Daemon listening", zap.String("addr", listener.Addr().String()))
+		errCh <- d.server.Serve(listener)
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), d.opts.DrainTimeout)
+	defer cancel()
+	return d.Drain(drainCtx)
+}
+
+// Drain stops accepting jobs, reports not ready so load balancers stop routing to the daemon, waits
+// for the jobs in flight and shuts the server down. If the context is done first, the jobs still in
+// flight are abandoned, the connections still open are closed and the error of the context is returned.
+func (d *Daemon) Drain(ctx context.Context) error {
+	d.mu.Lock()
+	d.draining.Store(true)
+	d.mu.Unlock()
+	d.metrics.draining.Set(1)
+
+	zap.L().Info("Daemon draining", zap.Int64("in_flight", d.inFlight.Load()))
+
+	drained := make(chan struct{})
+	go func() {
+		d.jobs.Wait()
+		close(drained)
+	}()
+
+	var toReturn error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		toReturn = fmt.Errorf("failed to drain %d jobs in flight: %w", d.inFlight.Load(), ctx.Err())
+	}
+
+	// Connections still open once the context is done are closed forcibly rather than left to
+	// outlive the daemon.
+	if err := d.server.Shutdown(ctx); err != nil {
+		toReturn = errors.Join(toReturn, err, d.server.Close())
+	}
+	return toReturn
+}
+
+// runChecks runs the checks concurrently, sorted by name.
+func (d *Daemon) runChecks(ctx context.Context) ([]*CheckResult, bool) {
+	d.mu.RLock()
+	checks := make(map[string]CheckFunc, len(d.checks))
+	for name, check := range d.checks {
+		checks[name] = check
+	}
+	d.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, d.opts.CheckTimeout)
+	defer cancel()
+
+	toReturn := make([]*CheckResult, 0, len(checks))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check CheckFunc) {
+			defer wg.Done()
+			result := &CheckResult{Name: name}
+			if err := check(ctx); err != nil {
+				result.Error = err.Error()
+			}
+			mu.Lock()
+			toReturn = append(toReturn, result)
+			mu.Unlock()
+		}(name, check)
+	}
+	wg.Wait()
+
+	sort.Slice(toReturn, func(i, j int) bool {
+		return toReturn[i].Name < toReturn[j].Name
+	})
+
+	healthy := true
+	for _, result := range toReturn {
+		if result.Error != "" {
+			healthy = false
+		}
+	}
+	return toReturn, healthy
+}
+
+// handleLiveness reports the daemon alive as long as it serves requests, draining included.
+func (d *Daemon) handleLiveness(w http.ResponseWriter, r *http.Request) {
+	status := &Status{Status: "ok", InFlight: d.inFlight.Load()}
+	if d.draining.Load() {
+		status.Status = "draining"
+	}
+	writeStatus(w, http.StatusOK, status)
+}
+
+// handleReadiness reports the daemon ready when it accepts jobs and its checks pass.
+func (d *Daemon) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	status := &Status{Status: "ok", InFlight: d.inFlight.Load()}
+	if d.draining.Load() {
+		status.Status = "draining"
+		writeStatus(w, http.StatusServiceUnavailable, status)
+		return
+	}
+
+	checks, healthy := d.runChecks(r.Context())
+	status.Checks = checks
+	if !healthy {
+		status.Status = "unavailable"
+		writeStatus(w, http.StatusServiceUnavailable, status)
+		return
+	}
+	writeStatus(w, http.StatusOK, status)
+}
+
+// writeStatus writes the status as JSON with the status code.
+func writeStatus(w http.ResponseWriter, code int, status *Status) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,233 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0x19/solc-switch"
+	"github.com/goccy/go-json"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo"
+	"github.com/unpackdev/solgo/compiler"
+	"github.com/unpackdev/solgo/ir"
+	"github.com/unpackdev/solgo/validation"
+)
+
+// outputBackendForTest returns an empty output for every compilation.
+type outputBackendForTest struct{}
+
+func (outputBackendForTest) Compile(ctx context.Context, version string, input *compiler.Input) (*compiler.Output, error) {
+	return &compiler.Output{}, nil
+}
+
+func getStatusForTest(t *testing.T, handler http.Handler, path string) (int, *Status) {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+	status := &Status{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), status))
+	return recorder.Code, status
+}
+
+func TestDaemonHealth(t *testing.T) {
+	_, err := NewDaemon(nil)
+	assert.Error(t, err)
+
+	daemon, err := NewDaemon(&Options{})
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, daemon.GetOptions().DrainTimeout)
+	handler := daemon.Handler()
+
+	code, status := getStatusForTest(t, handler, "/readyz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok", status.Status)
+
+	failing := errors.New("connection refused")
+	daemon.AddCheck("redis", func(ctx context.Context) error { return failing })
+	daemon.AddCheck("clients", func(ctx context.Context) error { return nil })
+	code, status = getStatusForTest(t, handler, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "unavailable", status.Status)
+	assert.Equal(t, []*CheckResult{{Name: "clients"}, {Name: "redis", Error: "connection refused"}}, status.Checks)
+
+	code, status = getStatusForTest(t, handler, "/healthz")
+	assert.Equal(t, http.StatusOK, code, "failing dependencies do not make the daemon dead")
+	assert.Equal(t, "ok", status.Status)
+
+	failing = nil
+	code, _ = getStatusForTest(t, handler, "/readyz")
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestDaemonMetrics(t *testing.T) {
+	daemon, err := NewDaemon(&Options{})
+	require.NoError(t, err)
+	metrics := daemon.GetMetrics()
+
+	metrics.ObserveParse(nil)
+	metrics.ObserveParse(nil)
+	metrics.ObserveParse(errors.New("syntax error"))
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.parsed.WithLabelValues("success")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.parsed.WithLabelValues("failure")))
+
+	assert.Equal(t, 0.0, metrics.GetVerificationSuccessRate())
+	metrics.ObserveVerification(ir.VerificationFullMatch)
+	metrics.ObserveVerification(ir.VerificationMetadataMismatch)
+	metrics.ObserveVerification(ir.VerificationPartialMatch)
+	metrics.ObserveVerification("")
+	assert.Equal(t, 0.75, metrics.GetVerificationSuccessRate())
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.verifications.WithLabelValues("none")))
+
+	store, err := compiler.NewFileCacheStore(t.TempDir())
+	require.NoError(t, err)
+	cached := compiler.NewCachedBackend(outputBackendForTest{}, store)
+	require.NoError(t, metrics.RegisterCache("compiler", cached))
+	assert.Error(t, metrics.RegisterCache("compiler", cached))
+	assert.Error(t, metrics.RegisterCache("other", nil))
+
+	input := compiler.NewInput(map[string]string{"Counter.sol": "contract Counter {}"})
+	for i := 0; i < 4; i++ {
+		_, err := cached.Compile(context.TODO(), "0.8.19", input)
+		require.NoError(t, err)
+	}
+
+	// Verifiers wrap the store in backends of their own, so the store counts their hits.
+	verifierStore, err := compiler.NewFileCacheStore(t.TempDir())
+	require.NoError(t, err)
+	counting, err := metrics.RegisterCacheStore("verifier", verifierStore)
+	require.NoError(t, err)
+	_, err = metrics.RegisterCacheStore("verifier", verifierStore)
+	assert.Error(t, err)
+	verifier, err := validation.NewVerifier(context.TODO(), &solc.Solc{}, &solgo.Sources{
+		SourceUnits:         []*solgo.SourceUnit{{Name: "Counter", Path: "Counter.sol", Content: "// SPDX-License-Identifier: MIT\npragma solidity ^0.8.19;\n\ncontract Counter {}\n"}},
+		EntrySourceUnitName: "Counter",
+	})
+	require.NoError(t, err)
+	verifier.SetBackend(outputBackendForTest{})
+	verifier.SetCache(counting)
+	for i := 0; i < 3; i++ {
+		_, err := verifier.CompileContracts(context.TODO(), "0.8.19")
+		require.NoError(t, err)
+	}
+
+	recorder := httptest.NewRecorder()
+	daemon.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	body := recorder.Body.String()
+	for _, line := range []string{
+		`solgo_contracts_parsed_total{status="success"} 2`,
+		`solgo_verifications_total{match_level="full"} 1`,
+		`solgo_verification_success_ratio 0.75`,
+		`solgo_cache_hits_total{cache="compiler"} 3`,
+		`solgo_cache_misses_total{cache="compiler"} 1`,
+		`solgo_cache_hit_ratio{cache="compiler"} 0.75`,
+		`solgo_cache_hits_total{cache="verifier"} 2`,
+		`solgo_cache_misses_total{cache="verifier"} 1`,
+		`solgo_jobs_in_flight 0`,
+		`solgo_draining 0`,
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+	assert.Contains(t, body, "go_goroutines")
+}
+
+func TestDaemonDrain(t *testing.T) {
+	daemon, err := NewDaemon(&Options{DrainTimeout: 5 * time.Second})
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	url := "http://" + listener.Addr().String()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- daemon.Serve(ctx, listener)
+	}()
+
+	require.Eventually(t, func() bool {
+		response, err := http.Get(url + "/readyz")
+		if err != nil {
+			return false
+		}
+		defer response.Body.Close()
+		return response.StatusCode == http.StatusOK
+	}, 5*time.Second, 10*time.Millisecond)
+
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	require.NoError(t, daemon.Go(ctx, func(ctx context.Context) {
+		<-release
+		close(finished)
+	}))
+	done, err := daemon.Track()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), daemon.GetInFlight())
+
+	cancel()
+	require.Eventually(t, daemon.IsDraining, 5*time.Second, 10*time.Millisecond)
+	assert.ErrorIs(t, daemon.Go(context.TODO(), func(ctx context.Context) {}), ErrDraining)
+	_, err = daemon.Track()
+	assert.ErrorIs(t, err, ErrDraining)
+
+	// Endpoints are served until the jobs in flight are drained.
+	response, err := http.Get(url + "/readyz")
+	require.NoError(t, err)
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, response.StatusCode)
+	assert.True(t, strings.Contains(string(body), `"status":"draining"`))
+
+	done()
+	done()
+	close(release)
+	<-finished
+	select {
+	case err := <-served:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("daemon did not stop once drained")
+	}
+	assert.Equal(t, int64(0), daemon.GetInFlight())
+
+	// Jobs outliving the timeout are abandoned.
+	stuck, err := NewDaemon(&Options{})
+	require.NoError(t, err)
+	_, err = stuck.Track()
+	require.NoError(t, err)
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer drainCancel()
+	assert.ErrorIs(t, stuck.Drain(drainCtx), context.DeadlineExceeded)
+
+	// Connections outliving the timeout are closed.
+	lingering, err := NewDaemon(&Options{})
+	require.NoError(t, err)
+	listener, err = net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go lingering.Serve(context.Background(), listener)
+	var conn net.Conn
+	require.Eventually(t, func() bool {
+		conn, err = net.Dial("tcp", listener.Addr().String())
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	defer conn.Close()
+	_, err = conn.Write([]byte("GET /healthz HTTP/1.1\r\n"))
+	require.NoError(t, err)
+	time.Sleep(50 * time.Millisecond)
+	drainCtx, drainCancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer drainCancel()
+	assert.ErrorIs(t, lingering.Drain(drainCtx), context.DeadlineExceeded)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = conn.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, io.EOF)
+}
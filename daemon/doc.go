@@ -0,0 +1,9 @@
+/*
+Package daemon runs solgo as a long-running service component. The daemon serves liveness and
+readiness checks and Prometheus metrics over HTTP, such as the number of contracts parsed, the success
+rate of bytecode verifications and the hit rates of compiler output caches, and tracks the work in
+flight so that stopping it drains that work before the server shuts down. Frontends such as a CLI or a
+gRPC server run their work through it, so orchestrators can probe, scrape and roll them like any other
+service.
+*/
+package daemon
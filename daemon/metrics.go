@@ -0,0 +1,191 @@
+package daemon
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/unpackdev/solgo/compiler"
+	"github.com/unpackdev/solgo/ir"
+)
+
+// namespace prefixes the names of the metrics.
+const namespace = "solgo"
+
+// Metrics holds the Prometheus metrics of the daemon, registered with its own registry.
+type Metrics struct {
+	registry      *prometheus.Registry
+	parsed        *prometheus.CounterVec
+	verifications *prometheus.CounterVec
+	inFlight      prometheus.Gauge
+	draining      prometheus.Gauge
+
+	mu       sync.RWMutex
+	verified int64
+	total    int64
+	caches   map[string]CacheCounter
+}
+
+// CacheCounter counts the hits and misses of a cache, as compiler.CachedBackend and
+// compiler.CountingCacheStore do.
+type CacheCounter interface {
+	GetHits() int64
+	GetMisses() int64
+}
+
+// NewMetrics creates the metrics of a daemon, along with the Go runtime and process collectors.
+func NewMetrics() *Metrics {
+	toReturn := &Metrics{
+		registry: prometheus.NewRegistry(),
+		parsed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "contracts_parsed_total",
+			Help:      "Number of contracts parsed, by status.",
+		}, []string{"status"}),
+		verifications: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "verifications_total",
+			Help:      "Number of bytecode verifications, by match level.",
+		}, []string{"match_level"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "jobs_in_flight",
+			Help:      "Number of jobs running.",
+		}),
+		draining: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "draining",
+			Help:      "Whether the daemon is draining, and accepts no new jobs.",
+		}),
+		caches: make(map[string]CacheCounter),
+	}
+
+	toReturn.registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		toReturn.parsed,
+		toReturn.verifications,
+		toReturn.inFlight,
+		toReturn.draining,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "verification_success_ratio",
+			Help:      "Ratio of bytecode verifications that matched.",
+		}, toReturn.GetVerificationSuccessRate),
+		&cacheCollector{metrics: toReturn},
+	)
+	return toReturn
+}
+
+// GetRegistry returns the registry the metrics are gathered from, to register more collectors with.
+func (m *Metrics) GetRegistry() *prometheus.Registry {
+	return m.registry
+}
+
+// ObserveParse counts a contract parsed, failed if parsing it returned an error.
+func (m *Metrics) ObserveParse(err error) {
+	status := "success"
+	if err != nil {
+		status = "failure"
+	}
+	m.parsed.WithLabelValues(status).Inc()
+}
+
+// ObserveVerification counts a bytecode verification at the match level it reached.
+func (m *Metrics) ObserveVerification(level ir.MatchLevel) {
+	if level == "" {
+		level = ir.VerificationNoMatch
+	}
+	m.verifications.WithLabelValues(level.String()).Inc()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.total++
+	if level.IsMatch() {
+		m.verified++
+	}
+}
+
+// GetVerificationSuccessRate returns the ratio of verifications that matched, or 0 if none ran.
+func (m *Metrics) GetVerificationSuccessRate() float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.total == 0 {
+		return 0
+	}
+	return float64(m.verified) / float64(m.total)
+}
+
+// RegisterCache exposes the hits and misses of the cache, such as a cached compiler backend, under the name.
+func (m *Metrics) RegisterCache(name string, cache CacheCounter) error {
+	if cache == nil {
+		return fmt.Errorf("cache %q must be set", name)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.caches[name]; ok {
+		return fmt.Errorf("cache %q already registered", name)
+	}
+	m.caches[name] = cache
+	return nil
+}
+
+// RegisterCacheStore exposes the hits and misses of the store under the name. The returned store counts
+// them, so it is the one to cache in, such as with Verifier.SetCache of the validation package.
+func (m *Metrics) RegisterCacheStore(name string, store compiler.CacheStore) (*compiler.CountingCacheStore, error) {
+	if store == nil {
+		return nil, fmt.Errorf("cache %q must be set", name)
+	}
+
+	toReturn := compiler.NewCountingCacheStore(store)
+	if err := m.RegisterCache(name, toReturn); err != nil {
+		return nil, err
+	}
+	return toReturn, nil
+}
+
+// cacheCollector collects the hits and misses of the registered caches when metrics are gathered, as
+// the caches count them.
+type cacheCollector struct {
+	metrics *Metrics
+}
+
+var (
+	cacheHitsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "cache", "hits_total"),
+		"Number of compilations whose output came from the cache.", []string{"cache"}, nil,
+	)
+	cacheMissesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "cache", "misses_total"),
+		"Number of compilations the cache had no output for.", []string{"cache"}, nil,
+	)
+	cacheHitRatioDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "cache", "hit_ratio"),
+		"Ratio of compilations whose output came from the cache.", []string{"cache"}, nil,
+	)
+)
+
+// Describe sends the descriptors of the cache metrics.
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cacheHitsDesc
+	ch <- cacheMissesDesc
+	ch <- cacheHitRatioDesc
+}
+
+// Collect sends the cache metrics of every registered cache.
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	c.metrics.mu.RLock()
+	defer c.metrics.mu.RUnlock()
+
+	for name, cache := range c.metrics.caches {
+		hits, misses := float64(cache.GetHits()), float64(cache.GetMisses())
+		ratio := 0.0
+		if hits+misses > 0 {
+			ratio = hits / (hits + misses)
+		}
+		ch <- prometheus.MustNewConstMetric(cacheHitsDesc, prometheus.CounterValue, hits, name)
+		ch <- prometheus.MustNewConstMetric(cacheMissesDesc, prometheus.CounterValue, misses, name)
+		ch <- prometheus.MustNewConstMetric(cacheHitRatioDesc, prometheus.GaugeValue, ratio, name)
+	}
+}